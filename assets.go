@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"githubscanner/scanner"
+)
+
+func assetsCommand(flags *flag.FlagSet) func(args []string) {
+	format := flags.String("format", "table", "output format: table (sorted by storage use) or json")
+	outputPath := flags.String("output", "", "output file (stdout by default)")
+	options := addScannerFlags(flags)
+
+	return func(args []string) {
+		if len(args) < 1 {
+			usage("account is not specified")
+		}
+
+		s, err := options.newScanner()
+		if err != nil {
+			fail(err)
+		}
+		accounts, err := options.resolveAccounts(args)
+		if err != nil {
+			fail(err)
+		}
+		items, skipped, err := s.ScanAccounts(accounts)
+		if err != nil {
+			fail(err)
+		}
+		for _, account := range skipped {
+			warn("account %s is skipped: %s", account.Account, account.Reason)
+		}
+		inventory := scanner.NewAssetInventory(items)
+
+		w, err := createOutput(*outputPath)
+		if err != nil {
+			fail(err)
+		}
+		defer w.Close()
+
+		switch *format {
+		case "table":
+			err = writeAssetInventory(w, inventory)
+		case "json":
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(inventory)
+		default:
+			err = fmt.Errorf("unknown output format: %s", *format)
+		}
+		if err != nil {
+			fail(err)
+		}
+		if len(skipped) > 0 {
+			w.Close()
+			exit(exitPartialFailure)
+		}
+	}
+}
+
+func writeAssetInventory(w io.Writer, inventory *scanner.AssetInventory) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "REPOSITORY\tRELEASES\tASSETS\tSIZE\tOLD RELEASES\tDOWNLOADS")
+	for _, repository := range inventory.Repositories {
+		fmt.Fprintf(table, "%s\t%s\n", repository.Repository, assetTotalsColumns(repository.AssetTotals))
+	}
+	fmt.Fprintf(table, "total\t%s\n", assetTotalsColumns(inventory.Total))
+	fmt.Fprintln(table)
+	fmt.Fprintln(table, "ACCOUNT\tRELEASES\tASSETS\tSIZE\tOLD RELEASES\tDOWNLOADS")
+	for _, account := range inventory.Accounts {
+		fmt.Fprintf(table, "%s\t%s\n", account.Account, assetTotalsColumns(account.AssetTotals))
+	}
+
+	return table.Flush()
+}
+
+func assetTotalsColumns(totals scanner.AssetTotals) string {
+	return fmt.Sprintf("%d\t%d\t%s\t%s\t%d", totals.Releases, totals.Assets, formatSize(totals.Bytes), formatSize(totals.OldBytes), totals.Downloads)
+}
+
+// formatSize formats the byte count with a binary unit, e.g. 1.5 GiB.
+func formatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	value, exponent := float64(bytes)/unit, 0
+	for value >= unit && exponent < 4 {
+		value /= unit
+		exponent++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", value, "KMGTP"[exponent])
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"githubscanner/scanner"
+)
+
+func branchesCommand(flags *flag.FlagSet) func(args []string) {
+	staleDays := flags.Int("stale-days", 90, "days without commits a branch is reported as stale after")
+	format := flags.String("format", "text", "output format: text or json")
+	outputPath := flags.String("output", "", "output file (stdout by default)")
+	options := addScannerFlags(flags)
+
+	return func(args []string) {
+		if len(args) < 1 {
+			usage("account is not specified")
+		}
+		if *staleDays <= 0 {
+			usage("stale days must be positive")
+		}
+
+		s, err := options.newScanner()
+		if err != nil {
+			fail(err)
+		}
+		s.ScanBranches = true
+		accounts, err := options.resolveAccounts(args)
+		if err != nil {
+			fail(err)
+		}
+		items, skipped, err := s.ScanAccounts(accounts)
+		if err != nil {
+			fail(err)
+		}
+		for _, account := range skipped {
+			warn("account %s is skipped: %s", account.Account, account.Reason)
+		}
+		stale := scanner.StaleBranches(items, time.Now().AddDate(0, 0, -*staleDays))
+
+		w, err := createOutput(*outputPath)
+		if err != nil {
+			fail(err)
+		}
+		defer w.Close()
+
+		switch *format {
+		case "text":
+			table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(table, "REPOSITORY\tBRANCH\tLAST COMMIT")
+			for _, branch := range stale {
+				name := branch.Branch
+				if branch.Default {
+					name += " (default)"
+				}
+				fmt.Fprintf(table, "%s\t%s\t%s\n", branch.Repository, name, branch.UpdatedAt.Format(time.DateOnly))
+			}
+			err = table.Flush()
+		case "json":
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(stale)
+		default:
+			err = fmt.Errorf("unknown output format: %s", *format)
+		}
+		if err != nil {
+			fail(err)
+		}
+		if len(skipped) > 0 {
+			w.Close()
+			exit(exitPartialFailure)
+		}
+	}
+}
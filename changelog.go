@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"strings"
+
+	"githubscanner/output"
+)
+
+func changelogCommand(flags *flag.FlagSet) func(args []string) {
+	from := flags.String("from", "", "version the changelog starts after, e.g. v1.0.0")
+	to := flags.String("to", "", "version the changelog ends with, e.g. v1.4.0")
+	outputPath := flags.String("output", "", "output file (stdout by default)")
+	options := addScannerFlags(flags)
+
+	return func(args []string) {
+		if len(args) < 1 {
+			usage("repository is not specified: changelog <owner>/<repo>")
+		}
+		owner, repository, ok := strings.Cut(args[0], "/")
+		if !ok {
+			usage("repository must be specified as <owner>/<repo>")
+		}
+		if *from == "" || *to == "" {
+			usage("versions are not specified, use -from and -to")
+		}
+
+		s, err := options.newScanner()
+		if err != nil {
+			fail(err)
+		}
+		changelog, err := s.GetChangelog(owner, repository, *from, *to)
+		if err != nil {
+			fail(err)
+		}
+
+		w, err := createOutput(*outputPath)
+		if err != nil {
+			fail(err)
+		}
+		defer w.Close()
+		if err := output.WriteChangelog(w, changelog); err != nil {
+			fail(err)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"githubscanner/scanner"
+)
+
+func compareCommand(flags *flag.FlagSet) func(args []string) {
+	matchDescriptions := flags.Float64("match-descriptions", 0, "also match repositories with description similarity from 0 to 1 at least the value, e.g. 0.6 (disabled by default)")
+	format := flags.String("format", "text", "output format: text or json")
+	outputPath := flags.String("output", "", "output file (stdout by default)")
+	options := addScannerFlags(flags)
+
+	return func(args []string) {
+		if len(args) > 0 && strings.Contains(args[0], "/") {
+			compareReleases(options, args, *format, *outputPath)
+			return
+		}
+		if len(args) != 2 {
+			usage("two accounts are expected: compare <accountA> <accountB>")
+		}
+		if *matchDescriptions < 0 || *matchDescriptions > 1 {
+			usage("description similarity must be from 0 to 1")
+		}
+
+		s, err := options.newScanner()
+		if err != nil {
+			fail(err)
+		}
+		repositories := make([][]*scanner.Repository, len(args))
+		for i, account := range args {
+			if repositories[i], err = getProvider(s).ListRepositories(context.Background(), account); err != nil {
+				fail(err)
+			}
+		}
+		comparison := scanner.CompareAccounts(args[0], args[1], repositories[0], repositories[1], *matchDescriptions)
+
+		w, err := createOutput(*outputPath)
+		if err != nil {
+			fail(err)
+		}
+		defer w.Close()
+
+		switch *format {
+		case "text":
+			writeComparison(w, comparison)
+		case "json":
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(comparison)
+		default:
+			err = fmt.Errorf("unknown output format: %s", *format)
+		}
+		if err != nil {
+			fail(err)
+		}
+	}
+}
+
+func writeComparison(w io.Writer, comparison *scanner.AccountComparison) {
+	fmt.Fprintf(w, "only in %s: %d\n", comparison.AccountA, len(comparison.OnlyA))
+	for _, repository := range comparison.OnlyA {
+		fmt.Fprintf(w, "  %s\n", repository.FullName)
+	}
+	fmt.Fprintf(w, "only in %s: %d\n", comparison.AccountB, len(comparison.OnlyB))
+	for _, repository := range comparison.OnlyB {
+		fmt.Fprintf(w, "  %s\n", repository.FullName)
+	}
+	fmt.Fprintf(w, "in both: %d\n", len(comparison.Matched))
+	for _, match := range comparison.Matched {
+		if match.By == scanner.MatchByDescription {
+			fmt.Fprintf(w, "  %s = %s (description similarity %.2f)\n", match.A.FullName, match.B.FullName, match.Similarity)
+		} else {
+			fmt.Fprintf(w, "  %s = %s\n", match.A.FullName, match.B.FullName)
+		}
+	}
+}
+
+// compareReleases compares two releases of a repository: compare <owner>/<repo> <base> <head>.
+func compareReleases(options *scannerOptions, args []string, format, outputPath string) {
+	if len(args) != 3 {
+		usage("a repository and two tags are expected: compare <owner>/<repo> <base> <head>")
+	}
+	owner, repository, _ := strings.Cut(args[0], "/")
+
+	s, err := options.newScanner()
+	if err != nil {
+		fail(err)
+	}
+	comparison, err := s.CompareReleases(owner, repository, args[1], args[2])
+	if err != nil {
+		fail(err)
+	}
+
+	w, err := createOutput(outputPath)
+	if err != nil {
+		fail(err)
+	}
+	defer w.Close()
+
+	switch format {
+	case "text":
+		writeReleaseComparison(w, comparison)
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(comparison)
+	default:
+		err = fmt.Errorf("unknown output format: %s", format)
+	}
+	if err != nil {
+		fail(err)
+	}
+}
+
+func writeReleaseComparison(w io.Writer, comparison *scanner.ReleaseComparison) {
+	fmt.Fprintf(w, "%s %s...%s: %s, %d commits ahead, %d behind\n", comparison.Repository, comparison.Base, comparison.Head, comparison.Status, comparison.AheadBy, comparison.BehindBy)
+	if comparison.URL != "" {
+		fmt.Fprintf(w, "%s\n", comparison.URL)
+	}
+	fmt.Fprintf(w, "contributors: %d\n", len(comparison.Contributors))
+	for _, author := range comparison.Contributors {
+		name := author.Name
+		if author.Login != "" {
+			name = author.Login
+		}
+		fmt.Fprintf(w, "  %-24s %d\n", name, author.Commits)
+	}
+	fmt.Fprintf(w, "commits: %d\n", len(comparison.Commits))
+	for _, commit := range comparison.Commits {
+		fmt.Fprintf(w, "  %.7s %s\n", commit.SHA, commit.Title())
+	}
+	fmt.Fprintf(w, "changed files: %d (+%d -%d)\n", len(comparison.Files), comparison.Additions, comparison.Deletions)
+	for _, file := range comparison.Files {
+		if file.PreviousFilename != "" {
+			fmt.Fprintf(w, "  %-9s %s -> %s +%d -%d\n", file.Status, file.PreviousFilename, file.Filename, file.Additions, file.Deletions)
+		} else {
+			fmt.Fprintf(w, "  %-9s %s +%d -%d\n", file.Status, file.Filename, file.Additions, file.Deletions)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+func completionCommand(flags *flag.FlagSet) func(args []string) {
+	return func(args []string) {
+		if len(args) < 1 {
+			usage("shell is not specified: completion bash|zsh|fish")
+		}
+
+		var err error
+		switch args[0] {
+		case "bash":
+			err = writeBashCompletion(os.Stdout)
+		case "zsh":
+			// zsh runs the bash completion through its bashcompinit emulation.
+			fmt.Fprintln(os.Stdout, "autoload -U +X bashcompinit && bashcompinit")
+			err = writeBashCompletion(os.Stdout)
+		case "fish":
+			err = writeFishCompletion(os.Stdout)
+		default:
+			usage(fmt.Sprintf("unsupported shell: %s", args[0]))
+		}
+		if err != nil {
+			fail(err)
+		}
+	}
+}
+
+// commandFlags returns the flags the command registers.
+func commandFlags(cmd *command) []*flag.Flag {
+	flags := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	cmd.setup(flags)
+
+	var result []*flag.Flag
+	flags.VisitAll(func(f *flag.Flag) {
+		result = append(result, f)
+	})
+
+	return result
+}
+
+func writeBashCompletion(w io.Writer) error {
+	var names []string
+	for _, cmd := range commands {
+		names = append(names, cmd.name)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "_%s() {\n", programName)
+	b.WriteString("  local cur=${COMP_WORDS[COMP_CWORD]}\n")
+	b.WriteString("  if [ \"$COMP_CWORD\" -eq 1 ]; then\n")
+	fmt.Fprintf(&b, "    COMPREPLY=($(compgen -W %q -- \"$cur\"))\n", strings.Join(names, " "))
+	b.WriteString("    return\n  fi\n")
+	b.WriteString("  case \"${COMP_WORDS[1]}\" in\n")
+	for _, cmd := range commands {
+		var flagNames []string
+		for _, f := range commandFlags(cmd) {
+			flagNames = append(flagNames, "-"+f.Name)
+		}
+		fmt.Fprintf(&b, "    %s) COMPREPLY=($(compgen -W %q -- \"$cur\")) ;;\n", cmd.name, strings.Join(flagNames, " "))
+	}
+	b.WriteString("  esac\n}\n")
+	fmt.Fprintf(&b, "complete -o default -F _%s %s\n", programName, programName)
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+func writeFishCompletion(w io.Writer) error {
+	var b strings.Builder
+	for _, cmd := range commands {
+		fmt.Fprintf(&b, "complete -c %s -n __fish_use_subcommand -f -a %s -d %s\n", programName, cmd.name, fishQuote(cmd.summary))
+		for _, f := range commandFlags(cmd) {
+			fmt.Fprintf(&b, "complete -c %s -n '__fish_seen_subcommand_from %s' -o %s -d %s\n", programName, cmd.name, f.Name, fishQuote(f.Usage))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+func fishQuote(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(value) + "'"
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"githubscanner/scanner"
+)
+
+func diffCommand(flags *flag.FlagSet) func(args []string) {
+	format := flags.String("format", "text", "output format: text or json")
+
+	return func(args []string) {
+		if len(args) < 2 {
+			usage("two snapshots are expected: diff <old.json> <new.json>")
+		}
+
+		from, err := scanner.LoadSnapshot(args[0])
+		if err != nil {
+			fail(err)
+		}
+		to, err := scanner.LoadSnapshot(args[1])
+		if err != nil {
+			fail(err)
+		}
+
+		changes := scanner.DiffResults(from.Items, to.Items)
+		switch *format {
+		case "text":
+			for _, change := range changes {
+				fmt.Println(formatChange(change))
+			}
+		case "json":
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(changes)
+		default:
+			err = fmt.Errorf("unknown output format: %s", *format)
+		}
+		if err != nil {
+			fail(err)
+		}
+	}
+}
+
+func formatChange(change *scanner.Change) string {
+	if change.Kind == scanner.ChangeSettingChanged {
+		return fmt.Sprintf("%s %s %s: %s -> %s", change.Kind, change.Repository, change.Setting, change.From, change.To)
+	}
+
+	return strings.TrimSpace(fmt.Sprintf("%s %s %s", change.Kind, change.Repository, change.Release))
+}
+
+func churnCommand(flags *flag.FlagSet) func(args []string) {
+	top := flags.Int("top", 10, "number of top contributors compared for maintainer churn")
+
+	return func(args []string) {
+		if len(args) < 2 {
+			usage("two snapshots are expected: churn <old.json> <new.json>")
+		}
+
+		from, err := scanner.LoadSnapshot(args[0])
+		if err != nil {
+			fail(err)
+		}
+		to, err := scanner.LoadSnapshot(args[1])
+		if err != nil {
+			fail(err)
+		}
+
+		report := scanner.Churn(from, to, *top)
+		fmt.Printf("Churn of %s from %s to %s\n\n", to.Account, from.ScannedAt.Format(time.DateOnly), to.ScannedAt.Format(time.DateOnly))
+		fmt.Println("Repositories:")
+		for _, churn := range report.Repositories {
+			if churn.OldName != "" {
+				fmt.Printf("  %s %s -> %s\n", churn.Kind, churn.OldName, churn.Repository)
+			} else {
+				fmt.Printf("  %s %s\n", churn.Kind, churn.Repository)
+			}
+		}
+		fmt.Println("\nTop contributors appeared:", strings.Join(report.AppearedContributors, ", "))
+		fmt.Println("Top contributors disappeared:", strings.Join(report.DisappearedContributors, ", "))
+	}
+}
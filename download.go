@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"githubscanner/scanner"
+)
+
+func downloadCommand(flags *flag.FlagSet) func(args []string) {
+	tag := flags.String("tag", "", "release tag (the latest release by default)")
+	assetPattern := flags.String("asset-pattern", "*", "glob pattern of the asset names to download")
+	dest := flags.String("dest", ".", "destination directory")
+	var platforms platformsFlag
+	flags.Var(&platforms, "platform", "only download assets for the os/arch targets, e.g. linux/amd64 (comma separated or repeated)")
+	options := addScannerFlags(flags)
+
+	return func(args []string) {
+		if len(args) < 1 {
+			usage("repository is not specified: download <owner>/<repo>")
+		}
+		owner, repository, ok := strings.Cut(args[0], "/")
+		if !ok {
+			usage("repository must be specified as <owner>/<repo>")
+		}
+
+		s, err := options.newScanner()
+		if err != nil {
+			fail(err)
+		}
+
+		ctx := context.Background()
+		release, err := s.GetReleaseByTag(ctx, owner, repository, *tag)
+		if err != nil {
+			fail(err)
+		}
+		if err := os.MkdirAll(*dest, 0755); err != nil {
+			fail(err)
+		}
+
+		var assets []*scanner.Asset
+		releaseAssets := release.Assets
+		if len(platforms) > 0 {
+			releaseAssets = scanner.FilterReleaseAssets(release, platforms)
+		}
+		for _, asset := range releaseAssets {
+			if matched, err := path.Match(*assetPattern, asset.Name); err == nil && matched {
+				assets = append(assets, asset)
+			}
+		}
+		if len(assets) == 0 {
+			usage(fmt.Sprintf("no assets of the release %s match %s", release.TagName, *assetPattern))
+		}
+
+		downloads, err := s.DownloadAssets(ctx, release, assets, *dest)
+		downloaded := 0
+		for _, download := range downloads {
+			if download.Err == nil {
+				downloaded++
+				if !quiet {
+					fmt.Println(download.Path)
+				}
+			}
+		}
+		if err != nil && downloaded > 0 {
+			fmt.Fprintln(os.Stderr, err.Error())
+			exit(exitPartialFailure)
+		}
+		if err != nil {
+			fail(err)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"text/tabwriter"
+
+	"githubscanner/scanner"
+)
+
+func driftCommand(flags *flag.FlagSet) func(args []string) {
+	deploymentsPath := flags.String("deployments", "", "csv (repository,version) or json file with the deployed versions of the repositories")
+	format := flags.String("format", "text", "output format: text or json")
+	outputPath := flags.String("output", "", "output file (stdout by default)")
+	options := addScannerFlags(flags)
+
+	return func(args []string) {
+		if len(args) < 1 {
+			usage("account is not specified")
+		}
+		if *deploymentsPath == "" {
+			usage("deployments file is not specified, use -deployments")
+		}
+
+		deployments, err := scanner.LoadDeployments(*deploymentsPath)
+		if err != nil {
+			fail(err)
+		}
+		s, err := options.newScanner()
+		if err != nil {
+			fail(err)
+		}
+		accounts, err := options.resolveAccounts(args)
+		if err != nil {
+			fail(err)
+		}
+		items, skipped, err := s.ScanAccounts(accounts)
+		if err != nil {
+			fail(err)
+		}
+		for _, account := range skipped {
+			warn("account %s is skipped: %s", account.Account, account.Reason)
+		}
+		drifts := s.VersionDrift(items, deployments)
+
+		w, err := createOutput(*outputPath)
+		if err != nil {
+			fail(err)
+		}
+		defer w.Close()
+
+		switch *format {
+		case "text":
+			table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(table, "REPOSITORY\tDEPLOYED\tLATEST\tBEHIND\tDISTANCE")
+			for _, drift := range drifts {
+				if drift.Error != "" {
+					fmt.Fprintf(table, "%s\t%s\t-\t-\t%s\n", drift.Repository, drift.Deployed, drift.Error)
+					continue
+				}
+				distance := drift.Distance
+				if distance == "" {
+					distance = "-"
+				}
+				fmt.Fprintf(table, "%s\t%s\t%s\t%d\t%s\n", drift.Repository, drift.Deployed, drift.Latest, drift.ReleasesBehind, distance)
+			}
+			err = table.Flush()
+		case "json":
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(drifts)
+		default:
+			err = fmt.Errorf("unknown output format: %s", *format)
+		}
+		if err != nil {
+			fail(err)
+		}
+		if len(skipped) > 0 {
+			w.Close()
+			exit(exitPartialFailure)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+// Package e2e runs the githubscanner binary end to end against the GitHub API responses recorded in the cassette.
+// The tests are skipped unless GITHUBSCANNER_E2E=1 is set, as they build the binary.
+//
+// GITHUBSCANNER_E2E_UPDATE=1 rewrites the golden outputs with the current ones. GITHUBSCANNER_E2E_RECORD=1 proxies
+// the requests to the real GitHub API with GITHUB_TOKEN and rewrites the cassette with the responses.
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const cassettePath = "testdata/cassette.json"
+
+// recordedHeaders are the response headers kept in the cassette, the scanner does not look at the rest.
+var recordedHeaders = []string{"Content-Type", "Link", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "X-GitHub-SSO"}
+
+var scenarios = []struct {
+	name     string
+	args     []string
+	exitCode int
+}{
+	{"scan-text", []string{"scan", "acme-corp"}, 0},
+	{"scan-ndjson", []string{"scan", "-format", "ndjson", "acme-corp"}, 0},
+	{"scan-settings", []string{"scan", "-format", "ndjson", "-settings", "acme-tools"}, 0},
+	{"scan-enrichers", []string{"scan", "-format", "ndjson", "-with-contributors", "-with-languages", "-with-go-modules", "acme-labs"}, 0},
+	{"scan-enrichers-text", []string{"scan", "-with-contributors", "-with-languages", "-with-go-modules", "acme-labs"}, 0},
+	{"repos", []string{"repos", "acme-corp"}, 0},
+	{"releases", []string{"releases", "acme-corp/scanner"}, 0},
+	{"rate-limited", []string{"scan", "acme-limited"}, 3},
+	{"unknown-flag", []string{"scan", "-bogus", "acme-corp"}, 1},
+	{"invalid-sort", []string{"scan", "-sort", "nope", "acme-corp"}, 1},
+}
+
+type interaction struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers"`
+	Body    string            `json:"body"`
+}
+
+type cassette struct {
+	Interactions []*interaction `json:"interactions"`
+}
+
+var binary string
+
+func TestMain(m *testing.M) {
+	if os.Getenv("GITHUBSCANNER_E2E") != "1" {
+		os.Exit(m.Run())
+	}
+
+	dir, err := os.MkdirTemp("", "githubscanner-e2e")
+	if err != nil {
+		panic(err)
+	}
+	binary = filepath.Join(dir, "githubscanner")
+	build := exec.Command("go", "build", "-o", binary, "githubscanner")
+	build.Stdout, build.Stderr = os.Stdout, os.Stderr
+	if err := build.Run(); err != nil {
+		panic(err)
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func TestScenarios(t *testing.T) {
+	if binary == "" {
+		t.Skip("end-to-end tests are enabled with GITHUBSCANNER_E2E=1")
+	}
+
+	var handler http.Handler
+	var recorded *cassette
+	if os.Getenv("GITHUBSCANNER_E2E_RECORD") == "1" {
+		recorded = &cassette{}
+		handler = recordingHandler(t, recorded)
+	} else {
+		handler = replayHandler(t, loadCassette(t))
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			cmd := exec.Command(binary, append(scenario.args, "-base-url", server.URL)...)
+			// scans record their progress in a checkpoint file of the working dir
+			cmd.Dir = t.TempDir()
+			cmd.Env = append(os.Environ(), "GITHUB_TOKEN=e2e", "GITHUBSCANNER_CONFIG="+filepath.Join(cmd.Dir, "config.json"))
+			cmd.Stdout, cmd.Stderr = &stdout, &stderr
+
+			exitCode := 0
+			var exitError *exec.ExitError
+			if err := cmd.Run(); errors.As(err, &exitError) {
+				exitCode = exitError.ExitCode()
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if exitCode != scenario.exitCode {
+				t.Fatalf("invalid exit code, expected %d, got %d, stderr:\n%s", scenario.exitCode, exitCode, stderr.String())
+			}
+
+			compareGolden(t, scenario.name+".stdout", stdout.String(), server.URL)
+			compareGolden(t, scenario.name+".stderr", stderr.String(), server.URL)
+		})
+	}
+
+	if recorded != nil {
+		data, err := json.MarshalIndent(recorded, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(cassettePath, append(data, '\n'), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func loadCassette(t *testing.T) *cassette {
+	data, err := os.ReadFile(cassettePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var c cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		t.Fatal(err)
+	}
+
+	return &c
+}
+
+// replayHandler responds with the recorded interaction of the same method and url. The same request always
+// gets the same response, so scenarios could share the cassette.
+func replayHandler(t *testing.T, c *cassette) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, interaction := range c.Interactions {
+			if interaction.Method == r.Method && interaction.URL == r.URL.RequestURI() {
+				for name, value := range interaction.Headers {
+					w.Header().Set(name, value)
+				}
+				w.WriteHeader(interaction.Status)
+				io.WriteString(w, interaction.Body)
+				return
+			}
+		}
+		t.Errorf("request %s %s is not recorded in the cassette", r.Method, r.URL.RequestURI())
+		http.NotFound(w, r)
+	})
+}
+
+// recordingHandler proxies requests to the GitHub API with the real token and records the responses.
+func recordingHandler(t *testing.T, c *cassette) http.Handler {
+	var mu sync.Mutex
+	recorded := make(map[string]bool)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request, err := http.NewRequestWithContext(r.Context(), r.Method, "https://api.github.com"+r.URL.RequestURI(), nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+			request.Header.Set("Authorization", "Bearer "+token)
+		}
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer response.Body.Close()
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		interaction := &interaction{Method: r.Method, URL: r.URL.RequestURI(), Status: response.StatusCode, Headers: map[string]string{}, Body: string(body)}
+		for _, name := range recordedHeaders {
+			if value := response.Header.Get(name); value != "" {
+				interaction.Headers[name] = value
+				w.Header().Set(name, value)
+			}
+		}
+		mu.Lock()
+		if !recorded[r.Method+" "+interaction.URL] {
+			recorded[r.Method+" "+interaction.URL] = true
+			c.Interactions = append(c.Interactions, interaction)
+		}
+		mu.Unlock()
+
+		w.WriteHeader(response.StatusCode)
+		w.Write(body)
+	})
+}
+
+// scanDuration matches the duration in the scan statistics.
+var scanDuration = regexp.MustCompile(`API requests in \d+\.\ds`)
+
+// compareGolden compares the output with the golden file. The replay server url and the scan duration are replaced
+// with placeholders, as they change between runs.
+func compareGolden(t *testing.T, name, actual, baseUrl string) {
+	actual = strings.ReplaceAll(actual, baseUrl, "{base-url}")
+	actual = scanDuration.ReplaceAllString(actual, "API requests in {duration}")
+	path := filepath.Join("testdata", "golden", name)
+	if os.Getenv("GITHUBSCANNER_E2E_UPDATE") == "1" {
+		if err := os.WriteFile(path, []byte(actual), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual != string(expected) {
+		t.Fatalf("invalid %s output, expected:\n%s\ngot:\n%s", name, expected, actual)
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"githubscanner/scanner"
+)
+
+// Exit codes of the CLI, so CI pipelines can distinguish failure causes. Only invalid arguments exit with
+// exitUsage, failures without a known class exit with exitFailure.
+const (
+	exitOK = iota
+	exitUsage
+	exitAPIError
+	exitRateLimited
+	exitPartialFailure
+	exitFailure
+)
+
+// quiet suppresses non-error output.
+var quiet bool
+
+type remediation struct {
+	class   error
+	message string
+	hint    string
+	docs    string
+}
+
+var remediations = []remediation{
+	{
+		class:   scanner.ErrBadCredentials,
+		message: "The API token is invalid or expired",
+		hint:    "run githubscanner login again, or create a new token and pass it with -token or the GITHUB_TOKEN env var",
+		docs:    "https://docs.github.com/en/authentication/keeping-your-account-and-data-secure/managing-your-personal-access-tokens",
+	},
+	{
+		class:   scanner.ErrSSORequired,
+		message: "The token is not authorized for the organization SAML single sign-on",
+		hint:    "authorize the token for the organization in the token settings (Configure SSO)",
+		docs:    "https://docs.github.com/en/enterprise-cloud@latest/authentication/authenticating-with-saml-single-sign-on/authorizing-a-personal-access-token-for-use-with-saml-single-sign-on",
+	},
+	{
+		class:   scanner.ErrRateLimited,
+		message: "The API rate limit is exceeded",
+		hint:    "wait until the limit resets or use a token: authenticated requests have a much higher limit",
+		docs:    "https://docs.github.com/en/rest/using-the-rest-api/rate-limits-for-the-rest-api",
+	},
+	{
+		class:   scanner.ErrInsufficientBudget,
+		message: "The remaining API rate limit is lower than the estimated requests of the scan",
+		hint:    "wait until the limit resets, disable optional scan data or pass -force to scan anyway",
+		docs:    "https://docs.github.com/en/rest/using-the-rest-api/rate-limits-for-the-rest-api",
+	},
+	{
+		class:   scanner.ErrProxyBlocked,
+		message: "The request was blocked by a proxy",
+		hint:    "check the -proxy flag or the HTTPS_PROXY env var and ask your network administrators to allow api.github.com",
+		docs:    "https://pkg.go.dev/net/http#ProxyFromEnvironment",
+	},
+	{
+		class:   scanner.ErrOffline,
+		message: "The data is not available in the offline mode",
+		hint:    "run the same command online with -cache-dir first to cache the responses, or pass a stored scan with -provider cache -cache-snapshot",
+		docs:    programName + " help scan",
+	},
+	{
+		class:   scanner.ErrNotFound,
+		message: "The account or repository does not exist or is not visible to the token",
+		hint:    "check the name spelling; private repositories require a token with access to them",
+		docs:    "https://docs.github.com/en/rest/using-the-rest-api/troubleshooting-the-rest-api",
+	},
+}
+
+// fail prints the error with remediation steps for known failure classes and exits with the code of its class.
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err.Error())
+	for _, r := range remediations {
+		if errors.Is(err, r.class) {
+			fmt.Fprintf(os.Stderr, "\n%s.\nHint: %s.\nSee: %s\n", r.message, r.hint, r.docs)
+			var apiError *scanner.APIError
+			if errors.As(err, &apiError) && apiError.SSOAuthorizationURL != "" {
+				fmt.Fprintf(os.Stderr, "Authorize: %s\n", apiError.SSOAuthorizationURL)
+			}
+			break
+		}
+	}
+	exit(exitCode(err))
+}
+
+func exitCode(err error) int {
+	var apiError *scanner.APIError
+	switch {
+	case errors.Is(err, scanner.ErrRateLimited), errors.Is(err, scanner.ErrInsufficientBudget):
+		return exitRateLimited
+	case errors.As(err, &apiError), errors.Is(err, scanner.ErrNotFound), errors.Is(err, scanner.ErrProxyBlocked):
+		return exitAPIError
+	default:
+		return exitFailure
+	}
+}
+
+// usage prints the message about invalid arguments and exits.
+func usage(message string) {
+	fmt.Fprintln(os.Stderr, message)
+	exit(exitUsage)
+}
+
+// exitHooks run before the CLI exits, e.g. to export the spans left in the batch of the trace exporter.
+var exitHooks []func()
+
+// runExitHooks runs the exit hooks once.
+func runExitHooks() {
+	hooks := exitHooks
+	exitHooks = nil
+	for _, hook := range hooks {
+		hook()
+	}
+}
+
+// exit runs the exit hooks and exits with the code.
+func exit(code int) {
+	runExitHooks()
+	os.Exit(code)
+}
+
+// warn prints the warning unless the quiet mode is on.
+func warn(format string, args ...any) {
+	if !quiet {
+		fmt.Fprintf(os.Stderr, format+"\n", args...)
+	}
+}
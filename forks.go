@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"text/tabwriter"
+
+	"githubscanner/scanner"
+)
+
+func forksCommand(flags *flag.FlagSet) func(args []string) {
+	staleOnly := flags.Bool("stale", false, "only report forks without commits of their own")
+	format := flags.String("format", "text", "output format: text or json")
+	outputPath := flags.String("output", "", "output file (stdout by default)")
+	options := addScannerFlags(flags)
+
+	return func(args []string) {
+		if len(args) < 1 {
+			usage("account is not specified")
+		}
+
+		s, err := options.newScanner()
+		if err != nil {
+			fail(err)
+		}
+		accounts, err := options.resolveAccounts(args)
+		if err != nil {
+			fail(err)
+		}
+		var forks []*scanner.Fork
+		for _, account := range accounts {
+			accountForks, err := s.GetForks(account)
+			if err != nil {
+				fail(err)
+			}
+			for _, fork := range accountForks {
+				if !*staleOnly || fork.Stale() {
+					forks = append(forks, fork)
+				}
+			}
+		}
+
+		w, err := createOutput(*outputPath)
+		if err != nil {
+			fail(err)
+		}
+		defer w.Close()
+
+		switch *format {
+		case "text":
+			table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(table, "FORK\tPARENT\tSOURCE\tBEHIND\tAHEAD\tNOTE")
+			for _, fork := range forks {
+				if fork.Error != "" {
+					fmt.Fprintf(table, "%s\t%s\t%s\t-\t-\t%s\n", fork.Repository, orDash(fork.Parent), orDash(fork.Source), fork.Error)
+					continue
+				}
+				note := "-"
+				if fork.Stale() {
+					note = "no own commits"
+				}
+				fmt.Fprintf(table, "%s\t%s\t%s\t%d\t%d\t%s\n", fork.Repository, fork.Parent, fork.Source, fork.BehindBy, fork.AheadBy, note)
+			}
+			err = table.Flush()
+		case "json":
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(forks)
+		default:
+			err = fmt.Errorf("unknown output format: %s", *format)
+		}
+		if err != nil {
+			fail(err)
+		}
+	}
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
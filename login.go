@@ -0,0 +1,279 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"githubscanner/scanner"
+)
+
+// keychainService is the service name of the token in the OS keychain.
+const keychainService = "githubscanner"
+
+func loginCommand(flags *flag.FlagSet) func(args []string) {
+	clientID := flags.String("client-id", os.Getenv("GITHUBSCANNER_CLIENT_ID"), "client id of the OAuth app with the device flow enabled (GITHUBSCANNER_CLIENT_ID env var by default)")
+	scopes := flags.String("scopes", "repo,read:org,read:packages", "comma separated OAuth scopes of the token")
+	loginUrl := flags.String("login-url", scanner.GitHubLogin, "base url of the GitHub OAuth endpoints")
+	storage := flags.String("storage", "auto", "token storage: keychain, file or auto (the keychain if available, the config dir otherwise)")
+	logout := flags.Bool("logout", false, "remove the stored token instead of logging in")
+	flags.BoolVar(&quiet, "quiet", false, "suppress non-error output")
+
+	return func(args []string) {
+		if *logout {
+			if err := deleteStoredToken(); err != nil {
+				fail(err)
+			}
+			warn("The stored token is removed.")
+			return
+		}
+		if *clientID == "" {
+			usage("login requires the client id of an OAuth app with the device flow enabled: pass -client-id or set GITHUBSCANNER_CLIENT_ID")
+		}
+		if *storage != "auto" && *storage != "keychain" && *storage != "file" {
+			usage(fmt.Sprintf("unknown token storage: %s", *storage))
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		flow := &scanner.DeviceFlow{BaseUrl: *loginUrl, ClientID: *clientID}
+		for _, scope := range strings.Split(*scopes, ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				flow.Scopes = append(flow.Scopes, scope)
+			}
+		}
+		code, err := flow.RequestCode(ctx)
+		if err != nil {
+			fail(err)
+		}
+		// the code is printed even in the quiet mode, the login can not be completed without it
+		fmt.Fprintf(os.Stderr, "Open %s and enter the code %s\n", code.VerificationURI, code.UserCode)
+
+		token, err := flow.PollToken(ctx, code)
+		if err != nil {
+			fail(err)
+		}
+		stored := &storedToken{AccessToken: token.AccessToken, Expiry: token.Expiry, RefreshToken: token.RefreshToken}
+		if token.RefreshToken != "" {
+			stored.ClientID, stored.LoginUrl = *clientID, *loginUrl
+		}
+		location, err := storeToken(stored, *storage)
+		if err != nil {
+			fail(err)
+		}
+		warn("Logged in, the token is stored in %s.", location)
+	}
+}
+
+// storedToken is the token stored by the login command. Expiring user tokens of GitHub Apps are stored with their
+// expiry, the refresh token and the OAuth app they are refreshed with, other tokens are stored as they are.
+type storedToken struct {
+	AccessToken  string    `json:"access_token"`
+	Expiry       time.Time `json:"expiry"`
+	RefreshToken string    `json:"refresh_token"`
+	ClientID     string    `json:"client_id"`
+	LoginUrl     string    `json:"login_url"`
+	// storage is where the token was loaded from, refreshed tokens are stored back there.
+	storage string
+}
+
+func (t *storedToken) encode() (string, error) {
+	if t.RefreshToken == "" {
+		return t.AccessToken, nil
+	}
+	data, err := json.Marshal(t)
+
+	return string(data), err
+}
+
+func decodeStoredToken(data, storage string) *storedToken {
+	data = strings.TrimSpace(data)
+	if data == "" {
+		return nil
+	}
+	token := &storedToken{AccessToken: data, storage: storage}
+	if strings.HasPrefix(data, "{") {
+		token = &storedToken{storage: storage}
+		if json.Unmarshal([]byte(data), token) != nil {
+			return nil
+		}
+	}
+
+	return token
+}
+
+// storedTokenSource returns the source of the token stored by the login command. Expiring tokens are refreshed
+// with their refresh token and stored again, so the next commands use the refreshed ones.
+func storedTokenSource(stored *storedToken, client *http.Client) scanner.TokenSource {
+	if stored.RefreshToken == "" {
+		return scanner.StaticTokenSource(stored.AccessToken)
+	}
+
+	return scanner.ReuseTokenSource(&scanner.OAuthRefreshTokenSource{
+		BaseUrl:      stored.LoginUrl,
+		ClientID:     stored.ClientID,
+		RefreshToken: stored.RefreshToken,
+		AccessToken:  &scanner.Token{Value: stored.AccessToken, Expiry: stored.Expiry},
+		Client:       client,
+		OnRefresh: func(token *scanner.Token, refreshToken string) {
+			refreshed := *stored
+			refreshed.AccessToken, refreshed.Expiry, refreshed.RefreshToken = token.Value, token.Expiry, refreshToken
+			if _, err := storeToken(&refreshed, stored.storage); err != nil {
+				warn("could not store the refreshed token: %v", err)
+			}
+		},
+	})
+}
+
+// tokenPath returns the path of the token file in the config dir.
+func tokenPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "githubscanner", "token"), nil
+}
+
+// storeToken stores the token in the storage and returns the description of its location.
+func storeToken(stored *storedToken, storage string) (string, error) {
+	token, err := stored.encode()
+	if err != nil {
+		return "", err
+	}
+	if storage != "file" {
+		err := keychainStore(token)
+		if err == nil {
+			// a token stored in the config dir by an earlier login would take precedence
+			if path, err := tokenPath(); err == nil {
+				os.Remove(path)
+			}
+			return "the OS keychain", nil
+		}
+		// the keychain could be locked or missing in headless sessions, the config dir is used then
+		if storage == "keychain" {
+			return "", fmt.Errorf("could not store the token in the OS keychain: %w", err)
+		}
+	}
+
+	path, err := tokenPath()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, []byte(token+"\n"), 0o600); err != nil {
+		return "", fmt.Errorf("could not store the token: %w", err)
+	}
+
+	return path, nil
+}
+
+// loadStoredToken returns the token stored by the login command, nil if there is none.
+func loadStoredToken() *storedToken {
+	if path, err := tokenPath(); err == nil {
+		if data, err := os.ReadFile(path); err == nil {
+			return decodeStoredToken(string(data), "file")
+		}
+	}
+	token, _ := keychainLoad()
+
+	return decodeStoredToken(token, "keychain")
+}
+
+func deleteStoredToken() error {
+	path, err := tokenPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	keychainDelete()
+
+	return nil
+}
+
+var errKeychainUnavailable = errors.New("keychain is not available")
+
+// keychainCommand returns the command line tool managing the OS keychain: security on macOS and secret-tool
+// of libsecret on Linux.
+func keychainCommand() (string, error) {
+	name := ""
+	switch runtime.GOOS {
+	case "darwin":
+		name = "security"
+	case "linux":
+		name = "secret-tool"
+	default:
+		return "", errKeychainUnavailable
+	}
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return "", errKeychainUnavailable
+	}
+
+	return path, nil
+}
+
+func keychainStore(token string) error {
+	path, err := keychainCommand()
+	if err != nil {
+		return err
+	}
+	// the token is passed on stdin, arguments are visible to other local users in the process list
+	if runtime.GOOS == "darwin" {
+		// -w without a value as the last argument prompts for the password and its confirmation
+		command := exec.Command(path, "add-generic-password", "-U", "-a", keychainService, "-s", keychainService, "-w")
+		command.Stdin = strings.NewReader(token + "\n" + token + "\n")
+		return command.Run()
+	}
+	command := exec.Command(path, "store", "--label", keychainService, "service", keychainService)
+	command.Stdin = strings.NewReader(token)
+
+	return command.Run()
+}
+
+func keychainLoad() (string, error) {
+	path, err := keychainCommand()
+	if err != nil {
+		return "", err
+	}
+	var output []byte
+	if runtime.GOOS == "darwin" {
+		output, err = exec.Command(path, "find-generic-password", "-a", keychainService, "-s", keychainService, "-w").Output()
+	} else {
+		output, err = exec.Command(path, "lookup", "service", keychainService).Output()
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// keychainDelete removes the token from the OS keychain. Errors are ignored, the token is missing there if it was
+// stored in the config dir.
+func keychainDelete() {
+	path, err := keychainCommand()
+	if err != nil {
+		return
+	}
+	if runtime.GOOS == "darwin" {
+		exec.Command(path, "delete-generic-password", "-a", keychainService, "-s", keychainService).Run()
+		return
+	}
+	exec.Command(path, "clear", "service", keychainService).Run()
+}
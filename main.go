@@ -1,28 +1,132 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
-	"githubscanner/scanner"
+	"io"
 	"os"
 )
 
+const programName = "githubscanner"
+
+// command is a CLI subcommand. Its setup registers the command flags and returns the function run with the
+// positional arguments once the flags are parsed, so the flags are known without running the command.
+type command struct {
+	name    string
+	args    string
+	summary string
+	setup   func(flags *flag.FlagSet) func(args []string)
+}
+
+var commands []*command
+
+func init() {
+	commands = []*command{
+		{"scan", "<account|group>...", "Scan releases of the repositories of the accounts", scanCommand},
+		{"repos", "<account|group>...", "List repositories of the accounts", reposCommand},
+		{"releases", "<owner>/<repo>", "List releases of the repository", releasesCommand},
+		{"changelog", "<owner>/<repo>", "Aggregate release notes between two versions into one Markdown changelog", changelogCommand},
+		{"branches", "<account|group>...", "Report branches not updated for a number of days", branchesCommand},
+		{"drift", "<account|group>...", "Report how far deployed versions are behind the latest releases", driftCommand},
+		{"compare", "<accountA> <accountB> | <owner>/<repo> <base> <head>", "Compare the repositories of two accounts, or the commits, contributors and files between two releases", compareCommand},
+		{"diff", "<old.json> <new.json>", "Show repositories and releases changed between two scan snapshots", diffCommand},
+		{"churn", "<old.json> <new.json>", "Show repository and maintainer churn between two scan snapshots", churnCommand},
+		{"watch", "<account|group>...", "Rescan the accounts periodically and print detected changes", watchCommand},
+		{"serve", "", "Serve scans over HTTP", serveCommand},
+		{"download", "<owner>/<repo>", "Download and verify release assets", downloadCommand},
+		{"forks", "<account|group>...", "Map forks to their upstreams and show how far behind they are", forksCommand},
+		{"ownership", "<org>...", "Report the teams owning every repository of the organizations", ownershipCommand},
+		{"assets", "<account|group>...", "Report release asset counts, sizes and downloads per repository and account", assetsCommand},
+		{"packages", "<account|group>...", "List GitHub Packages of the accounts with their versions", packagesCommand},
+		{"sbom", "<account|group|owner/repo>...", "Export SPDX SBOMs of the repository dependency graphs", sbomCommand},
+		{"ratelimit", "", "Show the remaining API rate limits of the token and their reset times", rateLimitCommand},
+		{"schema", "", "Print the JSON Schema of the json and ndjson scan output or validate a file against it", schemaCommand},
+		{"report", "<account|group>", "Render release notes as a Markdown report", reportCommand},
+		{"alert-rules", "[account|group]...", "Generate Prometheus alert rules for the serve mode metrics", alertRulesCommand},
+		{"login", "", "Log in with the OAuth device flow and store the token for subsequent commands", loginCommand},
+		{"completion", "bash|zsh|fish", "Generate the shell completion script", completionCommand},
+	}
+}
+
 func main() {
 	if len(os.Args) < 2 {
-		fmt.Println("account is not specified")
-		os.Exit(1)
+		printUsage(os.Stderr)
+		os.Exit(exitUsage)
+	}
+
+	switch os.Args[1] {
+	case "help", "-h", "-help", "--help":
+		if len(os.Args) > 2 {
+			if cmd := findCommand(os.Args[2]); cmd != nil {
+				runCommand(cmd, []string{"-help"})
+			}
+		}
+		printUsage(os.Stdout)
+		return
+	}
+
+	if cmd := findCommand(os.Args[1]); cmd != nil {
+		runCommand(cmd, os.Args[2:])
+		return
 	}
+	// Accounts were passed directly before the CLI had subcommands, so that still means a scan.
+	runCommand(findCommand("scan"), os.Args[1:])
+}
+
+func findCommand(name string) *command {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd
+		}
+	}
+
+	return nil
+}
 
-	items, err := scanner.GetDefaultScanner().ScanRepositories(os.Args[1])
-	if err != nil {
-		fmt.Println(err.Error())
-		os.Exit(1)
+func runCommand(cmd *command, args []string) {
+	// The flag package exits with 2 on invalid flags, which is exitAPIError here, so parse errors are reported with
+	// usage and exit with exitUsage.
+	flags := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	run := cmd.setup(flags)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s %s [flags] %s\n\n%s.\n\nFlags:\n", programName, cmd.name, cmd.args, cmd.summary)
+		flags.PrintDefaults()
 	}
+	flags.SetOutput(io.Discard)
+	positional, err := parseFlags(flags, args)
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		flags.SetOutput(os.Stdout)
+		flags.Usage()
+		exit(exitOK)
+	case err != nil:
+		usage(fmt.Sprintf("%s\nRun \"%s help %s\" for the command flags.", err, programName, cmd.name))
+	}
+	flags.SetOutput(nil)
+	run(positional)
+	runExitHooks()
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: %s <command> [flags] [arguments]\n\nCommands:\n", programName)
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-12s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(w, "\nRun \"%s help <command>\" for the command flags.\n", programName)
+}
 
-	for _, item := range items {
-		fmt.Println(item.Repository.FullName)
-		for _, release := range item.Releases {
-			fmt.Println(release.Name)
+// parseFlags parses flags placed both before and after positional arguments and returns the positional ones.
+func parseFlags(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+		if flags.NArg() == 0 {
+			return positional, nil
 		}
-		fmt.Println()
+		positional = append(positional, flags.Arg(0))
+		args = flags.Args()[1:]
 	}
 }
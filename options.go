@@ -0,0 +1,384 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"githubscanner/scanner"
+)
+
+type scannerOptions struct {
+	logLevel  slog.Level
+	logFormat string
+	provider  string
+	baseUrl   string
+	token     string
+	tokenFile string
+	// appID, appInstallationID and appKeyPath authenticate as a GitHub App installation.
+	appID             string
+	appInstallationID string
+	appKeyPath        string
+	// cacheSnapshot is the snapshot file served by the cache provider.
+	cacheSnapshot   string
+	configPath      string
+	annotationsPath string
+	// ignorePath is the ignore list of repositories skipped by scans, see defaultIgnoreFile.
+	ignorePath string
+	// cacheDir enables the disk cache of API responses.
+	cacheDir     string
+	cacheTTL     time.Duration
+	cacheMaxSize int64
+	// requestTimeout bounds every API request, so a hung connection can't stall the command.
+	requestTimeout time.Duration
+	// transport is the proxy and TLS configuration of the API requests.
+	transport scanner.TransportConfig
+	// offline serves the commands from the response cache or the cache provider snapshot without network calls.
+	offline bool
+	// versionSchemes take precedence over the version schemes of the config.
+	versionSchemes versionSchemesFlag
+	// otlpEndpoint is the OTLP/HTTP endpoint the spans are exported to.
+	otlpEndpoint string
+}
+
+func addScannerFlags(flags *flag.FlagSet) *scannerOptions {
+	options := &scannerOptions{logLevel: slog.LevelWarn}
+	flags.TextVar(&options.logLevel, "log-level", options.logLevel, "log level: debug, info, warn or error")
+	flags.StringVar(&options.logFormat, "log-format", "text", "log format: text or json")
+	flags.StringVar(&options.provider, "provider", "github", "code hosting provider: github, github-graphql, gitlab, gitea or cache; a comma separated list is a fallback chain, e.g. github-graphql,github,cache")
+	flags.StringVar(&options.baseUrl, "base-url", "", "API base url of the provider")
+	flags.StringVar(&options.cacheSnapshot, "cache-snapshot", "", "snapshot file (scan -format json output) served by the cache provider")
+	flags.StringVar(&options.token, "token", "", "provider token (GITHUB_TOKEN, GITLAB_TOKEN or GITEA_TOKEN env var by default, then the token stored by login)")
+	flags.StringVar(&options.tokenFile, "token-file", "", "file with the GitHub token, read again every minute so rotated tokens are picked up")
+	flags.StringVar(&options.appID, "app-id", os.Getenv("GITHUBSCANNER_APP_ID"), "GitHub App id to authenticate as an app installation (GITHUBSCANNER_APP_ID env var by default)")
+	flags.StringVar(&options.appInstallationID, "app-installation-id", os.Getenv("GITHUBSCANNER_APP_INSTALLATION_ID"), "GitHub App installation id (GITHUBSCANNER_APP_INSTALLATION_ID env var by default)")
+	flags.StringVar(&options.appKeyPath, "app-key", os.Getenv("GITHUBSCANNER_APP_KEY"), "PEM file with the GitHub App private key (GITHUBSCANNER_APP_KEY env var by default)")
+	flags.StringVar(&options.cacheDir, "cache-dir", "", "directory caching API responses, so repeated runs do not fetch the same pages again")
+	flags.DurationVar(&options.cacheTTL, "cache-dir-ttl", 5*time.Minute, "how long responses cached in -cache-dir are used without revalidation")
+	flags.Int64Var(&options.cacheMaxSize, "cache-max-size", 100, "max size of the response cache in MB")
+	flags.DurationVar(&options.requestTimeout, "request-timeout", 0, "fail API requests taking longer than the duration including the response body, e.g. 1m (not bounded by default)")
+	flags.StringVar(&options.transport.Proxy, "proxy", "", "http, https or socks5 proxy url, e.g. socks5://proxy.example.com:1080 (HTTPS_PROXY and NO_PROXY env vars by default)")
+	flags.StringVar(&options.transport.CAFile, "ca-file", "", "PEM bundle of CA certificates trusted in addition to the system ones, e.g. of a TLS-intercepting proxy or a GHES instance")
+	flags.StringVar(&options.transport.CertFile, "client-cert", "", "PEM client certificate for servers requiring mutual TLS, used with -client-key")
+	flags.StringVar(&options.transport.KeyFile, "client-key", "", "PEM key of the -client-cert certificate")
+	flags.BoolVar(&options.offline, "offline", false, "make no network calls: serve the API responses from -cache-dir or the snapshot of -provider cache")
+	flags.StringVar(&options.configPath, "config", defaultConfigPath(), "config file with account groups, aliases, group notifications and version schemes")
+	flags.Var(&options.versionSchemes, "version-scheme", "version scheme of the repositories matching a pattern: semver, calver, pep440 or date, e.g. acme/infra-*=calver (repeated, the first match wins, before the ones of the config)")
+	flags.StringVar(&options.annotationsPath, "annotations", "", "csv or json file with repository metadata joined into the results, e.g. owner team or tier")
+	flags.StringVar(&options.ignorePath, "ignore-file", "", "file listing repositories or glob patterns skipped by scans, one per line, e.g. acme/archive-* ("+defaultIgnoreFile+" of the working directory by default, if it exists)")
+	flags.StringVar(&options.otlpEndpoint, "otlp-endpoint", "", "OTLP/HTTP url the OpenTelemetry spans of the API calls are exported to, e.g. http://localhost:4318/v1/traces (OTEL_EXPORTER_OTLP_TRACES_ENDPOINT or OTEL_EXPORTER_OTLP_ENDPOINT env vars by default, not exported if none is set)")
+	flags.BoolVar(&quiet, "quiet", false, "suppress non-error output")
+
+	return options
+}
+
+// defaultIgnoreFile is the ignore list loaded from the working directory if -ignore-file is not set.
+const defaultIgnoreFile = ".scannerignore"
+
+func defaultConfigPath() string {
+	if path := os.Getenv("GITHUBSCANNER_CONFIG"); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, "githubscanner", "config.json")
+}
+
+func (o *scannerOptions) loadConfig() (*scanner.Config, error) {
+	return loadConfig(o.configPath)
+}
+
+// loadConfig reads the config file. Only the default config file may be missing, the config is empty then.
+func loadConfig(path string) (*scanner.Config, error) {
+	if path == "" {
+		return &scanner.Config{}, nil
+	}
+	config, err := scanner.LoadConfig(path)
+	if errors.Is(err, os.ErrNotExist) && path == defaultConfigPath() {
+		return &scanner.Config{}, nil
+	}
+
+	return config, err
+}
+
+// resolveAccounts expands the groups and aliases of the config in the account names.
+func (o *scannerOptions) resolveAccounts(names []string) ([]string, error) {
+	config, err := o.loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return config.ResolveAccounts(names), nil
+}
+
+func (o *scannerOptions) newScanner() (*scanner.Scanner, error) {
+	s := scanner.GetDefaultScanner()
+	s.Logger = o.newLogger()
+	s.RequestTimeout = o.requestTimeout
+	if tracingEnabled(o.otlpEndpoint) {
+		tracerProvider, err := newTracerProvider(o.otlpEndpoint)
+		if err != nil {
+			return nil, err
+		}
+		s.TracerProvider = tracerProvider
+	}
+	if o.baseUrl != "" {
+		s.BaseUrl = o.baseUrl
+	}
+	if !o.transport.IsZero() {
+		client, err := o.transport.NewClient()
+		if err != nil {
+			return nil, err
+		}
+		s.Client = client
+	}
+	var err error
+	if s.TokenSource, err = o.githubTokenSource(s.BaseUrl, s.Client); err != nil {
+		return nil, err
+	}
+	if o.offline {
+		if err := o.checkOffline(); err != nil {
+			return nil, err
+		}
+	}
+	if o.cacheDir != "" {
+		cache := &scanner.DiskCache{Dir: o.cacheDir, TTL: o.cacheTTL, MaxSize: o.cacheMaxSize << 20, Offline: o.offline}
+		s.Use(cache.Middleware())
+	}
+	if o.annotationsPath != "" {
+		annotations, err := scanner.LoadAnnotations(o.annotationsPath)
+		if err != nil {
+			return nil, err
+		}
+		s.Annotations = annotations
+	}
+	if s.Ignore, err = o.loadIgnoreList(); err != nil {
+		return nil, err
+	}
+	config, err := o.loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	rules, err := config.VersionSchemeRules()
+	if err != nil {
+		return nil, err
+	}
+	s.VersionSchemes = append(slices.Clone([]scanner.VersionSchemeRule(o.versionSchemes)), rules...)
+
+	var backends []scanner.Backend
+	for _, name := range strings.Split(o.provider, ",") {
+		name = strings.TrimSpace(name)
+		provider, err := o.newProvider(name, s)
+		if err != nil {
+			return nil, err
+		}
+		backends = append(backends, scanner.Backend{Name: name, Provider: provider})
+	}
+
+	if len(backends) > 1 {
+		s.Provider = &scanner.ChainProvider{Backends: backends}
+	} else if backends[0].Provider != s {
+		s.Provider = backends[0].Provider
+	}
+
+	return s, nil
+}
+
+// loadIgnoreList loads the ignore list of -ignore-file, the default ignore file if it is not set and exists.
+func (o *scannerOptions) loadIgnoreList() (scanner.IgnoreList, error) {
+	path := o.ignorePath
+	if path == "" {
+		if _, err := os.Stat(defaultIgnoreFile); err != nil {
+			return nil, nil
+		}
+		path = defaultIgnoreFile
+	}
+
+	return scanner.LoadIgnoreList(path)
+}
+
+// checkOffline checks the providers could be served offline: the cache provider from its snapshot and the
+// GitHub REST provider from the response cache.
+func (o *scannerOptions) checkOffline() error {
+	if o.appID != "" {
+		return fmt.Errorf("github app authentication is not supported in the offline mode, installation tokens are requested online")
+	}
+	for _, name := range strings.Split(o.provider, ",") {
+		switch name = strings.TrimSpace(name); name {
+		case "cache":
+		case "github":
+			if o.cacheDir == "" {
+				return fmt.Errorf("offline mode requires -cache-dir with the responses of a previous run, or -provider cache with -cache-snapshot")
+			}
+		default:
+			return fmt.Errorf("provider %s is not supported in the offline mode, use github with -cache-dir or cache", name)
+		}
+	}
+
+	return nil
+}
+
+// githubTokenSource returns the source of the GitHub tokens: a GitHub App, the token file, the -token flag, the
+// GITHUB_TOKEN env var or the token stored by the login command, in this order. It is nil for anonymous requests.
+func (o *scannerOptions) githubTokenSource(baseUrl string, client *http.Client) (scanner.TokenSource, error) {
+	if o.appID != "" || o.appInstallationID != "" {
+		if o.appID == "" || o.appInstallationID == "" || o.appKeyPath == "" {
+			return nil, fmt.Errorf("github app authentication requires -app-id, -app-installation-id and -app-key")
+		}
+		data, err := os.ReadFile(o.appKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		key, err := scanner.ParseGitHubAppKey(data)
+		if err != nil {
+			return nil, err
+		}
+		return scanner.ReuseTokenSource(&scanner.GitHubAppTokenSource{
+			BaseUrl:        baseUrl,
+			AppID:          o.appID,
+			InstallationID: o.appInstallationID,
+			PrivateKey:     key,
+			Client:         client,
+		}), nil
+	}
+	if o.tokenFile != "" {
+		return scanner.ReuseTokenSource(scanner.FileTokenSource(o.tokenFile)), nil
+	}
+	if source := o.tokenSource("GITHUB_TOKEN"); source != nil {
+		return source, nil
+	}
+	if stored := loadStoredToken(); stored != nil {
+		return storedTokenSource(stored, client), nil
+	}
+
+	return nil, nil
+}
+
+// tokenSource returns the source of the token of the -token flag or the env var, nil if neither is set.
+func (o *scannerOptions) tokenSource(envVar string) scanner.TokenSource {
+	if o.token != "" {
+		return scanner.StaticTokenSource(o.token)
+	}
+	if os.Getenv(envVar) != "" {
+		return scanner.EnvTokenSource(envVar)
+	}
+
+	return nil
+}
+
+// newProvider creates the provider by its name. The scanner itself is the GitHub REST provider.
+func (o *scannerOptions) newProvider(name string, s *scanner.Scanner) (scanner.Provider, error) {
+	switch name {
+	case "github":
+		return s, nil
+	case "github-graphql":
+		endpoint := ""
+		if o.baseUrl != "" {
+			endpoint = o.baseUrl + "/graphql"
+		}
+		return &scanner.GraphQLProvider{
+			Endpoint:       endpoint,
+			TokenSource:    s.TokenSource,
+			Logger:         s.Logger,
+			RequestHandler: s.RequestHandler(),
+		}, nil
+	case "gitlab":
+		return &scanner.GitLabProvider{
+			BaseUrl:     o.baseUrl,
+			TokenSource: o.tokenSource("GITLAB_TOKEN"),
+			Logger:      s.Logger,
+			Client:      s.Client,
+		}, nil
+	case "gitea":
+		return &scanner.GiteaProvider{
+			BaseUrl:     o.baseUrl,
+			TokenSource: o.tokenSource("GITEA_TOKEN"),
+			Logger:      s.Logger,
+			Client:      s.Client,
+		}, nil
+	case "cache":
+		if o.cacheSnapshot == "" {
+			return nil, fmt.Errorf("cache provider requires a snapshot file")
+		}
+		snapshot, err := scanner.LoadSnapshot(o.cacheSnapshot)
+		if err != nil {
+			return nil, err
+		}
+		return &scanner.SnapshotProvider{Snapshot: snapshot}, nil
+	}
+
+	return nil, fmt.Errorf("unknown provider: %s", name)
+}
+
+func (o *scannerOptions) newLogger() *slog.Logger {
+	handlerOptions := &slog.HandlerOptions{Level: o.logLevel}
+	if o.logFormat == "json" {
+		return slog.New(slog.NewJSONHandler(os.Stderr, handlerOptions))
+	}
+
+	return slog.New(slog.NewTextHandler(os.Stderr, handlerOptions))
+}
+
+// filterWhere keeps the items whose annotation matches the "key=value" condition, all items if it is empty.
+func filterWhere(items []*scanner.ResultItem, where string) []*scanner.ResultItem {
+	if where == "" {
+		return items
+	}
+	key, value, ok := strings.Cut(where, "=")
+	if !ok {
+		usage(fmt.Sprintf("invalid condition %s, expected key=value", where))
+	}
+
+	return scanner.FilterByAnnotation(items, key, value)
+}
+
+// filterTopic keeps the items of repositories tagged with the topic, all items if it is empty.
+func filterTopic(items []*scanner.ResultItem, topic string) []*scanner.ResultItem {
+	if topic == "" {
+		return items
+	}
+
+	return scanner.FilterByTopic(items, topic)
+}
+
+// flagPassed reports whether the flag is set on the command line rather than left with its default value.
+func flagPassed(flags *flag.FlagSet, name string) bool {
+	passed := false
+	flags.Visit(func(f *flag.Flag) {
+		passed = passed || f.Name == name
+	})
+
+	return passed
+}
+
+// versionSchemesFlag is the repeated -version-scheme flag of "pattern=scheme" rules.
+type versionSchemesFlag []scanner.VersionSchemeRule
+
+func (f *versionSchemesFlag) String() string {
+	var values []string
+	for _, rule := range *f {
+		values = append(values, rule.Pattern+"="+rule.Scheme.Name())
+	}
+
+	return strings.Join(values, ",")
+}
+
+func (f *versionSchemesFlag) Set(value string) error {
+	rule, err := scanner.ParseVersionSchemeRule(value)
+	if err != nil {
+		return err
+	}
+	*f = append(*f, rule)
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package output
+
+import (
+	"encoding/xml"
+	"io"
+	"net/url"
+	"sort"
+	"time"
+
+	"githubscanner/scanner"
+)
+
+type atomFeed struct {
+	XMLName xml.Name     `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string       `xml:"id"`
+	Title   string       `xml:"title"`
+	Updated string       `xml:"updated"`
+	Author  atomAuthor   `xml:"author"`
+	Entries []*atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	ID        string       `xml:"id"`
+	Title     string       `xml:"title"`
+	Updated   string       `xml:"updated"`
+	Published string       `xml:"published"`
+	Link      *atomLink    `xml:"link,omitempty"`
+	Content   *atomContent `xml:"content,omitempty"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// WriteAtom renders the published releases of the report items as an Atom feed, newest first, so a feed reader
+// could follow the scan output. Entries link the release pages if the provider reported them.
+func WriteAtom(w io.Writer, report *Report) error {
+	feed := &atomFeed{
+		ID:      "urn:githubscanner:feed:" + url.PathEscape(report.Title),
+		Title:   report.Title,
+		Updated: atomTime(report.GeneratedAt),
+		Author:  atomAuthor{Name: "githubscanner"},
+	}
+
+	type published struct {
+		item    *scanner.ResultItem
+		release *scanner.Release
+	}
+	var releases []published
+	for _, item := range report.Items {
+		for _, release := range item.Releases {
+			if release.PublishedAt != nil && !release.Draft {
+				releases = append(releases, published{item, release})
+			}
+		}
+	}
+	sort.SliceStable(releases, func(i, j int) bool {
+		return releases[i].release.PublishedAt.After(*releases[j].release.PublishedAt)
+	})
+
+	for _, published := range releases {
+		repository, release := published.item.Repository.FullName, published.release
+		entry := &atomEntry{
+			ID:        "urn:githubscanner:release:" + url.PathEscape(repository) + ":" + url.PathEscape(release.TagName),
+			Title:     repository + " " + release.TagName,
+			Updated:   atomTime(*release.PublishedAt),
+			Published: atomTime(*release.PublishedAt),
+		}
+		if release.Name != "" && release.Name != release.TagName {
+			entry.Title += ": " + release.Name
+		}
+		if release.HTMLURL != "" {
+			entry.ID = release.HTMLURL
+			entry.Link = &atomLink{Href: release.HTMLURL, Rel: "alternate"}
+		}
+		if release.Body != "" {
+			entry.Content = &atomContent{Type: "text", Body: release.Body}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	if len(releases) > 0 {
+		feed.Updated = atomTime(*releases[0].release.PublishedAt)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(feed); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
+
+func atomTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,43 @@
+package output
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"githubscanner/scanner"
+)
+
+func TestWriteAtom(t *testing.T) {
+	items := getReportItems()
+	items[0].Releases[0].HTMLURL = "https://github.com/test/test/releases/tag/v1.1.0"
+	later := time.Date(2024, 4, 1, 10, 0, 0, 0, time.UTC)
+	items = append(items, &scanner.ResultItem{
+		Repository: &scanner.Repository{FullName: "test/other", Name: "other"},
+		Releases:   []*scanner.Release{{TagName: "v2.0.0", Body: "<breaking> & changes", PublishedAt: &later}},
+	})
+
+	var buf bytes.Buffer
+	if err := WriteAtom(&buf, &Report{Title: "Releases of test", GeneratedAt: time.Now(), Items: items}); err != nil {
+		t.Fatal(err)
+	}
+
+	var feed atomFeed
+	if err := xml.Unmarshal(buf.Bytes(), &feed); err != nil {
+		t.Fatalf("invalid feed xml: %v\n%s", err, buf.String())
+	}
+	if feed.Title != "Releases of test" || feed.Updated != "2024-04-01T10:00:00Z" {
+		t.Fatalf("invalid feed, expected the title and the time of the newest release, got %q updated at %s", feed.Title, feed.Updated)
+	}
+	// the unpublished release is left out
+	if len(feed.Entries) != 2 {
+		t.Fatalf("invalid entries count, expected 2, got %d", len(feed.Entries))
+	}
+	if entry := feed.Entries[0]; entry.Title != "test/other v2.0.0" || entry.Content.Body != "<breaking> & changes" || entry.Link != nil {
+		t.Fatalf("invalid newest entry: %+v", entry)
+	}
+	if entry := feed.Entries[1]; entry.Title != "test/test v1.1.0: Release 1.1" || entry.ID != items[0].Releases[0].HTMLURL || entry.Link.Href != entry.ID {
+		t.Fatalf("invalid linked entry: %+v", entry)
+	}
+}
@@ -0,0 +1,33 @@
+package output
+
+import (
+	"io"
+
+	"githubscanner/scanner"
+)
+
+const changelogTemplate = `# Changelog of {{.Repository}}
+
+Changes after {{.From}} up to {{.To}}.
+{{range .Releases}}
+## {{or .Name .TagName}}
+{{with .PublishedAt}}
+Published on {{date .}}.
+{{end}}{{with .Body}}
+{{.}}
+{{end}}{{end}}{{with .Commits}}
+## Commits
+{{range .}}
+- {{.Title}} ({{printf "%.7s" .SHA}}{{with .Commit.Author.Name}}, {{.}}{{end}}){{end}}
+{{end}}`
+
+// WriteChangelog renders the changelog as one Markdown document, the release notes newest first followed by
+// the commits if the releases have no notes.
+func WriteChangelog(w io.Writer, changelog *scanner.Changelog) error {
+	tmpl, err := parseTemplate("changelog", changelogTemplate)
+	if err != nil {
+		return err
+	}
+
+	return tmpl.Execute(w, changelog)
+}
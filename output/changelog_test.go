@@ -0,0 +1,44 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"githubscanner/scanner"
+)
+
+func TestWriteChangelog(t *testing.T) {
+	changelog := &scanner.Changelog{
+		Repository: "test/test",
+		From:       "v1.0.0",
+		To:         "v1.1.0",
+		Releases:   getReportItems()[0].Releases[:1],
+	}
+
+	var buf bytes.Buffer
+	if err := WriteChangelog(&buf, changelog); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "# Changelog of test/test\n\nChanges after v1.0.0 up to v1.1.0.\n\n## Release 1.1\n\nPublished on 2024-03-01.\n\n* faster scans\n"
+	if buf.String() != expected {
+		t.Fatalf("invalid changelog, expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestWriteChangelogCommits(t *testing.T) {
+	commit := &scanner.Commit{SHA: "0123456789abcdef"}
+	commit.Commit.Message = "Fix pagination\n\nThe last page was skipped."
+	commit.Commit.Author.Name = "alice"
+	changelog := &scanner.Changelog{Repository: "test/test", From: "v1.0.0", To: "v1.1.0", Commits: []*scanner.Commit{commit}}
+
+	var buf bytes.Buffer
+	if err := WriteChangelog(&buf, changelog); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := "## Commits\n\n- Fix pagination (0123456, alice)\n"; !strings.HasSuffix(buf.String(), expected) {
+		t.Fatalf("invalid changelog, expected the %q suffix, got %q", expected, buf.String())
+	}
+}
@@ -0,0 +1,105 @@
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"githubscanner/scanner"
+)
+
+// Exporter stores scan snapshots, e.g. in a local file or an object storage bucket.
+type Exporter interface {
+	Export(ctx context.Context, snapshot *scanner.Snapshot) error
+}
+
+// NewExporter returns the exporter of the destination: "s3://bucket/key" for Amazon S3, "gs://bucket/object" for
+// Google Cloud Storage, "bq://project/dataset/table" for BigQuery or a local file path. Credentials are read from
+// the environment: AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN, AWS_REGION and AWS_ENDPOINT_URL
+// for S3 and GOOGLE_OAUTH_TOKEN for GCS and BigQuery.
+func NewExporter(destination string) (Exporter, error) {
+	if location, ok := strings.CutPrefix(destination, "s3://"); ok {
+		bucket, key, err := splitBucketPath(destination, location)
+		if err != nil {
+			return nil, err
+		}
+		region := os.Getenv("AWS_REGION")
+		if region == "" {
+			region = os.Getenv("AWS_DEFAULT_REGION")
+		}
+		return &S3Exporter{
+			Bucket:          bucket,
+			Key:             key,
+			Region:          region,
+			Endpoint:        os.Getenv("AWS_ENDPOINT_URL"),
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		}, nil
+	}
+	if location, ok := strings.CutPrefix(destination, "gs://"); ok {
+		bucket, object, err := splitBucketPath(destination, location)
+		if err != nil {
+			return nil, err
+		}
+		return &GCSExporter{Bucket: bucket, Object: object, AccessToken: os.Getenv("GOOGLE_OAUTH_TOKEN")}, nil
+	}
+	if location, ok := strings.CutPrefix(destination, "bq://"); ok {
+		parts := strings.Split(location, "/")
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+			return nil, fmt.Errorf("invalid export destination %s, expected bq://project/dataset/table", destination)
+		}
+		return &BigQueryExporter{Project: parts[0], Dataset: parts[1], Table: parts[2], AccessToken: os.Getenv("GOOGLE_OAUTH_TOKEN")}, nil
+	}
+	if strings.Contains(destination, "://") {
+		return nil, fmt.Errorf("unsupported export destination %s, expected s3://, gs://, bq:// or a file path", destination)
+	}
+
+	return &FileExporter{Path: destination}, nil
+}
+
+// IsRemoteDestination reports whether the destination is an object storage or warehouse url rather than a local file.
+func IsRemoteDestination(destination string) bool {
+	for _, scheme := range []string{"s3://", "gs://", "bq://"} {
+		if strings.HasPrefix(destination, scheme) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func splitBucketPath(destination, location string) (string, string, error) {
+	bucket, path, _ := strings.Cut(location, "/")
+	if bucket == "" || path == "" {
+		return "", "", fmt.Errorf("invalid export destination %s, expected a bucket and an object path", destination)
+	}
+
+	return bucket, path, nil
+}
+
+// FileExporter writes snapshots to a local json file.
+type FileExporter struct {
+	Path string
+}
+
+func (e *FileExporter) Export(ctx context.Context, snapshot *scanner.Snapshot) error {
+	data, err := encodeSnapshot(snapshot)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(e.Path, data, 0o644)
+}
+
+// encodeSnapshot encodes the snapshot the same way the json scan output does.
+func encodeSnapshot(snapshot *scanner.Snapshot) ([]byte, error) {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(data, '\n'), nil
+}
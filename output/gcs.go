@@ -0,0 +1,71 @@
+package output
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"githubscanner/scanner"
+)
+
+const GoogleStorageApi = "https://storage.googleapis.com"
+
+// GCSExporter uploads snapshots to a Google Cloud Storage bucket.
+type GCSExporter struct {
+	Bucket string
+	Object string
+	// AccessToken is an OAuth 2.0 access token with the devstorage.read_write scope.
+	AccessToken string
+	BaseUrl     string
+	Client      *http.Client
+}
+
+func (e *GCSExporter) Export(ctx context.Context, snapshot *scanner.Snapshot) error {
+	if e.AccessToken == "" {
+		return fmt.Errorf("gcs access token is not set, use the GOOGLE_OAUTH_TOKEN env var")
+	}
+	data, err := encodeSnapshot(snapshot)
+	if err != nil {
+		return err
+	}
+
+	baseUrl := e.BaseUrl
+	if baseUrl == "" {
+		baseUrl = GoogleStorageApi
+	}
+	query := url.Values{"uploadType": {"media"}, "name": {e.Object}}
+	uploadUrl := fmt.Sprintf("%s/upload/storage/v1/b/%s/o?%s", baseUrl, url.PathEscape(e.Bucket), query.Encode())
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, uploadUrl, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Authorization", "Bearer "+e.AccessToken)
+	request.Header.Set("Content-Type", "application/json")
+
+	client := e.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return fmt.Errorf("could not upload the snapshot to gs://%s/%s: %v", e.Bucket, e.Object, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		message := struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}{}
+		if err := json.NewDecoder(response.Body).Decode(&message); err != nil || message.Error.Message == "" {
+			return fmt.Errorf("could not upload the snapshot to gs://%s/%s: %s", e.Bucket, e.Object, response.Status)
+		}
+		return fmt.Errorf("could not upload the snapshot to gs://%s/%s: %s", e.Bucket, e.Object, message.Error.Message)
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package output
+
+import (
+	_ "embed"
+	"html/template"
+	"io"
+
+	"githubscanner/scanner"
+)
+
+//go:embed templates/report.html
+var htmlTemplate string
+
+// htmlRow is a table row of the HTML report, one per release. Repositories without releases get a single row.
+type htmlRow struct {
+	Repository *scanner.Repository
+	Release    *scanner.Release
+	Downloads  int
+}
+
+// htmlSection is a releases table of the HTML report, one per account if the report is grouped by account.
+type htmlSection struct {
+	// Summary is the account of the section, nil if the report is not grouped.
+	Summary *scanner.AccountSummary
+	Rows    []*htmlRow
+}
+
+// WriteHTML writes the report as a self-contained HTML page with a sortable table of repositories and releases.
+func WriteHTML(w io.Writer, report *Report) error {
+	tmpl, err := template.New("html").Funcs(template.FuncMap{"date": formatDate}).Parse(htmlTemplate)
+	if err != nil {
+		return err
+	}
+
+	var sections []*htmlSection
+	if len(report.Accounts) > 0 {
+		for _, group := range report.Accounts {
+			sections = append(sections, &htmlSection{Summary: group.Summary, Rows: htmlRows(group.Items)})
+		}
+	} else {
+		sections = append(sections, &htmlSection{Rows: htmlRows(report.Items)})
+	}
+	var rows []*htmlRow
+	for _, section := range sections {
+		rows = append(rows, section.Rows...)
+	}
+	var total *scanner.AccountSummary
+	if len(report.Accounts) > 0 {
+		_, total = scanner.SummarizeAccounts(report.Items)
+	}
+
+	return tmpl.Execute(w, struct {
+		*Report
+		Rows     []*htmlRow
+		Sections []*htmlSection
+		Total    *scanner.AccountSummary
+	}{report, rows, sections, total})
+}
+
+func htmlRows(items []*scanner.ResultItem) []*htmlRow {
+	var rows []*htmlRow
+	for _, item := range items {
+		if len(item.Releases) == 0 {
+			rows = append(rows, &htmlRow{Repository: item.Repository})
+		}
+		for _, release := range item.Releases {
+			row := &htmlRow{Repository: item.Repository, Release: release}
+			for _, asset := range release.Assets {
+				row.Downloads += asset.DownloadCount
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	return rows
+}
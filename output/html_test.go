@@ -0,0 +1,72 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"githubscanner/scanner"
+)
+
+func TestWriteHTML(t *testing.T) {
+	items := getReportItems()
+	items[0].Repository.Stars = 42
+	items[0].Releases[0].Assets = []*scanner.Asset{{Name: "tool.tar.gz", DownloadCount: 7}, {Name: "tool.zip", DownloadCount: 3}}
+	items = append(items, &scanner.ResultItem{Repository: &scanner.Repository{FullName: "test/<empty>"}})
+
+	report := NewReport("Releases", items, nil)
+	report.Stats = &scanner.ScanStats{Repositories: 2, Requests: 5}
+
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+
+	page := buf.String()
+	for _, expected := range []string{"<title>Releases</title>", "2 repositories scanned", "5 API requests", "<td>test/test</td>", `<td class="number">42</td>`, "<td>2024-03-01</td>", `<td class="number">10</td>`, "test/&lt;empty&gt;", "<script>"} {
+		if !strings.Contains(page, expected) {
+			t.Fatalf("page does not contain %q:\n%s", expected, page)
+		}
+	}
+}
+
+func TestWriteHTMLCadence(t *testing.T) {
+	items := getReportItems()
+	previous := time.Date(2023, 11, 2, 10, 0, 0, 0, time.UTC)
+	items[0].Releases[1].PublishedAt = &previous
+
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, NewReport("Releases", items, nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	page := buf.String()
+	for _, expected := range []string{"<h2>Release cadence</h2>", `<td class="number">120.0</td>`, "<td>2023-Q4: 1, 2024-Q1: 1</td>"} {
+		if !strings.Contains(page, expected) {
+			t.Fatalf("page does not contain %q:\n%s", expected, page)
+		}
+	}
+}
+
+func TestWriteHTMLGroupedByAccount(t *testing.T) {
+	items := getReportItems()
+	items = append(items, &scanner.ResultItem{Repository: &scanner.Repository{FullName: "other/repo"}})
+	report := NewReport("Releases", items, nil)
+	report.Accounts = scanner.GroupByAccount(items)
+
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+
+	page := buf.String()
+	for _, expected := range []string{`<table id="accounts">`, "<h2>other</h2>", "<h2>test</h2>", "<td>test/test</td>", "<th>Total</th>"} {
+		if !strings.Contains(page, expected) {
+			t.Fatalf("page does not contain %q:\n%s", expected, page)
+		}
+	}
+	if count := strings.Count(page, `<table class="releases">`); count != 2 {
+		t.Fatalf("invalid releases tables count, expected 2, got %d", count)
+	}
+}
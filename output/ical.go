@@ -0,0 +1,85 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// icalLineLength is the length in octets iCalendar content lines are folded at.
+const icalLineLength = 75
+
+// WriteICalendar renders the published releases of the report items as all-day iCalendar events, so the release
+// history could be overlaid on planning calendars. Events link the release pages if the provider reported them.
+func WriteICalendar(w io.Writer, report *Report) error {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//githubscanner//releases//EN",
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:" + icalText(report.Title),
+	}
+	stamp := report.GeneratedAt.UTC().Format("20060102T150405Z")
+	for _, item := range report.Items {
+		repository := item.Repository.FullName
+		for _, release := range item.Releases {
+			if release.PublishedAt == nil || release.Draft {
+				continue
+			}
+			day := release.PublishedAt.UTC()
+			summary := repository + " " + release.TagName
+			if release.Name != "" && release.Name != release.TagName {
+				summary += ": " + release.Name
+			}
+			lines = append(lines,
+				"BEGIN:VEVENT",
+				"UID:"+icalText(repository+"/"+release.TagName)+"@githubscanner",
+				"DTSTAMP:"+stamp,
+				"DTSTART;VALUE=DATE:"+day.Format("20060102"),
+				"DTEND;VALUE=DATE:"+day.AddDate(0, 0, 1).Format("20060102"),
+				"SUMMARY:"+icalText(summary),
+			)
+			description := fmt.Sprintf("Release %s of %s published at %s.", release.TagName, repository, day.Format(time.RFC3339))
+			if release.HTMLURL != "" {
+				description += "\nRelease notes: " + release.HTMLURL
+				lines = append(lines, "URL:"+release.HTMLURL)
+			}
+			lines = append(lines, "DESCRIPTION:"+icalText(description), "END:VEVENT")
+		}
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, foldICalLine(line)+"\r\n"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// icalText escapes the value of a TEXT property.
+func icalText(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(value)
+}
+
+// foldICalLine splits the content line into lines of at most 75 octets, continuation lines start with a space.
+// Lines are not split within UTF-8 characters.
+func foldICalLine(line string) string {
+	var folded strings.Builder
+	length := 0
+	for len(line) > 0 {
+		_, size := utf8.DecodeRuneInString(line)
+		if length+size > icalLineLength {
+			folded.WriteString("\r\n ")
+			length = 1
+		}
+		folded.WriteString(line[:size])
+		length += size
+		line = line[size:]
+	}
+
+	return folded.String()
+}
@@ -0,0 +1,43 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteICalendar(t *testing.T) {
+	items := getReportItems()
+	items[0].Releases[0].Name = "Release 1.1; faster, smaller"
+	items[0].Releases[0].HTMLURL = "https://github.com/test/test/releases/tag/v1.1.0"
+
+	var buf bytes.Buffer
+	if err := WriteICalendar(&buf, &Report{Title: "Releases of test", GeneratedAt: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), Items: items}); err != nil {
+		t.Fatal(err)
+	}
+
+	calendar := buf.String()
+	for _, expected := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:test/test/v1.1.0@githubscanner\r\n",
+		"DTSTAMP:20240501T000000Z\r\n",
+		"DTSTART;VALUE=DATE:20240301\r\nDTEND;VALUE=DATE:20240302\r\n",
+		`SUMMARY:test/test v1.1.0: Release 1.1\; faster\, smaller`,
+		"URL:https://github.com/test/test/releases/tag/v1.1.0\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(calendar, expected) {
+			t.Fatalf("calendar does not contain %q:\n%s", expected, calendar)
+		}
+	}
+	// the unpublished release has no event
+	if count := strings.Count(calendar, "BEGIN:VEVENT"); count != 1 {
+		t.Fatalf("invalid events count, expected 1, got %d", count)
+	}
+	for _, line := range strings.Split(calendar, "\r\n") {
+		if len(line) > icalLineLength {
+			t.Fatalf("line is not folded: %q", line)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package output
+
+import (
+	"io"
+	"time"
+
+	"githubscanner/scanner"
+)
+
+const defaultMarkdownTemplate = `{{define "release"}}Tag: ` + "`{{.TagName}}`" + `{{with .PublishedAt}}, published on {{date .}}{{end}}
+{{with .Body}}
+{{.}}
+{{end}}{{end}}{{define "activity"}}{{with .CommitActivity}}
+{{if .Total}}{{.Total}} commits in the last year{{with .LastCommitAt}}, the last one on {{date .}}{{end}}.{{else}}No commits in the last year.{{end}}
+{{end}}{{end}}# {{.Title}}
+
+Generated on {{date .GeneratedAt}}.
+{{if .Groups}}{{range .Groups}}
+## {{or .Name "No topic"}}
+{{range .Items}}
+### {{.Repository.FullName}}
+{{template "activity" .}}{{range .Releases}}
+#### {{or .Name .TagName}}
+
+{{template "release" .}}{{end}}{{end}}{{end}}{{else}}{{range .Items}}
+## {{.Repository.FullName}}
+{{template "activity" .}}{{range .Releases}}
+### {{or .Name .TagName}}
+
+{{template "release" .}}{{end}}{{end}}{{end}}{{with .Languages}}
+## Languages
+{{range .}}
+### {{.Account}}
+{{range .Languages}}
+- {{.Language}}: {{printf "%.1f" .Percent}}% ({{.Bytes}} bytes){{end}}
+{{end}}{{end}}{{with .Downloads}}
+## Most downloaded
+
+{{.Downloads}} downloads in total.
+
+### Releases
+{{range .Releases}}
+- {{.Repository}} {{.Release}}: {{.Downloads}}{{end}}
+
+### Assets
+{{range .Assets}}
+- {{.Repository}} {{.Release}} ` + "`{{.Asset}}`" + `: {{.Downloads}}{{end}}
+
+### Platforms
+{{range .Platforms}}
+- {{or .Platform "other"}}: {{.Downloads}} downloads of {{.Assets}} assets{{end}}
+{{end}}{{with .Stale}}
+## Stale repositories
+
+No pushes, releases or commits since {{date $.StaleSince}}.
+{{range .}}
+- {{.Repository}}: {{with .LastActivity}}last activity on {{date .}}{{else}}no known activity{{end}}{{if .Archived}} (archived){{end}}{{end}}
+{{end}}{{with .Stats}}
+---
+
+{{.}}.
+{{end}}`
+
+// Report is the data Markdown report templates are executed with.
+type Report struct {
+	Title       string
+	GeneratedAt time.Time
+	Items       []*scanner.ResultItem
+	// Languages are the language totals of all scanned repositories of every account, they are empty unless
+	// languages are scanned.
+	Languages []*scanner.AccountLanguages
+	// Downloads are the most downloaded releases and assets of all reported repositories, nil if nothing was
+	// downloaded.
+	Downloads *scanner.DownloadLeaderboard
+	// Groups are the reported items grouped by repository topics, they are empty unless the report is grouped.
+	Groups []*scanner.TopicGroup
+	// Accounts are the reported items grouped by account with their totals, they are only set for reports of
+	// several accounts. The HTML report renders a section per account then.
+	Accounts []*scanner.AccountGroup
+	// Stale are the repositories without any activity since StaleSince, they are empty unless requested.
+	Stale      []*scanner.StaleRepository
+	StaleSince time.Time
+	// Stats are the statistics of the scan of the items, nil if they are unknown.
+	Stats *scanner.ScanStats
+}
+
+// NewReport returns a report of the scanned items in the given order, their releases are put in the
+// scanner.SortResults order. If changes are given, only the releases added by them are reported, so a diff of two
+// scans becomes a "what's new" summary.
+func NewReport(title string, items []*scanner.ResultItem, changes []*scanner.Change) *Report {
+	scanner.SortReleases(items)
+	scanner.SetReleaseCadences(items, time.Now())
+	report := &Report{Title: title, GeneratedAt: time.Now().UTC(), Items: items, Languages: scanner.LanguagesByAccount(items)}
+	report.Downloads = scanner.NewDownloadLeaderboard(items, scanner.DownloadLeaderboardSize)
+	if changes == nil {
+		return report
+	}
+
+	added := make(map[string]map[string]bool)
+	for _, change := range changes {
+		if change.Kind != scanner.ChangeReleaseAdded {
+			continue
+		}
+		if added[change.Repository] == nil {
+			added[change.Repository] = make(map[string]bool)
+		}
+		added[change.Repository][change.Release] = true
+	}
+
+	report.Items = nil
+	for _, item := range items {
+		var releases []*scanner.Release
+		for _, release := range item.Releases {
+			if added[item.Repository.FullName][release.Version()] {
+				releases = append(releases, release)
+			}
+		}
+		if len(releases) > 0 {
+			report.Items = append(report.Items, &scanner.ResultItem{Repository: item.Repository, Releases: releases, Source: item.Source, CommitActivity: item.CommitActivity, Cadence: item.Cadence})
+		}
+	}
+
+	return report
+}
+
+// WriteMarkdown renders the report with the Go template, or with the default per-repository template if it is empty.
+func WriteMarkdown(w io.Writer, report *Report, text string) error {
+	if text == "" {
+		text = defaultMarkdownTemplate
+	}
+	tmpl, err := parseTemplate("report", text)
+	if err != nil {
+		return err
+	}
+
+	return tmpl.Execute(w, report)
+}
@@ -0,0 +1,185 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"githubscanner/scanner"
+)
+
+func getReportItems() []*scanner.ResultItem {
+	publishedAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	return []*scanner.ResultItem{{
+		Repository: &scanner.Repository{FullName: "test/test", Name: "test"},
+		Releases: []*scanner.Release{
+			{Name: "Release 1.1", TagName: "v1.1.0", Body: "* faster scans", PublishedAt: &publishedAt},
+			{Name: "Release 1.0", TagName: "v1.0.0", Body: "* first release"},
+		},
+	}}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, NewReport("What's new", getReportItems(), nil), ""); err != nil {
+		t.Fatal(err)
+	}
+
+	report := buf.String()
+	for _, expected := range []string{"# What's new", "## test/test", "### Release 1.1", "Tag: `v1.1.0`, published on 2024-03-01", "* faster scans", "### Release 1.0"} {
+		if !strings.Contains(report, expected) {
+			t.Fatalf("report does not contain %q:\n%s", expected, report)
+		}
+	}
+}
+
+func TestWriteMarkdownOfChanges(t *testing.T) {
+	changes := []*scanner.Change{{Kind: scanner.ChangeReleaseAdded, Repository: "test/test", Release: "v1.1.0"}}
+
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, NewReport("What's new", getReportItems(), changes), ""); err != nil {
+		t.Fatal(err)
+	}
+
+	report := buf.String()
+	if !strings.Contains(report, "### Release 1.1") || strings.Contains(report, "### Release 1.0") {
+		t.Fatalf("invalid report of changes:\n%s", report)
+	}
+}
+
+func TestWriteMarkdownCustomTemplate(t *testing.T) {
+	var buf bytes.Buffer
+	tmpl := "{{range .Items}}{{range .Releases}}- {{.TagName}} {{date .PublishedAt}}\n{{end}}{{end}}"
+	if err := WriteMarkdown(&buf, NewReport("", getReportItems(), nil), tmpl); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "- v1.1.0 2024-03-01\n- v1.0.0 \n"
+	if buf.String() != expected {
+		t.Fatalf("invalid report, expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestWriteMarkdownLanguages(t *testing.T) {
+	items := getReportItems()
+	items[0].Languages = map[string]int64{"Go": 300, "Shell": 100}
+
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, NewReport("What's new", items, nil), ""); err != nil {
+		t.Fatal(err)
+	}
+
+	report := buf.String()
+	for _, expected := range []string{"## Languages", "### test", "- Go: 75.0% (300 bytes)", "- Shell: 25.0% (100 bytes)"} {
+		if !strings.Contains(report, expected) {
+			t.Fatalf("report does not contain %q:\n%s", expected, report)
+		}
+	}
+}
+
+func TestWriteMarkdownDownloads(t *testing.T) {
+	items := getReportItems()
+	items[0].Releases[0].Assets = []*scanner.Asset{{Name: "test-linux-amd64.tar.gz", DownloadCount: 30}, {Name: "test-windows-amd64.zip", DownloadCount: 10}}
+
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, NewReport("What's new", items, nil), ""); err != nil {
+		t.Fatal(err)
+	}
+
+	report := buf.String()
+	for _, expected := range []string{"## Most downloaded", "40 downloads in total.", "- test/test v1.1.0: 40", "- test/test v1.1.0 `test-linux-amd64.tar.gz`: 30", "- linux/amd64: 30 downloads of 1 assets"} {
+		if !strings.Contains(report, expected) {
+			t.Fatalf("report does not contain %q:\n%s", expected, report)
+		}
+	}
+
+	buf.Reset()
+	if err := WriteMarkdown(&buf, NewReport("What's new", getReportItems(), nil), ""); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "Most downloaded") {
+		t.Fatalf("report of releases without downloads contains the downloads section:\n%s", buf.String())
+	}
+}
+
+func TestWriteMarkdownGroupedByTopic(t *testing.T) {
+	items := append(getReportItems(), &scanner.ResultItem{Repository: &scanner.Repository{FullName: "test/docs"}})
+	items[0].Repository.Topics = []string{"kubernetes"}
+	report := NewReport("What's new", items, nil)
+	report.Groups = scanner.GroupByTopic(report.Items)
+
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, report, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"## kubernetes", "### test/test", "#### Release 1.1", "## No topic", "### test/docs"}
+	output := buf.String()
+	position := 0
+	for _, heading := range expected {
+		i := strings.Index(output[position:], heading)
+		if i < 0 {
+			t.Fatalf("report does not contain %q after position %d:\n%s", heading, position, output)
+		}
+		position += i + len(heading)
+	}
+}
+
+func TestWriteMarkdownCommitActivity(t *testing.T) {
+	items := append(getReportItems(), &scanner.ResultItem{Repository: &scanner.Repository{FullName: "test/old"}})
+	lastCommitAt := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	items[0].CommitActivity = &scanner.CommitActivity{Weeks: []int{2, 0, 3}, Total: 5, LastCommitAt: &lastCommitAt}
+	items[1].CommitActivity = &scanner.CommitActivity{Weeks: []int{0, 0, 0}}
+
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, NewReport("What's new", items, nil), ""); err != nil {
+		t.Fatal(err)
+	}
+
+	report := buf.String()
+	for _, expected := range []string{"5 commits in the last year, the last one on 2024-03-05.", "## test/old\n\nNo commits in the last year."} {
+		if !strings.Contains(report, expected) {
+			t.Fatalf("report does not contain %q:\n%s", expected, report)
+		}
+	}
+}
+
+func TestWriteMarkdownStale(t *testing.T) {
+	items := append(getReportItems(), &scanner.ResultItem{Repository: &scanner.Repository{FullName: "test/old", Archived: true}})
+	report := NewReport("What's new", items, nil)
+	report.StaleSince = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	report.Stale = scanner.StaleRepositories(items, report.StaleSince)
+
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, report, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	output := buf.String()
+	for _, expected := range []string{"## Stale repositories", "No pushes, releases or commits since 2024-01-01.", "- test/old: no known activity (archived)"} {
+		if !strings.Contains(output, expected) {
+			t.Fatalf("report does not contain %q:\n%s", expected, output)
+		}
+	}
+	if strings.Contains(output, "- test/test:") {
+		t.Fatalf("report lists the active repository as stale:\n%s", output)
+	}
+}
+
+func TestWriteMarkdownStats(t *testing.T) {
+	report := NewReport("What's new", getReportItems(), nil)
+	report.Stats = &scanner.ScanStats{Repositories: 1, WithReleases: 1, Releases: 2, Requests: 4, DurationSeconds: 2}
+
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, report, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	output := buf.String()
+	expected := "1 repositories scanned, 1 with releases, 0 without, 2 releases, 4 API requests in 2.0s, 0 errors, 0 warnings."
+	if !strings.HasSuffix(output, expected+"\n") {
+		t.Fatalf("report does not end with %q:\n%s", expected, output)
+	}
+}
@@ -0,0 +1,161 @@
+package output
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"githubscanner/scanner"
+)
+
+const defaultS3Region = "us-east-1"
+
+// S3Exporter uploads snapshots to an Amazon S3 bucket, or an S3-compatible storage if the endpoint is set.
+// Requests are signed with AWS Signature Version 4.
+type S3Exporter struct {
+	Bucket string
+	Key    string
+	// Region is us-east-1 by default.
+	Region string
+	// Endpoint is the url of an S3-compatible storage, e.g. http://localhost:9000, objects are addressed by path
+	// there. The AWS virtual-hosted bucket url is used by default.
+	Endpoint        string
+	AccessKeyID     string
+	SecretAccessKey string
+	// SessionToken is only set for temporary credentials.
+	SessionToken string
+	Client       *http.Client
+
+	now func() time.Time
+}
+
+func (e *S3Exporter) Export(ctx context.Context, snapshot *scanner.Snapshot) error {
+	if e.AccessKeyID == "" || e.SecretAccessKey == "" {
+		return fmt.Errorf("s3 credentials are not set, use the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY env vars")
+	}
+	data, err := encodeSnapshot(snapshot)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPut, e.objectUrl(), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if e.SessionToken != "" {
+		request.Header.Set("X-Amz-Security-Token", e.SessionToken)
+	}
+	now := time.Now
+	if e.now != nil {
+		now = e.now
+	}
+	e.sign(request, data, now().UTC())
+
+	client := e.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return fmt.Errorf("could not upload the snapshot to s3://%s/%s: %v", e.Bucket, e.Key, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return fmt.Errorf("could not upload the snapshot to s3://%s/%s: %s %s", e.Bucket, e.Key, response.Status, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
+
+func (e *S3Exporter) objectUrl() string {
+	if e.Endpoint != "" {
+		return fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(e.Endpoint, "/"), e.Bucket, awsEscapePath(e.Key))
+	}
+
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", e.Bucket, e.region(), awsEscapePath(e.Key))
+}
+
+func (e *S3Exporter) region() string {
+	if e.Region == "" {
+		return defaultS3Region
+	}
+
+	return e.Region
+}
+
+// sign adds the AWS Signature Version 4 authorization of the request with the payload. All request headers and
+// the host are signed.
+func (e *S3Exporter) sign(request *http.Request, payload []byte, now time.Time) {
+	payloadHash := sha256.Sum256(payload)
+	amzDate := now.Format("20060102T150405Z")
+	request.Header.Set("X-Amz-Date", amzDate)
+	request.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+
+	headers := map[string]string{"host": request.URL.Host}
+	for name, values := range request.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&canonicalHeaders, "%s:%s\n", name, headers[name])
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		request.URL.EscapedPath(),
+		request.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+	date := amzDate[:8]
+	scope := date + "/" + e.region() + "/s3/aws4_request"
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(canonicalRequestHash[:])
+
+	key := []byte("AWS4" + e.SecretAccessKey)
+	for _, part := range []string{date, e.region(), "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	request.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s,SignedHeaders=%s,Signature=%s", e.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+
+	return mac.Sum(nil)
+}
+
+// awsEscapePath escapes the object key as AWS expects: everything but unreserved characters and slashes.
+func awsEscapePath(path string) string {
+	var escaped strings.Builder
+	for _, b := range []byte(path) {
+		if 'A' <= b && b <= 'Z' || 'a' <= b && b <= 'z' || '0' <= b && b <= '9' || strings.IndexByte("-_.~/", b) >= 0 {
+			escaped.WriteByte(b)
+		} else {
+			fmt.Fprintf(&escaped, "%%%02X", b)
+		}
+	}
+
+	return escaped.String()
+}
@@ -0,0 +1,161 @@
+package output
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"githubscanner/scanner"
+)
+
+const GoogleSheetsApi = "https://sheets.googleapis.com/v4"
+
+// GoogleSheet pushes scans to a Google spreadsheet using the same sheets as the xlsx export.
+// Missing sheets are created, existing ones are overwritten.
+type GoogleSheet struct {
+	SpreadsheetID string
+	// AccessToken is an OAuth 2.0 access token with the spreadsheets scope.
+	AccessToken string
+	BaseUrl     string
+	Client      *http.Client
+}
+
+func (g *GoogleSheet) Push(ctx context.Context, items []*scanner.ResultItem) error {
+	scanner.SortReleases(items)
+	repositories := repositoriesXLSXSheet(items)
+	releases := releasesXLSXSheet(items)
+	sheets := []*sheet{repositories, releases, summaryXLSXSheet(len(repositories.rows)-1, len(releases.rows)-1, nil)}
+
+	if err := g.addMissingSheets(ctx, sheets); err != nil {
+		return fmt.Errorf("could not prepare the google sheet: %v", err)
+	}
+
+	var ranges []string
+	var values, formulas []map[string]interface{}
+	for _, sheet := range sheets {
+		ranges = append(ranges, sheet.name)
+		values = append(values, map[string]interface{}{"range": sheet.name + "!A1", "values": sheetValues(sheet, false)})
+		formulas = append(formulas, map[string]interface{}{"range": sheet.name + "!A1", "values": sheetValues(sheet, true)})
+	}
+	if err := g.call(ctx, http.MethodPost, "/values:batchClear", map[string]interface{}{"ranges": ranges}, nil); err != nil {
+		return fmt.Errorf("could not clear the google sheet: %v", err)
+	}
+	// Names of repositories, releases and assets are not trusted, a value like "=IMPORTXML(...)" would run as a
+	// formula if it was entered as typed by a user. Only the generated formulas are.
+	for _, update := range []map[string]interface{}{
+		{"valueInputOption": "RAW", "data": values},
+		{"valueInputOption": "USER_ENTERED", "data": formulas},
+	} {
+		if err := g.call(ctx, http.MethodPost, "/values:batchUpdate", update, nil); err != nil {
+			return fmt.Errorf("could not update the google sheet: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func (g *GoogleSheet) addMissingSheets(ctx context.Context, sheets []*sheet) error {
+	var spreadsheet struct {
+		Sheets []struct {
+			Properties struct {
+				Title string `json:"title"`
+			} `json:"properties"`
+		} `json:"sheets"`
+	}
+	if err := g.call(ctx, http.MethodGet, "?fields=sheets.properties.title", nil, &spreadsheet); err != nil {
+		return err
+	}
+
+	existing := map[string]bool{}
+	for _, sheet := range spreadsheet.Sheets {
+		existing[sheet.Properties.Title] = true
+	}
+	var requests []interface{}
+	for _, sheet := range sheets {
+		if !existing[sheet.name] {
+			requests = append(requests, map[string]interface{}{
+				"addSheet": map[string]interface{}{"properties": map[string]string{"title": sheet.name}},
+			})
+		}
+	}
+	if len(requests) == 0 {
+		return nil
+	}
+
+	return g.call(ctx, http.MethodPost, ":batchUpdate", map[string]interface{}{"requests": requests}, nil)
+}
+
+// call sends a request to the spreadsheet endpoint, the path is appended to the spreadsheet url as is.
+func (g *GoogleSheet) call(ctx context.Context, method, path string, body, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	baseUrl := g.BaseUrl
+	if baseUrl == "" {
+		baseUrl = GoogleSheetsApi
+	}
+	request, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/spreadsheets/%s%s", baseUrl, url.PathEscape(g.SpreadsheetID), path), reader)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Authorization", "Bearer "+g.AccessToken)
+	request.Header.Set("Content-Type", "application/json")
+
+	client := g.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		message := struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}{}
+		if err := json.NewDecoder(response.Body).Decode(&message); err != nil || message.Error.Message == "" {
+			return fmt.Errorf("%s", response.Status)
+		}
+		return fmt.Errorf("%s", message.Error.Message)
+	}
+	if result == nil {
+		return nil
+	}
+
+	return json.NewDecoder(response.Body).Decode(result)
+}
+
+// sheetValues returns the values of the cells without formulas, or the formulas of the cells with them. The other
+// cells are nil, the Sheets API leaves them unchanged.
+func sheetValues(sheet *sheet, formulas bool) [][]interface{} {
+	values := make([][]interface{}, 0, len(sheet.rows))
+	for _, row := range sheet.rows {
+		rowValues := make([]interface{}, 0, len(row))
+		for _, cell := range row {
+			switch {
+			case formulas && cell.formula != "":
+				rowValues = append(rowValues, "="+cell.formula)
+			case !formulas && cell.formula == "":
+				rowValues = append(rowValues, cell.value)
+			default:
+				rowValues = append(rowValues, nil)
+			}
+		}
+		values = append(values, rowValues)
+	}
+
+	return values
+}
@@ -0,0 +1,67 @@
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"githubscanner/scanner"
+)
+
+func TestGoogleSheetPush(t *testing.T) {
+	type update struct {
+		ValueInputOption string `json:"valueInputOption"`
+		Data             []struct {
+			Range  string  `json:"range"`
+			Values [][]any `json:"values"`
+		} `json:"data"`
+	}
+	var updates []update
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet:
+			w.Write([]byte(`{"sheets": []}`))
+		case strings.HasSuffix(r.URL.Path, "/values:batchUpdate"):
+			var body update
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Error(err)
+			}
+			updates = append(updates, body)
+			w.Write([]byte(`{}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	items := []*scanner.ResultItem{{
+		Repository: &scanner.Repository{FullName: "test/test", Name: "test"},
+		Releases:   []*scanner.Release{{Name: `=IMPORTXML("https://example.com", "//a")`, TagName: "v1.0.0"}},
+	}}
+	sheet := &GoogleSheet{SpreadsheetID: "sheet", BaseUrl: server.URL}
+	if err := sheet.Push(context.Background(), items); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(updates) != 2 || updates[0].ValueInputOption != "RAW" || updates[1].ValueInputOption != "USER_ENTERED" {
+		t.Fatalf("invalid updates, expected RAW values and USER_ENTERED formulas, got %+v", updates)
+	}
+	for _, data := range updates[1].Data {
+		for _, row := range data.Values {
+			for _, value := range row {
+				if value == nil {
+					continue
+				}
+				if formula, ok := value.(string); !ok || strings.Contains(formula, "IMPORTXML") {
+					t.Fatalf("invalid value entered as typed by a user in %s: %v", data.Range, value)
+				}
+			}
+		}
+	}
+	if release := updates[0].Data[1].Values[1][1]; release != items[0].Releases[0].Name {
+		t.Fatalf("invalid raw release name, expected %s, got %v", items[0].Releases[0].Name, release)
+	}
+}
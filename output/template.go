@@ -0,0 +1,75 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"githubscanner/scanner"
+)
+
+// templateFuncs are the helpers available in user templates.
+var templateFuncs = template.FuncMap{
+	"date":          formatDate,
+	"semverCompare": semverCompare,
+	"truncate":      truncate,
+}
+
+// WriteTemplate renders the scanned items with the Go text template. Items are rendered in the given order, their
+// releases in the scanner.SortResults order.
+func WriteTemplate(w io.Writer, items []*scanner.ResultItem, text string) error {
+	scanner.SortReleases(items)
+	tmpl, err := parseTemplate("output", text)
+	if err != nil {
+		return err
+	}
+
+	return tmpl.Execute(w, items)
+}
+
+func parseTemplate(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the template: %v", err)
+	}
+
+	return tmpl, nil
+}
+
+func formatDate(value any) string {
+	switch t := value.(type) {
+	case time.Time:
+		return t.Format(time.DateOnly)
+	case *time.Time:
+		if t == nil {
+			return ""
+		}
+		return t.Format(time.DateOnly)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// semverCompare returns -1, 0 or 1 if the version a is lower, equal or greater than b.
+func semverCompare(a, b string) (int, error) {
+	scheme, err := scanner.GetVersionScheme("semver")
+	if err != nil {
+		return 0, err
+	}
+
+	return scheme.Compare(a, b)
+}
+
+// truncate shortens the text to the length in runes, ending it with "..." if it was cut.
+func truncate(length int, text string) string {
+	if utf8.RuneCountInString(text) <= length {
+		return text
+	}
+	if length <= 3 {
+		return string([]rune(text)[:length])
+	}
+
+	return string([]rune(text)[:length-3]) + "..."
+}
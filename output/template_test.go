@@ -0,0 +1,47 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"githubscanner/scanner"
+)
+
+func TestWriteTemplate(t *testing.T) {
+	tmpl := `{{range .}}{{.Repository.FullName}}:{{range .Releases}} {{.TagName}}{{if ge (semverCompare .TagName "v1.1.0") 0}}(new){{end}} {{truncate 8 .Body}} {{date .PublishedAt}};{{end}}{{end}}`
+
+	var buf bytes.Buffer
+	if err := WriteTemplate(&buf, getReportItems(), tmpl); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "test/test: v1.1.0(new) * fas... 2024-03-01; v1.0.0 * fir... ;"
+	if buf.String() != expected {
+		t.Fatalf("invalid output, expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestWriteTemplateInvalid(t *testing.T) {
+	items := []*scanner.ResultItem{}
+	if err := WriteTemplate(&bytes.Buffer{}, items, "{{range .}"); err == nil {
+		t.Fatal("expected template parse error")
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	cases := []struct {
+		length   int
+		text     string
+		expected string
+	}{
+		{10, "short", "short"},
+		{5, "long text", "lo..."},
+		{2, "long text", "lo"},
+		{4, "привет", "п..."},
+	}
+	for _, c := range cases {
+		if actual := truncate(c.length, c.text); actual != c.expected {
+			t.Fatalf("invalid truncated text, expected %q, got %q", c.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package output
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"githubscanner/scanner"
+)
+
+const BigQueryApi = "https://bigquery.googleapis.com/bigquery/v2"
+
+// bigQueryInsertBatch is the count of rows inserted with a request, BigQuery recommends at most 500.
+const bigQueryInsertBatch = 500
+
+// WarehouseRow is a flattened scan row for data warehouses, one per release. Repositories without releases get
+// a row without the release columns.
+type WarehouseRow struct {
+	SchemaVersion string     `json:"schema_version"`
+	Account       string     `json:"account"`
+	Repository    string     `json:"repository"`
+	Stars         int        `json:"stars"`
+	Archived      bool       `json:"archived"`
+	Language      string     `json:"language,omitempty"`
+	Release       string     `json:"release,omitempty"`
+	Tag           string     `json:"tag,omitempty"`
+	Draft         bool       `json:"draft"`
+	Prerelease    bool       `json:"prerelease"`
+	PublishedAt   *time.Time `json:"published_at,omitempty"`
+	Assets        int        `json:"assets"`
+	Downloads     int        `json:"downloads"`
+	ScannedAt     time.Time  `json:"scanned_at"`
+}
+
+// WarehouseField is a column of the warehouse table in the BigQuery schema format.
+type WarehouseField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Mode string `json:"mode"`
+}
+
+// WarehouseSchema is the schema of the warehouse rows. New columns are only appended as nullable, so existing
+// tables are migrated by adding the missing ones.
+var WarehouseSchema = []*WarehouseField{
+	{Name: "account", Type: "STRING", Mode: "REQUIRED"},
+	{Name: "repository", Type: "STRING", Mode: "REQUIRED"},
+	{Name: "stars", Type: "INTEGER", Mode: "NULLABLE"},
+	{Name: "archived", Type: "BOOLEAN", Mode: "NULLABLE"},
+	{Name: "language", Type: "STRING", Mode: "NULLABLE"},
+	{Name: "release", Type: "STRING", Mode: "NULLABLE"},
+	{Name: "tag", Type: "STRING", Mode: "NULLABLE"},
+	{Name: "draft", Type: "BOOLEAN", Mode: "NULLABLE"},
+	{Name: "prerelease", Type: "BOOLEAN", Mode: "NULLABLE"},
+	{Name: "published_at", Type: "TIMESTAMP", Mode: "NULLABLE"},
+	{Name: "assets", Type: "INTEGER", Mode: "NULLABLE"},
+	{Name: "downloads", Type: "INTEGER", Mode: "NULLABLE"},
+	{Name: "scanned_at", Type: "TIMESTAMP", Mode: "REQUIRED"},
+	{Name: "schema_version", Type: "STRING", Mode: "NULLABLE"},
+}
+
+// NewWarehouseRows flattens the snapshot. The account is the repository owner, as snapshots may cover several.
+func NewWarehouseRows(snapshot *scanner.Snapshot) []*WarehouseRow {
+	scanner.SortResults(snapshot.Items)
+
+	var rows []*WarehouseRow
+	for _, item := range snapshot.Items {
+		repository := item.Repository
+		account, _, _ := strings.Cut(repository.FullName, "/")
+		newRow := func() *WarehouseRow {
+			return &WarehouseRow{
+				SchemaVersion: scanner.SchemaVersion,
+				Account:       account,
+				Repository:    repository.FullName,
+				Stars:         repository.Stars,
+				Archived:      repository.Archived,
+				Language:      repository.Language,
+				ScannedAt:     snapshot.ScannedAt,
+			}
+		}
+		if len(item.Releases) == 0 {
+			rows = append(rows, newRow())
+		}
+		for _, release := range item.Releases {
+			row := newRow()
+			row.Release, row.Tag = release.Name, release.TagName
+			row.Draft, row.Prerelease = release.Draft, release.Prerelease
+			row.PublishedAt = release.PublishedAt
+			row.Assets = len(release.Assets)
+			for _, asset := range release.Assets {
+				row.Downloads += asset.DownloadCount
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	return rows
+}
+
+// WriteJSONL writes the flattened rows of the snapshot as newline delimited json, which warehouses load directly,
+// e.g. with "bq load --source_format=NEWLINE_DELIMITED_JSON" and the WarehouseSchema.
+func WriteJSONL(w io.Writer, snapshot *scanner.Snapshot) error {
+	encoder := json.NewEncoder(w)
+	for _, row := range NewWarehouseRows(snapshot) {
+		if err := encoder.Encode(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteWarehouseSchema writes the WarehouseSchema as a BigQuery json schema file.
+func WriteWarehouseSchema(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(WarehouseSchema)
+}
+
+// BigQueryExporter streams the flattened snapshot rows into a BigQuery table. The table is created if it does
+// not exist, and the missing columns of the WarehouseSchema are added to an existing one.
+type BigQueryExporter struct {
+	Project string
+	Dataset string
+	Table   string
+	// AccessToken is an OAuth 2.0 access token with the bigquery scope.
+	AccessToken string
+	BaseUrl     string
+	Client      *http.Client
+}
+
+func (e *BigQueryExporter) Export(ctx context.Context, snapshot *scanner.Snapshot) error {
+	if e.AccessToken == "" {
+		return fmt.Errorf("bigquery access token is not set, use the GOOGLE_OAUTH_TOKEN env var")
+	}
+	if err := e.prepareTable(ctx); err != nil {
+		return fmt.Errorf("could not prepare the bigquery table %s: %v", e.tableID(), err)
+	}
+
+	rows := NewWarehouseRows(snapshot)
+	for start := 0; start < len(rows); start += bigQueryInsertBatch {
+		batch := rows[start:min(start+bigQueryInsertBatch, len(rows))]
+		insert := map[string]any{"kind": "bigquery#tableDataInsertAllRequest"}
+		var insertRows []map[string]any
+		for _, row := range batch {
+			insertRows = append(insertRows, map[string]any{"json": row})
+		}
+		insert["rows"] = insertRows
+
+		var result struct {
+			InsertErrors []struct {
+				Index  int `json:"index"`
+				Errors []struct {
+					Message string `json:"message"`
+				} `json:"errors"`
+			} `json:"insertErrors"`
+		}
+		if err := e.call(ctx, http.MethodPost, e.tablePath()+"/insertAll", insert, &result); err != nil {
+			return fmt.Errorf("could not insert rows into the bigquery table %s: %v", e.tableID(), err)
+		}
+		if len(result.InsertErrors) > 0 && len(result.InsertErrors[0].Errors) > 0 {
+			failed := result.InsertErrors[0]
+			return fmt.Errorf("could not insert %d rows into the bigquery table %s: row %d: %s", len(result.InsertErrors), e.tableID(), start+failed.Index, failed.Errors[0].Message)
+		}
+	}
+
+	return nil
+}
+
+// prepareTable creates the table with the WarehouseSchema or adds its missing columns to the existing table.
+func (e *BigQueryExporter) prepareTable(ctx context.Context) error {
+	var table struct {
+		Schema struct {
+			Fields []*WarehouseField `json:"fields"`
+		} `json:"schema"`
+	}
+	err := e.call(ctx, http.MethodGet, e.tablePath(), nil, &table)
+	var statusError *bigQueryStatusError
+	if errors.As(err, &statusError) && statusError.status == http.StatusNotFound {
+		create := map[string]any{
+			"tableReference": map[string]string{"projectId": e.Project, "datasetId": e.Dataset, "tableId": e.Table},
+			"schema":         map[string]any{"fields": WarehouseSchema},
+		}
+		return e.call(ctx, http.MethodPost, fmt.Sprintf("/projects/%s/datasets/%s/tables", url.PathEscape(e.Project), url.PathEscape(e.Dataset)), create, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[string]bool, len(table.Schema.Fields))
+	for _, field := range table.Schema.Fields {
+		existing[field.Name] = true
+	}
+	fields := table.Schema.Fields
+	for _, field := range WarehouseSchema {
+		if !existing[field.Name] {
+			// Columns added to existing tables can only be nullable.
+			fields = append(fields, &WarehouseField{Name: field.Name, Type: field.Type, Mode: "NULLABLE"})
+		}
+	}
+	if len(fields) == len(table.Schema.Fields) {
+		return nil
+	}
+
+	return e.call(ctx, http.MethodPatch, e.tablePath(), map[string]any{"schema": map[string]any{"fields": fields}}, nil)
+}
+
+func (e *BigQueryExporter) tableID() string {
+	return e.Project + "." + e.Dataset + "." + e.Table
+}
+
+func (e *BigQueryExporter) tablePath() string {
+	return fmt.Sprintf("/projects/%s/datasets/%s/tables/%s", url.PathEscape(e.Project), url.PathEscape(e.Dataset), url.PathEscape(e.Table))
+}
+
+// bigQueryStatusError is a failed BigQuery API response.
+type bigQueryStatusError struct {
+	status  int
+	message string
+}
+
+func (e *bigQueryStatusError) Error() string {
+	return e.message
+}
+
+// call sends a request to the BigQuery API, the path is appended to the API url.
+func (e *BigQueryExporter) call(ctx context.Context, method, path string, body, result any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	baseUrl := e.BaseUrl
+	if baseUrl == "" {
+		baseUrl = BigQueryApi
+	}
+	request, err := http.NewRequestWithContext(ctx, method, baseUrl+path, reader)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Authorization", "Bearer "+e.AccessToken)
+	request.Header.Set("Content-Type", "application/json")
+
+	client := e.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		message := struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}{}
+		if err := json.NewDecoder(response.Body).Decode(&message); err != nil || message.Error.Message == "" {
+			return &bigQueryStatusError{status: response.StatusCode, message: response.Status}
+		}
+		return &bigQueryStatusError{status: response.StatusCode, message: message.Error.Message}
+	}
+	if result == nil {
+		return nil
+	}
+
+	return json.NewDecoder(response.Body).Decode(result)
+}
@@ -0,0 +1,100 @@
+package output
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"githubscanner/scanner"
+)
+
+func TestWriteJSONL(t *testing.T) {
+	items := append(getReportItems(), &scanner.ResultItem{Repository: &scanner.Repository{FullName: "test/docs", Stars: 3}})
+	items[0].Releases[0].Assets = []*scanner.Asset{{Name: "tool.tar.gz", DownloadCount: 7}, {Name: "tool.zip", DownloadCount: 3}}
+
+	var buf bytes.Buffer
+	if err := WriteJSONL(&buf, scanner.NewSnapshot("test", items)); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("invalid rows count, expected 3, got %d:\n%s", len(lines), buf.String())
+	}
+	var rows []*WarehouseRow
+	for _, line := range lines {
+		var row WarehouseRow
+		if err := json.Unmarshal([]byte(line), &row); err != nil {
+			t.Fatal(err)
+		}
+		rows = append(rows, &row)
+	}
+	if rows[0].Repository != "test/docs" || rows[0].Stars != 3 || rows[0].Tag != "" {
+		t.Fatalf("invalid row of the repository without releases, got %+v", rows[0])
+	}
+	if rows[1].Account != "test" || rows[1].Tag != "v1.1.0" || rows[1].Assets != 2 || rows[1].Downloads != 10 || rows[1].PublishedAt == nil {
+		t.Fatalf("invalid release row, got %+v", rows[1])
+	}
+}
+
+func TestBigQueryExporter(t *testing.T) {
+	for _, tableExists := range []bool{false, true} {
+		var requests []string
+		var inserted int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.Method+" "+r.URL.Path)
+			body, _ := io.ReadAll(r.Body)
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == "/projects/p/datasets/d/tables/releases":
+				if !tableExists {
+					w.WriteHeader(http.StatusNotFound)
+					w.Write([]byte(`{"error": {"message": "Not found: Table p:d.releases"}}`))
+					return
+				}
+				w.Write([]byte(`{"schema": {"fields": [{"name": "account", "type": "STRING", "mode": "REQUIRED"}]}}`))
+			case r.Method == http.MethodPatch:
+				var table struct {
+					Schema struct {
+						Fields []*WarehouseField `json:"fields"`
+					} `json:"schema"`
+				}
+				json.Unmarshal(body, &table)
+				if len(table.Schema.Fields) != len(WarehouseSchema) || table.Schema.Fields[1].Mode != "NULLABLE" {
+					t.Errorf("unexpected migrated schema %s", body)
+				}
+				w.Write([]byte(`{}`))
+			case r.URL.Path == "/projects/p/datasets/d/tables/releases/insertAll":
+				var insert struct {
+					Rows []json.RawMessage `json:"rows"`
+				}
+				json.Unmarshal(body, &insert)
+				inserted += len(insert.Rows)
+				w.Write([]byte(`{"kind": "bigquery#tableDataInsertAllResponse"}`))
+			default:
+				w.Write([]byte(`{}`))
+			}
+		}))
+
+		exporter := &BigQueryExporter{Project: "p", Dataset: "d", Table: "releases", AccessToken: "token", BaseUrl: server.URL}
+		if err := exporter.Export(context.Background(), scanner.NewSnapshot("test", getReportItems())); err != nil {
+			t.Fatal(err)
+		}
+		server.Close()
+
+		expected := "POST /projects/p/datasets/d/tables"
+		if tableExists {
+			expected = "PATCH /projects/p/datasets/d/tables/releases"
+		}
+		if len(requests) != 3 || requests[1] != expected {
+			t.Fatalf("invalid requests, expected %s to prepare the table, got %v", expected, requests)
+		}
+		if inserted != 2 {
+			t.Fatalf("invalid inserted rows count, expected 2, got %d", inserted)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package output
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"githubscanner/scanner"
+)
+
+const (
+	repositoriesSheet = "Repositories"
+	releasesSheet     = "Releases"
+	summarySheet      = "Summary"
+)
+
+type cell struct {
+	value   interface{}
+	formula string
+}
+
+type sheet struct {
+	name string
+	rows [][]cell
+}
+
+// WriteXLSX writes the scan as an Excel workbook with repositories, releases and summary sheets.
+// Summary values and per-repository release counts are formulas, so they stay correct when the sheets are edited.
+// The scan statistics are added to the summary sheet as values if they are not nil.
+func WriteXLSX(w io.Writer, items []*scanner.ResultItem, stats *scanner.ScanStats) error {
+	scanner.SortReleases(items)
+	sheets := []*sheet{
+		repositoriesXLSXSheet(items),
+		releasesXLSXSheet(items),
+	}
+	sheets = append(sheets, summaryXLSXSheet(len(sheets[0].rows)-1, len(sheets[1].rows)-1, stats))
+
+	archive := zip.NewWriter(w)
+	files := map[string]string{
+		"[Content_Types].xml":        xlsxContentTypes(sheets),
+		"_rels/.rels":                xlsxRootRels,
+		"xl/workbook.xml":            xlsxWorkbook(sheets),
+		"xl/_rels/workbook.xml.rels": xlsxWorkbookRels(sheets),
+	}
+	for i, sheet := range sheets {
+		files[fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1)] = xlsxSheet(sheet)
+	}
+	for _, name := range sortedKeys(files) {
+		file, err := archive.Create(name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(file, files[name]); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+func repositoriesXLSXSheet(items []*scanner.ResultItem) *sheet {
+	sheet := &sheet{name: repositoriesSheet}
+	sheet.rows = append(sheet.rows, []cell{{value: "Repository"}, {value: "Name"}, {value: "Releases"}, {value: "Downloads"}})
+	for i, item := range items {
+		row := i + 2
+		sheet.rows = append(sheet.rows, []cell{
+			{value: item.Repository.FullName},
+			{value: item.Repository.Name},
+			{formula: fmt.Sprintf("COUNTIF(%s!A:A,A%d)", releasesSheet, row), value: len(item.Releases)},
+			{formula: fmt.Sprintf("SUMIF(%s!A:A,A%d,%s!G:G)", releasesSheet, row, releasesSheet), value: downloadCount(item.Releases)},
+		})
+	}
+
+	return sheet
+}
+
+func releasesXLSXSheet(items []*scanner.ResultItem) *sheet {
+	sheet := &sheet{name: releasesSheet}
+	sheet.rows = append(sheet.rows, []cell{{value: "Repository"}, {value: "Release"}, {value: "Tag"}, {value: "Draft"}, {value: "Prerelease"}, {value: "Assets"}, {value: "Downloads"}})
+	for _, item := range items {
+		for _, release := range item.Releases {
+			sheet.rows = append(sheet.rows, []cell{
+				{value: item.Repository.FullName},
+				{value: release.Name},
+				{value: release.TagName},
+				{value: release.Draft},
+				{value: release.Prerelease},
+				{value: len(release.Assets)},
+				{value: downloadCount([]*scanner.Release{release})},
+			})
+		}
+	}
+
+	return sheet
+}
+
+func summaryXLSXSheet(repositoriesCount, releasesCount int, stats *scanner.ScanStats) *sheet {
+	sheet := &sheet{
+		name: summarySheet,
+		rows: [][]cell{
+			{{value: "Repositories"}, {formula: fmt.Sprintf("COUNTA(%s!A2:A%d)", repositoriesSheet, repositoriesCount+1), value: repositoriesCount}},
+			{{value: "Releases"}, {formula: fmt.Sprintf("COUNTA(%s!A2:A%d)", releasesSheet, releasesCount+1), value: releasesCount}},
+			{{value: "Repositories without releases"}, {formula: fmt.Sprintf("COUNTIF(%s!C2:C%d,0)", repositoriesSheet, repositoriesCount+1)}},
+			{{value: "Downloads"}, {formula: fmt.Sprintf("SUM(%s!G2:G%d)", releasesSheet, releasesCount+1)}},
+		},
+	}
+	if stats != nil {
+		sheet.rows = append(sheet.rows,
+			[]cell{{value: "API requests"}, {value: stats.Requests}},
+			[]cell{{value: "Scan duration, seconds"}, {value: stats.DurationSeconds}},
+			[]cell{{value: "Errors"}, {value: stats.Errors}},
+			[]cell{{value: "Warnings"}, {value: stats.Warnings}},
+		)
+		if stats.Partial {
+			sheet.rows = append(sheet.rows, []cell{{value: "Partial scan"}, {value: "interrupted"}})
+		}
+	}
+
+	return sheet
+}
+
+func downloadCount(releases []*scanner.Release) int {
+	count := 0
+	for _, release := range releases {
+		for _, asset := range release.Assets {
+			count += asset.DownloadCount
+		}
+	}
+
+	return count
+}
+
+func xlsxSheet(sheet *sheet) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for i, row := range sheet.rows {
+		fmt.Fprintf(&b, `<row r="%d">`, i+1)
+		for j, cell := range row {
+			ref := fmt.Sprintf("%s%d", columnName(j), i+1)
+			b.WriteString(xlsxCell(ref, cell))
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData></worksheet>`)
+
+	return b.String()
+}
+
+func xlsxCell(ref string, cell cell) string {
+	if cell.formula != "" {
+		value := ""
+		if cell.value != nil {
+			value = fmt.Sprintf("<v>%v</v>", cell.value)
+		}
+		return fmt.Sprintf(`<c r="%s"><f>%s</f>%s</c>`, ref, escapeXML(cell.formula), value)
+	}
+
+	switch v := cell.value.(type) {
+	case int, int64:
+		return fmt.Sprintf(`<c r="%s"><v>%d</v></c>`, ref, v)
+	case float64:
+		return fmt.Sprintf(`<c r="%s"><v>%g</v></c>`, ref, v)
+	case bool:
+		b := 0
+		if v {
+			b = 1
+		}
+		return fmt.Sprintf(`<c r="%s" t="b"><v>%d</v></c>`, ref, b)
+	default:
+		return fmt.Sprintf(`<c r="%s" t="inlineStr"><is><t>%s</t></is></c>`, ref, escapeXML(fmt.Sprint(v)))
+	}
+}
+
+func xlsxWorkbook(sheets []*sheet) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	for i, sheet := range sheets {
+		fmt.Fprintf(&b, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escapeXML(sheet.name), i+1, i+1)
+	}
+	b.WriteString(`</sheets><calcPr fullCalcOnLoad="1"/></workbook>`)
+
+	return b.String()
+}
+
+func xlsxWorkbookRels(sheets []*sheet) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := range sheets {
+		fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+	}
+	b.WriteString(`</Relationships>`)
+
+	return b.String()
+}
+
+func xlsxContentTypes(sheets []*sheet) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	b.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	for i := range sheets {
+		fmt.Fprintf(&b, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+	}
+	b.WriteString(`</Types>`)
+
+	return b.String()
+}
+
+const xlsxRootRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+// columnName converts a zero based column index to its spreadsheet name: A, B, ..., Z, AA, AB, ...
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+
+	return name
+}
+
+func escapeXML(value string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(value))
+
+	return b.String()
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,68 @@
+package output
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"githubscanner/scanner"
+)
+
+func TestWriteXLSX(t *testing.T) {
+	items := []*scanner.ResultItem{{
+		Repository: &scanner.Repository{FullName: "test/test", Name: "test"},
+		Releases: []*scanner.Release{{
+			Name:    "Release <1.0>",
+			TagName: "v1.0.0",
+			Assets:  []*scanner.Asset{{Name: "tool.tar.gz", DownloadCount: 7}},
+		}},
+	}}
+
+	var buf bytes.Buffer
+	if err := WriteXLSX(&buf, items, &scanner.ScanStats{Requests: 3, DurationSeconds: 1.5}); err != nil {
+		t.Fatal(err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{}
+	for _, file := range archive.File {
+		reader, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[file.Name] = string(content)
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "xl/workbook.xml", "xl/worksheets/sheet1.xml", "xl/worksheets/sheet2.xml", "xl/worksheets/sheet3.xml"} {
+		if _, ok := files[name]; !ok {
+			t.Fatalf("workbook file %s is missing", name)
+		}
+	}
+	if !strings.Contains(files["xl/worksheets/sheet2.xml"], "Release &lt;1.0&gt;") {
+		t.Fatalf("release name is not escaped in the releases sheet: %s", files["xl/worksheets/sheet2.xml"])
+	}
+	if !strings.Contains(files["xl/worksheets/sheet3.xml"], "<f>COUNTA(Repositories!A2:A2)</f>") {
+		t.Fatalf("repositories count formula is missing in the summary sheet: %s", files["xl/worksheets/sheet3.xml"])
+	}
+	if !strings.Contains(files["xl/worksheets/sheet3.xml"], `<c r="B6"><v>1.5</v></c>`) {
+		t.Fatalf("scan duration is missing in the summary sheet: %s", files["xl/worksheets/sheet3.xml"])
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	for index, expected := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		if name := columnName(index); name != expected {
+			t.Errorf("invalid column name for %d, expected %s, got %s", index, expected, name)
+		}
+	}
+}
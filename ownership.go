@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"githubscanner/scanner"
+)
+
+// repositoryOwnership is a row of the "who owns what" report.
+type repositoryOwnership struct {
+	Repository string                `json:"repository"`
+	Owners     []string              `json:"owners"`
+	Teams      []*scanner.TeamAccess `json:"teams"`
+}
+
+func ownershipCommand(flags *flag.FlagSet) func(args []string) {
+	format := flags.String("format", "text", "output format: text or json")
+	outputPath := flags.String("output", "", "output file (stdout by default)")
+	options := addScannerFlags(flags)
+
+	return func(args []string) {
+		if len(args) < 1 {
+			usage("organization is not specified")
+		}
+
+		s, err := options.newScanner()
+		if err != nil {
+			fail(err)
+		}
+		orgs, err := options.resolveAccounts(args)
+		if err != nil {
+			fail(err)
+		}
+
+		var rows []*repositoryOwnership
+		for _, org := range orgs {
+			repositories, err := s.GetAllRepositories(org)
+			if err != nil {
+				fail(err)
+			}
+			ownership, err := s.GetOwnership(org)
+			if err != nil {
+				fail(err)
+			}
+			for _, repository := range repositories {
+				item := &scanner.ResultItem{Repository: repository, Teams: ownership[repository.FullName]}
+				rows = append(rows, &repositoryOwnership{Repository: repository.FullName, Owners: item.Owners(), Teams: item.Teams})
+			}
+		}
+
+		w, err := createOutput(*outputPath)
+		if err != nil {
+			fail(err)
+		}
+		defer w.Close()
+
+		switch *format {
+		case "text":
+			table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(table, "REPOSITORY\tOWNERS\tTEAMS")
+			for _, row := range rows {
+				owners := "-"
+				if len(row.Owners) > 0 {
+					owners = strings.Join(row.Owners, ",")
+				}
+				var teams []string
+				for _, access := range row.Teams {
+					teams = append(teams, access.Team+":"+access.Role)
+				}
+				if len(teams) == 0 {
+					teams = []string{"-"}
+				}
+				fmt.Fprintf(table, "%s\t%s\t%s\n", row.Repository, owners, strings.Join(teams, ","))
+			}
+			err = table.Flush()
+		case "json":
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(rows)
+		default:
+			err = fmt.Errorf("unknown output format: %s", *format)
+		}
+		if err != nil {
+			fail(err)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"githubscanner/scanner"
+)
+
+func packagesCommand(flags *flag.FlagSet) func(args []string) {
+	format := flags.String("format", "text", "output format: text or json")
+	outputPath := flags.String("output", "", "output file (stdout by default)")
+	options := addScannerFlags(flags)
+
+	return func(args []string) {
+		if len(args) < 1 {
+			usage("account is not specified")
+		}
+
+		s, err := options.newScanner()
+		if err != nil {
+			fail(err)
+		}
+		accounts, err := options.resolveAccounts(args)
+		if err != nil {
+			fail(err)
+		}
+
+		var packages []*scanner.Package
+		for _, account := range accounts {
+			accountPackages, err := s.GetPackages(account)
+			if err != nil {
+				fail(err)
+			}
+			packages = append(packages, accountPackages...)
+		}
+
+		w, err := createOutput(*outputPath)
+		if err != nil {
+			fail(err)
+		}
+		defer w.Close()
+
+		switch *format {
+		case "text":
+			table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(table, "TYPE\tPACKAGE\tREPOSITORY\tVERSIONS\tLATEST")
+			for _, pkg := range packages {
+				repository := "-"
+				if pkg.Repository != nil {
+					repository = pkg.Repository.FullName
+				}
+				fmt.Fprintf(table, "%s\t%s\t%s\t%d\t%s\n", pkg.PackageType, pkg.Name, repository, len(pkg.Versions), latestPackageVersion(pkg))
+			}
+			err = table.Flush()
+		case "json":
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(packages)
+		default:
+			err = fmt.Errorf("unknown output format: %s", *format)
+		}
+		if err != nil {
+			fail(err)
+		}
+	}
+}
+
+// latestPackageVersion returns the latest version name, container versions are digests so their tags are used.
+func latestPackageVersion(pkg *scanner.Package) string {
+	if len(pkg.Versions) == 0 {
+		return "-"
+	}
+	version := pkg.Versions[0]
+	if version.Metadata.Container != nil && len(version.Metadata.Container.Tags) > 0 {
+		return strings.Join(version.Metadata.Container.Tags, ",")
+	}
+
+	return version.Name
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"githubscanner/scanner"
+)
+
+const progressBarWidth = 30
+
+// progressBar renders scan progress and the remaining rate limit budget on a single terminal line.
+type progressBar struct {
+	w       io.Writer
+	scanner *scanner.Scanner
+	started bool
+}
+
+func (p *progressBar) update(done, total int, repository string) {
+	filled := progressBarWidth
+	if total > 0 {
+		filled = progressBarWidth * done / total
+	}
+	line := fmt.Sprintf("[%s%s] %d/%d repositories, %d remaining", strings.Repeat("=", filled), strings.Repeat(" ", progressBarWidth-filled), done, total, total-done)
+	if rateLimit := p.scanner.RateLimit(); rateLimit != nil {
+		line += fmt.Sprintf(", rate limit %d/%d", rateLimit.Remaining, rateLimit.Limit)
+	}
+	// The line is cleared to the end, so a shorter update does not leave a tail of the previous one.
+	fmt.Fprintf(p.w, "\r%s %s\033[K", line, repository)
+	p.started = true
+}
+
+// finish moves past the progress line, so the following output starts on a new line.
+func (p *progressBar) finish() {
+	if p.started {
+		fmt.Fprintln(p.w)
+		p.started = false
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"githubscanner/scanner"
+)
+
+func rateLimitCommand(flags *flag.FlagSet) func(args []string) {
+	all := flags.Bool("all", false, "show all rate limit resources instead of core, search and graphql")
+	format := flags.String("format", "text", "output format: text or json")
+	options := addScannerFlags(flags)
+
+	return func(args []string) {
+		s, err := options.newScanner()
+		if err != nil {
+			fail(err)
+		}
+		rateLimits, err := s.GetRateLimits()
+		if err != nil {
+			fail(err)
+		}
+
+		resources := scanner.RateLimitResources
+		if *all {
+			resources = make([]string, 0, len(rateLimits))
+			for resource := range rateLimits {
+				resources = append(resources, resource)
+			}
+			sort.Strings(resources)
+		}
+
+		w, err := createOutput("")
+		if err != nil {
+			fail(err)
+		}
+		defer w.Close()
+
+		switch *format {
+		case "text":
+			table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(table, "RESOURCE\tREMAINING\tLIMIT\tRESETS")
+			for _, resource := range resources {
+				if rateLimit := rateLimits[resource]; rateLimit != nil {
+					fmt.Fprintf(table, "%s\t%d\t%d\t%s (in %s)\n", resource, rateLimit.Remaining, rateLimit.Limit, rateLimit.Reset.Format(time.TimeOnly), time.Until(rateLimit.Reset).Round(time.Second))
+				}
+			}
+			err = table.Flush()
+		case "json":
+			selected := make(map[string]*scanner.RateLimit, len(resources))
+			for _, resource := range resources {
+				if rateLimit := rateLimits[resource]; rateLimit != nil {
+					selected[resource] = rateLimit
+				}
+			}
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(selected)
+		default:
+			err = fmt.Errorf("unknown output format: %s", *format)
+		}
+		if err != nil {
+			fail(err)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"githubscanner/output"
+	"githubscanner/scanner"
+	"githubscanner/server"
+)
+
+func reportCommand(flags *flag.FlagSet) func(args []string) {
+	snapshotPath := flags.String("snapshot", "", "render the stored snapshot instead of scanning the account")
+	since := flags.String("since", "", "snapshot of a previous scan, only releases added after it are reported")
+	templatePath := flags.String("template", "", "file with a Go template the report is rendered with instead of the default one")
+	title := flags.String("title", "", "report title (\"What's new in <account>\" by default)")
+	outputPath := flags.String("output", "", "output file (stdout by default)")
+	where := flags.String("where", "", "only report repositories with the annotation value, e.g. team=platform")
+	topic := flags.String("topic", "", "only report repositories tagged with the topic")
+	groupBy := flags.String("group-by", "", "group the reported repositories: topic")
+	approvalsPath := flags.String("approvals", "", "json file with release approvals made in the serve mode")
+	review := flags.String("review", "", "only report releases in the review status: reviewed, approved or unreviewed")
+	withLanguages := flags.Bool("with-languages", false, "scan the language breakdown of the repositories and report the totals per account")
+	staleAfter := flags.String("stale-after", "", "add a section of repositories without pushes, releases or commits for the period, e.g. 90d, 6w, 18m or 2y")
+	withCommitActivity := flags.Bool("with-commit-activity", false, "scan the commit activity of the repositories and report their last year commits")
+	releasedAfter := flags.String("released-after", "", "only report releases published on or after the date, e.g. 2024-01-01")
+	var platforms platformsFlag
+	flags.Var(&platforms, "platform", "only report assets for the os/arch targets, e.g. linux/amd64 (comma separated or repeated)")
+	releasedBefore := flags.String("released-before", "", "only report releases published before the date, e.g. 2024-04-01 for the first quarter with -released-after 2024-01-01")
+	options := addScannerFlags(flags)
+
+	return func(args []string) {
+		if *groupBy != "" && *groupBy != "topic" {
+			usage(fmt.Sprintf("unknown grouping %s, expected topic", *groupBy))
+		}
+		var staleSince time.Time
+		if *staleAfter != "" {
+			var err error
+			if staleSince, err = scanner.ParsePeriod(*staleAfter, time.Now()); err != nil {
+				usage(err.Error())
+			}
+		}
+		window, err := scanner.ParseReleaseWindow(*releasedAfter, *releasedBefore)
+		if err != nil {
+			usage(err.Error())
+		}
+
+		var current *scanner.Snapshot
+		var skipped []*scanner.SkippedAccount
+		if *snapshotPath != "" {
+			var err error
+			if current, err = scanner.LoadSnapshot(*snapshotPath); err != nil {
+				fail(err)
+			}
+			if options.annotationsPath != "" {
+				annotations, err := scanner.LoadAnnotations(options.annotationsPath)
+				if err != nil {
+					fail(err)
+				}
+				annotations.Apply(current.Items)
+			}
+			window.Apply(current.Items)
+		} else {
+			if len(args) < 1 {
+				usage("account is not specified: report <account or group>")
+			}
+			s, err := options.newScanner()
+			if err != nil {
+				fail(err)
+			}
+			s.ScanLanguages = *withLanguages
+			s.ScanCommitActivity = *withCommitActivity
+			s.ReleaseWindow = window
+			accounts, err := options.resolveAccounts(args[:1])
+			if err != nil {
+				fail(err)
+			}
+			start := time.Now()
+			var items []*scanner.ResultItem
+			items, skipped, err = s.ScanAccounts(accounts)
+			if err != nil {
+				fail(err)
+			}
+			for _, account := range skipped {
+				warn("account %s is skipped: %s", account.Account, account.Reason)
+			}
+			current = scanner.NewSnapshot(args[0], items)
+			current.Stats = scanner.NewScanStats(items)
+			current.Stats.Requests, current.Stats.DurationSeconds = s.Requests(), time.Since(start).Seconds()
+			current.Stats.Errors = len(skipped)
+		}
+
+		var changes []*scanner.Change
+		if *since != "" {
+			previous, err := scanner.LoadSnapshot(*since)
+			if err != nil {
+				fail(err)
+			}
+			// releases outside of the window are not reported as removed
+			window.Apply(previous.Items)
+			changes = scanner.DiffResults(previous.Items, current.Items)
+		}
+
+		var tmpl string
+		if *templatePath != "" {
+			data, err := os.ReadFile(*templatePath)
+			if err != nil {
+				fail(err)
+			}
+			tmpl = string(data)
+		}
+		if *title == "" {
+			*title = "What's new in " + current.Account
+		}
+
+		items := scanner.FilterAssetsByPlatform(filterTopic(filterWhere(current.Items, *where), *topic), platforms)
+		if *review != "" {
+			approvals, err := scanner.OpenApprovalStore(*approvalsPath)
+			if err != nil {
+				fail(err)
+			}
+			items = approvals.FilterByReviewStatus(items, *review)
+		}
+
+		w, err := createOutput(*outputPath)
+		if err != nil {
+			fail(err)
+		}
+		defer w.Close()
+		report := output.NewReport(*title, items, changes)
+		report.Stats = current.Stats
+		if *groupBy == "topic" {
+			report.Groups = scanner.GroupByTopic(report.Items)
+		}
+		if *staleAfter != "" {
+			// Stale repositories are found among all reported ones, not only those with new releases.
+			report.Stale = scanner.StaleRepositories(items, staleSince)
+			report.StaleSince = staleSince
+		}
+		if err := output.WriteMarkdown(w, report, tmpl); err != nil {
+			fail(err)
+		}
+		if len(skipped) > 0 {
+			w.Close()
+			exit(exitPartialFailure)
+		}
+	}
+}
+
+func alertRulesCommand(flags *flag.FlagSet) func(args []string) {
+	configPath := flags.String("config", defaultConfigPath(), "config file with account groups and aliases")
+	staleAfter := flags.Duration("stale-after", time.Hour, "age of the last successful scan an account data is alerted as stale after")
+
+	return func(args []string) {
+		config, err := loadConfig(*configPath)
+		if err != nil {
+			fail(err)
+		}
+		// All accounts of the config groups are alerted on unless accounts or groups are given.
+		names := args
+		if len(names) == 0 {
+			names = sortedGroups(config)
+		}
+
+		options := server.AlertRulesOptions{Accounts: config.ResolveAccounts(names), StaleAfter: *staleAfter}
+		if err := server.WriteAlertRules(os.Stdout, options); err != nil {
+			fail(err)
+		}
+	}
+}
+
+func sortedGroups(config *scanner.Config) []string {
+	groups := make([]string, 0, len(config.Groups))
+	for group := range config.Groups {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	return groups
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"githubscanner/scanner"
+)
+
+func reposCommand(flags *flag.FlagSet) func(args []string) {
+	format := flags.String("format", "text", "output format: text or json")
+	outputPath := flags.String("output", "", "output file (stdout by default)")
+	options := addScannerFlags(flags)
+
+	return func(args []string) {
+		if len(args) < 1 {
+			usage("account is not specified")
+		}
+
+		s, err := options.newScanner()
+		if err != nil {
+			fail(err)
+		}
+		accounts, err := options.resolveAccounts(args)
+		if err != nil {
+			fail(err)
+		}
+
+		var repositories []*scanner.Repository
+		for _, account := range accounts {
+			accountRepositories, err := getProvider(s).ListRepositories(context.Background(), account)
+			if err != nil {
+				fail(err)
+			}
+			repositories = append(repositories, s.Ignore.Filter(accountRepositories)...)
+		}
+
+		w, err := createOutput(*outputPath)
+		if err != nil {
+			fail(err)
+		}
+		defer w.Close()
+
+		switch *format {
+		case "text":
+			err = writeRepositories(w, repositories)
+		case "json":
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(repositories)
+		default:
+			err = fmt.Errorf("unknown output format: %s", *format)
+		}
+		if err != nil {
+			fail(err)
+		}
+	}
+}
+
+func releasesCommand(flags *flag.FlagSet) func(args []string) {
+	var platforms platformsFlag
+	flags.Var(&platforms, "platform", "only list assets for the os/arch targets, e.g. linux/amd64 (comma separated or repeated)")
+	format := flags.String("format", "text", "output format: text or json")
+	outputPath := flags.String("output", "", "output file (stdout by default)")
+	options := addScannerFlags(flags)
+
+	return func(args []string) {
+		if len(args) < 1 {
+			usage("repository is not specified: releases <owner>/<repo>")
+		}
+		owner, repository, ok := strings.Cut(args[0], "/")
+		if !ok {
+			usage("repository must be specified as <owner>/<repo>")
+		}
+
+		s, err := options.newScanner()
+		if err != nil {
+			fail(err)
+		}
+		releases, err := getProvider(s).ListReleases(context.Background(), owner, repository)
+		if err != nil {
+			fail(err)
+		}
+		if len(platforms) > 0 {
+			for i, release := range releases {
+				filteredRelease := *release
+				filteredRelease.Assets = scanner.FilterReleaseAssets(release, platforms)
+				releases[i] = &filteredRelease
+			}
+		}
+
+		w, err := createOutput(*outputPath)
+		if err != nil {
+			fail(err)
+		}
+		defer w.Close()
+
+		switch *format {
+		case "text":
+			for _, release := range releases {
+				writeRelease(w, release)
+			}
+		case "json":
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(releases)
+		default:
+			err = fmt.Errorf("unknown output format: %s", *format)
+		}
+		if err != nil {
+			fail(err)
+		}
+	}
+}
+
+// getProvider returns the provider of the scanner, which is the scanner itself for GitHub.
+func getProvider(s *scanner.Scanner) scanner.Provider {
+	if s.Provider == nil {
+		return s
+	}
+
+	return s.Provider
+}
+
+// writeRepositories prints the repository inventory as a table.
+func writeRepositories(w io.Writer, repositories []*scanner.Repository) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "REPOSITORY\tSTARS\tLANGUAGE\tLAST PUSH\tARCHIVED")
+	for _, repository := range repositories {
+		language := repository.Language
+		if language == "" {
+			language = "-"
+		}
+		pushed := "-"
+		if repository.PushedAt != nil {
+			pushed = repository.PushedAt.Format(time.DateOnly)
+		}
+		archived := "no"
+		if repository.Archived {
+			archived = "yes"
+		}
+		fmt.Fprintf(table, "%s\t%d\t%s\t%s\t%s\n", repository.FullName, repository.Stars, language, pushed, archived)
+	}
+
+	return table.Flush()
+}
+
+// writeRelease prints the release line with its publication date and state followed by the release assets.
+func writeRelease(w io.Writer, release *scanner.Release) {
+	published := "unpublished"
+	if release.PublishedAt != nil {
+		published = release.PublishedAt.Format(time.DateOnly)
+	}
+	var states []string
+	if release.Draft {
+		states = append(states, "draft")
+	}
+	if release.Prerelease {
+		states = append(states, "prerelease")
+	}
+	fmt.Fprintf(w, "%s\t%s\t%s", release.TagName, published, release.Name)
+	if len(states) > 0 {
+		fmt.Fprintf(w, " (%s)", strings.Join(states, ", "))
+	}
+	fmt.Fprintln(w)
+
+	for _, asset := range release.Assets {
+		fmt.Fprintf(w, "  %s\t%d bytes\t%d downloads", asset.Name, asset.Size, asset.DownloadCount)
+		if platform := asset.Platform().String(); platform != "" {
+			fmt.Fprintf(w, "\t%s", platform)
+		}
+		fmt.Fprintln(w)
+	}
+}
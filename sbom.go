@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"githubscanner/scanner"
+)
+
+func sbomCommand(flags *flag.FlagSet) func(args []string) {
+	merge := flags.Bool("merge", false, "write a single SBOM of all repositories instead of an SBOM per repository")
+	outputDir := flags.String("output-dir", ".", "directory the per-repository <owner>_<repo>.spdx.json files are written to")
+	outputPath := flags.String("output", "", "output file of the merged SBOM (stdout by default)")
+	options := addScannerFlags(flags)
+
+	return func(args []string) {
+		if len(args) < 1 {
+			usage("account or repository is not specified: sbom <account|group|owner/repo>...")
+		}
+
+		s, err := options.newScanner()
+		if err != nil {
+			fail(err)
+		}
+		names, err := options.resolveAccounts(args)
+		if err != nil {
+			fail(err)
+		}
+
+		var repositories []string
+		for _, name := range names {
+			if strings.Contains(name, "/") {
+				repositories = append(repositories, name)
+				continue
+			}
+			accountRepositories, err := s.GetAllRepositories(name)
+			if err != nil {
+				fail(err)
+			}
+			for _, repository := range accountRepositories {
+				repositories = append(repositories, repository.FullName)
+			}
+		}
+
+		var sboms []*scanner.SBOM
+		partial := false
+		for _, repository := range repositories {
+			owner, name, _ := strings.Cut(repository, "/")
+			sbom, err := s.GetSBOM(owner, name)
+			// The dependency graph could be disabled for some repositories of an account.
+			if errors.Is(err, scanner.ErrNotFound) {
+				warn("repository %s is skipped: %s", repository, err)
+				partial = true
+				continue
+			}
+			if err != nil {
+				fail(err)
+			}
+			if *merge {
+				sboms = append(sboms, sbom)
+				continue
+			}
+			if err := writeSBOMFile(filepath.Join(*outputDir, owner+"_"+name+".spdx.json"), sbom); err != nil {
+				fail(err)
+			}
+		}
+
+		if *merge {
+			w, err := createOutput(*outputPath)
+			if err != nil {
+				fail(err)
+			}
+			defer w.Close()
+			if err := writeSBOM(w, scanner.MergeSBOMs(strings.Join(args, ","), sboms)); err != nil {
+				fail(err)
+			}
+			w.Close()
+		}
+		if partial {
+			exit(exitPartialFailure)
+		}
+	}
+}
+
+func writeSBOMFile(path string, sbom *scanner.SBOM) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := writeSBOM(file, sbom); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+func writeSBOM(w io.Writer, sbom *scanner.SBOM) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(sbom)
+}
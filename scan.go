@@ -0,0 +1,908 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"githubscanner/output"
+	"githubscanner/scanner"
+)
+
+func scanCommand(flags *flag.FlagSet) func(args []string) {
+	var platforms platformsFlag
+	var steps stepsFlag
+	var sortBy sortFlag
+	var verifyCommands verifyCommandsFlag
+	flags.Var(&sortBy, "sort", "order of the repositories: name, stars, last-release or release-count, ascending by default or with :asc or :desc, e.g. stars:desc")
+	flags.Var(&steps, "step", "post-processing step applied to the results after the filters, e.g. topic=go, where=team=platform or sort (repeated, in order)")
+	flags.Var(&platforms, "platform", "only consider assets for the os/arch targets, e.g. linux/amd64 (comma separated or repeated)")
+	starred := flags.Bool("starred", false, "scan repositories starred by the account instead of owned ones")
+	mine := flags.Bool("mine", false, "scan all repositories the token owner can access, including private ones")
+	instance := flags.Bool("instance", false, "scan all organizations and users of the instance, e.g. a GitHub Enterprise Server with a site admin token")
+	query := flags.String("query", "", `scan the repositories matching the search query instead of accounts, e.g. "org:foo topic:sdk language:go"`)
+	format := flags.String("format", "text", "output format: text, json, ndjson, jsonl (flattened rows for data warehouses), xlsx, html, atom (feed of the releases), ics (calendar of the release dates) or template")
+	templateFile := flags.String("template-file", "", "file with a Go template the scan is rendered with in the template format")
+	outputPath := flags.String("output", "", "output file (stdout by default), s3://bucket/key or gs://bucket/object for the json format, or bq://project/dataset/table")
+	schemaPath := flags.String("schema-output", "", "also write the BigQuery json schema of the jsonl rows to the file")
+	googleSheet := flags.String("google-sheet", "", "id of a google spreadsheet the scan is pushed to (GOOGLE_OAUTH_TOKEN env var is used for auth)")
+	transparencyLog := flags.String("transparency-log", "", "append digests of the scanned assets to the hash-chained log file")
+	rekorUrl := flags.String("rekor-url", "", "also submit the transparency log entries to the Rekor-compatible log, e.g. https://rekor.sigstore.dev")
+	rekorKey := flags.String("rekor-key", "", "PEM encoded EC private key the entries submitted to Rekor are signed with")
+	settings := flags.Bool("settings", false, "also capture repository settings (merge strategies, default branch) to track their drift")
+	withContributors := flags.Bool("with-contributors", false, "also capture contributor logins and commit counts of every repository")
+	withLanguages := flags.Bool("with-languages", false, "also capture the language breakdown of every repository")
+	withBranches := flags.Bool("with-branches", false, "also capture branches and their last commit dates (a request per branch)")
+	withIssues := flags.Bool("with-issues", false, "also capture open issue and pull request counts of every repository")
+	withIssueLists := flags.Bool("with-issue-lists", false, "also capture the full lists of open issues and pull requests (implies -with-issues)")
+	withWorkflows := flags.Bool("with-workflows", false, "also capture the latest GitHub Actions runs and the CI health of every repository")
+	withAlerts := flags.Bool("with-alerts", false, "also capture open Dependabot alert counts by severity (the token needs the security_events scope)")
+	withTraffic := flags.Bool("with-traffic", false, "also capture 14-day views and clones of the repositories the token has push access to")
+	withCommitActivity := flags.Bool("with-commit-activity", false, "also capture weekly commit counts of the last year to tell active repositories from abandoned ones")
+	withOwnership := flags.Bool("with-ownership", false, "also map organization repositories to the teams with access to them")
+	withGoModules := flags.Bool("with-go-modules", false, "also capture the Go module of the root go.mod and flag release tags that are not valid module versions")
+	security := flags.Bool("security", false, "security posture profile: capture Dependabot, code scanning and secret scanning alert counts")
+	where := flags.String("where", "", "only keep repositories with the annotation value, e.g. team=platform")
+	topic := flags.String("topic", "", "only keep repositories tagged with the topic")
+	strictValidate := flags.Bool("strict-validate", false, "fail instead of writing the results if the validation finds suspicious data")
+	requireVersions := flags.Bool("require-parseable-versions", false, "report releases whose versions do not follow the repository version scheme as invalid")
+	artifactPolicyPath := flags.String("artifact-policy", "", "json file mapping repositories or patterns to the asset patterns every release must ship, e.g. {\"acme/*\": [\"checksums.txt\"]}; releases missing them are reported as invalid")
+	staleAfter := flags.String("stale-after", "", "also list repositories without pushes, releases or commits for the period, e.g. 90d, 6w, 18m or 2y")
+	since := flags.String("since", "", "incremental scan: skip repositories not pushed since the date, e.g. 2024-01-01, and only keep releases published after it")
+	sinceLastScan := flags.String("since-last-scan", "", "incremental scan since the time of the snapshot (scan -format json output) of the previous scan")
+	releasedAfter := flags.String("released-after", "", "only keep releases published on or after the date, e.g. 2024-01-01")
+	releasedBefore := flags.String("released-before", "", "only keep releases published before the date, e.g. 2024-04-01 for the first quarter with -released-after 2024-01-01")
+	accountConcurrency := flags.Int("account-concurrency", 4, "count of accounts scanned at once, they share the rate limit budget and the workers")
+	priority := flags.String("priority", "", "comma separated high priority accounts or groups, they are scanned first")
+	maxRepos := flags.Int("max-repos", 0, "only scan the most recently pushed repositories of every account, e.g. 50 (all by default)")
+	maxReleases := flags.Int("max-releases-per-repo", 0, "only fetch the most recent releases of every repository, e.g. 10 (all by default)")
+	scanTimeout := flags.Duration("scan-timeout", 0, "fail the scan if it is still running after the duration, e.g. 2h for scheduled jobs (not bounded by default)")
+	checkpointPath := flags.String("checkpoint", "", "file the scan progress is recorded in, it is removed once the scan completes (githubscanner-<digest of the accounts and flags>.checkpoint of the working directory by default, so every scan has its own)")
+	resume := flags.Bool("resume", false, "resume the interrupted scan recorded in the checkpoint file instead of starting over")
+	dryRun := flags.Bool("dry-run", false, "only list the repositories and estimate the API requests the scan takes")
+	force := flags.Bool("force", false, "scan even if the remaining rate limit is lower than the estimated requests")
+	verifySignatures := flags.Bool("verify-signatures", false, "also verify the signature and attestation assets of every release and record the verification status (signed assets are downloaded)")
+	cosignKey := flags.String("cosign-key", "", "PEM encoded public key the cosign .sig signatures without a .pem certificate are verified with")
+	cosignRoots := flags.String("cosign-roots", "", "PEM encoded root and intermediate certificates the cosign .pem certificates of keyless signatures are verified with, e.g. the Sigstore Fulcio ones (requires -cosign-identity, -cosign-issuer and -cosign-rekor-url)")
+	cosignIdentity := flags.String("cosign-identity", "", "expected email or URI identities of the cosign certificates, * matches any characters, e.g. https://github.com/acme/* (comma separated)")
+	cosignIssuer := flags.String("cosign-issuer", "", "expected OIDC issuers of the cosign certificates, e.g. https://token.actions.githubusercontent.com (comma separated)")
+	cosignRekorUrl := flags.String("cosign-rekor-url", "", "transparency log the cosign signatures must be recorded in, e.g. https://rekor.sigstore.dev")
+	flags.Var(&verifyCommands, "verify-command", `command verifying the signatures with a name suffix, e.g. ".asc=gpg --verify {signature} {asset}" (repeated, tried before cosign)`)
+	progress := flags.Bool("progress", false, "show a progress bar of scanned repositories and the remaining rate limit on stderr")
+	options := addScannerFlags(flags)
+
+	return func(args []string) {
+		if len(args) < 1 && !*mine && *query == "" && !*instance {
+			usage("account is not specified")
+		}
+		if *instance && (len(args) > 0 || *mine || *starred || *query != "") {
+			usage("-instance could not be combined with accounts, -mine, -starred or -query")
+		}
+		if *query != "" && (len(args) > 0 || *mine || *starred) {
+			usage("-query could not be combined with accounts, -mine or -starred")
+		}
+		// BigQuery is loaded with the flattened rows whatever the format is.
+		if output.IsRemoteDestination(*outputPath) && !strings.HasPrefix(*outputPath, "bq://") && *format != "json" {
+			usage("object storage output is only supported for the json format")
+		}
+		if *maxRepos < 0 || *maxReleases < 0 {
+			usage("-max-repos and -max-releases-per-repo must not be negative")
+		}
+		if *accountConcurrency < 1 {
+			usage("-account-concurrency must be at least 1")
+		}
+		if *scanTimeout < 0 {
+			usage("-scan-timeout must not be negative")
+		}
+		if *schemaPath != "" {
+			if err := writeWarehouseSchema(*schemaPath); err != nil {
+				fail(err)
+			}
+		}
+		var artifacts scanner.ArtifactPolicy
+		if *artifactPolicyPath != "" {
+			if len(platforms) > 0 {
+				usage("-artifact-policy could not be used with -platform, the assets of other platforms are left out before the check")
+			}
+			if *format == "ndjson" {
+				usage("-artifact-policy is not supported for the ndjson format, items are streamed without validation")
+			}
+			var err error
+			if artifacts, err = scanner.LoadArtifactPolicy(*artifactPolicyPath); err != nil {
+				fail(err)
+			}
+		}
+		var staleSince time.Time
+		if *staleAfter != "" {
+			var err error
+			if staleSince, err = scanner.ParsePeriod(*staleAfter, time.Now()); err != nil {
+				usage(err.Error())
+			}
+		}
+
+		s, err := options.newScanner()
+		if err != nil {
+			fail(err)
+		}
+		s.ScanSettings = *settings
+		s.ScanContributors = *withContributors
+		s.ScanLanguages = *withLanguages
+		s.ScanBranches = *withBranches
+		s.ScanIssues = *withIssues
+		s.ScanIssueLists = *withIssueLists
+		s.ScanWorkflows = *withWorkflows
+		s.ScanTraffic = *withTraffic
+		s.ScanCommitActivity = *withCommitActivity
+		s.ScanOwnership = *withOwnership
+		s.ScanGoModules = *withGoModules
+		s.MaxRepositories = *maxRepos
+		s.MaxReleases = *maxReleases
+		if *verifySignatures {
+			cosign := &scanner.CosignVerifier{
+				Identities: splitList(*cosignIdentity),
+				Issuers:    splitList(*cosignIssuer),
+			}
+			if *cosignRoots != "" && (len(cosign.Identities) == 0 || len(cosign.Issuers) == 0) {
+				usage("-cosign-roots requires -cosign-identity and -cosign-issuer, any workflow certificate of the roots would be trusted otherwise")
+			}
+			if *cosignRoots != "" && *cosignRekorUrl == "" {
+				usage("-cosign-roots requires -cosign-rekor-url, the short-lived certificates are checked at the time their signatures were logged")
+			}
+			if *cosignRekorUrl != "" {
+				cosign.Rekor = &scanner.RekorClient{BaseUrl: *cosignRekorUrl}
+			}
+			if s.SignatureVerifiers, err = signatureVerifiers(verifyCommands, cosign, *cosignKey, *cosignRoots); err != nil {
+				fail(err)
+			}
+			s.VerifySignatures = true
+		} else if len(verifyCommands) > 0 || *cosignKey != "" || *cosignRoots != "" || *cosignIdentity != "" || *cosignIssuer != "" || *cosignRekorUrl != "" {
+			usage("-verify-command and the -cosign flags require -verify-signatures")
+		}
+		if s.Since, err = sinceTime(*since, *sinceLastScan); err != nil {
+			fail(err)
+		}
+		if s.ReleaseWindow, err = scanner.ParseReleaseWindow(*releasedAfter, *releasedBefore); err != nil {
+			usage(err.Error())
+		}
+		if *security {
+			s.ScanAlerts = scanner.AlertSources
+		} else if *withAlerts {
+			s.ScanAlerts = []string{scanner.AlertSourceDependabot}
+		}
+		s.CheckBudget = !*force
+		if *dryRun {
+			if *mine || *starred || *query != "" {
+				usage("-dry-run is only supported for scans of account repositories")
+			}
+			accounts, err := scanAccounts(s, options, args, *instance)
+			if err != nil {
+				fail(err)
+			}
+			if err := estimateScan(os.Stdout, s, accounts, *force); err != nil {
+				fail(err)
+			}
+			return
+		}
+		if *format == "ndjson" {
+			if len(steps) > 0 || sortBy.comparator != nil {
+				usage("-step and -sort are not supported for the ndjson format, items are streamed as they are scanned")
+			}
+			if *mine || *starred || *query != "" {
+				usage("ndjson format is only supported for scans of account repositories")
+			}
+		}
+		accounts, err := scanAccounts(s, options, args, *instance)
+		if err != nil {
+			fail(err)
+		}
+		var priorityAccounts []string
+		if *priority != "" {
+			if priorityAccounts, err = options.resolveAccounts(strings.Split(*priority, ",")); err != nil {
+				fail(err)
+			}
+		}
+
+		// The checkpoint is opened once nothing fails before the scan, so it is removed by closeCheckpoint.
+		key := checkpointKey(flags, args)
+		if *checkpointPath == "" {
+			*checkpointPath = fmt.Sprintf("githubscanner-%s.checkpoint", key[:12])
+		}
+		if !*resume {
+			if info, err := os.Stat(*checkpointPath); err == nil && info.Size() > 0 {
+				warn("the interrupted scan recorded in %s is discarded, pass -resume to continue it", *checkpointPath)
+			}
+		}
+		if s.Checkpoint, err = scanner.OpenCheckpoint(*checkpointPath, key, *resume); err != nil {
+			fail(err)
+		}
+		bar := &progressBar{w: os.Stderr, scanner: s}
+		if *progress && !quiet {
+			s.OnProgress = bar.update
+		}
+		interruptOnSignal(s)
+		start := time.Now()
+		if *scanTimeout > 0 {
+			s.ScanDeadline = start.Add(*scanTimeout)
+		}
+
+		if *format == "ndjson" {
+			stats, err := streamNDJSON(s, accounts, platforms, *outputPath)
+			bar.finish()
+			closeCheckpoint(s.Checkpoint, *checkpointPath, err)
+			interrupted := errors.Is(err, scanner.ErrInterrupted)
+			if err != nil && !interrupted {
+				fail(err)
+			}
+			stats.Requests, stats.DurationSeconds = s.Requests(), time.Since(start).Seconds()
+			stats.Partial = interrupted
+			warn("%s", stats)
+			if interrupted {
+				exit(exitPartialFailure)
+			}
+			return
+		}
+
+		var items []*scanner.ResultItem
+		var skipped []*scanner.SkippedAccount
+		if *query != "" {
+			items, err = s.ScanSearch(*query)
+		} else if *mine {
+			items, err = s.ScanMine()
+		} else if *starred {
+			items, err = scanStarred(s, accounts)
+		} else if len(accounts) > 1 {
+			coordinator := &scanner.Coordinator{Scanner: s, Concurrency: *accountConcurrency, Priority: priorityAccounts}
+			items, skipped, err = coordinator.ScanAccounts(accounts)
+		} else {
+			items, err = s.ScanRepositories(accounts[0])
+		}
+		bar.finish()
+		closeCheckpoint(s.Checkpoint, *checkpointPath, err)
+		// an interrupted scan still writes the items scanned before, marked as partial
+		interrupted := errors.Is(err, scanner.ErrInterrupted)
+		if err != nil && !interrupted {
+			fail(err)
+		}
+		for _, account := range skipped {
+			warn("account %s is skipped: the token is not authorized for the organization SSO, authorize it at %s", account.Account, account.AuthorizationURL)
+		}
+		pipeline := scanner.NewPipeline(scanner.PlatformStep(platforms))
+		if *where != "" {
+			step, err := scanner.NewStep("where=" + *where)
+			if err != nil {
+				usage(err.Error())
+			}
+			pipeline.Add(step)
+		}
+		if *topic != "" {
+			pipeline.Filter(scanner.TopicFilter(*topic))
+		}
+		pipeline.Add(steps...)
+		if sortBy.comparator != nil {
+			pipeline.Add(scanner.SortByStep(sortBy.comparator))
+		}
+		if items, err = pipeline.Run(items); err != nil {
+			fail(err)
+		}
+
+		issues := s.ValidateResults(items, scanner.ValidationPolicy{RequireParseableVersions: *requireVersions, Artifacts: artifacts})
+		for _, issue := range issues {
+			warn("warning: %s", issue)
+		}
+		if *strictValidate && len(issues) > 0 {
+			fail(fmt.Errorf("scan results are rejected: %d validation issues found", len(issues)))
+		}
+		stats := scanner.NewScanStats(items)
+		stats.Requests, stats.DurationSeconds = s.Requests(), time.Since(start).Seconds()
+		stats.Errors, stats.Warnings = len(skipped), len(issues)
+		stats.Partial = interrupted
+
+		if *transparencyLog != "" {
+			if err := appendTransparencyLog(*transparencyLog, *rekorUrl, *rekorKey, items); err != nil {
+				fail(err)
+			}
+		}
+
+		if *googleSheet != "" {
+			sheet := &output.GoogleSheet{
+				SpreadsheetID: *googleSheet,
+				AccessToken:   os.Getenv("GOOGLE_OAUTH_TOKEN"),
+			}
+			if err := sheet.Push(context.Background(), items); err != nil {
+				fail(err)
+			}
+		}
+
+		// results of several accounts are grouped by account with their totals
+		grouped := len(accounts) > 1 && !*mine && !*starred
+		// the search query or the instance stands for the accounts in titles of the output
+		if *query != "" {
+			args = []string{*query}
+		} else if *instance {
+			args = []string{s.BaseUrl}
+		}
+		newSnapshot := func() *scanner.Snapshot {
+			snapshot := scanner.NewSnapshot(strings.Join(args, ","), items)
+			snapshot.Options = s.Options()
+			snapshot.Stats = stats
+			if grouped {
+				snapshot.Summarize()
+			}
+			return snapshot
+		}
+
+		if output.IsRemoteDestination(*outputPath) {
+			exporter, err := output.NewExporter(*outputPath)
+			if err != nil {
+				fail(err)
+			}
+			if err := exporter.Export(context.Background(), newSnapshot()); err != nil {
+				fail(err)
+			}
+			warn("%s", stats)
+			if len(skipped) > 0 || interrupted {
+				exit(exitPartialFailure)
+			}
+			return
+		}
+
+		w, err := createOutput(*outputPath)
+		if err != nil {
+			fail(err)
+		}
+		defer w.Close()
+
+		switch *format {
+		case "text":
+			if grouped {
+				writeGroupedText(w, items, len(platforms) > 0)
+			} else {
+				writeText(w, items, len(platforms) > 0)
+			}
+			if *staleAfter != "" {
+				writeStale(w, scanner.StaleRepositories(items, staleSince), staleSince)
+			}
+			fmt.Fprintf(w, "== scan statistics ==\n%s\n", stats)
+		case "json":
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(newSnapshot())
+		case "jsonl":
+			err = output.WriteJSONL(w, newSnapshot())
+			warn("%s", stats)
+		case "xlsx":
+			err = output.WriteXLSX(w, items, stats)
+		case "html":
+			report := output.NewReport("Releases of "+strings.Join(args, ", "), items, nil)
+			report.Stats = stats
+			if grouped {
+				report.Accounts = scanner.GroupByAccount(items)
+			}
+			err = output.WriteHTML(w, report)
+		case "atom":
+			err = output.WriteAtom(w, output.NewReport("Releases of "+strings.Join(args, ", "), items, nil))
+			warn("%s", stats)
+		case "ics":
+			err = output.WriteICalendar(w, output.NewReport("Releases of "+strings.Join(args, ", "), items, nil))
+			warn("%s", stats)
+		case "template":
+			var tmpl []byte
+			if *templateFile == "" {
+				err = fmt.Errorf("template file is not specified, use -template-file")
+			} else if tmpl, err = os.ReadFile(*templateFile); err == nil {
+				err = output.WriteTemplate(w, items, string(tmpl))
+			}
+			warn("%s", stats)
+		default:
+			err = fmt.Errorf("unknown output format: %s", *format)
+		}
+		if err != nil {
+			fail(err)
+		}
+		if len(skipped) > 0 || interrupted {
+			w.Close()
+			exit(exitPartialFailure)
+		}
+	}
+}
+
+func writeWarehouseSchema(path string) error {
+	w, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	return output.WriteWarehouseSchema(w)
+}
+
+// estimateScan lists the repositories of the accounts and prints the estimated requests of their scan. It fails if
+// the remaining rate limit is lower than the estimate unless forced.
+func estimateScan(w io.Writer, s *scanner.Scanner, accounts []string, force bool) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "ACCOUNT	REPOSITORIES	REQUESTS")
+	total := &scanner.ScanEstimate{}
+	for _, account := range accounts {
+		repositories, err := getProvider(s).ListRepositories(context.Background(), account)
+		if err != nil {
+			return err
+		}
+		estimate := s.EstimateScan(s.Ignore.Filter(repositories))
+		fmt.Fprintf(table, "%s\t%d\t%d\n", account, estimate.Repositories, estimate.Requests())
+		total.Repositories += estimate.Repositories
+		total.ListRequests += estimate.ListRequests
+		total.ScanRequests += estimate.ScanRequests
+	}
+	if len(accounts) > 1 {
+		fmt.Fprintf(table, "total\t%d\t%d\n", total.Repositories, total.Requests())
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	rateLimit := s.RateLimit()
+	if rateLimit == nil {
+		fmt.Fprintln(w, "rate limit: unknown")
+		return nil
+	}
+	fmt.Fprintf(w, "rate limit: %d of %d remaining, resets at %s\n", rateLimit.Remaining, rateLimit.Limit, rateLimit.Reset.Format("15:04 MST"))
+	if total.ScanRequests > rateLimit.Remaining && !force {
+		return fmt.Errorf("%w: the scan takes at least %d more requests, use -force to scan anyway", scanner.ErrInsufficientBudget, total.ScanRequests)
+	}
+
+	return nil
+}
+
+// scanAccounts resolves the accounts of the scan, or lists all accounts of the instance.
+func scanAccounts(s *scanner.Scanner, options *scannerOptions, args []string, instance bool) ([]string, error) {
+	if !instance {
+		return options.resolveAccounts(args)
+	}
+	accounts, err := s.GetInstanceAccounts()
+	if err != nil {
+		return nil, err
+	}
+	if len(accounts) == 0 {
+		return nil, fmt.Errorf("no organizations or users found on the instance %s", s.BaseUrl)
+	}
+
+	return accounts, nil
+}
+
+// scanStarred scans the repositories starred by any of the accounts, a repository starred by several of them is
+// reported once. The items scanned before an interruption are returned with the error.
+func scanStarred(s *scanner.Scanner, accounts []string) ([]*scanner.ResultItem, error) {
+	var items []*scanner.ResultItem
+	var err error
+	seen := make(map[string]bool)
+	for _, account := range accounts {
+		var starred []*scanner.ResultItem
+		starred, err = s.ScanStarred(account)
+		for _, item := range starred {
+			if !seen[item.Repository.FullName] {
+				seen[item.Repository.FullName] = true
+				items = append(items, item)
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	scanner.SortResults(items)
+
+	return items, err
+}
+
+// interruptOnSignal interrupts the scans of the scanner on SIGINT or SIGTERM so that the repositories scanned
+// before are written, another signal terminates the process as usual.
+func interruptOnSignal(s *scanner.Scanner) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		signal.Stop(signals)
+		warn("interrupted: completing the repositories being scanned to write partial results, interrupt again to exit immediately")
+		s.Interrupt()
+	}()
+}
+
+// checkpointIgnoredFlags are the flags of the scan run which do not change the scanned items, e.g. the output and
+// the credentials, so they could differ when the scan is resumed.
+var checkpointIgnoredFlags = map[string]bool{
+	"checkpoint": true, "resume": true, "progress": true, "force": true, "scan-timeout": true, "dry-run": true,
+	"output": true, "format": true, "template-file": true, "schema-output": true, "google-sheet": true, "quiet": true,
+	"log-level": true, "log-format": true, "token": true, "token-file": true, "app-key": true, "proxy": true,
+	"ca-file": true, "client-cert": true, "client-key": true, "request-timeout": true, "otlp-endpoint": true,
+}
+
+// checkpointKey returns the hex digest of the accounts and the flags of the scan, so a checkpoint is only resumed
+// by the same scan and the default checkpoint files of different scans do not overwrite each other.
+func checkpointKey(flags *flag.FlagSet, args []string) string {
+	hash := sha256.New()
+	flags.Visit(func(f *flag.Flag) {
+		if !checkpointIgnoredFlags[f.Name] {
+			fmt.Fprintf(hash, "-%s=%q\n", f.Name, f.Value.String())
+		}
+	})
+	for _, arg := range args {
+		fmt.Fprintf(hash, "%q\n", arg)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// closeCheckpoint keeps the checkpoint of a failed scan to be resumed, and removes it once the scan completes or
+// if the scan failed before any progress was recorded.
+func closeCheckpoint(checkpoint *scanner.Checkpoint, path string, err error) {
+	if checkpoint == nil {
+		return
+	}
+	if err != nil && !checkpoint.Empty() {
+		checkpoint.Close()
+		warn("scan progress is recorded in %s, rerun the scan with -resume to continue", path)
+		return
+	}
+	if err := checkpoint.Remove(); err != nil {
+		warn("could not remove the checkpoint %s: %v", path, err)
+	}
+}
+
+// sinceTime returns the cutoff of an incremental scan: the date or the time of the previous scan snapshot.
+func sinceTime(date, snapshotPath string) (time.Time, error) {
+	if date != "" && snapshotPath != "" {
+		usage("only one of -since and -since-last-scan may be specified")
+	}
+	if snapshotPath != "" {
+		snapshot, err := scanner.LoadSnapshot(snapshotPath)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return snapshot.ScannedAt, nil
+	}
+	if date == "" {
+		return time.Time{}, nil
+	}
+	since, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %s, expected YYYY-MM-DD", date)
+	}
+
+	return since, nil
+}
+
+// streamNDJSON writes every repository as a json line tagged with the schema version as soon as its releases
+// are scanned. It returns the statistics of the written repositories, also if the scan fails.
+func streamNDJSON(s *scanner.Scanner, accounts []string, platforms []scanner.Platform, outputPath string) (*scanner.ScanStats, error) {
+	w, err := createOutput(outputPath)
+	if err != nil {
+		return nil, err
+	}
+	defer w.Close()
+
+	stats := &scanner.ScanStats{}
+	encoder := json.NewEncoder(w)
+	for _, account := range accounts {
+		err := s.StreamRepositories(account, func(item *scanner.ResultItem) error {
+			item = scanner.FilterAssetsByPlatform([]*scanner.ResultItem{item}, platforms)[0]
+			stats.Add(item)
+			return encoder.Encode(scanner.NewSchemaItem(item))
+		})
+		if err != nil {
+			return stats, err
+		}
+	}
+
+	return stats, nil
+}
+
+func appendTransparencyLog(path, rekorUrl, rekorKey string, items []*scanner.ResultItem) error {
+	log, err := scanner.OpenTransparencyLog(path)
+	if err != nil {
+		return err
+	}
+
+	if rekorUrl != "" {
+		data, err := os.ReadFile(rekorKey)
+		if err != nil {
+			return fmt.Errorf("could not read the rekor signing key: %v", err)
+		}
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return fmt.Errorf("rekor signing key %s is not PEM encoded", rekorKey)
+		}
+		key, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return fmt.Errorf("could not parse the rekor signing key: %v", err)
+		}
+		log.Rekor = &scanner.RekorClient{BaseUrl: rekorUrl, Signer: key}
+	}
+
+	_, err = log.AppendItems(context.Background(), items)
+
+	return err
+}
+
+// createOutput creates the output file, or returns stdout if the path is empty. Stdout output is discarded in the quiet mode.
+func createOutput(path string) (io.WriteCloser, error) {
+	if path != "" {
+		return os.Create(path)
+	}
+	if quiet {
+		return nopCloser{io.Discard}, nil
+	}
+
+	return nopCloser{os.Stdout}, nil
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+func writeText(w io.Writer, items []*scanner.ResultItem, withAssets bool) {
+	for _, item := range items {
+		fmt.Fprintln(w, item.Repository.FullName)
+		if item.Unchanged {
+			fmt.Fprintln(w, "releases not fetched: no pushes since the cutoff")
+		}
+		if item.Activity != nil {
+			fmt.Fprintf(w, "open issues: %d, open pull requests: %d\n", item.Activity.OpenIssues, item.Activity.OpenPullRequests)
+		}
+		if item.CI != nil {
+			fmt.Fprintf(w, "ci: %s (%d workflows)", item.CI.Status, item.CI.Workflows)
+			if len(item.CI.Failing) > 0 {
+				fmt.Fprintf(w, ", failing: %s", strings.Join(item.CI.Failing, ", "))
+			}
+			fmt.Fprintln(w)
+		}
+		if item.Alerts != nil {
+			writeAlerts(w, item.Alerts)
+		}
+		if len(item.Teams) > 0 {
+			var teams []string
+			for _, access := range item.Teams {
+				teams = append(teams, fmt.Sprintf("%s (%s)", access.Team, access.Role))
+			}
+			fmt.Fprintf(w, "teams: %s\n", strings.Join(teams, ", "))
+		}
+		if item.CommitActivity != nil {
+			writeCommitActivity(w, item.CommitActivity)
+		}
+		if item.GoModule != nil {
+			writeGoModule(w, item.GoModule)
+		}
+		if item.Traffic != nil {
+			fmt.Fprintf(w, "14-day traffic: %d views (%d unique), %d clones (%d unique)\n", item.Traffic.Views, item.Traffic.UniqueVisitors, item.Traffic.Clones, item.Traffic.UniqueCloners)
+		}
+		for _, release := range item.Releases {
+			if release.Verification == nil {
+				fmt.Fprintln(w, release.Name)
+			} else {
+				fmt.Fprintf(w, "%s (signatures: %s)\n", release.Name, release.Verification.Status)
+				for _, asset := range release.Verification.Assets {
+					if asset.Status == scanner.VerificationFailed {
+						fmt.Fprintf(w, "  %s: signature %s is invalid: %s\n", asset.Asset, asset.Signature, asset.Error)
+					}
+				}
+			}
+			if withAssets {
+				for _, asset := range release.Assets {
+					fmt.Fprintf(w, "  %s (%s)\n", asset.Name, asset.Platform())
+				}
+			}
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// writeGroupedText writes the items under a heading of every account and the totals of the accounts after them.
+func writeGroupedText(w io.Writer, items []*scanner.ResultItem, withAssets bool) {
+	groups := scanner.GroupByAccount(items)
+	for _, group := range groups {
+		fmt.Fprintf(w, "== %s: %s ==\n\n", group.Summary.Account, formatAccountSummary(group.Summary))
+		writeText(w, group.Items, withAssets)
+	}
+
+	summaries, total := scanner.SummarizeAccounts(items)
+	fmt.Fprintln(w, "== summary ==")
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "ACCOUNT\tREPOSITORIES\tRELEASES\tASSETS\tDOWNLOADS\tSTARS")
+	for _, summary := range append(summaries, total) {
+		account := summary.Account
+		if account == "" {
+			account = "total"
+		}
+		fmt.Fprintf(table, "%s\t%d\t%d\t%d\t%d\t%d\n", account, summary.Repositories, summary.Releases, summary.Assets, summary.Downloads, summary.Stars)
+	}
+	table.Flush()
+	fmt.Fprintln(w)
+}
+
+func formatAccountSummary(summary *scanner.AccountSummary) string {
+	return fmt.Sprintf("%d repositories, %d releases, %d downloads", summary.Repositories, summary.Releases, summary.Downloads)
+}
+
+func writeGoModule(w io.Writer, module *scanner.GoModule) {
+	if module.Problem != "" {
+		fmt.Fprintf(w, "go module: go.mod could not be read (%s)\n", module.Problem)
+		return
+	}
+	fmt.Fprintf(w, "go module: %s", module.Path)
+	if module.GoVersion != "" {
+		fmt.Fprintf(w, " (go %s)", module.GoVersion)
+	}
+	fmt.Fprintln(w)
+	for _, version := range module.InvalidVersions() {
+		fmt.Fprintf(w, "invalid module version: %s (%s)\n", version.Tag, version.Problem)
+	}
+}
+
+func writeAlerts(w io.Writer, alerts *scanner.SecurityAlerts) {
+	if alerts.Dependabot != nil {
+		fmt.Fprintf(w, "dependabot alerts: %s\n", scanner.FormatSeverityCounts(alerts.Dependabot))
+	}
+	if alerts.CodeScanning != nil {
+		fmt.Fprintf(w, "code scanning alerts: %s\n", scanner.FormatSeverityCounts(alerts.CodeScanning))
+	}
+	if alerts.SecretScanning != nil {
+		fmt.Fprintf(w, "secret scanning alerts: %s\n", scanner.FormatSeverityCounts(alerts.SecretScanning))
+	}
+	if alerts.CodeScanning != nil || alerts.SecretScanning != nil {
+		fmt.Fprintf(w, "all alerts: %s\n", scanner.FormatSeverityCounts(alerts.BySeverity()))
+	}
+	if len(alerts.Unavailable) > 0 {
+		fmt.Fprintf(w, "alerts unavailable: %s\n", strings.Join(alerts.Unavailable, ", "))
+	}
+}
+
+func writeStale(w io.Writer, stale []*scanner.StaleRepository, since time.Time) {
+	fmt.Fprintf(w, "stale repositories (no activity since %s): %d\n", since.Format(time.DateOnly), len(stale))
+	for _, repository := range stale {
+		lastActivity := "unknown"
+		if repository.LastActivity != nil {
+			lastActivity = repository.LastActivity.Format(time.DateOnly)
+		}
+		if repository.Archived {
+			lastActivity += ", archived"
+		}
+		fmt.Fprintf(w, "  %s (last activity: %s)\n", repository.Repository, lastActivity)
+	}
+}
+
+func writeCommitActivity(w io.Writer, activity *scanner.CommitActivity) {
+	if activity.LastCommitAt == nil {
+		fmt.Fprintln(w, "commits: none in the last year")
+		return
+	}
+	fmt.Fprintf(w, "commits: %d in the last year, %d in the last 4 weeks, last on %s\n", activity.Total, activity.RecentCommits(4), activity.LastCommitAt.Format(time.DateOnly))
+}
+
+type platformsFlag []scanner.Platform
+
+func (f *platformsFlag) String() string {
+	var values []string
+	for _, platform := range *f {
+		values = append(values, platform.String())
+	}
+
+	return strings.Join(values, ",")
+}
+
+func (f *platformsFlag) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		platform, err := scanner.ParsePlatform(v)
+		if err != nil {
+			return err
+		}
+		*f = append(*f, platform)
+	}
+
+	return nil
+}
+
+// stepsFlag is the repeated -step flag of pipeline steps.
+type stepsFlag []scanner.Transformer
+
+func (f *stepsFlag) String() string {
+	return ""
+}
+
+func (f *stepsFlag) Set(value string) error {
+	step, err := scanner.NewStep(value)
+	if err != nil {
+		return err
+	}
+	*f = append(*f, step)
+
+	return nil
+}
+
+// verifyCommandsFlag is the -verify-command flag, the suffix and the command are separated by "=", the arguments
+// of the command by spaces.
+type verifyCommandsFlag []*scanner.CommandVerifier
+
+func (f *verifyCommandsFlag) String() string {
+	return ""
+}
+
+func (f *verifyCommandsFlag) Set(value string) error {
+	suffix, command, ok := strings.Cut(value, "=")
+	if !ok || strings.TrimSpace(suffix) == "" || len(strings.Fields(command)) == 0 {
+		return fmt.Errorf("invalid verification command %q, expected <suffix>=<command>", value)
+	}
+	*f = append(*f, &scanner.CommandVerifier{Suffixes: []string{strings.TrimSpace(suffix)}, Command: strings.Fields(command)})
+
+	return nil
+}
+
+// splitList returns the non-empty values of a comma separated flag.
+func splitList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
+
+// signatureVerifiers returns the verification commands followed by the cosign verifier.
+func signatureVerifiers(commands verifyCommandsFlag, cosign *scanner.CosignVerifier, keyPath, rootsPath string) ([]scanner.SignatureVerifier, error) {
+	var verifiers []scanner.SignatureVerifier
+	for _, command := range commands {
+		verifiers = append(verifiers, command)
+	}
+	if keyPath != "" {
+		key, err := scanner.LoadPublicKey(keyPath)
+		if err != nil {
+			return nil, err
+		}
+		cosign.PublicKeys = append(cosign.PublicKeys, key)
+	}
+	if rootsPath != "" {
+		roots, err := scanner.LoadCertificatePool(rootsPath)
+		if err != nil {
+			return nil, err
+		}
+		cosign.Roots = roots
+	}
+
+	return append(verifiers, cosign), nil
+}
+
+// sortFlag is the -sort flag, the spec is parsed with the flags so a typo fails before the scan.
+type sortFlag struct {
+	spec       string
+	comparator scanner.Comparator
+}
+
+func (f *sortFlag) String() string {
+	return f.spec
+}
+
+func (f *sortFlag) Set(value string) error {
+	comparator, err := scanner.ParseSort(value)
+	if err != nil {
+		return err
+	}
+	f.spec, f.comparator = value, comparator
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package scanner
+
+import (
+	"sort"
+	"strings"
+)
+
+// SkippedAccount is an account left out of a multi-account scan.
+type SkippedAccount struct {
+	Account string `json:"account"`
+	Reason  string `json:"reason"`
+	// AuthorizationURL is where the token could be authorized for the organization SSO.
+	AuthorizationURL string `json:"authorization_url,omitempty"`
+}
+
+// ScanAccounts scans the accounts concurrently and returns their items together, see Coordinator.ScanAccounts.
+func (s *Scanner) ScanAccounts(accounts []string) ([]*ResultItem, []*SkippedAccount, error) {
+	return (&Coordinator{Scanner: s}).ScanAccounts(accounts)
+}
+
+// AccountSummary is the totals of the scanned repositories of an account, or of all accounts if the account is
+// empty.
+type AccountSummary struct {
+	Account      string `json:"account,omitempty"`
+	Repositories int    `json:"repositories"`
+	Releases     int    `json:"releases"`
+	Assets       int    `json:"assets"`
+	Downloads    int    `json:"downloads"`
+	Stars        int    `json:"stars"`
+}
+
+func (s *AccountSummary) add(item *ResultItem) {
+	s.Repositories++
+	s.Stars += item.Repository.Stars
+	for _, release := range item.Releases {
+		s.Releases++
+		s.Assets += len(release.Assets)
+		for _, asset := range release.Assets {
+			s.Downloads += asset.DownloadCount
+		}
+	}
+}
+
+// AccountGroup is the scanned items of the repositories owned by the account.
+type AccountGroup struct {
+	Summary *AccountSummary
+	Items   []*ResultItem
+}
+
+// GroupByAccount groups the items by the repository owners. Groups are ordered by account, the items keep their
+// order.
+func GroupByAccount(items []*ResultItem) []*AccountGroup {
+	groups := make(map[string]*AccountGroup)
+	var accounts []string
+	for _, item := range items {
+		account, _, _ := strings.Cut(item.Repository.FullName, "/")
+		group := groups[account]
+		if group == nil {
+			group = &AccountGroup{Summary: &AccountSummary{Account: account}}
+			groups[account] = group
+			accounts = append(accounts, account)
+		}
+		group.Summary.add(item)
+		group.Items = append(group.Items, item)
+	}
+	sort.Strings(accounts)
+
+	result := make([]*AccountGroup, 0, len(accounts))
+	for _, account := range accounts {
+		result = append(result, groups[account])
+	}
+
+	return result
+}
+
+// SummarizeAccounts returns the totals of every account, ordered by account, and of all of them.
+func SummarizeAccounts(items []*ResultItem) ([]*AccountSummary, *AccountSummary) {
+	total := &AccountSummary{}
+	var summaries []*AccountSummary
+	for _, group := range GroupByAccount(items) {
+		summaries = append(summaries, group.Summary)
+	}
+	for _, item := range items {
+		total.add(item)
+	}
+
+	return summaries, total
+}
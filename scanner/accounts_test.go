@@ -0,0 +1,70 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestScanAccountsSkipsSSOProtectedOrgs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/open/repos":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"full_name": "open/repo", "name": "repo"}]`))
+		case "/repos/open/repo/releases":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"name": "v1.0.0"}]`))
+		case "/users/protected/repos":
+			w.Header().Set("X-GitHub-SSO", "required; url=https://github.com/orgs/protected/sso?authorization_request=123")
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message": "Resource protected by organization SAML enforcement"}`))
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL, Token: "secret"}
+	items, skipped, err := scanner.ScanAccounts([]string{"protected", "open"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 1 || items[0].Repository.FullName != "open/repo" {
+		t.Fatalf("invalid scanned items: %v", items)
+	}
+	if len(skipped) != 1 || skipped[0].Account != "protected" {
+		t.Fatalf("invalid skipped accounts: %v", skipped)
+	}
+	if skipped[0].AuthorizationURL != "https://github.com/orgs/protected/sso?authorization_request=123" {
+		t.Fatalf("invalid authorization url: %s", skipped[0].AuthorizationURL)
+	}
+}
+
+func TestGroupByAccount(t *testing.T) {
+	items := []*ResultItem{
+		{Repository: &Repository{FullName: "second/b", Stars: 1}},
+		{Repository: &Repository{FullName: "first/a", Stars: 2}, Releases: []*Release{{Assets: []*Asset{{DownloadCount: 3}, {DownloadCount: 4}}}}},
+		{Repository: &Repository{FullName: "second/a", Stars: 5}, Releases: []*Release{{}, {Assets: []*Asset{{DownloadCount: 6}}}}},
+	}
+
+	groups := GroupByAccount(items)
+	if len(groups) != 2 || groups[0].Summary.Account != "first" || groups[1].Summary.Account != "second" {
+		t.Fatalf("invalid groups, expected first and second, got %v", groups)
+	}
+	if len(groups[1].Items) != 2 || groups[1].Items[0] != items[0] || groups[1].Items[1] != items[2] {
+		t.Fatalf("invalid items of second, expected second/b and second/a, got %v", groups[1].Items)
+	}
+	expected := AccountSummary{Account: "second", Repositories: 2, Releases: 2, Assets: 1, Downloads: 6, Stars: 6}
+	if *groups[1].Summary != expected {
+		t.Fatalf("invalid summary of second, expected %+v, got %+v", expected, *groups[1].Summary)
+	}
+
+	summaries, total := SummarizeAccounts(items)
+	if len(summaries) != 2 {
+		t.Fatalf("invalid summaries count, expected 2, got %d", len(summaries))
+	}
+	expected = AccountSummary{Repositories: 3, Releases: 3, Assets: 3, Downloads: 13, Stars: 8}
+	if *total != expected {
+		t.Fatalf("invalid total, expected %+v, got %+v", expected, *total)
+	}
+}
@@ -0,0 +1,126 @@
+package scanner
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// AnnotationRule assigns organizational metadata, e.g. owner team, cost center or tier, to the repositories
+// whose full names match the pattern.
+type AnnotationRule struct {
+	Pattern     string
+	Annotations map[string]string
+}
+
+// Annotations joins metadata GitHub does not know about into the scan results. All matching rules are applied
+// in order, so later rules override values of earlier ones.
+type Annotations []*AnnotationRule
+
+// LoadAnnotations reads the annotations from a csv or json file. The first csv column is the repository full
+// name or pattern and the header names the other columns, e.g. "repository,team,tier". The json file maps
+// repositories or patterns to objects of values; patterns are applied before exact repository names.
+func LoadAnnotations(filePath string) (Annotations, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var annotations Annotations
+	if strings.EqualFold(filepath.Ext(filePath), ".json") {
+		annotations, err = parseJSONAnnotations(data)
+	} else {
+		annotations, err = parseCSVAnnotations(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the annotations %s: %v", filePath, err)
+	}
+
+	return annotations, nil
+}
+
+func parseCSVAnnotations(data []byte) (Annotations, error) {
+	records, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	header := records[0]
+	var annotations Annotations
+	for _, record := range records[1:] {
+		rule := &AnnotationRule{Pattern: record[0], Annotations: make(map[string]string)}
+		for i := 1; i < len(record) && i < len(header); i++ {
+			if record[i] != "" {
+				rule.Annotations[header[i]] = record[i]
+			}
+		}
+		annotations = append(annotations, rule)
+	}
+
+	return annotations, nil
+}
+
+func parseJSONAnnotations(data []byte) (Annotations, error) {
+	var values map[string]map[string]string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+
+	var annotations Annotations
+	for pattern, repositoryAnnotations := range values {
+		annotations = append(annotations, &AnnotationRule{Pattern: pattern, Annotations: repositoryAnnotations})
+	}
+	sort.Slice(annotations, func(i, j int) bool {
+		iPattern, jPattern := isPattern(annotations[i].Pattern), isPattern(annotations[j].Pattern)
+		if iPattern != jPattern {
+			return iPattern
+		}
+		return annotations[i].Pattern < annotations[j].Pattern
+	})
+
+	return annotations, nil
+}
+
+func isPattern(value string) bool {
+	return strings.ContainsAny(value, `*?[\`)
+}
+
+// Get returns the annotations of the repository.
+func (a Annotations) Get(repositoryFullName string) map[string]string {
+	var result map[string]string
+	for _, rule := range a {
+		if matched, _ := path.Match(rule.Pattern, repositoryFullName); !matched {
+			continue
+		}
+		if result == nil {
+			result = make(map[string]string)
+		}
+		for key, value := range rule.Annotations {
+			result[key] = value
+		}
+	}
+
+	return result
+}
+
+// Apply sets the annotations of the items.
+func (a Annotations) Apply(items []*ResultItem) {
+	for _, item := range items {
+		item.Annotations = a.Get(item.Repository.FullName)
+	}
+}
+
+// FilterByAnnotation returns the items whose annotation has the value.
+func FilterByAnnotation(items []*ResultItem, key, value string) []*ResultItem {
+	filtered, _ := FilterStep(AnnotationFilter(key, value)).Transform(items)
+
+	return filtered
+}
@@ -0,0 +1,60 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadCSVAnnotations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "annotations.csv")
+	data := "repository,team,tier\norg/*,platform,2\norg/api,api,1\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	annotations, err := LoadAnnotations(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items := []*ResultItem{
+		{Repository: &Repository{FullName: "org/api"}},
+		{Repository: &Repository{FullName: "org/web"}},
+		{Repository: &Repository{FullName: "other/repo"}},
+	}
+	annotations.Apply(items)
+
+	if items[0].Annotations["team"] != "api" || items[0].Annotations["tier"] != "1" {
+		t.Fatalf("invalid annotations of org/api: %v", items[0].Annotations)
+	}
+	if items[1].Annotations["team"] != "platform" {
+		t.Fatalf("invalid annotations of org/web: %v", items[1].Annotations)
+	}
+	if items[2].Annotations != nil {
+		t.Fatalf("unexpected annotations of other/repo: %v", items[2].Annotations)
+	}
+
+	filtered := FilterByAnnotation(items, "team", "platform")
+	if len(filtered) != 1 || filtered[0].Repository.FullName != "org/web" {
+		t.Fatalf("invalid filtered items: %v", filtered)
+	}
+}
+
+func TestLoadJSONAnnotations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "annotations.json")
+	data := `{"org/api": {"cost_center": "42"}, "org/*": {"cost_center": "1", "team": "platform"}}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	annotations, err := LoadAnnotations(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := annotations.Get("org/api")
+	if result["cost_center"] != "42" || result["team"] != "platform" {
+		t.Fatalf("invalid annotations, expected exact name to override the pattern, got %v", result)
+	}
+}
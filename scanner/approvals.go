@@ -0,0 +1,171 @@
+package scanner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	ReviewStatusReviewed   = "reviewed"
+	ReviewStatusApproved   = "approved"
+	ReviewStatusUnreviewed = "unreviewed"
+)
+
+// Approval is a review of a release by a person.
+type Approval struct {
+	Repository string    `json:"repository"`
+	Release    string    `json:"release"`
+	Status     string    `json:"status"`
+	Reviewer   string    `json:"reviewer"`
+	ReviewedAt time.Time `json:"reviewed_at"`
+}
+
+type approvalKey struct {
+	repository string
+	release    string
+}
+
+// ApprovalStore keeps release approvals in a json file. The store is in memory only if the path is empty.
+// It is safe for concurrent use.
+type ApprovalStore struct {
+	path string
+
+	mu        sync.RWMutex
+	approvals map[approvalKey]*Approval
+}
+
+// OpenApprovalStore loads the approvals stored at the path. A missing file results in an empty store.
+func OpenApprovalStore(path string) (*ApprovalStore, error) {
+	store := &ApprovalStore{path: path, approvals: make(map[approvalKey]*Approval)}
+	if path == "" {
+		return store, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var approvals []*Approval
+	if err := json.Unmarshal(data, &approvals); err != nil {
+		return nil, fmt.Errorf("could not parse the approvals %s: %v", path, err)
+	}
+	for _, approval := range approvals {
+		store.approvals[approvalKey{approval.Repository, approval.Release}] = approval
+	}
+
+	return store, nil
+}
+
+// Set stores the approval of the release, replacing its previous review.
+func (s *ApprovalStore) Set(approval *Approval) error {
+	if approval.Status != ReviewStatusReviewed && approval.Status != ReviewStatusApproved {
+		return fmt.Errorf("invalid review status: %s", approval.Status)
+	}
+	if approval.Reviewer == "" {
+		return errors.New("reviewer is not specified")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.approvals[approvalKey{approval.Repository, approval.Release}] = approval
+
+	return s.save()
+}
+
+// Delete removes the approval of the release.
+func (s *ApprovalStore) Delete(repository, release string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.approvals, approvalKey{repository, release})
+
+	return s.save()
+}
+
+// Get returns the approval of the release or nil if it is not reviewed.
+func (s *ApprovalStore) Get(repository, release string) *Approval {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.approvals[approvalKey{repository, release}]
+}
+
+// List returns all approvals ordered by repository and release.
+func (s *ApprovalStore) List() []*Approval {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.list()
+}
+
+// ReviewStatus returns the review status of the release, "unreviewed" if it has no approval.
+func (s *ApprovalStore) ReviewStatus(repository, release string) string {
+	if approval := s.Get(repository, release); approval != nil {
+		return approval.Status
+	}
+
+	return ReviewStatusUnreviewed
+}
+
+// FilterByReviewStatus returns copies of the items with only the releases in the review status.
+// Items left without releases are omitted.
+func (s *ApprovalStore) FilterByReviewStatus(items []*ResultItem, status string) []*ResultItem {
+	var filtered []*ResultItem
+	for _, item := range items {
+		filteredItem := *item
+		filteredItem.Releases = nil
+		for _, release := range item.Releases {
+			if s.ReviewStatus(item.Repository.FullName, release.Version()) == status {
+				filteredItem.Releases = append(filteredItem.Releases, release)
+			}
+		}
+		if len(filteredItem.Releases) > 0 {
+			filtered = append(filtered, &filteredItem)
+		}
+	}
+
+	return filtered
+}
+
+func (s *ApprovalStore) list() []*Approval {
+	approvals := make([]*Approval, 0, len(s.approvals))
+	for _, approval := range s.approvals {
+		approvals = append(approvals, approval)
+	}
+	sort.Slice(approvals, func(i, j int) bool {
+		if approvals[i].Repository != approvals[j].Repository {
+			return approvals[i].Repository < approvals[j].Repository
+		}
+		return approvals[i].Release < approvals[j].Release
+	})
+
+	return approvals
+}
+
+// save writes the approvals to a temporary file first, so the store is not corrupted by a failed write.
+func (s *ApprovalStore) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s.list(), "", "  ")
+	if err != nil {
+		return err
+	}
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, s.path)
+}
@@ -0,0 +1,52 @@
+package scanner
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestApprovalStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "approvals.json")
+	store, err := OpenApprovalStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	approval := &Approval{Repository: "user/repo", Release: "v1.1.0", Status: ReviewStatusApproved, Reviewer: "alice", ReviewedAt: time.Now().UTC()}
+	if err := store.Set(approval); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set(&Approval{Repository: "user/repo", Release: "v1.0.0", Status: "rejected", Reviewer: "alice"}); err == nil {
+		t.Fatal("expected invalid review status error")
+	}
+
+	// Approvals are persisted.
+	store, err = OpenApprovalStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored := store.Get("user/repo", "v1.1.0"); stored == nil || stored.Reviewer != "alice" {
+		t.Fatalf("invalid stored approval: %v", stored)
+	}
+
+	items := []*ResultItem{{
+		Repository: &Repository{FullName: "user/repo"},
+		Releases:   []*Release{{TagName: "v1.1.0"}, {TagName: "v1.0.0"}},
+	}}
+	approved := store.FilterByReviewStatus(items, ReviewStatusApproved)
+	if len(approved) != 1 || len(approved[0].Releases) != 1 || approved[0].Releases[0].TagName != "v1.1.0" {
+		t.Fatalf("invalid approved items: %v", approved)
+	}
+	unreviewed := store.FilterByReviewStatus(items, ReviewStatusUnreviewed)
+	if len(unreviewed) != 1 || unreviewed[0].Releases[0].TagName != "v1.0.0" {
+		t.Fatalf("invalid unreviewed items: %v", unreviewed)
+	}
+
+	if err := store.Delete("user/repo", "v1.1.0"); err != nil {
+		t.Fatal(err)
+	}
+	if status := store.ReviewStatus("user/repo", "v1.1.0"); status != ReviewStatusUnreviewed {
+		t.Fatalf("invalid review status after delete, expected %s, got %s", ReviewStatusUnreviewed, status)
+	}
+}
@@ -0,0 +1,96 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"sort"
+)
+
+// ArtifactRule expects assets matching the asset patterns in the releases of the repositories whose full names
+// match the pattern.
+type ArtifactRule struct {
+	Pattern string
+	// Assets are patterns of asset names, e.g. "*_linux_amd64.tar.gz" or "checksums.txt".
+	Assets []string
+}
+
+// ArtifactPolicy is the assets every release is expected to ship, e.g. to gate the release quality of many
+// repositories. The expected assets of all matching rules add up.
+type ArtifactPolicy []*ArtifactRule
+
+// LoadArtifactPolicy reads the policy from a json file mapping repositories or patterns to the patterns of their
+// expected assets, e.g. {"acme/*": ["checksums.txt"], "acme/cli": ["*_linux_amd64.tar.gz"]}.
+func LoadArtifactPolicy(filePath string) (ArtifactPolicy, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := parseArtifactPolicy(data)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the artifact policy %s: %v", filePath, err)
+	}
+
+	return policy, nil
+}
+
+func parseArtifactPolicy(data []byte) (ArtifactPolicy, error) {
+	var values map[string][]string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+
+	var policy ArtifactPolicy
+	for pattern, assets := range values {
+		for _, value := range append([]string{pattern}, assets...) {
+			if _, err := path.Match(value, ""); err != nil {
+				return nil, fmt.Errorf("invalid pattern %q", value)
+			}
+		}
+		policy = append(policy, &ArtifactRule{Pattern: pattern, Assets: assets})
+	}
+	sort.Slice(policy, func(i, j int) bool {
+		iPattern, jPattern := isPattern(policy[i].Pattern), isPattern(policy[j].Pattern)
+		if iPattern != jPattern {
+			return iPattern
+		}
+		return policy[i].Pattern < policy[j].Pattern
+	})
+
+	return policy, nil
+}
+
+// Expected returns the patterns of the assets expected in the releases of the repository.
+func (p ArtifactPolicy) Expected(repositoryFullName string) []string {
+	var expected []string
+	for _, rule := range p {
+		if matched, _ := path.Match(rule.Pattern, repositoryFullName); !matched {
+			continue
+		}
+		for _, asset := range rule.Assets {
+			if !slices.Contains(expected, asset) {
+				expected = append(expected, asset)
+			}
+		}
+	}
+
+	return expected
+}
+
+// MissingAssets returns the patterns of the expected assets of the repository no asset of the release matches.
+func (p ArtifactPolicy) MissingAssets(repositoryFullName string, release *Release) []string {
+	var missing []string
+	for _, pattern := range p.Expected(repositoryFullName) {
+		found := slices.ContainsFunc(release.Assets, func(asset *Asset) bool {
+			matched, _ := path.Match(pattern, asset.Name)
+			return matched
+		})
+		if !found {
+			missing = append(missing, pattern)
+		}
+	}
+
+	return missing
+}
@@ -0,0 +1,35 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadArtifactPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "artifacts.json")
+	content := `{"acme/cli": ["*_linux_amd64.tar.gz", "checksums.txt"], "acme/*": ["checksums.txt"]}`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	policy, err := LoadArtifactPolicy(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"checksums.txt", "*_linux_amd64.tar.gz"}; !equal(policy.Expected("acme/cli"), expected) {
+		t.Fatalf("invalid expected assets of acme/cli, expected %v, got %v", expected, policy.Expected("acme/cli"))
+	}
+	if expected := policy.Expected("other/cli"); expected != nil {
+		t.Fatalf("invalid expected assets of other/cli, expected none, got %v", expected)
+	}
+
+	release := &Release{TagName: "v1.0.0", Assets: []*Asset{{Name: "cli_1.0.0_linux_amd64.tar.gz"}}}
+	if missing := policy.MissingAssets("acme/cli", release); !equal(missing, []string{"checksums.txt"}) {
+		t.Fatalf("invalid missing assets, expected [checksums.txt], got %v", missing)
+	}
+
+	if _, err := parseArtifactPolicy([]byte(`{"acme/cli": ["[linux"]}`)); err == nil {
+		t.Fatalf("invalid result of the malformed asset pattern, expected an error")
+	}
+}
@@ -0,0 +1,114 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// releasesBatchSize is the count of repositories whose releases are listed with one ListReleasesBatch call. Batch
+// listers split the batches further to fit their own limits.
+const releasesBatchSize = 50
+
+// errReleasesBatchFailed is returned for the failed repositories of a batch but the one the failure of the batch
+// is reported for, so it is reported once.
+var errReleasesBatchFailed = errors.New("releases batch failed")
+
+// releaseBatches looks up the releases of the repositories of a scan listed in batches. A batch is fetched by the
+// first worker scanning one of its repositories while the workers of the other ones wait for it, so the items of
+// the first batches are emitted before the releases of the whole account are listed.
+type releaseBatches struct {
+	provider Provider
+	// list lists the releases of the batch with the backends they are listed with, keyed by full names.
+	list    func(ctx context.Context, repositories []*Repository) (map[string][]*Release, map[string]string, error)
+	batches map[*Repository]*releaseBatch
+}
+
+type releaseBatch struct {
+	repositories []*Repository
+
+	mu       sync.Mutex
+	fetching bool
+	reported bool
+	done     chan struct{}
+	releases map[string][]*Release
+	sources  map[string]string
+	err      error
+}
+
+// newReleaseBatches splits the repositories into batches in their order, nil if the provider does not list
+// releases in batches. Repositories are left out if their releases are not listed, e.g. the unchanged ones or the
+// ones recorded in the checkpoint.
+func newReleaseBatches(provider Provider, repositories []*Repository, listed func(repository *Repository) bool) *releaseBatches {
+	batches := &releaseBatches{provider: provider, batches: make(map[*Repository]*releaseBatch)}
+	switch lister := provider.(type) {
+	case *ChainProvider:
+		if !lister.listsReleasesInBatches() {
+			return nil
+		}
+		batches.list = lister.listReleasesBatchWithSource
+	case BatchReleasesLister:
+		batches.list = func(ctx context.Context, repositories []*Repository) (map[string][]*Release, map[string]string, error) {
+			releases, err := lister.ListReleasesBatch(ctx, repositories)
+			return releases, nil, err
+		}
+	default:
+		return nil
+	}
+
+	var batch *releaseBatch
+	for _, repository := range repositories {
+		if !listed(repository) {
+			continue
+		}
+		if batch == nil || len(batch.repositories) == releasesBatchSize {
+			batch = &releaseBatch{done: make(chan struct{})}
+		}
+		batch.repositories = append(batch.repositories, repository)
+		batches.batches[repository] = batch
+	}
+
+	return batches
+}
+
+// releases returns the releases of the repository of the account and the backend of a chain they are listed
+// with, fetching its batch if no other worker fetches it yet. Releases of repositories left out of the batches are
+// listed alone.
+func (b *releaseBatches) releases(ctx context.Context, user string, repository *Repository) ([]*Release, string, error) {
+	batch := b.batches[repository]
+	if batch == nil {
+		if chain, ok := b.provider.(*ChainProvider); ok {
+			return chain.listReleasesWithSource(ctx, repositoryOwner(repository, user), repository.Name)
+		}
+		releases, err := b.provider.ListReleases(ctx, repositoryOwner(repository, user), repository.Name)
+		return releases, "", err
+	}
+
+	batch.mu.Lock()
+	fetch := !batch.fetching
+	batch.fetching = true
+	batch.mu.Unlock()
+	if fetch {
+		batch.releases, batch.sources, batch.err = b.list(ctx, batch.repositories)
+		close(batch.done)
+	} else {
+		select {
+		case <-batch.done:
+		case <-ctx.Done():
+			return nil, "", ctx.Err()
+		}
+	}
+
+	releases, ok := batch.releases[repository.FullName]
+	if ok || batch.err == nil {
+		return releases, batch.sources[repository.FullName], nil
+	}
+	batch.mu.Lock()
+	defer batch.mu.Unlock()
+	if batch.reported {
+		return nil, "", errReleasesBatchFailed
+	}
+	batch.reported = true
+
+	return nil, "", batch.err
+}
@@ -0,0 +1,99 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeBatchProvider lists releases of repositories in batches, the missing repository fails.
+type fakeBatchProvider struct {
+	repositories []*Repository
+	missing      string
+
+	mu      sync.Mutex
+	batches []int
+}
+
+func (p *fakeBatchProvider) ListRepositories(ctx context.Context, account string) ([]*Repository, error) {
+	return p.repositories, nil
+}
+
+func (p *fakeBatchProvider) ListReleases(ctx context.Context, owner, repository string) ([]*Release, error) {
+	return nil, errors.New("releases must be listed in batches")
+}
+
+func (p *fakeBatchProvider) ListReleasesBatch(ctx context.Context, repositories []*Repository) (map[string][]*Release, error) {
+	p.mu.Lock()
+	p.batches = append(p.batches, len(repositories))
+	p.mu.Unlock()
+
+	releases := make(map[string][]*Release, len(repositories))
+	var err error
+	for _, repository := range repositories {
+		if repository.FullName == p.missing {
+			err = fmt.Errorf("could not get releases for the repository %s: %w", repository.FullName, newNotFoundError("repository %s does not exist", repository.FullName))
+			continue
+		}
+		releases[repository.FullName] = []*Release{{TagName: "v1.0.0"}}
+	}
+
+	return releases, err
+}
+
+func TestScanRepositoriesInBatches(t *testing.T) {
+	provider := &fakeBatchProvider{}
+	for i := 0; i < 2*releasesBatchSize+10; i++ {
+		provider.repositories = append(provider.repositories, &Repository{FullName: fmt.Sprintf("test/repo%03d", i), Name: fmt.Sprintf("repo%03d", i)})
+	}
+	checkpoint, err := OpenCheckpoint(filepath.Join(t.TempDir(), "scan.checkpoint"), "test", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer checkpoint.Close()
+	// The recorded repositories are resumed without listing their releases.
+	for _, repository := range provider.repositories[:releasesBatchSize] {
+		if err := checkpoint.AddItem("StreamRepositories:test", &ResultItem{Repository: repository}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scanner := Scanner{Provider: provider, Checkpoint: checkpoint}
+	var emitted []string
+	err = scanner.StreamRepositories("test", func(item *ResultItem) error {
+		emitted = append(emitted, item.Repository.FullName)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(emitted) != len(provider.repositories) {
+		t.Fatalf("invalid emitted items count, expected %d, got %d", len(provider.repositories), len(emitted))
+	}
+	if fmt.Sprint(provider.batches) != fmt.Sprintf("[%d %d]", releasesBatchSize, 10) {
+		t.Fatalf("invalid batches, got %v", provider.batches)
+	}
+	if item := checkpoint.Item("StreamRepositories:test", provider.repositories[len(provider.repositories)-1].FullName); item == nil {
+		t.Fatal("items scanned in batches are not recorded in the checkpoint")
+	}
+}
+
+func TestScanRepositoriesInBatchesFailure(t *testing.T) {
+	provider := &fakeBatchProvider{missing: "test/repo3"}
+	for i := 0; i < 10; i++ {
+		provider.repositories = append(provider.repositories, &Repository{FullName: fmt.Sprintf("test/repo%d", i), Name: fmt.Sprintf("repo%d", i)})
+	}
+
+	scanner := Scanner{Provider: provider}
+	_, err := scanner.ScanRepositories("test")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("invalid error of the failed batch, expected %v, got %v", ErrNotFound, err)
+	}
+	if count := strings.Count(err.Error(), "does not exist"); count != 1 {
+		t.Fatalf("failure of the batch must be reported once, got %d times: %v", count, err)
+	}
+}
@@ -0,0 +1,139 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+)
+
+type Branch struct {
+	Name      string `json:"name"`
+	Protected bool   `json:"protected"`
+	Commit    struct {
+		SHA string `json:"sha"`
+	} `json:"commit"`
+	// UpdatedAt is the commit date of the branch head.
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// StaleBranch is a branch not updated for longer than the allowed age.
+type StaleBranch struct {
+	Repository string    `json:"repository"`
+	Branch     string    `json:"branch"`
+	Default    bool      `json:"default"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// GetAllBranches returns branches of the repository with the commit dates of their heads.
+// The dates take a request per branch.
+func (s *Scanner) GetAllBranches(user, repository string) ([]*Branch, error) {
+	return s.getAllBranches(context.Background(), user, repository)
+}
+
+func (s *Scanner) getAllBranches(ctx context.Context, user, repository string) ([]*Branch, error) {
+	branches, err := paginate(ctx, func(ctx context.Context, page int) ([]*Branch, pageLinks, error) {
+		return s.getBranchesPerPage(ctx, user, repository, page)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, branch := range branches {
+		updatedAt, err := s.getCommitDate(ctx, user, repository, branch.Commit.SHA)
+		if err != nil {
+			return nil, err
+		}
+		branch.UpdatedAt = updatedAt
+	}
+
+	return branches, nil
+}
+
+func (s *Scanner) getBranchesPerPage(ctx context.Context, user, repository string, page int) ([]*Branch, pageLinks, error) {
+	if err := s.checkPage(page); err != nil {
+		return nil, pageLinks{}, err
+	}
+	if err := s.checkUser(user); err != nil {
+		return nil, pageLinks{}, err
+	}
+	if err := s.checkRepository(repository); err != nil {
+		return nil, pageLinks{}, err
+	}
+	ctx, span := s.getTracer().Start(ctx, "GetBranchesPerPage", StringAttribute("account", user), StringAttribute("repository", repository), IntAttribute("page", page))
+	defer span.End()
+
+	response, err := s.get(ctx, span, fmt.Sprintf("%s/repos/%s/%s/branches?per_page=%d&page=%d", s.BaseUrl, user, repository, s.getPerPage(), page))
+	if err != nil {
+		return nil, pageLinks{}, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, pageLinks{}, fmt.Errorf("could not get branches for the repository %s: %w", repository, s.newApiError(response))
+	}
+
+	var branches []*Branch
+	if err := json.NewDecoder(response.Body).Decode(&branches); err != nil {
+		return nil, pageLinks{}, err
+	}
+
+	return branches, parsePageLinks(response.Header), nil
+}
+
+func (s *Scanner) getCommitDate(ctx context.Context, user, repository, sha string) (*time.Time, error) {
+	ctx, span := s.getTracer().Start(ctx, "GetCommit", StringAttribute("account", user), StringAttribute("repository", repository), StringAttribute("sha", sha))
+	defer span.End()
+
+	response, err := s.get(ctx, span, fmt.Sprintf("%s/repos/%s/%s/commits/%s", s.BaseUrl, user, repository, sha))
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not get commit %s of the repository %s: %w", sha, repository, s.newApiError(response))
+	}
+
+	var commit struct {
+		Commit struct {
+			Committer struct {
+				Date *time.Time `json:"date"`
+			} `json:"committer"`
+		} `json:"commit"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&commit); err != nil {
+		return nil, err
+	}
+
+	return commit.Commit.Committer.Date, nil
+}
+
+// StaleBranches returns the scanned branches not updated since the time, ordered by repository and branch.
+// Default branches are flagged, a stale default branch usually means an abandoned repository.
+func StaleBranches(items []*ResultItem, since time.Time) []*StaleBranch {
+	var stale []*StaleBranch
+	for _, item := range items {
+		for _, branch := range item.Branches {
+			if branch.UpdatedAt == nil || !branch.UpdatedAt.Before(since) {
+				continue
+			}
+			stale = append(stale, &StaleBranch{
+				Repository: item.Repository.FullName,
+				Branch:     branch.Name,
+				Default:    branch.Name == item.Repository.DefaultBranch,
+				UpdatedAt:  *branch.UpdatedAt,
+			})
+		}
+	}
+	sort.Slice(stale, func(i, j int) bool {
+		if stale[i].Repository != stale[j].Repository {
+			return stale[i].Repository < stale[j].Repository
+		}
+		return stale[i].Branch < stale[j].Branch
+	})
+
+	return stale
+}
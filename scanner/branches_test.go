@@ -0,0 +1,60 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetAllBranches(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/test/test/branches":
+			w.Write([]byte(`[{"name": "main", "protected": true, "commit": {"sha": "a1"}}, {"name": "feature", "commit": {"sha": "b2"}}]`))
+		case "/repos/test/test/commits/a1":
+			w.Write([]byte(`{"commit": {"committer": {"date": "2024-03-01T10:00:00Z"}}}`))
+		case "/repos/test/test/commits/b2":
+			w.Write([]byte(`{"commit": {"committer": {"date": "2023-01-01T10:00:00Z"}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL, PerPage: 100}
+	branches, err := scanner.GetAllBranches("test", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(branches) != 2 {
+		t.Fatalf("invalid branches count, expected 2, got %d", len(branches))
+	}
+	if !branches[0].Protected || branches[0].UpdatedAt == nil || branches[0].UpdatedAt.Format(time.DateOnly) != "2024-03-01" {
+		t.Fatalf("invalid main branch, expected protected and updated on 2024-03-01, got %v and %v", branches[0].Protected, branches[0].UpdatedAt)
+	}
+}
+
+func TestStaleBranches(t *testing.T) {
+	old := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	recent := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	items := []*ResultItem{{
+		Repository: &Repository{FullName: "test/test", DefaultBranch: "main"},
+		Branches: []*Branch{
+			{Name: "main", UpdatedAt: &old},
+			{Name: "feature", UpdatedAt: &recent},
+			{Name: "abandoned", UpdatedAt: &old},
+		},
+	}}
+
+	stale := StaleBranches(items, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	if len(stale) != 2 {
+		t.Fatalf("invalid stale branches count, expected 2, got %d", len(stale))
+	}
+	if stale[0].Branch != "abandoned" || stale[0].Default {
+		t.Fatalf("invalid first stale branch, expected non-default abandoned, got %s (default %v)", stale[0].Branch, stale[0].Default)
+	}
+	if stale[1].Branch != "main" || !stale[1].Default {
+		t.Fatalf("invalid second stale branch, expected default main, got %s (default %v)", stale[1].Branch, stale[1].Default)
+	}
+}
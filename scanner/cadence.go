@@ -0,0 +1,79 @@
+package scanner
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+// ReleaseCadence is the release frequency of a repository computed from its published releases.
+type ReleaseCadence struct {
+	Releases int `json:"releases"`
+	// MeanIntervalDays is the mean number of days between consecutive releases, zero with less than two releases.
+	MeanIntervalDays float64 `json:"mean_interval_days"`
+	// Quarters are the release counts per calendar quarter, oldest first. Quarters without releases are included
+	// between the first and the last release, so gaps are visible.
+	Quarters []*QuarterReleases `json:"quarters,omitempty"`
+	// DaysSinceLast is the number of days since the last release, -1 if there are no published releases.
+	DaysSinceLast int `json:"days_since_last"`
+}
+
+// QuarterReleases is the release count of a calendar quarter, e.g. "2024-Q1".
+type QuarterReleases struct {
+	Quarter  string `json:"quarter"`
+	Releases int    `json:"releases"`
+}
+
+// NewReleaseCadence computes the cadence of the releases as of now. Drafts are ignored, as they are not published.
+func NewReleaseCadence(releases []*Release, now time.Time) *ReleaseCadence {
+	var dates []time.Time
+	for _, release := range releases {
+		if release.PublishedAt != nil {
+			dates = append(dates, release.PublishedAt.UTC())
+		}
+	}
+	cadence := &ReleaseCadence{Releases: len(dates), DaysSinceLast: -1}
+	if len(dates) == 0 {
+		return cadence
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	first, last := dates[0], dates[len(dates)-1]
+	if len(dates) > 1 {
+		cadence.MeanIntervalDays = last.Sub(first).Hours() / 24 / float64(len(dates)-1)
+	}
+	cadence.DaysSinceLast = int(now.Sub(last).Hours() / 24)
+
+	counts := make(map[string]int)
+	for _, date := range dates {
+		counts[quarterOf(date)]++
+	}
+	quarterStart := time.Date(first.Year(), (first.Month()-1)/3*3+1, 1, 0, 0, 0, 0, time.UTC)
+	for !quarterStart.After(last) {
+		quarter := quarterOf(quarterStart)
+		cadence.Quarters = append(cadence.Quarters, &QuarterReleases{Quarter: quarter, Releases: counts[quarter]})
+		quarterStart = quarterStart.AddDate(0, 3, 0)
+	}
+
+	return cadence
+}
+
+// SetReleaseCadences computes the release cadence of every item as of now. Releases of items with an assigned
+// version scheme are only counted if their versions follow it, so e.g. a rolling "nightly" release republished
+// every day does not hide a project that stopped shipping versions.
+func SetReleaseCadences(items []*ResultItem, now time.Time) {
+	for _, item := range items {
+		releases := item.Releases
+		if scheme := assignedVersionScheme(item); scheme != nil {
+			releases = slices.DeleteFunc(slices.Clone(releases), func(release *Release) bool {
+				return !followsScheme(scheme, release.Version())
+			})
+		}
+		item.Cadence = NewReleaseCadence(releases, now)
+	}
+}
+
+func quarterOf(date time.Time) string {
+	return fmt.Sprintf("%d-Q%d", date.Year(), (int(date.Month())-1)/3+1)
+}
@@ -0,0 +1,68 @@
+package scanner
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewReleaseCadence(t *testing.T) {
+	date := func(year int, month time.Month, day int) *time.Time {
+		d := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+	releases := []*Release{
+		{TagName: "v1.2.0", PublishedAt: date(2024, 1, 31)},
+		{TagName: "v1.3.0", Draft: true},
+		{TagName: "v1.0.0", PublishedAt: date(2023, 7, 1)},
+		{TagName: "v1.1.0", PublishedAt: date(2023, 8, 16)},
+	}
+
+	cadence := NewReleaseCadence(releases, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	if cadence.Releases != 3 {
+		t.Fatalf("invalid releases count, expected 3, got %d", cadence.Releases)
+	}
+	if cadence.MeanIntervalDays != 107 {
+		t.Fatalf("invalid mean interval, expected 107, got %v", cadence.MeanIntervalDays)
+	}
+	if cadence.DaysSinceLast != 30 {
+		t.Fatalf("invalid days since the last release, expected 30, got %d", cadence.DaysSinceLast)
+	}
+	expected := []QuarterReleases{{"2023-Q3", 2}, {"2023-Q4", 0}, {"2024-Q1", 1}}
+	if len(cadence.Quarters) != len(expected) {
+		t.Fatalf("invalid quarters, expected %v, got %d quarters", expected, len(cadence.Quarters))
+	}
+	for i, quarter := range cadence.Quarters {
+		if *quarter != expected[i] {
+			t.Fatalf("invalid quarter, expected %v, got %v", expected[i], *quarter)
+		}
+	}
+
+	cadence = NewReleaseCadence([]*Release{{TagName: "v0.1.0", Draft: true}}, time.Now())
+	if cadence.Releases != 0 || cadence.DaysSinceLast != -1 || cadence.Quarters != nil {
+		t.Fatalf("invalid cadence without published releases, got %+v", cadence)
+	}
+}
+
+func TestSetReleaseCadencesVersionScheme(t *testing.T) {
+	old := time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC)
+	recent := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+	items := []*ResultItem{
+		{
+			Repository:    &Repository{FullName: "test/calver"},
+			Releases:      []*Release{{TagName: "nightly", PublishedAt: &recent}, {TagName: "2023.1.10", PublishedAt: &old}},
+			VersionScheme: "calver",
+		},
+		{
+			Repository: &Repository{FullName: "test/default"},
+			Releases:   []*Release{{TagName: "nightly", PublishedAt: &recent}},
+		},
+	}
+	SetReleaseCadences(items, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	if cadence := items[0].Cadence; cadence.Releases != 1 || cadence.DaysSinceLast != 416 {
+		t.Fatalf("invalid cadence of the calver releases, expected 1 release 416 days ago, got %+v", cadence)
+	}
+	if cadence := items[1].Cadence; cadence.Releases != 1 || cadence.DaysSinceLast != 1 {
+		t.Fatalf("invalid cadence without a version scheme, expected 1 release 1 day ago, got %+v", cadence)
+	}
+}
@@ -0,0 +1,187 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Backend is a named provider in a fallback chain.
+type Backend struct {
+	Name     string
+	Provider Provider
+}
+
+// ChainProvider tries its backends in order, e.g. GraphQL, REST and then stored snapshots,
+// falling back to the next one when a backend fails, for instance because of a missing token scope.
+// The backend that returned releases of a repository is recorded in ResultItem.Source.
+type ChainProvider struct {
+	Backends []Backend
+}
+
+func (c *ChainProvider) ListRepositories(ctx context.Context, account string) ([]*Repository, error) {
+	var errs []error
+	for _, backend := range c.Backends {
+		repositories, err := backend.Provider.ListRepositories(ctx, account)
+		if err == nil {
+			return repositories, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", backend.Name, err))
+	}
+
+	return nil, c.chainError(errs)
+}
+
+func (c *ChainProvider) ListReleases(ctx context.Context, owner, repository string) ([]*Release, error) {
+	releases, _, err := c.listReleasesWithSource(ctx, owner, repository)
+
+	return releases, err
+}
+
+func (c *ChainProvider) listReleasesWithSource(ctx context.Context, owner, repository string) ([]*Release, string, error) {
+	return c.listReleasesFallback(ctx, owner, repository, -1, nil)
+}
+
+// ListReleasesBatch lists the releases with the first backend listing releases in batches. Releases of the
+// repositories it fails for are listed one by one with the other backends.
+func (c *ChainProvider) ListReleasesBatch(ctx context.Context, repositories []*Repository) (map[string][]*Release, error) {
+	releases, _, err := c.listReleasesBatchWithSource(ctx, repositories)
+
+	return releases, err
+}
+
+// listsReleasesInBatches reports whether a backend lists releases in batches.
+func (c *ChainProvider) listsReleasesInBatches() bool {
+	return c.batchBackend() >= 0
+}
+
+// batchBackend returns the index of the first backend listing releases in batches, -1 if there is none.
+func (c *ChainProvider) batchBackend() int {
+	return slices.IndexFunc(c.Backends, func(backend Backend) bool {
+		if chain, ok := backend.Provider.(*ChainProvider); ok {
+			return chain.listsReleasesInBatches()
+		}
+		_, ok := backend.Provider.(BatchReleasesLister)
+		return ok
+	})
+}
+
+func (c *ChainProvider) listReleasesBatchWithSource(ctx context.Context, repositories []*Repository) (map[string][]*Release, map[string]string, error) {
+	releases := make(map[string][]*Release, len(repositories))
+	sources := make(map[string]string, len(repositories))
+	index := c.batchBackend()
+	var batchErr error
+	if index >= 0 {
+		backend := c.Backends[index]
+		var batchReleases map[string][]*Release
+		batchReleases, batchErr = backend.Provider.(BatchReleasesLister).ListReleasesBatch(ctx, repositories)
+		for fullName, repositoryReleases := range batchReleases {
+			releases[fullName], sources[fullName] = repositoryReleases, backend.Name
+		}
+		if batchErr == nil {
+			return releases, sources, nil
+		}
+		if ctx.Err() != nil {
+			return releases, sources, ctx.Err()
+		}
+		batchErr = fmt.Errorf("%s: %w", backend.Name, batchErr)
+	}
+
+	var errs []error
+	for _, repository := range repositories {
+		if _, ok := releases[repository.FullName]; ok {
+			continue
+		}
+		owner, name := splitFullName(repository.FullName)
+		var batchErrs []error
+		if batchErr != nil {
+			batchErrs = []error{batchErr}
+		}
+		repositoryReleases, source, err := c.listReleasesFallback(ctx, owner, name, index, batchErrs)
+		if err != nil {
+			if ctx.Err() != nil {
+				return releases, sources, ctx.Err()
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", repository.FullName, err))
+			continue
+		}
+		releases[repository.FullName], sources[repository.FullName] = repositoryReleases, source
+	}
+
+	return releases, sources, errors.Join(errs...)
+}
+
+// listReleasesFallback lists the releases with the backends in order but the skipped one. The errors of the
+// backends tried before are reported with the ones of the backends failing now.
+func (c *ChainProvider) listReleasesFallback(ctx context.Context, owner, repository string, skip int, errs []error) ([]*Release, string, error) {
+	for i, backend := range c.Backends {
+		if i == skip {
+			continue
+		}
+		releases, err := backend.Provider.ListReleases(ctx, owner, repository)
+		if err == nil {
+			return releases, backend.Name, nil
+		}
+		if ctx.Err() != nil {
+			return nil, "", ctx.Err()
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", backend.Name, err))
+	}
+
+	return nil, "", c.chainError(errs)
+}
+
+// chainError wraps the errors of all backends, so the failure classes like ErrRateLimited are still matched by
+// errors.Is.
+func (c *ChainProvider) chainError(errs []error) error {
+	if len(errs) == 0 {
+		return errors.New("no backends are configured")
+	}
+	format := "all backends failed: " + strings.Repeat("; %w", len(errs))[2:]
+	args := make([]any, 0, len(errs))
+	for _, err := range errs {
+		args = append(args, err)
+	}
+
+	return fmt.Errorf(format, args...)
+}
+
+// SnapshotProvider serves repositories and releases from a stored snapshot, e.g. as the last backend of a chain.
+// Snapshots of multi-account or group scans serve the repositories owned by every account of the scan.
+type SnapshotProvider struct {
+	Snapshot *Snapshot
+}
+
+func (p *SnapshotProvider) ListRepositories(ctx context.Context, account string) ([]*Repository, error) {
+	accounts := strings.Split(p.Snapshot.Account, ",")
+	single := len(accounts) == 1 && strings.EqualFold(accounts[0], account)
+
+	repositories := make([]*Repository, 0, len(p.Snapshot.Items))
+	for _, item := range p.Snapshot.Items {
+		owner, _, _ := strings.Cut(item.Repository.FullName, "/")
+		if single || strings.EqualFold(owner, account) {
+			repositories = append(repositories, item.Repository)
+		}
+	}
+	if len(repositories) == 0 && !slices.ContainsFunc(accounts, func(a string) bool { return strings.EqualFold(a, account) }) {
+		return nil, fmt.Errorf("snapshot of the account %s is not found", account)
+	}
+
+	return repositories, nil
+}
+
+func (p *SnapshotProvider) ListReleases(ctx context.Context, owner, repository string) ([]*Release, error) {
+	fullName := owner + "/" + repository
+	for _, item := range p.Snapshot.Items {
+		if strings.EqualFold(item.Repository.FullName, fullName) {
+			return item.Releases, nil
+		}
+	}
+
+	return nil, fmt.Errorf("repository %s is not found in the snapshot", fullName)
+}
@@ -0,0 +1,147 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestChainProviderFallback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/graphql":
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message": "Bad credentials"}`))
+		case "/users/test/repos":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"full_name": "test/live", "name": "live"}, {"full_name": "test/cached", "name": "cached"}]`))
+		case "/repos/test/live/releases":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"tag_name": "v2.0.0"}]`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message": "forbidden"}`))
+		}
+	}))
+	defer server.Close()
+
+	rest := &Scanner{BaseUrl: server.URL}
+	snapshot := &Snapshot{
+		Account: "test",
+		Items: []*ResultItem{{
+			Repository: &Repository{FullName: "test/cached", Name: "cached"},
+			Releases:   []*Release{{TagName: "v1.0.0"}},
+		}},
+	}
+	scanner := Scanner{
+		BaseUrl: server.URL,
+		Provider: &ChainProvider{Backends: []Backend{
+			{Name: "graphql", Provider: &GraphQLProvider{Endpoint: server.URL + "/graphql"}},
+			{Name: "rest", Provider: rest},
+			{Name: "cache", Provider: &SnapshotProvider{Snapshot: snapshot}},
+		}},
+	}
+	items, err := scanner.ScanRepositories("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 2 {
+		t.Fatalf("invalid scanned repositories items count, expected 2, got %d", len(items))
+	}
+	expected := map[string]string{"test/cached": "cache", "test/live": "rest"}
+	for _, item := range items {
+		if item.Source != expected[item.Repository.FullName] {
+			t.Errorf("invalid source of the repository %s, expected %s, got %s", item.Repository.FullName, expected[item.Repository.FullName], item.Source)
+		}
+		if len(item.Releases) != 1 {
+			t.Errorf("invalid releases count of the repository %s, expected 1, got %d", item.Repository.FullName, len(item.Releases))
+		}
+	}
+}
+
+func TestChainProviderErrorClass(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message": "API rate limit exceeded"}`))
+	}))
+	defer server.Close()
+
+	chain := &ChainProvider{Backends: []Backend{
+		{Name: "graphql", Provider: &GraphQLProvider{Endpoint: server.URL + "/graphql"}},
+		{Name: "rest", Provider: &Scanner{BaseUrl: server.URL}},
+	}}
+	_, err := chain.ListRepositories(context.Background(), "test")
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("invalid error of the failed backends, expected %v, got %v", ErrRateLimited, err)
+	}
+	if !strings.HasPrefix(err.Error(), "all backends failed: graphql: ") || !strings.Contains(err.Error(), "; rest: ") {
+		t.Fatalf("invalid error message of the failed backends, got %s", err)
+	}
+}
+
+func TestSnapshotProviderAccounts(t *testing.T) {
+	snapshot := &Snapshot{
+		Account: "a,b",
+		Items: []*ResultItem{
+			{Repository: &Repository{FullName: "a/one", Name: "one"}},
+			{Repository: &Repository{FullName: "b/two", Name: "two"}},
+		},
+	}
+	provider := &SnapshotProvider{Snapshot: snapshot}
+	repositories, err := provider.ListRepositories(context.Background(), "B")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repositories) != 1 || repositories[0].FullName != "b/two" {
+		t.Fatalf("invalid repositories of the account, expected b/two, got %v", repositories)
+	}
+	if _, err := provider.ListRepositories(context.Background(), "c"); err == nil {
+		t.Fatalf("invalid result of an account missing in the snapshot, expected an error")
+	}
+}
+
+func TestChainProviderBatches(t *testing.T) {
+	batch := &fakeBatchProvider{missing: "test/repo3"}
+	for i := 0; i < 5; i++ {
+		batch.repositories = append(batch.repositories, &Repository{FullName: fmt.Sprintf("test/repo%d", i), Name: fmt.Sprintf("repo%d", i)})
+	}
+	snapshot := &Snapshot{
+		Account: "test",
+		Items: []*ResultItem{{
+			Repository: &Repository{FullName: "test/repo3", Name: "repo3"},
+			Releases:   []*Release{{TagName: "v0.1.0"}},
+		}},
+	}
+	scanner := Scanner{
+		Provider: &ChainProvider{Backends: []Backend{
+			{Name: "graphql", Provider: batch},
+			{Name: "cache", Provider: &SnapshotProvider{Snapshot: snapshot}},
+		}},
+	}
+	items, err := scanner.ScanRepositories("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fmt.Sprint(batch.batches) != "[5]" {
+		t.Fatalf("releases of the chain are not listed in a batch: %v", batch.batches)
+	}
+	if len(items) != 5 {
+		t.Fatalf("invalid scanned repositories items count, expected 5, got %d", len(items))
+	}
+	for _, item := range items {
+		expected := "graphql"
+		if item.Repository.FullName == "test/repo3" {
+			expected = "cache"
+		}
+		if item.Source != expected || len(item.Releases) != 1 {
+			t.Errorf("invalid item of the repository %s: source %s, %d releases", item.Repository.FullName, item.Source, len(item.Releases))
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Commit is a commit of the compare API response.
+type Commit struct {
+	SHA string `json:"sha"`
+	// Author is the account the commit is linked to by its email, nil if there is none.
+	Author *Member `json:"author,omitempty"`
+	Commit struct {
+		Message string `json:"message"`
+		Author  struct {
+			Name string     `json:"name"`
+			Date *time.Time `json:"date"`
+		} `json:"author"`
+	} `json:"commit"`
+}
+
+// Title returns the first line of the commit message.
+func (c *Commit) Title() string {
+	title, _, _ := strings.Cut(c.Commit.Message, "\n")
+
+	return title
+}
+
+// Changelog is the history of a repository between two versions.
+type Changelog struct {
+	Repository string
+	From       string
+	To         string
+	// Releases are the releases after From up to To, newest first.
+	Releases []*Release
+	// Commits are the commits between From and To, oldest first. They are only fetched if no release in the range
+	// has release notes.
+	Commits []*Commit
+}
+
+// GetChangelog returns the releases of the repository newer than the from version up to the to version according
+// to the repository version scheme. If none of them has release notes, the commits between the two tags are
+// fetched from the compare API instead. Drafts are ignored.
+func (s *Scanner) GetChangelog(user, repository, from, to string) (*Changelog, error) {
+	return s.getChangelog(context.Background(), user, repository, from, to)
+}
+
+func (s *Scanner) getChangelog(ctx context.Context, user, repository, from, to string) (*Changelog, error) {
+	scheme := s.GetVersionScheme(user + "/" + repository)
+	for _, version := range []string{from, to} {
+		if _, err := scheme.Compare(version, version); err != nil {
+			return nil, err
+		}
+	}
+	if result, _ := scheme.Compare(from, to); result >= 0 {
+		return nil, fmt.Errorf("version %s is not older than %s", from, to)
+	}
+
+	releases, err := s.getAllReleases(ctx, user, repository)
+	if err != nil {
+		return nil, err
+	}
+	changelog := &Changelog{Repository: user + "/" + repository, From: from, To: to}
+	withNotes := false
+	for _, release := range releases {
+		if release.Draft {
+			continue
+		}
+		afterFrom, err := scheme.Compare(release.Version(), from)
+		if err != nil || afterFrom <= 0 {
+			continue
+		}
+		if beforeTo, _ := scheme.Compare(release.Version(), to); beforeTo > 0 {
+			continue
+		}
+		changelog.Releases = append(changelog.Releases, release)
+		withNotes = withNotes || strings.TrimSpace(release.Body) != ""
+	}
+	sort.SliceStable(changelog.Releases, func(i, j int) bool {
+		result, _ := scheme.Compare(changelog.Releases[i].Version(), changelog.Releases[j].Version())
+		return result > 0
+	})
+
+	if !withNotes {
+		if changelog.Commits, err = s.getCompareCommits(ctx, user, repository, from, to); err != nil {
+			return nil, err
+		}
+	}
+
+	return changelog, nil
+}
+
+// GetCompareCommits returns the commits reachable from the head but not from the base, oldest first.
+// The compare API returns at most 250 commits.
+func (s *Scanner) GetCompareCommits(user, repository, base, head string) ([]*Commit, error) {
+	return s.getCompareCommits(context.Background(), user, repository, base, head)
+}
+
+func (s *Scanner) getCompareCommits(ctx context.Context, user, repository, base, head string) ([]*Commit, error) {
+	comparison, err := s.getComparison(ctx, user, repository, base, head)
+	if err != nil {
+		return nil, err
+	}
+
+	return comparison.Commits, nil
+}
+
+// Comparison is the compare API response: how far the head is ahead of and behind the base.
+type Comparison struct {
+	Status       string         `json:"status"`
+	AheadBy      int            `json:"ahead_by"`
+	BehindBy     int            `json:"behind_by"`
+	TotalCommits int            `json:"total_commits"`
+	HTMLURL      string         `json:"html_url"`
+	Commits      []*Commit      `json:"commits"`
+	Files        []*ChangedFile `json:"files"`
+}
+
+// ChangedFile is a file changed between the compared refs.
+type ChangedFile struct {
+	Filename         string `json:"filename"`
+	PreviousFilename string `json:"previous_filename,omitempty"`
+	// Status is added, removed, modified, renamed, copied, changed or unchanged.
+	Status    string `json:"status"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+}
+
+// CommitAuthor is an author of the commits between two releases.
+type CommitAuthor struct {
+	// Login is the account of the author, empty if the commit email is not linked to an account.
+	Login   string `json:"login,omitempty"`
+	Name    string `json:"name"`
+	Commits int    `json:"commits"`
+}
+
+// ReleaseComparison is the difference between two releases of a repository.
+type ReleaseComparison struct {
+	Repository   string `json:"repository"`
+	Base         string `json:"base"`
+	Head         string `json:"head"`
+	Status       string `json:"status"`
+	AheadBy      int    `json:"ahead_by"`
+	BehindBy     int    `json:"behind_by"`
+	TotalCommits int    `json:"total_commits"`
+	URL          string `json:"html_url,omitempty"`
+	// Commits are the commits reachable from the head but not from the base, oldest first.
+	Commits []*Commit `json:"commits"`
+	// Contributors are the authors of the commits, the most commits first.
+	Contributors []*CommitAuthor `json:"contributors"`
+	// Files are the changed files, the API lists at most 300 of them.
+	Files     []*ChangedFile `json:"files"`
+	Additions int            `json:"additions"`
+	Deletions int            `json:"deletions"`
+}
+
+// CompareReleases compares two release tags of the repository, e.g. to prepare the review of an upgrade: the commits
+// between them with their authors and the changed files. All commits are fetched page by page, unlike
+// GetCompareCommits. The head could be any ref, e.g. a branch to compare the latest release with.
+func (s *Scanner) CompareReleases(owner, repository, base, head string) (*ReleaseComparison, error) {
+	return s.compareReleases(context.Background(), owner, repository, base, head)
+}
+
+func (s *Scanner) compareReleases(ctx context.Context, owner, repository, base, head string) (*ReleaseComparison, error) {
+	var first *Comparison
+	commits, err := paginate(ctx, func(ctx context.Context, page int) ([]*Commit, pageLinks, error) {
+		comparison, links, err := s.getComparisonPerPage(ctx, owner, repository, base, head, page)
+		if err != nil {
+			return nil, pageLinks{}, err
+		}
+		// files are only listed with the first page
+		if page == 1 {
+			first = comparison
+		}
+		return comparison.Commits, links, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReleaseComparison{
+		Repository:   owner + "/" + repository,
+		Base:         base,
+		Head:         head,
+		Status:       first.Status,
+		AheadBy:      first.AheadBy,
+		BehindBy:     first.BehindBy,
+		TotalCommits: first.TotalCommits,
+		URL:          first.HTMLURL,
+		Commits:      commits,
+		Contributors: commitAuthors(commits),
+		Files:        first.Files,
+	}
+	for _, file := range first.Files {
+		result.Additions += file.Additions
+		result.Deletions += file.Deletions
+	}
+
+	return result, nil
+}
+
+// commitAuthors counts the commits by their authors. Authors are identified by the account, by the name if the
+// commit is not linked to one.
+func commitAuthors(commits []*Commit) []*CommitAuthor {
+	var authors []*CommitAuthor
+	byKey := make(map[string]*CommitAuthor)
+	for _, commit := range commits {
+		key, login := "name:"+commit.Commit.Author.Name, ""
+		if commit.Author != nil && commit.Author.Login != "" {
+			key, login = "login:"+strings.ToLower(commit.Author.Login), commit.Author.Login
+		}
+		author := byKey[key]
+		if author == nil {
+			author = &CommitAuthor{Login: login, Name: commit.Commit.Author.Name}
+			byKey[key] = author
+			authors = append(authors, author)
+		}
+		author.Commits++
+	}
+	sort.SliceStable(authors, func(i, j int) bool {
+		return authors[i].Commits > authors[j].Commits
+	})
+
+	return authors
+}
+
+// getComparison compares the head with the base of the repository. The head may be in a fork, as "owner:branch".
+// The response lists at most 250 commits.
+func (s *Scanner) getComparison(ctx context.Context, user, repository, base, head string) (*Comparison, error) {
+	comparison, _, err := s.getComparisonPerPage(ctx, user, repository, base, head, 0)
+
+	return comparison, err
+}
+
+// getComparisonPerPage fetches a page of the commits of the comparison, the page 0 is the unpaginated response.
+func (s *Scanner) getComparisonPerPage(ctx context.Context, user, repository, base, head string, page int) (*Comparison, pageLinks, error) {
+	if page != 0 {
+		if err := s.checkPage(page); err != nil {
+			return nil, pageLinks{}, err
+		}
+	}
+	if err := s.checkUser(user); err != nil {
+		return nil, pageLinks{}, err
+	}
+	if err := s.checkRepository(repository); err != nil {
+		return nil, pageLinks{}, err
+	}
+	ctx, span := s.getTracer().Start(ctx, "GetComparison", StringAttribute("account", user), StringAttribute("repository", repository), IntAttribute("page", page))
+	defer span.End()
+
+	apiUrl := fmt.Sprintf("%s/repos/%s/%s/compare/%s...%s", s.BaseUrl, user, repository, url.PathEscape(base), url.PathEscape(head))
+	if page != 0 {
+		apiUrl += fmt.Sprintf("?per_page=%d&page=%d", s.getPerPage(), page)
+	}
+	response, err := s.get(ctx, span, apiUrl)
+	if err != nil {
+		return nil, pageLinks{}, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil, pageLinks{}, newNotFoundError("repository %s/%s or its tags %s, %s do not exist", user, repository, base, head)
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, pageLinks{}, fmt.Errorf("could not compare %s...%s of the repository %s/%s: %w", base, head, user, repository, s.newApiError(response))
+	}
+
+	var comparison Comparison
+	if err := json.NewDecoder(response.Body).Decode(&comparison); err != nil {
+		return nil, pageLinks{}, err
+	}
+
+	return &comparison, parsePageLinks(response.Header), nil
+}
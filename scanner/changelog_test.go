@@ -0,0 +1,118 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetChangelog(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/test/test/releases":
+			w.Write([]byte(`[
+				{"tag_name": "v1.5.0", "body": "* next"},
+				{"tag_name": "v1.4.0", "body": "* faster"},
+				{"tag_name": "v1.3.0", "draft": true, "body": "* draft"},
+				{"tag_name": "v1.2.0", "body": "* fixes"},
+				{"tag_name": "v1.0.0", "body": "* first"}
+			]`))
+		case "/repos/test/notes/releases":
+			w.Write([]byte(`[{"tag_name": "v2.0.0"}, {"tag_name": "v1.0.0"}]`))
+		case "/repos/test/notes/compare/v1.0.0...v2.0.0":
+			w.Write([]byte(`{"commits": [{"sha": "abc", "commit": {"message": "Add scans\n\nDetails"}}, {"sha": "def", "commit": {"message": "Fix scans"}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL, PerPage: 100}
+	changelog, err := scanner.GetChangelog("test", "test", "v1.0.0", "v1.4.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var versions []string
+	for _, release := range changelog.Releases {
+		versions = append(versions, release.Version())
+	}
+	if expected := []string{"v1.4.0", "v1.2.0"}; !equal(versions, expected) {
+		t.Fatalf("invalid changelog releases, expected %v, got %v", expected, versions)
+	}
+	if changelog.Commits != nil {
+		t.Fatalf("invalid changelog commits, expected none, got %v", changelog.Commits)
+	}
+
+	changelog, err = scanner.GetChangelog("test", "notes", "v1.0.0", "v2.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changelog.Commits) != 2 || changelog.Commits[0].Title() != "Add scans" {
+		t.Fatalf("invalid changelog commits, expected [Add scans, Fix scans], got %v", changelog.Commits)
+	}
+
+	if _, err := scanner.GetChangelog("test", "test", "v1.4.0", "v1.0.0"); err == nil {
+		t.Fatalf("invalid result of the reversed range, expected an error")
+	}
+}
+
+func TestCompareReleases(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/test/test/compare/v1.0.0...v2.0.0" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.URL.Query().Get("page") {
+		case "1":
+			w.Header().Set("Link", fmt.Sprintf(`<%s%s?per_page=2&page=2>; rel="next"`, server.URL, r.URL.Path))
+			w.Write([]byte(`{
+				"status": "ahead", "ahead_by": 3, "total_commits": 3, "html_url": "https://github.com/test/test/compare/v1.0.0...v2.0.0",
+				"commits": [
+					{"sha": "a1", "author": {"login": "alice"}, "commit": {"message": "Add scans", "author": {"name": "Alice"}}},
+					{"sha": "b1", "commit": {"message": "Fix docs", "author": {"name": "Bob"}}}
+				],
+				"files": [
+					{"filename": "scanner.go", "status": "modified", "additions": 10, "deletions": 2},
+					{"filename": "new.go", "previous_filename": "old.go", "status": "renamed", "additions": 1, "deletions": 1}
+				]
+			}`))
+		case "2":
+			w.Write([]byte(`{"status": "ahead", "ahead_by": 3, "total_commits": 3, "commits": [
+				{"sha": "a2", "author": {"login": "Alice"}, "commit": {"message": "Fix scans", "author": {"name": "Alice A."}}}
+			]}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL, PerPage: 2}
+	comparison, err := scanner.CompareReleases("test", "test", "v1.0.0", "v2.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var shas []string
+	for _, commit := range comparison.Commits {
+		shas = append(shas, commit.SHA)
+	}
+	if expected := []string{"a1", "b1", "a2"}; !equal(shas, expected) {
+		t.Fatalf("invalid commits, expected %v, got %v", expected, shas)
+	}
+	if comparison.AheadBy != 3 || comparison.Status != "ahead" || comparison.URL == "" {
+		t.Fatalf("invalid comparison, expected 3 commits ahead, got %d %s", comparison.AheadBy, comparison.Status)
+	}
+	if len(comparison.Contributors) != 2 || comparison.Contributors[0].Login != "alice" || comparison.Contributors[0].Commits != 2 ||
+		comparison.Contributors[1].Name != "Bob" || comparison.Contributors[1].Commits != 1 {
+		t.Fatalf("invalid contributors, expected alice with 2 commits and Bob with 1, got %+v", comparison.Contributors)
+	}
+	if len(comparison.Files) != 2 || comparison.Additions != 11 || comparison.Deletions != 3 {
+		t.Fatalf("invalid changed files, expected 2 with +11 -3, got %d with +%d -%d", len(comparison.Files), comparison.Additions, comparison.Deletions)
+	}
+
+	if _, err := scanner.CompareReleases("test", "test", "v1.0.0", "v3.0.0"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("invalid error of a missing tag, expected %v, got %v", ErrNotFound, err)
+	}
+}
@@ -0,0 +1,291 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// checkpointRecord is a json line of the checkpoint file: the header with the key of the scan run, the listed
+// repositories of a scan, a page of the repository list fetched before the list is complete, or a scanned item.
+type checkpointRecord struct {
+	// Key identifies the scan run the checkpoint records, it is only set in the header, the first line.
+	Key string `json:"key,omitempty"`
+	// Scan identifies the scan, e.g. "ScanRepositories:myorg".
+	Scan         string          `json:"scan,omitempty"`
+	Repositories []*Repository   `json:"repositories,omitempty"`
+	Page         *checkpointPage `json:"page,omitempty"`
+	Item         *ResultItem     `json:"item,omitempty"`
+}
+
+// checkpointPage is a fetched page of a paginated list with its pagination cursors.
+type checkpointPage struct {
+	// List is the number of the list among the lists fetched to list the repositories of the scan, e.g. the
+	// repositories of every group of a GitLab account.
+	List   int             `json:"list"`
+	Number int             `json:"number"`
+	Next   int             `json:"next,omitempty"`
+	Last   int             `json:"last,omitempty"`
+	Items  json.RawMessage `json:"items"`
+}
+
+type pageKey struct {
+	scan         string
+	list, number int
+}
+
+// Checkpoint persists scan progress, the listed repositories and the scanned items, so an interrupted scan is
+// resumed without fetching them again. Records are appended as json lines as soon as they are known, a line cut
+// off by an interruption is ignored on resume. It is safe for concurrent use.
+type Checkpoint struct {
+	path string
+	key  string
+
+	mu           sync.Mutex
+	file         *os.File
+	repositories map[string][]*Repository
+	pages        map[pageKey]*checkpointPage
+	items        map[string]map[string]*ResultItem
+}
+
+// ErrNothingToResume is returned when a scan is resumed from a checkpoint file without recorded progress.
+var ErrNothingToResume = errors.New("no scan progress is recorded")
+
+// ErrCheckpointMismatch is returned when a scan is resumed from a checkpoint file recording another scan.
+var ErrCheckpointMismatch = errors.New("checkpoint records another scan")
+
+// OpenCheckpoint opens the checkpoint file of the scan run identified by the key, e.g. a digest of its accounts
+// and options. The progress recorded in it is loaded if resume is set, it fails with ErrNothingToResume if there
+// is none and with ErrCheckpointMismatch if it is recorded with another key. Otherwise the file is truncated and
+// the scan starts over.
+func OpenCheckpoint(path, key string, resume bool) (*Checkpoint, error) {
+	checkpoint := &Checkpoint{
+		path:         path,
+		key:          key,
+		repositories: make(map[string][]*Repository),
+		pages:        make(map[pageKey]*checkpointPage),
+		items:        make(map[string]map[string]*ResultItem),
+	}
+
+	if resume {
+		size, err := checkpoint.load()
+		if err != nil {
+			return nil, err
+		}
+		if checkpoint.Empty() {
+			return nil, fmt.Errorf("%w in %s, there is nothing to resume", ErrNothingToResume, path)
+		}
+		if err := os.Truncate(path, size); err != nil {
+			return nil, err
+		}
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, err
+		}
+		checkpoint.file = file
+		return checkpoint, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	checkpoint.file = file
+	if err := checkpoint.write(&checkpointRecord{Key: key}); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return checkpoint, nil
+}
+
+// load reads the records of the file and returns the size of its complete lines. The header must have the key of
+// the checkpoint.
+func (c *Checkpoint) load() (int64, error) {
+	file, err := os.Open(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	var size int64
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			// The last line without a newline was cut off by an interruption.
+			return size, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		var record checkpointRecord
+		if err := json.Unmarshal(bytes.TrimSpace(line), &record); err != nil {
+			return 0, fmt.Errorf("could not parse the checkpoint %s: %v", c.path, err)
+		}
+		if size == 0 && record.Key != c.key {
+			return 0, fmt.Errorf("%w in %s, rerun the scan with the same accounts and flags to resume it", ErrCheckpointMismatch, c.path)
+		}
+		c.apply(&record)
+		size += int64(len(line))
+	}
+}
+
+func (c *Checkpoint) apply(record *checkpointRecord) {
+	if record.Repositories != nil {
+		c.repositories[record.Scan] = record.Repositories
+	}
+	if record.Page != nil {
+		c.pages[pageKey{record.Scan, record.Page.List, record.Page.Number}] = record.Page
+	}
+	if record.Item != nil && record.Item.Repository != nil {
+		if c.items[record.Scan] == nil {
+			c.items[record.Scan] = make(map[string]*ResultItem)
+		}
+		c.items[record.Scan][record.Item.Repository.FullName] = record.Item
+	}
+}
+
+// Repositories returns the repositories listed by the scan, nil if they are not recorded or the checkpoint is nil.
+func (c *Checkpoint) Repositories(scan string) []*Repository {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.repositories[scan]
+}
+
+// Item returns the recorded item of the repository scanned by the scan, nil if it is not scanned yet or the
+// checkpoint is nil.
+func (c *Checkpoint) Item(scan, repository string) *ResultItem {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.items[scan][repository]
+}
+
+// AddRepositories records the repositories listed by the scan.
+func (c *Checkpoint) AddRepositories(scan string, repositories []*Repository) error {
+	return c.write(&checkpointRecord{Scan: scan, Repositories: repositories})
+}
+
+// page returns the recorded page of the list of the scan, nil if it is not fetched yet.
+func (c *Checkpoint) page(scan string, list, number int) *checkpointPage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.pages[pageKey{scan, list, number}]
+}
+
+// addPage records a fetched page of the list of the scan.
+func (c *Checkpoint) addPage(scan string, list, number int, items any, links pageLinks) error {
+	data, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+
+	return c.write(&checkpointRecord{Scan: scan, Page: &checkpointPage{List: list, Number: number, Next: links.next, Last: links.last, Items: data}})
+}
+
+// AddItem records the scanned item.
+func (c *Checkpoint) AddItem(scan string, item *ResultItem) error {
+	return c.write(&checkpointRecord{Scan: scan, Item: item})
+}
+
+func (c *Checkpoint) write(record *checkpointRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := c.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("could not write the checkpoint %s: %v", c.path, err)
+	}
+	c.apply(record)
+
+	return nil
+}
+
+type pageCheckpointKey struct{}
+
+// pageCheckpoint records the pages of the lists fetched with a context, so an interrupted listing of a huge account
+// resumes after the fetched pages. The lists are numbered in the order they are started, which is deterministic as
+// listings start them one by one.
+type pageCheckpoint struct {
+	checkpoint *Checkpoint
+	scan       string
+	lists      atomic.Int32
+}
+
+// withPageCheckpoint returns the context recording the pages of the lists paginated with it in the checkpoint.
+func withPageCheckpoint(ctx context.Context, checkpoint *Checkpoint, scan string) context.Context {
+	if checkpoint == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, pageCheckpointKey{}, &pageCheckpoint{checkpoint: checkpoint, scan: scan})
+}
+
+// checkpointPages wraps the page fetch of a list: pages recorded in the checkpoint of the context are returned
+// without fetching them, fetched pages are recorded. The fetch is returned as is without a checkpoint.
+func checkpointPages[T any](ctx context.Context, fetch func(ctx context.Context, page int) ([]T, pageLinks, error)) func(ctx context.Context, page int) ([]T, pageLinks, error) {
+	pages, _ := ctx.Value(pageCheckpointKey{}).(*pageCheckpoint)
+	if pages == nil {
+		return fetch
+	}
+	list := int(pages.lists.Add(1))
+
+	return func(ctx context.Context, number int) ([]T, pageLinks, error) {
+		if page := pages.checkpoint.page(pages.scan, list, number); page != nil {
+			var items []T
+			if err := json.Unmarshal(page.Items, &items); err == nil {
+				return items, pageLinks{next: page.Next, last: page.Last}, nil
+			}
+		}
+		items, links, err := fetch(ctx, number)
+		if err != nil {
+			return nil, links, err
+		}
+
+		return items, links, pages.checkpoint.addPage(pages.scan, list, number, items, links)
+	}
+}
+
+// Close closes the checkpoint file keeping the progress.
+func (c *Checkpoint) Close() error {
+	return c.file.Close()
+}
+
+// Empty reports whether no scan progress is recorded, so there is nothing to resume.
+func (c *Checkpoint) Empty() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.repositories) == 0 && len(c.pages) == 0 && len(c.items) == 0
+}
+
+// Remove closes and deletes the checkpoint file, once the scan is completed.
+func (c *Checkpoint) Remove() error {
+	if err := c.file.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(c.path)
+}
@@ -0,0 +1,199 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCheckpointResume(t *testing.T) {
+	var checkpoint *Checkpoint
+	var failing atomic.Bool
+	var requests atomic.Int32
+	failing.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/users/test/repos":
+			w.Write([]byte(`[{"full_name": "test/a", "name": "a"}, {"full_name": "test/b", "name": "b"}]`))
+		case "/repos/test/a/releases":
+			w.Write([]byte(`[{"tag_name": "v1.0.0"}]`))
+		case "/repos/test/b/releases":
+			if failing.Load() {
+				// The interruption comes once the other repository is recorded, so the test is deterministic.
+				for checkpoint.Item("ScanRepositories:test", "test/a") == nil {
+					time.Sleep(time.Millisecond)
+				}
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.Write([]byte(`[{"tag_name": "v2.0.0"}]`))
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "scan.checkpoint")
+	var err error
+	checkpoint, err = OpenCheckpoint(path, "test", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanner := Scanner{BaseUrl: server.URL, PerPage: 100, Checkpoint: checkpoint}
+	if _, err := scanner.ScanRepositories("test"); err == nil {
+		t.Fatalf("invalid result of the interrupted scan, expected an error")
+	}
+	checkpoint.Close()
+
+	// A line cut off by the interruption is ignored.
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString(`{"scan": "ScanRepositories:test", "item": {"repos`)
+	file.Close()
+
+	failing.Store(false)
+	requests.Store(0)
+	if scanner.Checkpoint, err = OpenCheckpoint(path, "test", true); err != nil {
+		t.Fatal(err)
+	}
+	items, err := scanner.ScanRepositories("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests.Load() != 1 {
+		t.Fatalf("invalid requests count of the resumed scan, expected 1, got %d", requests.Load())
+	}
+	if len(items) != 2 || items[0].Releases[0].TagName != "v1.0.0" || items[1].Releases[0].TagName != "v2.0.0" {
+		t.Fatalf("invalid items of the resumed scan, got %d items", len(items))
+	}
+
+	if err := scanner.Checkpoint.Remove(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("invalid checkpoint after the completed scan, expected it removed, got %v", err)
+	}
+}
+
+func TestCheckpointResumeListing(t *testing.T) {
+	var failing atomic.Bool
+	var firstPageRequests atomic.Int32
+	failing.Store(true)
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/test/repos":
+			if r.URL.Query().Get("page") == "2" {
+				if failing.Load() {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				w.Write([]byte(`[{"full_name": "test/b", "name": "b"}]`))
+				return
+			}
+			firstPageRequests.Add(1)
+			w.Header().Set("Link", fmt.Sprintf(`<%s/users/test/repos?page=2>; rel="next", <%s/users/test/repos?page=2>; rel="last"`, server.URL, server.URL))
+			w.Write([]byte(`[{"full_name": "test/a", "name": "a"}]`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "scan.checkpoint")
+	checkpoint, err := OpenCheckpoint(path, "test", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanner := Scanner{BaseUrl: server.URL, PerPage: 1, Checkpoint: checkpoint}
+	if _, err := scanner.ScanRepositories("test"); err == nil {
+		t.Fatalf("invalid result of the interrupted scan, expected an error")
+	}
+	checkpoint.Close()
+
+	failing.Store(false)
+	if scanner.Checkpoint, err = OpenCheckpoint(path, "test", true); err != nil {
+		t.Fatal(err)
+	}
+	defer scanner.Checkpoint.Close()
+	items, err := scanner.ScanRepositories("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if firstPageRequests.Load() != 1 {
+		t.Fatalf("invalid requests count of the recorded page, expected 1, got %d", firstPageRequests.Load())
+	}
+	if len(items) != 2 || items[0].Repository.FullName != "test/a" || items[1].Repository.FullName != "test/b" {
+		t.Fatalf("invalid items of the resumed scan, got %d items", len(items))
+	}
+}
+
+func TestCheckpointResumeWithoutProgress(t *testing.T) {
+	dir := t.TempDir()
+	missing := filepath.Join(dir, "missing.checkpoint")
+	if _, err := OpenCheckpoint(missing, "test", true); !errors.Is(err, ErrNothingToResume) {
+		t.Fatalf("invalid error of a missing checkpoint, expected %v, got %v", ErrNothingToResume, err)
+	}
+	empty := filepath.Join(dir, "empty.checkpoint")
+	if err := os.WriteFile(empty, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenCheckpoint(empty, "test", true); !errors.Is(err, ErrNothingToResume) {
+		t.Fatalf("invalid error of an empty checkpoint, expected %v, got %v", ErrNothingToResume, err)
+	}
+}
+
+func TestCheckpointMismatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scan.checkpoint")
+	checkpoint, err := OpenCheckpoint(path, "acme", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkpoint.Close()
+	// The header alone is no progress to resume.
+	if _, err := OpenCheckpoint(path, "acme", true); !errors.Is(err, ErrNothingToResume) {
+		t.Fatalf("invalid error of a checkpoint without progress, expected %v, got %v", ErrNothingToResume, err)
+	}
+
+	if checkpoint, err = OpenCheckpoint(path, "acme", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkpoint.AddRepositories("ScanRepositories:acme", []*Repository{{FullName: "acme/a", Name: "a"}}); err != nil {
+		t.Fatal(err)
+	}
+	checkpoint.Close()
+	if _, err := OpenCheckpoint(path, "other", true); !errors.Is(err, ErrCheckpointMismatch) {
+		t.Fatalf("invalid error of a checkpoint of another scan, expected %v, got %v", ErrCheckpointMismatch, err)
+	}
+	if checkpoint, err = OpenCheckpoint(path, "acme", true); err != nil {
+		t.Fatal(err)
+	}
+	defer checkpoint.Close()
+	if repositories := checkpoint.Repositories("ScanRepositories:acme"); len(repositories) != 1 {
+		t.Fatalf("invalid resumed repositories: %v", repositories)
+	}
+}
+
+func TestCheckpointEmpty(t *testing.T) {
+	checkpoint, err := OpenCheckpoint(filepath.Join(t.TempDir(), "scan.checkpoint"), "test", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer checkpoint.Close()
+	if !checkpoint.Empty() {
+		t.Fatalf("invalid new checkpoint, expected it empty")
+	}
+	if err := checkpoint.AddRepositories("ScanRepositories:test", []*Repository{{FullName: "test/a", Name: "a"}}); err != nil {
+		t.Fatal(err)
+	}
+	if checkpoint.Empty() {
+		t.Fatalf("invalid checkpoint with recorded repositories, expected it not empty")
+	}
+}
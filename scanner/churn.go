@@ -0,0 +1,128 @@
+package scanner
+
+import "sort"
+
+const defaultTopContributorsCount = 10
+
+const (
+	ChurnRepositoryCreated  = "created"
+	ChurnRepositoryArchived = "archived"
+	ChurnRepositoryDeleted  = "deleted"
+	ChurnRepositoryRenamed  = "renamed"
+)
+
+type RepositoryChurn struct {
+	Kind       string `json:"kind"`
+	Repository string `json:"repository"`
+	// OldName is the previous full name of a renamed repository.
+	OldName string `json:"old_name,omitempty"`
+}
+
+type ChurnReport struct {
+	Repositories []*RepositoryChurn `json:"repositories"`
+	// AppearedContributors are top contributors of the newer snapshot missing in the older top.
+	AppearedContributors []string `json:"appeared_contributors"`
+	// DisappearedContributors are top contributors of the older snapshot missing in the newer top.
+	DisappearedContributors []string `json:"disappeared_contributors"`
+}
+
+// Churn compares two snapshots of an account. Repositories are matched by ID, so renames are detected,
+// and by full name if IDs are unknown. Maintainer churn compares the top contributors of the account
+// by the total contributions across its repositories.
+func Churn(from, to *Snapshot, topContributorsCount int) *ChurnReport {
+	if topContributorsCount <= 0 {
+		topContributorsCount = defaultTopContributorsCount
+	}
+
+	report := &ChurnReport{}
+	oldRepositories := indexRepositories(from.Items)
+	newRepositories := indexRepositories(to.Items)
+	for key, repository := range newRepositories {
+		oldRepository, ok := oldRepositories[key]
+		switch {
+		case !ok:
+			report.Repositories = append(report.Repositories, &RepositoryChurn{Kind: ChurnRepositoryCreated, Repository: repository.FullName})
+			continue
+		case oldRepository.FullName != repository.FullName:
+			report.Repositories = append(report.Repositories, &RepositoryChurn{Kind: ChurnRepositoryRenamed, Repository: repository.FullName, OldName: oldRepository.FullName})
+		}
+		if !oldRepository.Archived && repository.Archived {
+			report.Repositories = append(report.Repositories, &RepositoryChurn{Kind: ChurnRepositoryArchived, Repository: repository.FullName})
+		}
+	}
+	for key, repository := range oldRepositories {
+		if _, ok := newRepositories[key]; !ok {
+			report.Repositories = append(report.Repositories, &RepositoryChurn{Kind: ChurnRepositoryDeleted, Repository: repository.FullName})
+		}
+	}
+	sort.SliceStable(report.Repositories, func(i, j int) bool {
+		if report.Repositories[i].Kind != report.Repositories[j].Kind {
+			return report.Repositories[i].Kind < report.Repositories[j].Kind
+		}
+		return report.Repositories[i].Repository < report.Repositories[j].Repository
+	})
+
+	oldTop := TopContributors(from.Items, topContributorsCount)
+	newTop := TopContributors(to.Items, topContributorsCount)
+	report.AppearedContributors = missingLogins(newTop, oldTop)
+	report.DisappearedContributors = missingLogins(oldTop, newTop)
+
+	return report
+}
+
+// TopContributors returns up to count contributors with the most contributions across all items.
+func TopContributors(items []*ResultItem, count int) []*Contributor {
+	totals := map[string]int{}
+	for _, item := range items {
+		for _, contributor := range item.Contributors {
+			totals[contributor.Login] += contributor.Contributions
+		}
+	}
+
+	contributors := make([]*Contributor, 0, len(totals))
+	for login, contributions := range totals {
+		contributors = append(contributors, &Contributor{Login: login, Contributions: contributions})
+	}
+	sort.Slice(contributors, func(i, j int) bool {
+		if contributors[i].Contributions != contributors[j].Contributions {
+			return contributors[i].Contributions > contributors[j].Contributions
+		}
+		return contributors[i].Login < contributors[j].Login
+	})
+	if len(contributors) > count {
+		contributors = contributors[:count]
+	}
+
+	return contributors
+}
+
+func indexRepositories(items []*ResultItem) map[interface{}]*Repository {
+	index := make(map[interface{}]*Repository, len(items))
+	for _, item := range items {
+		if item.Repository.ID != 0 {
+			index[item.Repository.ID] = item.Repository
+		} else {
+			index[item.Repository.FullName] = item.Repository
+		}
+	}
+
+	return index
+}
+
+// missingLogins returns logins of the contributors a that are absent in b.
+func missingLogins(a, b []*Contributor) []string {
+	logins := map[string]bool{}
+	for _, contributor := range b {
+		logins[contributor.Login] = true
+	}
+
+	var missing []string
+	for _, contributor := range a {
+		if !logins[contributor.Login] {
+			missing = append(missing, contributor.Login)
+		}
+	}
+	sort.Strings(missing)
+
+	return missing
+}
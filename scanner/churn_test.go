@@ -0,0 +1,43 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChurn(t *testing.T) {
+	from := &Snapshot{Items: []*ResultItem{
+		{Repository: &Repository{ID: 1, FullName: "test/old-name"}, Contributors: []*Contributor{{Login: "alice", Contributions: 10}}},
+		{Repository: &Repository{ID: 2, FullName: "test/archived"}, Contributors: []*Contributor{{Login: "bob", Contributions: 5}}},
+		{Repository: &Repository{ID: 3, FullName: "test/deleted"}, Contributors: []*Contributor{{Login: "carol", Contributions: 3}}},
+	}}
+	to := &Snapshot{Items: []*ResultItem{
+		{Repository: &Repository{ID: 1, FullName: "test/new-name"}, Contributors: []*Contributor{{Login: "alice", Contributions: 12}}},
+		{Repository: &Repository{ID: 2, FullName: "test/archived", Archived: true}, Contributors: []*Contributor{{Login: "bob", Contributions: 5}}},
+		{Repository: &Repository{ID: 4, FullName: "test/created"}, Contributors: []*Contributor{{Login: "dave", Contributions: 8}}},
+	}}
+
+	report := Churn(from, to, 2)
+
+	expected := []RepositoryChurn{
+		{Kind: ChurnRepositoryArchived, Repository: "test/archived"},
+		{Kind: ChurnRepositoryCreated, Repository: "test/created"},
+		{Kind: ChurnRepositoryDeleted, Repository: "test/deleted"},
+		{Kind: ChurnRepositoryRenamed, Repository: "test/new-name", OldName: "test/old-name"},
+	}
+	if len(report.Repositories) != len(expected) {
+		t.Fatalf("invalid repository churn count, expected %d, got %d", len(expected), len(report.Repositories))
+	}
+	for i, churn := range report.Repositories {
+		if *churn != expected[i] {
+			t.Errorf("invalid repository churn %d, expected %+v, got %+v", i, expected[i], *churn)
+		}
+	}
+
+	if !reflect.DeepEqual(report.AppearedContributors, []string{"dave"}) {
+		t.Fatalf("invalid appeared contributors: %v", report.AppearedContributors)
+	}
+	if !reflect.DeepEqual(report.DisappearedContributors, []string{"bob"}) {
+		t.Fatalf("invalid disappeared contributors: %v", report.DisappearedContributors)
+	}
+}
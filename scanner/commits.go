@@ -0,0 +1,110 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// statsRetries and statsRetryDelay bound waiting for GitHub to compute repository statistics, which it does in the
+// background responding with 202 Accepted meanwhile.
+var (
+	statsRetries    = 5
+	statsRetryDelay = 2 * time.Second
+)
+
+// errStatsPending is returned when GitHub is still computing the statistics after all retries.
+var errStatsPending = errors.New("statistics are still being computed")
+
+// CommitActivity is the commit activity of the last year of a repository.
+type CommitActivity struct {
+	// Weeks are the weekly commit counts, oldest first.
+	Weeks []int `json:"weeks"`
+	Total int   `json:"total"`
+	// LastCommitAt is the day of the last commit of the year, nil if there were no commits.
+	LastCommitAt *time.Time `json:"last_commit_at,omitempty"`
+}
+
+// RecentCommits returns the count of commits made in the last weeks.
+func (a *CommitActivity) RecentCommits(weeks int) int {
+	count := 0
+	for i := max(len(a.Weeks)-weeks, 0); i < len(a.Weeks); i++ {
+		count += a.Weeks[i]
+	}
+
+	return count
+}
+
+// GetCommitActivity returns the weekly commit counts of the repository for the last year.
+func (s *Scanner) GetCommitActivity(user, repository string) (*CommitActivity, error) {
+	return s.getCommitActivity(context.Background(), user, repository)
+}
+
+func (s *Scanner) getCommitActivity(ctx context.Context, user, repository string) (*CommitActivity, error) {
+	if err := s.checkUser(user); err != nil {
+		return nil, err
+	}
+	if err := s.checkRepository(repository); err != nil {
+		return nil, err
+	}
+	ctx, span := s.getTracer().Start(ctx, "GetCommitActivity", StringAttribute("account", user), StringAttribute("repository", repository))
+	defer span.End()
+
+	url := fmt.Sprintf("%s/repos/%s/%s/stats/commit_activity", s.BaseUrl, user, repository)
+	for attempt := 0; ; attempt++ {
+		response, err := s.get(ctx, span, url)
+		if err != nil {
+			return nil, err
+		}
+
+		switch response.StatusCode {
+		case http.StatusOK:
+			var weeks []struct {
+				Total int    `json:"total"`
+				Week  int64  `json:"week"`
+				Days  [7]int `json:"days"`
+			}
+			err := json.NewDecoder(response.Body).Decode(&weeks)
+			response.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+
+			activity := &CommitActivity{Weeks: make([]int, 0, len(weeks))}
+			for _, week := range weeks {
+				activity.Weeks = append(activity.Weeks, week.Total)
+				activity.Total += week.Total
+				for day := len(week.Days) - 1; day >= 0; day-- {
+					if week.Days[day] > 0 {
+						lastCommitAt := time.Unix(week.Week, 0).UTC().AddDate(0, 0, day)
+						activity.LastCommitAt = &lastCommitAt
+						break
+					}
+				}
+			}
+			return activity, nil
+		case http.StatusAccepted:
+			response.Body.Close()
+		case http.StatusNoContent:
+			// Empty repositories have no statistics.
+			response.Body.Close()
+			return &CommitActivity{}, nil
+		default:
+			defer response.Body.Close()
+			return nil, fmt.Errorf("could not get commit activity of the repository %s/%s: %w", user, repository, s.newApiError(response))
+		}
+
+		if attempt == statsRetries {
+			return nil, fmt.Errorf("could not get commit activity of the repository %s/%s: %w", user, repository, errStatsPending)
+		}
+		s.getLogger().Debug("waiting for commit activity to be computed", "repository", user+"/"+repository, "attempt", attempt+1)
+		select {
+		case <-time.After(statsRetryDelay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package scanner
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestGetCommitActivity(t *testing.T) {
+	statsRetryDelay = time.Millisecond
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/test/test/stats/commit_activity":
+			// The first request starts computing the statistics.
+			if requests++; requests == 1 {
+				w.WriteHeader(http.StatusAccepted)
+				w.Write([]byte(`{}`))
+				return
+			}
+			w.Write([]byte(`[
+				{"days": [0, 1, 1, 0, 0, 0, 0], "total": 2, "week": 1709424000},
+				{"days": [0, 0, 0, 3, 0, 0, 0], "total": 3, "week": 1710028800},
+				{"days": [0, 0, 0, 0, 0, 0, 0], "total": 0, "week": 1710633600}
+			]`))
+		case "/repos/test/empty/stats/commit_activity":
+			w.WriteHeader(http.StatusNoContent)
+		case "/repos/test/pending/stats/commit_activity":
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL}
+	activity, err := scanner.GetCommitActivity("test", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 {
+		t.Fatalf("invalid requests count, expected 2, got %d", requests)
+	}
+	if !slices.Equal(activity.Weeks, []int{2, 3, 0}) || activity.Total != 5 {
+		t.Fatalf("invalid weekly commits, expected [2 3 0] with total 5, got %v with total %d", activity.Weeks, activity.Total)
+	}
+	expected := time.Date(2024, 3, 13, 0, 0, 0, 0, time.UTC)
+	if activity.LastCommitAt == nil || !activity.LastCommitAt.Equal(expected) {
+		t.Fatalf("invalid last commit date, expected %v, got %v", expected, activity.LastCommitAt)
+	}
+	if recent := activity.RecentCommits(2); recent != 3 {
+		t.Fatalf("invalid recent commits, expected 3, got %d", recent)
+	}
+
+	activity, err = scanner.GetCommitActivity("test", "empty")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if activity.LastCommitAt != nil || activity.Total != 0 {
+		t.Fatalf("invalid activity of an empty repository, expected none, got %v", activity)
+	}
+
+	if _, err := scanner.GetCommitActivity("test", "pending"); !errors.Is(err, errStatsPending) {
+		t.Fatalf("invalid error, expected %v, got %v", errStatsPending, err)
+	}
+}
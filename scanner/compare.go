@@ -0,0 +1,139 @@
+package scanner
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Repository match kinds of an account comparison.
+const (
+	MatchByName        = "name"
+	MatchByDescription = "description"
+)
+
+// RepositoryMatch is a pair of repositories of two compared accounts considered the same repository.
+type RepositoryMatch struct {
+	A  *Repository `json:"a"`
+	B  *Repository `json:"b"`
+	By string      `json:"by"`
+	// Similarity is the description similarity from 0 to 1, it is only set for matches by description.
+	Similarity float64 `json:"similarity,omitempty"`
+}
+
+// AccountComparison is the difference of the repository sets of two accounts.
+type AccountComparison struct {
+	AccountA string             `json:"account_a"`
+	AccountB string             `json:"account_b"`
+	Matched  []*RepositoryMatch `json:"matched"`
+	OnlyA    []*Repository      `json:"only_a"`
+	OnlyB    []*Repository      `json:"only_b"`
+}
+
+// CompareAccounts matches the repositories of two accounts by case-insensitive name. If minSimilarity is
+// positive, the repositories left unmatched are then paired by the similarity of their descriptions, the most
+// similar pairs first, as long as it is at least minSimilarity. Repositories are ordered by name.
+func CompareAccounts(accountA, accountB string, repositoriesA, repositoriesB []*Repository, minSimilarity float64) *AccountComparison {
+	comparison := &AccountComparison{AccountA: accountA, AccountB: accountB}
+
+	byName := make(map[string]*Repository, len(repositoriesB))
+	for _, repository := range repositoriesB {
+		byName[strings.ToLower(repository.Name)] = repository
+	}
+	matchedB := make(map[*Repository]bool)
+	var unmatchedA []*Repository
+	for _, a := range repositoriesA {
+		if b := byName[strings.ToLower(a.Name)]; b != nil && !matchedB[b] {
+			comparison.Matched = append(comparison.Matched, &RepositoryMatch{A: a, B: b, By: MatchByName})
+			matchedB[b] = true
+		} else {
+			unmatchedA = append(unmatchedA, a)
+		}
+	}
+	var unmatchedB []*Repository
+	for _, b := range repositoriesB {
+		if !matchedB[b] {
+			unmatchedB = append(unmatchedB, b)
+		}
+	}
+
+	if minSimilarity > 0 {
+		var candidates []*RepositoryMatch
+		for _, a := range unmatchedA {
+			for _, b := range unmatchedB {
+				if similarity := descriptionSimilarity(a.Description, b.Description); similarity >= minSimilarity {
+					candidates = append(candidates, &RepositoryMatch{A: a, B: b, By: MatchByDescription, Similarity: similarity})
+				}
+			}
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return candidates[i].Similarity > candidates[j].Similarity
+		})
+		paired := make(map[*Repository]bool)
+		for _, candidate := range candidates {
+			if paired[candidate.A] || paired[candidate.B] {
+				continue
+			}
+			paired[candidate.A], paired[candidate.B] = true, true
+			comparison.Matched = append(comparison.Matched, candidate)
+		}
+		unmatchedA = withoutRepositories(unmatchedA, paired)
+		unmatchedB = withoutRepositories(unmatchedB, paired)
+	}
+
+	sort.SliceStable(comparison.Matched, func(i, j int) bool {
+		return comparison.Matched[i].A.Name < comparison.Matched[j].A.Name
+	})
+	comparison.OnlyA = sortRepositoriesByName(unmatchedA)
+	comparison.OnlyB = sortRepositoriesByName(unmatchedB)
+
+	return comparison
+}
+
+// descriptionSimilarity is the Jaccard index of the lowercase word sets of the descriptions. Empty descriptions
+// are not similar to anything.
+func descriptionSimilarity(a, b string) float64 {
+	wordsA, wordsB := descriptionWords(a), descriptionWords(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return 0
+	}
+
+	common := 0
+	for word := range wordsA {
+		if wordsB[word] {
+			common++
+		}
+	}
+
+	return float64(common) / float64(len(wordsA)+len(wordsB)-common)
+}
+
+func descriptionWords(description string) map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		words[word] = true
+	}
+
+	return words
+}
+
+func withoutRepositories(repositories []*Repository, excluded map[*Repository]bool) []*Repository {
+	var kept []*Repository
+	for _, repository := range repositories {
+		if !excluded[repository] {
+			kept = append(kept, repository)
+		}
+	}
+
+	return kept
+}
+
+func sortRepositoriesByName(repositories []*Repository) []*Repository {
+	sort.SliceStable(repositories, func(i, j int) bool {
+		return repositories[i].Name < repositories[j].Name
+	})
+
+	return repositories
+}
@@ -0,0 +1,41 @@
+package scanner
+
+import "testing"
+
+func TestCompareAccounts(t *testing.T) {
+	repositoriesA := []*Repository{
+		{FullName: "old/api", Name: "api"},
+		{FullName: "old/Scanner", Name: "Scanner", Description: "Scans GitHub releases"},
+		{FullName: "old/release-scanner", Name: "release-scanner", Description: "Scanner of GitHub releases and assets"},
+		{FullName: "old/legacy", Name: "legacy", Description: "Legacy deploy scripts"},
+	}
+	repositoriesB := []*Repository{
+		{FullName: "new/scanner", Name: "scanner"},
+		{FullName: "new/api", Name: "api"},
+		{FullName: "new/releases-scanner", Name: "releases-scanner", Description: "Scanner of GitHub releases and their assets"},
+		{FullName: "new/docs", Name: "docs", Description: "Documentation"},
+	}
+
+	comparison := CompareAccounts("old", "new", repositoriesA, repositoriesB, 0)
+	if len(comparison.Matched) != 2 || comparison.Matched[0].B.FullName != "new/scanner" || comparison.Matched[1].B.FullName != "new/api" {
+		t.Fatalf("invalid matches by name, expected api and scanner, got %d matches", len(comparison.Matched))
+	}
+	if len(comparison.OnlyA) != 2 || len(comparison.OnlyB) != 2 {
+		t.Fatalf("invalid unmatched repositories, expected 2 and 2, got %d and %d", len(comparison.OnlyA), len(comparison.OnlyB))
+	}
+
+	comparison = CompareAccounts("old", "new", repositoriesA, repositoriesB, 0.5)
+	if len(comparison.Matched) != 3 {
+		t.Fatalf("invalid matches, expected 3, got %d", len(comparison.Matched))
+	}
+	match := comparison.Matched[2]
+	if match.A.Name != "release-scanner" || match.B.Name != "releases-scanner" || match.By != MatchByDescription {
+		t.Fatalf("invalid match by description, expected release-scanner and releases-scanner, got %s and %s by %s", match.A.Name, match.B.Name, match.By)
+	}
+	if match.Similarity != 6.0/7.0 {
+		t.Fatalf("invalid similarity, expected %v, got %v", 6.0/7.0, match.Similarity)
+	}
+	if len(comparison.OnlyA) != 1 || comparison.OnlyA[0].Name != "legacy" || len(comparison.OnlyB) != 1 || comparison.OnlyB[0].Name != "docs" {
+		t.Fatalf("invalid unmatched repositories, expected legacy and docs, got %v and %v", comparison.OnlyA, comparison.OnlyB)
+	}
+}
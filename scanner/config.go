@@ -0,0 +1,136 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// Config is the user configuration with named account groups, aliases, group notifications and version schemes
+// of repositories, e.g.
+//
+//	{"groups": {"platform": ["org-a", "org-b", "user-c"]}, "aliases": {"k8s": "kubernetes"},
+//	 "notifications": {"platform": {"webhook_url": "https://example.com/hook", "kinds": ["release_added"]}},
+//	 "version_schemes": [{"pattern": "org-a/*", "scheme": "calver"}]}
+type Config struct {
+	Groups  map[string][]string `json:"groups"`
+	Aliases map[string]string   `json:"aliases"`
+	// Notifications configure how the changes of the accounts of a group detected in watch mode are notified,
+	// by group name.
+	Notifications map[string]*GroupNotifications `json:"notifications,omitempty"`
+	// VersionSchemes assign version schemes to repositories by full name patterns, the first matching one wins.
+	VersionSchemes []VersionSchemePattern `json:"version_schemes,omitempty"`
+}
+
+// GroupNotifications are the notification settings of an account group.
+type GroupNotifications struct {
+	// WebhookURL receives the changes of the accounts of the group as JSON POST requests.
+	WebhookURL string `json:"webhook_url"`
+	// Kinds are the change kinds notified, e.g. release_added. All changes are notified if it is empty.
+	Kinds []string `json:"kinds,omitempty"`
+}
+
+// Filter returns the changes of the notified kinds.
+func (n *GroupNotifications) Filter(changes []*Change) []*Change {
+	if len(n.Kinds) == 0 {
+		return changes
+	}
+
+	var filtered []*Change
+	for _, change := range changes {
+		if slices.Contains(n.Kinds, change.Kind) {
+			filtered = append(filtered, change)
+		}
+	}
+
+	return filtered
+}
+
+// VersionSchemePattern assigns the version scheme of the name to the repositories matching the pattern.
+type VersionSchemePattern struct {
+	Pattern string `json:"pattern"`
+	Scheme  string `json:"scheme"`
+}
+
+// LoadConfig reads the config file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("could not parse the config %s: %v", path, err)
+	}
+
+	return &config, nil
+}
+
+// ResolveAccounts expands group names to their members and aliases to the accounts they stand for.
+// Other names are accounts themselves. Every account is returned once, in the order of its first appearance.
+func (c *Config) ResolveAccounts(names []string) []string {
+	var accounts []string
+	add := func(name string) {
+		if account, ok := c.Aliases[name]; ok {
+			name = account
+		}
+		if !slices.Contains(accounts, name) {
+			accounts = append(accounts, name)
+		}
+	}
+	for _, name := range names {
+		if members, ok := c.Groups[name]; ok {
+			for _, member := range members {
+				add(member)
+			}
+			continue
+		}
+		add(name)
+	}
+
+	return accounts
+}
+
+// AccountGroups returns the names of the groups the account is a member of.
+func (c *Config) AccountGroups(account string) []string {
+	var groups []string
+	for group, members := range c.Groups {
+		for _, member := range c.ResolveAccounts(members) {
+			if member == account {
+				groups = append(groups, group)
+				break
+			}
+		}
+	}
+	slices.Sort(groups)
+
+	return groups
+}
+
+// AccountNotifications returns the notification settings of the groups the account is a member of by group name.
+func (c *Config) AccountNotifications(account string) map[string]*GroupNotifications {
+	notifications := make(map[string]*GroupNotifications)
+	for _, group := range c.AccountGroups(account) {
+		if n, ok := c.Notifications[group]; ok {
+			notifications[group] = n
+		}
+	}
+
+	return notifications
+}
+
+// VersionSchemeRules returns the rules of the configured version schemes.
+func (c *Config) VersionSchemeRules() ([]VersionSchemeRule, error) {
+	var rules []VersionSchemeRule
+	for _, pattern := range c.VersionSchemes {
+		rule, err := NewVersionSchemeRule(pattern.Pattern, pattern.Scheme)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version scheme of the config: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,96 @@
+package scanner
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	data := `{"groups": {"platform": ["org-a", "b", "user-c"], "infra": ["org-a"]}, "aliases": {"b": "org-b"}}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accounts := config.ResolveAccounts([]string{"platform", "b", "other"})
+	expected := []string{"org-a", "org-b", "user-c", "other"}
+	if !slices.Equal(accounts, expected) {
+		t.Fatalf("invalid accounts, expected %v, got %v", expected, accounts)
+	}
+
+	groups := config.AccountGroups("org-a")
+	if !slices.Equal(groups, []string{"infra", "platform"}) {
+		t.Fatalf("invalid groups, expected %v, got %v", []string{"infra", "platform"}, groups)
+	}
+	if groups := config.AccountGroups("org-b"); !slices.Equal(groups, []string{"platform"}) {
+		t.Fatalf("invalid groups of the aliased account: %v", groups)
+	}
+}
+
+func TestLoadMissingConfig(t *testing.T) {
+	if _, err := LoadConfig(filepath.Join(t.TempDir(), "missing.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("invalid error of a missing config, expected %v, got %v", os.ErrNotExist, err)
+	}
+}
+
+func TestConfigAccountNotifications(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	data := `{"groups": {"platform": ["org-a", "org-b"], "infra": ["org-a"]},
+		"notifications": {"platform": {"webhook_url": "https://example.com/hook", "kinds": ["release_added"]}}}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notifications := config.AccountNotifications("org-a")
+	if len(notifications) != 1 || notifications["platform"] == nil || notifications["platform"].WebhookURL != "https://example.com/hook" {
+		t.Fatalf("invalid notifications of org-a, expected the platform group ones, got %v", notifications)
+	}
+	if notifications := config.AccountNotifications("other"); len(notifications) != 0 {
+		t.Fatalf("invalid notifications of an account without groups, expected none, got %v", notifications)
+	}
+
+	changes := []*Change{{Kind: ChangeReleaseAdded, Repository: "org-a/a"}, {Kind: ChangeRepositoryAdded, Repository: "org-a/b"}}
+	if filtered := notifications["platform"].Filter(changes); len(filtered) != 1 || filtered[0].Kind != ChangeReleaseAdded {
+		t.Fatalf("invalid notified changes, expected the added release, got %v", filtered)
+	}
+}
+
+func TestConfigVersionSchemeRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	data := `{"version_schemes": [{"pattern": "org-a/infra-*", "scheme": "calver"}, {"pattern": "org-a/*", "scheme": "pep440"}]}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err := config.VersionSchemeRules()
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanner := Scanner{VersionSchemes: rules}
+	for repository, expected := range map[string]string{"org-a/infra-dns": "calver", "org-a/tool": "pep440", "org-b/tool": "semver"} {
+		if scheme := scanner.GetVersionScheme(repository); scheme.Name() != expected {
+			t.Errorf("invalid version scheme of %s, expected %s, got %s", repository, expected, scheme.Name())
+		}
+	}
+
+	config.VersionSchemes = []VersionSchemePattern{{Pattern: "org-a/*", Scheme: "romver"}}
+	if _, err := config.VersionSchemeRules(); err == nil {
+		t.Fatalf("invalid result of an unknown version scheme, expected an error")
+	}
+}
@@ -0,0 +1,53 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+func (s *Scanner) GetAllContributors(user, repository string) ([]*Contributor, error) {
+	return s.getAllContributors(context.Background(), user, repository)
+}
+
+func (s *Scanner) getAllContributors(ctx context.Context, user, repository string) ([]*Contributor, error) {
+	return paginate(ctx, func(ctx context.Context, page int) ([]*Contributor, pageLinks, error) {
+		return s.getContributorsPerPage(ctx, user, repository, page)
+	})
+}
+
+func (s *Scanner) getContributorsPerPage(ctx context.Context, user, repository string, page int) ([]*Contributor, pageLinks, error) {
+	if err := s.checkPage(page); err != nil {
+		return nil, pageLinks{}, err
+	}
+	if err := s.checkUser(user); err != nil {
+		return nil, pageLinks{}, err
+	}
+	if err := s.checkRepository(repository); err != nil {
+		return nil, pageLinks{}, err
+	}
+	ctx, span := s.getTracer().Start(ctx, "GetContributorsPerPage", StringAttribute("account", user), StringAttribute("repository", repository), IntAttribute("page", page))
+	defer span.End()
+
+	response, err := s.get(ctx, span, fmt.Sprintf("%s/repos/%s/%s/contributors?per_page=%d&page=%d", s.BaseUrl, user, repository, s.getPerPage(), page))
+	if err != nil {
+		return nil, pageLinks{}, err
+	}
+	defer response.Body.Close()
+
+	// GitHub responds with no content for empty repositories.
+	if response.StatusCode == http.StatusNoContent {
+		return nil, pageLinks{}, nil
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, pageLinks{}, fmt.Errorf("could not get contributors for the repository %s: %w", repository, s.newApiError(response))
+	}
+
+	var contributors []*Contributor
+	if err := json.NewDecoder(response.Body).Decode(&contributors); err != nil {
+		return nil, pageLinks{}, err
+	}
+
+	return contributors, parsePageLinks(response.Header), nil
+}
@@ -0,0 +1,67 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetAllContributors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/test/test/contributors":
+			if r.URL.Query().Get("page") == "1" {
+				setPageLinks(w, r, 2, 2)
+				w.Write([]byte(`[{"login": "alice", "contributions": 120}, {"login": "bob", "contributions": 30}]`))
+			} else {
+				w.Write([]byte(`[{"login": "carol", "contributions": 2}]`))
+			}
+		case "/repos/test/empty/contributors":
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL, PerPage: 2}
+	contributors, err := scanner.GetAllContributors("test", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(contributors) != 3 {
+		t.Fatalf("invalid contributors count, expected 3, got %d", len(contributors))
+	}
+	if contributors[0].Login != "alice" || contributors[0].Contributions != 120 {
+		t.Fatalf("invalid first contributor, expected alice with 120 contributions, got %s with %d", contributors[0].Login, contributors[0].Contributions)
+	}
+
+	contributors, err = scanner.GetAllContributors("test", "empty")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(contributors) != 0 {
+		t.Fatalf("invalid contributors count of the empty repository, expected 0, got %d", len(contributors))
+	}
+}
+
+func TestScanContributors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/test/repos":
+			w.Write([]byte(`[{"full_name": "test/test", "name": "test"}]`))
+		case "/repos/test/test/releases":
+			w.Write([]byte(`[]`))
+		case "/repos/test/test/contributors":
+			w.Write([]byte(`[{"login": "alice", "contributions": 120}]`))
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL, PerPage: 100, ScanContributors: true}
+	items, err := scanner.ScanRepositories("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items[0].Contributors) != 1 || items[0].Contributors[0].Login != "alice" {
+		t.Fatalf("invalid scanned contributors, expected [alice], got %v", items[0].Contributors)
+	}
+}
@@ -0,0 +1,313 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// accountsConcurrency is the count of accounts a Coordinator scans at once by default.
+	accountsConcurrency = 4
+	// defaultMaxPriorityStarts is the count of high priority accounts a Coordinator starts in a row by default.
+	defaultMaxPriorityStarts = 3
+)
+
+// Coordinator scans several accounts concurrently with one scanner instead of one by one. The scans share the
+// rate limit state and the workers of the scanner: no more than maxWorkersCount repositories are scanned at once
+// across the accounts, the secondary rate limit pauses all of them, and with Scanner.CheckBudget the estimated
+// requests of every account are checked against the remaining rate limit together with the requests reserved by
+// the others.
+type Coordinator struct {
+	Scanner *Scanner
+	// Concurrency is the count of accounts scanned at once, 4 if it is 0.
+	Concurrency int
+	// Priority lists the high priority accounts, their scans are started ahead of the other accounts.
+	Priority []string
+	// MaxPriorityStarts is how many high priority scans may be started in a row while other accounts wait, so they
+	// can not starve the others of the account slots. It is 3 by default.
+	MaxPriorityStarts int
+}
+
+// ScanAccounts scans the accounts concurrently and returns their items together. Organizations the token is not
+// SSO-authorized for are skipped instead of failing the whole scan, any other error cancels the scans of the
+// other accounts. Repositories listed under several accounts, e.g. transferred ones, are scanned once and
+// returned once. Once the scanner is interrupted the items scanned before are returned with ErrInterrupted.
+// Scanner.OnProgress is called with the totals of all accounts, the calls are not concurrent.
+func (c *Coordinator) ScanAccounts(accounts []string) ([]*ResultItem, []*SkippedAccount, error) {
+	s := c.Scanner
+	var interrupted atomic.Bool
+	results := make([][]*ResultItem, len(accounts))
+	skipped := make([]*SkippedAccount, len(accounts))
+	progress := &accountsProgress{report: s.reportProgress, done: make([]int, len(accounts)), total: make([]int, len(accounts))}
+
+	group, ctx := errgroup.WithContext(withRunResults(context.Background(), &runResults{}))
+	group.SetLimit(c.getConcurrency())
+	for _, i := range c.schedule(accounts) {
+		account := accounts[i]
+		group.Go(func() error {
+			err := s.stream(ctx, account, "ScanRepositories", s.getProvider().ListRepositories, func(item *ResultItem) error {
+				results[i] = append(results[i], item)
+				return nil
+			}, func(done, total int, repository string) {
+				progress.update(i, done, total, repository)
+			})
+			if errors.Is(err, ErrSSORequired) {
+				skipped[i] = newSkippedAccount(account, err)
+				s.getLogger().Warn("account skipped: token is not authorized for SSO", "account", account, "authorization_url", skipped[i].AuthorizationURL)
+				return nil
+			}
+			// The other scans are not cancelled, they stop taking repositories themselves.
+			if errors.Is(err, ErrInterrupted) {
+				interrupted.Store(true)
+				return nil
+			}
+			return err
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	var items []*ResultItem
+	scanned := make(map[string]bool)
+	for _, accountItems := range results {
+		for _, item := range accountItems {
+			if name := strings.ToLower(item.Repository.FullName); !scanned[name] {
+				scanned[name] = true
+				items = append(items, item)
+			}
+		}
+	}
+	SortResults(items)
+	var skippedAccounts []*SkippedAccount
+	for _, account := range skipped {
+		if account != nil {
+			skippedAccounts = append(skippedAccounts, account)
+		}
+	}
+
+	if interrupted.Load() {
+		return items, skippedAccounts, ErrInterrupted
+	}
+
+	return items, skippedAccounts, nil
+}
+
+// schedule returns the indexes of the accounts in the order their scans are started: high priority accounts first,
+// except that an other account is started after every MaxPriorityStarts high priority ones.
+func (c *Coordinator) schedule(accounts []string) []int {
+	var high, low []int
+	for i, account := range accounts {
+		if slices.Contains(c.Priority, account) {
+			high = append(high, i)
+		} else {
+			low = append(low, i)
+		}
+	}
+
+	maxStarts := c.getMaxPriorityStarts()
+	order := make([]int, 0, len(accounts))
+	for len(high) > 0 && len(low) > 0 {
+		n := min(maxStarts, len(high))
+		order = append(order, high[:n]...)
+		order = append(order, low[0])
+		high, low = high[n:], low[1:]
+	}
+	order = append(order, high...)
+
+	return append(order, low...)
+}
+
+func (c *Coordinator) getMaxPriorityStarts() int {
+	if c.MaxPriorityStarts <= 0 {
+		return defaultMaxPriorityStarts
+	}
+
+	return c.MaxPriorityStarts
+}
+
+func (c *Coordinator) getConcurrency() int {
+	if c.Concurrency <= 0 {
+		return accountsConcurrency
+	}
+
+	return c.Concurrency
+}
+
+func newSkippedAccount(account string, err error) *SkippedAccount {
+	skipped := &SkippedAccount{Account: account, Reason: err.Error()}
+	var apiError *APIError
+	if errors.As(err, &apiError) {
+		skipped.AuthorizationURL = apiError.SSOAuthorizationURL
+	}
+
+	return skipped
+}
+
+// accountsProgress sums the progress of concurrent account scans, the total grows as the accounts are listed.
+type accountsProgress struct {
+	mu     sync.Mutex
+	report func(done, total int, repository string)
+	done   []int
+	total  []int
+}
+
+func (p *accountsProgress) update(account, done, total int, repository string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.done[account], p.total[account] = done, total
+	var allDone, allTotal int
+	for i := range p.done {
+		allDone += p.done[i]
+		allTotal += p.total[i]
+	}
+	p.report(allDone, allTotal, repository)
+}
+
+// runResults are the items of the repositories scanned by a run of several scans, so repositories listed by more
+// than one of them are scanned once. Repository names are case-insensitive.
+type runResults struct {
+	mu      sync.Mutex
+	entries map[string]*runResult
+}
+
+type runResult struct {
+	done chan struct{}
+	item *ResultItem
+	err  error
+}
+
+type runResultsKey struct{}
+
+func withRunResults(ctx context.Context, results *runResults) context.Context {
+	return context.WithValue(ctx, runResultsKey{}, results)
+}
+
+// runResultsFrom returns the results of the run the scan is a part of, nil if it is not.
+func runResultsFrom(ctx context.Context) *runResults {
+	results, _ := ctx.Value(runResultsKey{}).(*runResults)
+
+	return results
+}
+
+// clone copies the item with its list of releases, which emitting the item sorts and cuts.
+func (i *ResultItem) clone() *ResultItem {
+	clone := *i
+	clone.Releases = slices.Clone(i.Releases)
+
+	return &clone
+}
+
+// claim returns the result of the repository if another scan of the run claimed it, the result is complete once
+// its done channel is closed. Otherwise the repository is claimed and nil is returned, the caller must complete it.
+// Scans that are not a part of a run claim every repository.
+func (r *runResults) claim(repository string) *runResult {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	name := strings.ToLower(repository)
+	if result := r.entries[name]; result != nil {
+		return result
+	}
+	if r.entries == nil {
+		r.entries = make(map[string]*runResult)
+	}
+	r.entries[name] = &runResult{done: make(chan struct{})}
+
+	return nil
+}
+
+// complete records the item of the claimed repository, a copy as the scan goes on to change it.
+func (r *runResults) complete(repository string, item *ResultItem, err error) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	result := r.entries[strings.ToLower(repository)]
+	r.mu.Unlock()
+
+	if item != nil {
+		item = item.clone()
+	}
+	result.item, result.err = item, err
+	close(result.done)
+}
+
+// wait returns a copy of the item of the repository scanned by another scan of the run.
+func (r *runResult) wait(ctx context.Context, repository *Repository) (*ResultItem, error) {
+	select {
+	case <-r.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	item := r.item.clone()
+	// the item references the repository of the scan waiting for it
+	item.Repository = repository
+
+	return item, nil
+}
+
+// budget is the rate limit requests reserved by the running scans of a scanner. Scans reserve their estimated
+// requests before fetching releases and release them as the repositories are scanned, by then the requests are
+// counted in the remaining rate limit.
+type budget struct {
+	mu       sync.Mutex
+	reserved int
+}
+
+// reserve adds the requests to the reserved ones if they fit the remaining rate limit, any requests fit if the
+// rate limit is unknown. It returns the requests reserved before.
+func (b *budget) reserve(requests int, rateLimit *RateLimit) (int, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	reserved := b.reserved
+	if rateLimit != nil && reserved+requests > rateLimit.Remaining {
+		return reserved, false
+	}
+	b.reserved += requests
+
+	return reserved, true
+}
+
+func (b *budget) release(requests int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.reserved -= requests
+}
+
+// workers limits the repositories scanned at once by all scans of a scanner to maxWorkersCount.
+type workers struct {
+	once  sync.Once
+	slots chan struct{}
+}
+
+func (w *workers) acquire(ctx context.Context) error {
+	w.once.Do(func() {
+		w.slots = make(chan struct{}, maxWorkersCount)
+	})
+	select {
+	case w.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *workers) release() {
+	<-w.slots
+}
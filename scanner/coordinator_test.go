@@ -0,0 +1,166 @@
+package scanner
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCoordinatorScanAccounts(t *testing.T) {
+	bListed := make(chan struct{})
+	var once sync.Once
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/a/repos":
+			// a is listed once b is, so the accounts must be scanned concurrently
+			select {
+			case <-bListed:
+			case <-time.After(5 * time.Second):
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.Write([]byte(`[{"full_name": "a/one", "name": "one"}, {"full_name": "a/two", "name": "two"}]`))
+		case "/users/b/repos":
+			once.Do(func() { close(bListed) })
+			w.Write([]byte(`[{"full_name": "b/one", "name": "one"}]`))
+		case "/users/sso/repos":
+			w.Header().Set("X-GitHub-SSO", "required; url=https://github.com/orgs/sso/sso")
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message": "Resource protected by organization SAML enforcement"}`))
+		default:
+			w.Write([]byte(`[{"tag_name": "v1.0.0"}]`))
+		}
+	}))
+	defer server.Close()
+
+	var totals []int
+	scanner := Scanner{BaseUrl: server.URL, Token: "secret"}
+	scanner.OnProgress = func(done, total int, repository string) {
+		totals = append(totals, total)
+	}
+	coordinator := &Coordinator{Scanner: &scanner, Concurrency: 3}
+	items, skipped, err := coordinator.ScanAccounts([]string{"a", "sso", "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 3 || items[0].Repository.FullName != "a/one" || items[2].Repository.FullName != "b/one" {
+		t.Fatalf("invalid scanned items, expected a/one, a/two and b/one, got %v", items)
+	}
+	if len(skipped) != 1 || skipped[0].Account != "sso" || skipped[0].AuthorizationURL != "https://github.com/orgs/sso/sso" {
+		t.Fatalf("invalid skipped accounts, expected sso, got %v", skipped)
+	}
+	if len(totals) != 3 || totals[2] != 3 {
+		t.Fatalf("invalid progress totals, expected 3 updates up to 3 repositories, got %v", totals)
+	}
+}
+
+func TestCoordinatorScanAccountsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/users/broken/repos" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	coordinator := &Coordinator{Scanner: &Scanner{BaseUrl: server.URL}}
+	if _, _, err := coordinator.ScanAccounts([]string{"ok", "broken"}); err == nil {
+		t.Fatalf("invalid error of the broken account, expected error, got nil")
+	}
+}
+
+func TestBudget(t *testing.T) {
+	var b budget
+	rateLimit := &RateLimit{Remaining: 10}
+	if _, ok := b.reserve(6, rateLimit); !ok {
+		t.Fatalf("invalid reservation, expected 6 of 10 requests to fit")
+	}
+	reserved, ok := b.reserve(6, rateLimit)
+	if ok || reserved != 6 {
+		t.Fatalf("invalid concurrent reservation, expected 6 requests not to fit besides 6 reserved, got %v besides %d", ok, reserved)
+	}
+	b.release(6)
+	if _, ok := b.reserve(6, rateLimit); !ok {
+		t.Fatalf("invalid reservation after the release, expected 6 of 10 requests to fit")
+	}
+	if _, ok := b.reserve(100, nil); !ok {
+		t.Fatalf("invalid reservation of an unknown rate limit, expected any requests to fit")
+	}
+}
+
+func TestScannerCheckBudgetOfConcurrentScans(t *testing.T) {
+	scanner := Scanner{}
+	scanner.rateLimit.Store(&RateLimit{Remaining: 3})
+	repositories := []*Repository{{FullName: "test/a"}, {FullName: "test/b"}}
+	if _, err := scanner.checkBudget("first", repositories); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := scanner.checkBudget("second", repositories); !errors.Is(err, ErrInsufficientBudget) {
+		t.Fatalf("invalid error, expected %v, got %v", ErrInsufficientBudget, err)
+	}
+}
+
+func TestCoordinatorScanAccountsDuplicates(t *testing.T) {
+	var mu sync.Mutex
+	releaseRequests := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/a/repos":
+			w.Write([]byte(`[{"full_name": "a/tool", "name": "tool"}]`))
+		case "/users/b/repos":
+			// a transferred repository is listed under both accounts
+			w.Write([]byte(`[{"full_name": "A/Tool", "name": "Tool"}, {"full_name": "b/lib", "name": "lib"}]`))
+		default:
+			mu.Lock()
+			releaseRequests[strings.ToLower(r.URL.Path)]++
+			mu.Unlock()
+			w.Write([]byte(`[{"tag_name": "v1.0.0"}, {"tag_name": "v1.1.0"}]`))
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL, MaxReleases: 1}
+	items, _, err := (&Coordinator{Scanner: &scanner}).ScanAccounts([]string{"a", "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("invalid scanned items, expected a/tool and b/lib, got %v", items)
+	}
+	if requests := releaseRequests["/repos/a/tool/releases"]; requests != 1 {
+		t.Fatalf("invalid release requests of the duplicated repository, expected 1, got %d", requests)
+	}
+	for _, item := range items {
+		if len(item.Releases) != 1 {
+			t.Fatalf("invalid releases of %s, expected 1, got %d", item.Repository.FullName, len(item.Releases))
+		}
+	}
+}
+
+func TestCoordinatorScanAccountsPriority(t *testing.T) {
+	var mu sync.Mutex
+	var listed []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if account, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/users/"), "/repos"); ok {
+			mu.Lock()
+			listed = append(listed, account)
+			mu.Unlock()
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	coordinator := &Coordinator{Scanner: &Scanner{BaseUrl: server.URL}, Concurrency: 1, Priority: []string{"c", "d", "e"}, MaxPriorityStarts: 2}
+	if _, _, err := coordinator.ScanAccounts([]string{"a", "b", "c", "d", "e"}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(listed, ",") != "c,d,a,e,b" {
+		t.Fatalf("invalid order of the scanned accounts, expected c,d,a,e,b, got %v", listed)
+	}
+}
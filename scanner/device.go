@@ -0,0 +1,164 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// GitHubLogin is the base url of the GitHub OAuth endpoints.
+const GitHubLogin = "https://github.com"
+
+// deviceFlowInterval is the unit of the polling intervals reported by GitHub, tests shorten it.
+var deviceFlowInterval = time.Second
+
+// ErrDeviceFlowDenied is returned when the user cancels the authorization of the device.
+var ErrDeviceFlowDenied = errors.New("authorization denied")
+
+// ErrDeviceFlowExpired is returned when the device code expires before the user authorizes it.
+var ErrDeviceFlowExpired = errors.New("device code expired")
+
+// DeviceCode is the code the user enters at the verification url to authorize the device.
+type DeviceCode struct {
+	DeviceCode      string `json:"device_code"`
+	UserCode        string `json:"user_code"`
+	VerificationURI string `json:"verification_uri"`
+	ExpiresIn       int    `json:"expires_in"`
+	Interval        int    `json:"interval"`
+}
+
+// DeviceToken is the user token authorized with the device flow. Expiring user tokens of GitHub Apps come with a
+// refresh token, see OAuthRefreshTokenSource, tokens of OAuth apps never expire.
+type DeviceToken struct {
+	AccessToken string
+	// Expiry is zero if the token does not expire.
+	Expiry       time.Time
+	RefreshToken string
+}
+
+// DeviceFlow obtains a user token with the OAuth device authorization flow of an OAuth app, so users do not have
+// to create personal access tokens themselves.
+type DeviceFlow struct {
+	// BaseUrl is the GitHub host serving the OAuth endpoints, GitHubLogin if it is empty.
+	BaseUrl  string
+	ClientID string
+	Scopes   []string
+	Client   *http.Client
+}
+
+// RequestCode requests the device and user codes starting the flow.
+func (f *DeviceFlow) RequestCode(ctx context.Context) (*DeviceCode, error) {
+	var code DeviceCode
+	err := f.post(ctx, "/login/device/code", url.Values{
+		"client_id": {f.ClientID},
+		"scope":     {strings.Join(f.Scopes, " ")},
+	}, &code)
+	if err != nil {
+		return nil, fmt.Errorf("could not request the device code: %w", err)
+	}
+	if code.DeviceCode == "" {
+		return nil, errors.New("could not request the device code: no code in the response")
+	}
+
+	return &code, nil
+}
+
+// PollToken polls for the access token until the user authorizes the device, denies it or the code expires.
+func (f *DeviceFlow) PollToken(ctx context.Context, code *DeviceCode) (*DeviceToken, error) {
+	interval := time.Duration(max(code.Interval, 1)) * deviceFlowInterval
+	expiresIn := code.ExpiresIn
+	if expiresIn <= 0 {
+		// GitHub device codes expire in 15 minutes
+		expiresIn = 900
+	}
+	expiresAt := time.Now().Add(time.Duration(expiresIn) * deviceFlowInterval)
+
+	for {
+		if time.Now().Add(interval).After(expiresAt) {
+			return nil, ErrDeviceFlowExpired
+		}
+		timer := time.NewTimer(interval)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+
+		var token struct {
+			AccessToken      string `json:"access_token"`
+			ExpiresIn        int    `json:"expires_in"`
+			RefreshToken     string `json:"refresh_token"`
+			Error            string `json:"error"`
+			ErrorDescription string `json:"error_description"`
+			Interval         int    `json:"interval"`
+		}
+		err := f.post(ctx, "/login/oauth/access_token", url.Values{
+			"client_id":   {f.ClientID},
+			"device_code": {code.DeviceCode},
+			"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+		}, &token)
+		if err != nil {
+			return nil, fmt.Errorf("could not request the access token: %w", err)
+		}
+
+		switch token.Error {
+		case "":
+			if token.AccessToken == "" {
+				return nil, errors.New("could not request the access token: no token in the response")
+			}
+			result := &DeviceToken{AccessToken: token.AccessToken, RefreshToken: token.RefreshToken}
+			if token.ExpiresIn > 0 {
+				result.Expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+			}
+			return result, nil
+		case "authorization_pending":
+		case "slow_down":
+			interval = time.Duration(max(token.Interval, code.Interval+5)) * deviceFlowInterval
+		case "expired_token":
+			return nil, ErrDeviceFlowExpired
+		case "access_denied":
+			return nil, ErrDeviceFlowDenied
+		default:
+			return nil, fmt.Errorf("could not request the access token: %s: %s", token.Error, token.ErrorDescription)
+		}
+	}
+}
+
+func (f *DeviceFlow) post(ctx context.Context, path string, form url.Values, result any) error {
+	baseUrl := f.BaseUrl
+	if baseUrl == "" {
+		baseUrl = GitHubLogin
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(baseUrl, "/")+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+
+	response, err := f.getClient().Do(request)
+	if err != nil {
+		return wrapTransportError(err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return newApiError(response, response.Status)
+	}
+
+	return json.NewDecoder(response.Body).Decode(result)
+}
+
+func (f *DeviceFlow) getClient() *http.Client {
+	if f.Client == nil {
+		return http.DefaultClient
+	}
+
+	return f.Client
+}
@@ -0,0 +1,94 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDeviceFlow(t *testing.T) {
+	deviceFlowInterval = time.Millisecond
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Accept") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		r.ParseForm()
+		if r.PostForm.Get("client_id") != "client" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch r.URL.Path {
+		case "/login/device/code":
+			if r.PostForm.Get("scope") != "repo read:org" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(`{"device_code": "device", "user_code": "ABCD-1234", "verification_uri": "https://github.com/login/device", "expires_in": 900, "interval": 1}`))
+		case "/login/oauth/access_token":
+			if r.PostForm.Get("device_code") != "device" || r.PostForm.Get("grant_type") != "urn:ietf:params:oauth:grant-type:device_code" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			switch polls++; polls {
+			case 1:
+				w.Write([]byte(`{"error": "authorization_pending"}`))
+			case 2:
+				w.Write([]byte(`{"error": "slow_down", "interval": 6}`))
+			default:
+				w.Write([]byte(`{"access_token": "token", "expires_in": 28800, "refresh_token": "refresh", "token_type": "bearer", "scope": "repo,read:org"}`))
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	flow := DeviceFlow{BaseUrl: server.URL, ClientID: "client", Scopes: []string{"repo", "read:org"}}
+	code, err := flow.RequestCode(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code.UserCode != "ABCD-1234" || code.VerificationURI != "https://github.com/login/device" {
+		t.Fatalf("invalid device code, expected ABCD-1234 at https://github.com/login/device, got %s at %s", code.UserCode, code.VerificationURI)
+	}
+
+	token, err := flow.PollToken(context.Background(), code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.AccessToken != "token" || token.RefreshToken != "refresh" || token.Expiry.IsZero() {
+		t.Fatalf("invalid token, expected token refreshed with refresh and an expiry, got %+v", token)
+	}
+	if polls != 3 {
+		t.Fatalf("invalid polls count, expected 3, got %d", polls)
+	}
+}
+
+func TestDeviceFlowErrors(t *testing.T) {
+	deviceFlowInterval = time.Millisecond
+	cases := []struct {
+		response string
+		expected error
+	}{
+		{`{"error": "access_denied"}`, ErrDeviceFlowDenied},
+		{`{"error": "expired_token"}`, ErrDeviceFlowExpired},
+		{`{"error": "authorization_pending"}`, ErrDeviceFlowExpired},
+	}
+	for _, c := range cases {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(c.response))
+		}))
+
+		flow := DeviceFlow{BaseUrl: server.URL, ClientID: "client"}
+		_, err := flow.PollToken(context.Background(), &DeviceCode{DeviceCode: "device", ExpiresIn: 20, Interval: 1})
+		server.Close()
+		if !errors.Is(err, c.expected) {
+			t.Fatalf("invalid error of %s, expected %v, got %v", c.response, c.expected, err)
+		}
+	}
+}
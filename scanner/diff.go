@@ -0,0 +1,95 @@
+package scanner
+
+import "sort"
+
+const (
+	ChangeRepositoryAdded   = "repository_added"
+	ChangeRepositoryRemoved = "repository_removed"
+	ChangeReleaseAdded      = "release_added"
+	ChangeReleaseRemoved    = "release_removed"
+)
+
+type Change struct {
+	Kind       string `json:"kind"`
+	Repository string `json:"repository"`
+	Release    string `json:"release,omitempty"`
+	// Setting is the name of the changed repository setting, From and To are its old and new values.
+	Setting string `json:"setting,omitempty"`
+	From    string `json:"from,omitempty"`
+	To      string `json:"to,omitempty"`
+}
+
+// DiffResults returns changes between two scans of the same account ordered by repository and kind.
+// Releases are matched by their versions.
+func DiffResults(old, new []*ResultItem) []*Change {
+	oldItems := indexResultItems(old)
+	newItems := indexResultItems(new)
+
+	var changes []*Change
+	for fullName, newItem := range newItems {
+		oldItem, ok := oldItems[fullName]
+		if !ok {
+			changes = append(changes, &Change{Kind: ChangeRepositoryAdded, Repository: fullName})
+			for _, release := range newItem.Releases {
+				changes = append(changes, &Change{Kind: ChangeReleaseAdded, Repository: fullName, Release: release.Version()})
+			}
+			continue
+		}
+		changes = append(changes, diffReleases(fullName, oldItem.Releases, newItem.Releases)...)
+		changes = append(changes, diffSettings(fullName, oldItem.Settings, newItem.Settings)...)
+	}
+	for fullName := range oldItems {
+		if _, ok := newItems[fullName]; !ok {
+			changes = append(changes, &Change{Kind: ChangeRepositoryRemoved, Repository: fullName})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Repository != changes[j].Repository {
+			return changes[i].Repository < changes[j].Repository
+		}
+		if changes[i].Kind != changes[j].Kind {
+			return changes[i].Kind > changes[j].Kind
+		}
+		if changes[i].Release != changes[j].Release {
+			return changes[i].Release < changes[j].Release
+		}
+		return changes[i].Setting < changes[j].Setting
+	})
+
+	return changes
+}
+
+func diffReleases(repository string, old, new []*Release) []*Change {
+	oldVersions := map[string]bool{}
+	for _, release := range old {
+		oldVersions[release.Version()] = true
+	}
+	newVersions := map[string]bool{}
+	for _, release := range new {
+		newVersions[release.Version()] = true
+	}
+
+	var changes []*Change
+	for version := range newVersions {
+		if !oldVersions[version] {
+			changes = append(changes, &Change{Kind: ChangeReleaseAdded, Repository: repository, Release: version})
+		}
+	}
+	for version := range oldVersions {
+		if !newVersions[version] {
+			changes = append(changes, &Change{Kind: ChangeReleaseRemoved, Repository: repository, Release: version})
+		}
+	}
+
+	return changes
+}
+
+func indexResultItems(items []*ResultItem) map[string]*ResultItem {
+	index := make(map[string]*ResultItem, len(items))
+	for _, item := range items {
+		index[item.Repository.FullName] = item
+	}
+
+	return index
+}
@@ -0,0 +1,32 @@
+package scanner
+
+import "testing"
+
+func TestDiffResults(t *testing.T) {
+	old := []*ResultItem{
+		{Repository: &Repository{FullName: "test/a"}, Releases: []*Release{{TagName: "v1"}, {TagName: "v2"}}},
+		{Repository: &Repository{FullName: "test/b"}},
+	}
+	new := []*ResultItem{
+		{Repository: &Repository{FullName: "test/a"}, Releases: []*Release{{TagName: "v2"}, {TagName: "v3"}}},
+		{Repository: &Repository{FullName: "test/c"}, Releases: []*Release{{TagName: "v1"}}},
+	}
+
+	changes := DiffResults(old, new)
+
+	expected := []Change{
+		{Kind: ChangeReleaseRemoved, Repository: "test/a", Release: "v1"},
+		{Kind: ChangeReleaseAdded, Repository: "test/a", Release: "v3"},
+		{Kind: ChangeRepositoryRemoved, Repository: "test/b"},
+		{Kind: ChangeRepositoryAdded, Repository: "test/c"},
+		{Kind: ChangeReleaseAdded, Repository: "test/c", Release: "v1"},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("invalid changes count, expected %d, got %d", len(expected), len(changes))
+	}
+	for i, change := range changes {
+		if *change != expected[i] {
+			t.Errorf("invalid change %d, expected %+v, got %+v", i, expected[i], *change)
+		}
+	}
+}
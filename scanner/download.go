@@ -0,0 +1,171 @@
+package scanner
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// GetReleaseByTag returns the release of the repository with the tag, or the latest release if the tag is empty.
+func (s *Scanner) GetReleaseByTag(ctx context.Context, owner, repository, tag string) (*Release, error) {
+	if err := s.checkUser(owner); err != nil {
+		return nil, err
+	}
+	if err := s.checkRepository(repository); err != nil {
+		return nil, err
+	}
+	ctx, span := s.getTracer().Start(ctx, "GetReleaseByTag", StringAttribute("account", owner), StringAttribute("repository", repository), StringAttribute("tag", tag))
+	defer span.End()
+
+	apiUrl := fmt.Sprintf("%s/repos/%s/%s/releases/latest", s.BaseUrl, owner, repository)
+	if tag != "" {
+		apiUrl = fmt.Sprintf("%s/repos/%s/%s/releases/tags/%s", s.BaseUrl, owner, repository, url.PathEscape(tag))
+	}
+	response, err := s.get(ctx, span, apiUrl)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil, newNotFoundError("release %s of the repository %s/%s does not exist", tag, owner, repository)
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not get release %s of the repository %s/%s: %w", tag, owner, repository, s.newApiError(response))
+	}
+
+	var release Release
+	if err := json.NewDecoder(response.Body).Decode(&release); err != nil {
+		return nil, err
+	}
+
+	return &release, nil
+}
+
+// DownloadAsset writes the asset content to w. If the asset has a digest, the content is verified against it
+// and an error is returned on mismatch, in which case the written data must be discarded.
+// With a token the asset is fetched through the API url, so assets of private repositories are downloadable too.
+func (s *Scanner) DownloadAsset(ctx context.Context, asset *Asset, w io.Writer) error {
+	ctx, span := s.getTracer().Start(ctx, "DownloadAsset", StringAttribute("asset", asset.Name))
+	defer span.End()
+
+	response, err := s.openAsset(ctx, span, asset, 0, "")
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	verifier, err := newDigestVerifier(asset.Digest)
+	if err != nil {
+		return err
+	}
+	if verifier != nil {
+		w = io.MultiWriter(w, verifier)
+	}
+	if _, err := io.Copy(w, response.Body); err != nil {
+		return fmt.Errorf("could not download the asset %s: %v", asset.Name, err)
+	}
+	if verifier != nil {
+		if err := verifier.verify(); err != nil {
+			return fmt.Errorf("could not verify the asset %s: %v", asset.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// openAsset requests the asset content starting from the offset. The response status is 206 if the server
+// honored the range, 200 if the whole content is sent and 416 if there is nothing after the offset. If the etag
+// is set, the range is only honored if the content still has the etag.
+func (s *Scanner) openAsset(ctx context.Context, span Span, asset *Asset, offset int64, etag string) (*http.Response, error) {
+	downloadUrl := asset.BrowserDownloadURL
+	if s.authenticated() && asset.URL != "" {
+		downloadUrl = asset.URL
+	}
+	if downloadUrl == "" {
+		return nil, fmt.Errorf("asset %s has no download url", asset.Name)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, downloadUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Accept", "application/octet-stream")
+	token, err := s.token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	if offset > 0 {
+		request.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		if etag != "" {
+			request.Header.Set("If-Range", etag)
+		}
+	}
+
+	s.getLogger().Debug("asset download", "asset", asset.Name, "url", downloadUrl, "offset", offset)
+	response, err := s.roundTrip(request)
+	if err != nil {
+		span.RecordError(err)
+		return nil, wrapTransportError(err)
+	}
+	span.SetAttributes(IntAttribute("http.status_code", response.StatusCode))
+
+	// The range is not satisfiable if a resumed download is already complete.
+	if response.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0 {
+		return response, nil
+	}
+	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusPartialContent {
+		response.Body.Close()
+		return nil, fmt.Errorf("could not download the asset %s: %w", asset.Name, newApiError(response, response.Status))
+	}
+
+	return response, nil
+}
+
+// digestVerifier checks content against a digest in the "algorithm:hex" format GitHub reports for assets.
+type digestVerifier struct {
+	hash.Hash
+	algorithm string
+	expected  string
+}
+
+func newDigestVerifier(digest string) (*digestVerifier, error) {
+	if digest == "" {
+		return nil, nil
+	}
+	algorithm, expected, ok := strings.Cut(digest, ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid digest: %s", digest)
+	}
+
+	verifier := &digestVerifier{algorithm: algorithm, expected: strings.ToLower(expected)}
+	switch algorithm {
+	case "sha256":
+		verifier.Hash = sha256.New()
+	case "sha512":
+		verifier.Hash = sha512.New()
+	default:
+		return nil, fmt.Errorf("unsupported digest algorithm: %s", algorithm)
+	}
+
+	return verifier, nil
+}
+
+func (v *digestVerifier) verify() error {
+	if actual := hex.EncodeToString(v.Sum(nil)); actual != v.expected {
+		return fmt.Errorf("%s digest mismatch, expected %s, got %s", v.algorithm, v.expected, actual)
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDownloadAsset(t *testing.T) {
+	content := []byte("release content")
+	sum := sha256.Sum256(content)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/test/test/releases/tags/v1.0.0":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"tag_name": "v1.0.0", "assets": [
+				{"name": "valid.tar.gz", "browser_download_url": "` + "http://" + r.Host + `/download", "digest": "sha256:` + hex.EncodeToString(sum[:]) + `"},
+				{"name": "corrupted.tar.gz", "browser_download_url": "` + "http://" + r.Host + `/download", "digest": "sha256:0000"}
+			]}`))
+		case "/download":
+			w.WriteHeader(http.StatusOK)
+			w.Write(content)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL}
+	release, err := scanner.GetReleaseByTag(context.Background(), "test", "test", "v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(release.Assets) != 2 {
+		t.Fatalf("invalid release assets count, expected 2, got %d", len(release.Assets))
+	}
+
+	var buf bytes.Buffer
+	if err := scanner.DownloadAsset(context.Background(), release.Assets[0], &buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), content) {
+		t.Fatalf("invalid downloaded content: %s", buf.String())
+	}
+
+	err = scanner.DownloadAsset(context.Background(), release.Assets[1], &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "digest mismatch") {
+		t.Fatalf("digest mismatch error is expected, got %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package scanner
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Deployments maps repository full names to their currently deployed versions.
+type Deployments map[string]string
+
+// Drift is how far the deployed version of a repository is behind its latest release.
+type Drift struct {
+	Repository string `json:"repository"`
+	Deployed   string `json:"deployed"`
+	// Latest is empty if the repository has no release following its version scheme.
+	Latest string `json:"latest,omitempty"`
+	// ReleasesBehind counts the published releases greater than the deployed version.
+	ReleasesBehind int `json:"releases_behind"`
+	// Distance is the semver distance to the latest release, e.g. "2 major" or "3 minor", empty unless both
+	// versions are semver.
+	Distance string `json:"distance,omitempty"`
+	// Error explains why the drift is unknown, e.g. the repository is not scanned.
+	Error string `json:"error,omitempty"`
+}
+
+// LoadDeployments reads the deployed versions from a csv or json file. The csv file has "repository,version"
+// records with an optional header, the json file is an object mapping repository full names to versions.
+func LoadDeployments(filePath string) (Deployments, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	deployments := make(Deployments)
+	if strings.EqualFold(filepath.Ext(filePath), ".json") {
+		err = json.Unmarshal(data, &deployments)
+	} else {
+		var records [][]string
+		reader := csv.NewReader(strings.NewReader(string(data)))
+		reader.FieldsPerRecord = 2
+		if records, err = reader.ReadAll(); err == nil {
+			for i, record := range records {
+				if i == 0 && strings.EqualFold(record[0], "repository") {
+					continue
+				}
+				deployments[record[0]] = record[1]
+			}
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the deployments %s: %v", filePath, err)
+	}
+
+	return deployments, nil
+}
+
+// VersionDrift compares the deployed versions with the latest releases of the scanned items according to the
+// repository version schemes. Drafts and pre-releases are not counted. The drifts are ordered by repository.
+func (s *Scanner) VersionDrift(items []*ResultItem, deployments Deployments) []*Drift {
+	scanned := make(map[string]*ResultItem, len(items))
+	for _, item := range items {
+		scanned[item.Repository.FullName] = item
+	}
+
+	drifts := make([]*Drift, 0, len(deployments))
+	for repository, deployed := range deployments {
+		drift := &Drift{Repository: repository, Deployed: deployed}
+		drifts = append(drifts, drift)
+
+		item := scanned[repository]
+		if item == nil {
+			drift.Error = "repository is not scanned"
+			continue
+		}
+		scheme := s.GetVersionScheme(repository)
+		if _, err := scheme.Compare(deployed, deployed); err != nil {
+			drift.Error = err.Error()
+			continue
+		}
+		latest := s.LatestRelease(item)
+		if latest == nil {
+			drift.Error = "repository has no releases following the " + scheme.Name() + " scheme"
+			continue
+		}
+		drift.Latest = latest.Version()
+
+		for _, release := range item.Releases {
+			if release.Draft || release.Prerelease {
+				continue
+			}
+			if result, err := scheme.Compare(release.Version(), deployed); err == nil && result > 0 {
+				drift.ReleasesBehind++
+			}
+		}
+		if scheme.Name() == "semver" {
+			drift.Distance = semverDistance(deployed, drift.Latest)
+		}
+	}
+	sort.Slice(drifts, func(i, j int) bool {
+		return drifts[i].Repository < drifts[j].Repository
+	})
+
+	return drifts
+}
+
+// semverDistance returns the most significant difference of the versions, e.g. "2 major" if the major versions
+// differ, or "up to date" if the deployed version is not behind.
+func semverDistance(deployed, latest string) string {
+	deployedMatch := semverRegexp.FindStringSubmatch(deployed)
+	latestMatch := semverRegexp.FindStringSubmatch(latest)
+	if result, _ := (semverScheme{}).Compare(latest, deployed); result <= 0 {
+		return "up to date"
+	}
+
+	for i, name := range []string{"major", "minor", "patch"} {
+		if difference := atoi(latestMatch[i+1]) - atoi(deployedMatch[i+1]); difference != 0 {
+			return fmt.Sprintf("%d %s", difference, name)
+		}
+	}
+
+	return "pre-release"
+}
@@ -0,0 +1,68 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadDeployments(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "deployments.csv")
+	if err := os.WriteFile(csvPath, []byte("repository,version\ntest/api,v1.2.0\ntest/web,2024.01\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	jsonPath := filepath.Join(dir, "deployments.json")
+	if err := os.WriteFile(jsonPath, []byte(`{"test/api": "v1.2.0", "test/web": "2024.01"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{csvPath, jsonPath} {
+		deployments, err := LoadDeployments(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(deployments) != 2 || deployments["test/api"] != "v1.2.0" || deployments["test/web"] != "2024.01" {
+			t.Fatalf("invalid deployments of %s, got %v", filepath.Base(path), deployments)
+		}
+	}
+}
+
+func TestVersionDrift(t *testing.T) {
+	items := []*ResultItem{
+		{
+			Repository: &Repository{FullName: "test/api"},
+			Releases: []*Release{
+				{TagName: "v3.0.0-rc1", Prerelease: true},
+				{TagName: "v2.1.0"},
+				{TagName: "v2.0.0"},
+				{TagName: "v1.3.0"},
+				{TagName: "v1.2.0"},
+			},
+		},
+		{Repository: &Repository{FullName: "test/cli"}, Releases: []*Release{{TagName: "v0.4.0"}, {TagName: "v0.3.1"}}},
+		{Repository: &Repository{FullName: "test/docs"}},
+	}
+	deployments := Deployments{"test/api": "v1.2.0", "test/cli": "v0.4.0", "test/docs": "v1.0.0", "test/gone": "v1.0.0"}
+
+	scanner := Scanner{}
+	drifts := scanner.VersionDrift(items, deployments)
+	expected := []Drift{
+		{Repository: "test/api", Deployed: "v1.2.0", Latest: "v2.1.0", ReleasesBehind: 3, Distance: "1 major"},
+		{Repository: "test/cli", Deployed: "v0.4.0", Latest: "v0.4.0", Distance: "up to date"},
+		{Repository: "test/docs", Deployed: "v1.0.0", Error: "repository has no releases following the semver scheme"},
+		{Repository: "test/gone", Deployed: "v1.0.0", Error: "repository is not scanned"},
+	}
+	if len(drifts) != len(expected) {
+		t.Fatalf("invalid drifts count, expected %d, got %d", len(expected), len(drifts))
+	}
+	for i, drift := range drifts {
+		if *drift != expected[i] {
+			t.Fatalf("invalid drift, expected %+v, got %+v", expected[i], *drift)
+		}
+	}
+
+	if distance := semverDistance("v2.0.3", "v2.3.0"); distance != "3 minor" {
+		t.Fatalf("invalid distance, expected 3 minor, got %s", distance)
+	}
+}
@@ -0,0 +1,100 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Error classes of failed API calls. They are matched with errors.Is against errors returned by the scanner.
+var (
+	ErrBadCredentials = errors.New("bad credentials")
+	ErrSSORequired    = errors.New("token is not authorized for SAML SSO")
+	ErrRateLimited    = errors.New("rate limit exceeded")
+	ErrNotFound       = errors.New("not found")
+	ErrProxyBlocked   = errors.New("request blocked by a proxy")
+	ErrRequestTimeout = errors.New("request timed out")
+)
+
+// ErrDeadlineExceeded is returned by the scans still running at Scanner.ScanDeadline.
+var ErrDeadlineExceeded = errors.New("scan deadline exceeded")
+
+// APIError is a failed API response. Its class is one of the Err* errors, or nil for other failures.
+type APIError struct {
+	StatusCode int
+	Message    string
+	Class      error
+	// SSOAuthorizationURL is the url the token should be authorized for the organization SSO at.
+	SSOAuthorizationURL string
+}
+
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Class
+}
+
+// newApiError classifies the response by its status and headers. The message is the API error message.
+func newApiError(response *http.Response, message string) *APIError {
+	apiError := &APIError{
+		StatusCode: response.StatusCode,
+		Message:    message,
+	}
+
+	switch {
+	case response.StatusCode == http.StatusUnauthorized:
+		apiError.Class = ErrBadCredentials
+	case response.StatusCode == http.StatusProxyAuthRequired:
+		apiError.Class = ErrProxyBlocked
+	case response.StatusCode == http.StatusForbidden && response.Header.Get("X-GitHub-SSO") != "":
+		apiError.Class = ErrSSORequired
+		apiError.SSOAuthorizationURL = parseSSOAuthorizationURL(response.Header.Get("X-GitHub-SSO"))
+		if apiError.SSOAuthorizationURL != "" {
+			apiError.Message = fmt.Sprintf("%s, authorize the token at %s", message, apiError.SSOAuthorizationURL)
+		}
+	case response.StatusCode == http.StatusTooManyRequests,
+		response.StatusCode == http.StatusForbidden && response.Header.Get("X-RateLimit-Remaining") == "0",
+		response.StatusCode == http.StatusForbidden && response.Header.Get("Retry-After") != "",
+		response.StatusCode == http.StatusForbidden && strings.Contains(strings.ToLower(message), "rate limit"):
+		apiError.Class = ErrRateLimited
+	case response.StatusCode == http.StatusNotFound:
+		apiError.Class = ErrNotFound
+	case response.StatusCode == http.StatusForbidden && strings.HasPrefix(response.Header.Get("Content-Type"), "text/html"):
+		// GitHub API never responds with HTML, it is a block page of a corporate proxy.
+		apiError.Class = ErrProxyBlocked
+	}
+
+	return apiError
+}
+
+// parseSSOAuthorizationURL extracts the url from the X-GitHub-SSO header value, e.g. "required; url=https://github.com/orgs/...".
+func parseSSOAuthorizationURL(header string) string {
+	for _, part := range strings.Split(header, ";") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(part), "url="); ok {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// newNotFoundError is returned when an account or a repository does not exist.
+func newNotFoundError(format string, args ...interface{}) *APIError {
+	return &APIError{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf(format, args...),
+		Class:      ErrNotFound,
+	}
+}
+
+// wrapTransportError marks failures to connect through a proxy.
+func wrapTransportError(err error) error {
+	if strings.Contains(err.Error(), "proxyconnect") {
+		return fmt.Errorf("%w: %v", ErrProxyBlocked, err)
+	}
+
+	return err
+}
@@ -0,0 +1,41 @@
+package scanner
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestApiErrorClasses(t *testing.T) {
+	cases := []struct {
+		status   int
+		headers  map[string]string
+		body     string
+		expected error
+	}{
+		{http.StatusUnauthorized, nil, `{"message": "Bad credentials"}`, ErrBadCredentials},
+		{http.StatusForbidden, map[string]string{"X-RateLimit-Remaining": "0"}, `{"message": "API rate limit exceeded"}`, ErrRateLimited},
+		{http.StatusTooManyRequests, nil, `{"message": "slow down"}`, ErrRateLimited},
+		{http.StatusForbidden, map[string]string{"X-GitHub-SSO": "required; url=https://github.com/orgs/test/sso"}, `{"message": "Resource protected by organization SAML enforcement"}`, ErrSSORequired},
+		{http.StatusForbidden, map[string]string{"Content-Type": "text/html"}, `<html>Access denied</html>`, ErrProxyBlocked},
+		{http.StatusNotFound, nil, `{"message": "Not Found"}`, ErrNotFound},
+	}
+	for _, c := range cases {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for key, value := range c.headers {
+				w.Header().Set(key, value)
+			}
+			w.WriteHeader(c.status)
+			w.Write([]byte(c.body))
+		}))
+
+		scanner := Scanner{BaseUrl: server.URL}
+		_, err := scanner.ScanRepositories("test")
+		server.Close()
+
+		if !errors.Is(err, c.expected) {
+			t.Errorf("invalid error class for the status %d, expected %v, got %v", c.status, c.expected, err)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInsufficientBudget is returned when the remaining rate limit is lower than the estimated cost of a scan.
+var ErrInsufficientBudget = errors.New("insufficient rate limit budget")
+
+// ScanEstimate is the estimated count of GitHub REST API requests of a scan. The counts are lower bounds:
+// paginated lists are assumed to fit a page, branches to be a single one and the signature verification to
+// download one signed asset with its signature per repository. Releases listed by other providers, e.g. GraphQL,
+// GitLab or Gitea, are not counted as they do not take GitHub REST API requests.
+type ScanEstimate struct {
+	Repositories int `json:"repositories"`
+	// ListRequests are the requests listing the repositories.
+	ListRequests int `json:"list_requests"`
+	// ScanRequests are the requests fetching releases and the enabled optional data of the repositories.
+	ScanRequests int `json:"scan_requests"`
+}
+
+// Requests returns the estimated count of all requests of the scan.
+func (e *ScanEstimate) Requests() int {
+	return e.ListRequests + e.ScanRequests
+}
+
+// EstimateScan estimates the API requests a scan of the listed repositories takes with the enabled scanner
+// options. Only the repositories within Scanner.MaxRepositories are scanned, but all of them are listed.
+func (s *Scanner) EstimateScan(repositories []*Repository) *ScanEstimate {
+	listRequests := max((len(repositories)+s.getPerPage()-1)/s.getPerPage(), 1)
+	repositories = s.limitRepositories(repositories)
+	estimate := &ScanEstimate{
+		Repositories: len(repositories),
+		ListRequests: listRequests,
+	}
+	if s.ScanOwnership {
+		estimate.ScanRequests++
+	}
+	for _, repository := range repositories {
+		estimate.ScanRequests += s.estimateRepositoryRequests(repository)
+	}
+
+	return estimate
+}
+
+func (s *Scanner) estimateRepositoryRequests(repository *Repository) int {
+	requests := 0
+	if !s.unchangedSince(repository) && s.listsReleasesWithREST(s.getProvider()) {
+		requests++
+	}
+	for _, enabled := range []bool{s.ScanSettings, s.ScanContributors, s.ScanLanguages, s.ScanWorkflows, s.ScanCommitActivity, s.ScanGoModules} {
+		if enabled {
+			requests++
+		}
+	}
+	if s.ScanBranches {
+		// The branch list and the head commit of the default branch.
+		requests += 2
+	}
+	if s.ScanIssueLists {
+		requests += 2
+	} else if s.ScanIssues {
+		requests++
+	}
+	requests += len(s.ScanAlerts)
+	if s.ScanTraffic {
+		requests += 2
+	}
+	if s.VerifySignatures {
+		// Authenticated downloads of the asset and its signature are API requests.
+		requests += 2
+	}
+
+	return requests
+}
+
+// listsReleasesWithREST reports whether the provider lists releases with REST requests of the scanner, i.e. it is
+// the scanner itself or a chain starting with it.
+func (s *Scanner) listsReleasesWithREST(provider Provider) bool {
+	switch provider := provider.(type) {
+	case *Scanner:
+		return provider == s
+	case *ChainProvider:
+		return len(provider.Backends) > 0 && s.listsReleasesWithREST(provider.Backends[0].Provider)
+	}
+
+	return false
+}
+
+// checkBudget fails if the remaining rate limit is known and lower than the estimated requests of scanning the
+// listed repositories and the requests reserved by concurrent scans. Otherwise it reserves the estimated requests
+// in the budget of the scanner and returns their count.
+func (s *Scanner) checkBudget(user string, repositories []*Repository) (int, error) {
+	estimate := s.EstimateScan(repositories)
+	reserved, ok := s.budget.reserve(estimate.ScanRequests, s.RateLimit())
+	if ok {
+		return estimate.ScanRequests, nil
+	}
+	rateLimit := s.RateLimit()
+	if reserved > 0 {
+		return 0, fmt.Errorf("%w: the scan of %s takes at least %d requests, %d remain until %s and %d are reserved by concurrent scans", ErrInsufficientBudget, user, estimate.ScanRequests, rateLimit.Remaining, rateLimit.Reset.Format("15:04 MST"), reserved)
+	}
+
+	return 0, fmt.Errorf("%w: the scan of %s takes at least %d requests, %d remain until %s", ErrInsufficientBudget, user, estimate.ScanRequests, rateLimit.Remaining, rateLimit.Reset.Format("15:04 MST"))
+}
@@ -0,0 +1,82 @@
+package scanner
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestEstimateScan(t *testing.T) {
+	pushedAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	repositories := []*Repository{{FullName: "test/a"}, {FullName: "test/b"}, {FullName: "test/c", PushedAt: &pushedAt}}
+
+	scanner := Scanner{PerPage: 2}
+	if estimate := scanner.EstimateScan(repositories); estimate.ListRequests != 2 || estimate.ScanRequests != 3 {
+		t.Fatalf("invalid estimate, expected 2 list and 3 scan requests, got %+v", estimate)
+	}
+
+	scanner.ScanBranches = true
+	scanner.ScanIssues = true
+	scanner.ScanAlerts = AlertSources
+	scanner.Since = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// Releases of the repository not pushed since the cutoff are not fetched.
+	estimate := scanner.EstimateScan(repositories)
+	if expected := 3*(2+1+len(AlertSources)) + 2; estimate.ScanRequests != expected || estimate.Requests() != expected+2 {
+		t.Fatalf("invalid estimate, expected %d scan requests, got %+v", expected, estimate)
+	}
+}
+
+func TestEstimateScanVerifySignatures(t *testing.T) {
+	repositories := []*Repository{{FullName: "test/a"}, {FullName: "test/b"}}
+	scanner := Scanner{VerifySignatures: true}
+	if estimate := scanner.EstimateScan(repositories); estimate.ScanRequests != 2*(1+2) {
+		t.Fatalf("invalid estimate, expected %d scan requests with the signature downloads, got %+v", 2*(1+2), estimate)
+	}
+}
+
+func TestEstimateScanProviders(t *testing.T) {
+	repositories := []*Repository{{FullName: "test/a"}, {FullName: "test/b"}}
+	scanner := Scanner{ScanLanguages: true}
+	tests := []struct {
+		name     string
+		provider Provider
+		expected int
+	}{
+		{"rest", nil, 2 * 2},
+		{"graphql", &GraphQLProvider{}, 2},
+		{"gitlab", &GitLabProvider{}, 2},
+		{"chain", &ChainProvider{Backends: []Backend{{Name: "rest", Provider: &scanner}, {Name: "graphql", Provider: &GraphQLProvider{}}}}, 2 * 2},
+		{"graphql chain", &ChainProvider{Backends: []Backend{{Name: "graphql", Provider: &GraphQLProvider{}}, {Name: "rest", Provider: &scanner}}}, 2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scanner.Provider = test.provider
+			// Releases listed by other providers do not take REST requests.
+			if estimate := scanner.EstimateScan(repositories); estimate.ScanRequests != test.expected {
+				t.Fatalf("invalid estimate, expected %d scan requests, got %+v", test.expected, estimate)
+			}
+		})
+	}
+}
+
+func TestScanCheckBudget(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "60")
+		w.Header().Set("X-RateLimit-Remaining", "1")
+		w.Header().Set("X-RateLimit-Reset", "1700000000")
+		switch r.URL.Path {
+		case "/users/test/repos":
+			w.Write([]byte(`[{"full_name": "test/a", "name": "a"}, {"full_name": "test/b", "name": "b"}]`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL, PerPage: 100, CheckBudget: true}
+	if _, err := scanner.ScanRepositories("test"); !errors.Is(err, ErrInsufficientBudget) {
+		t.Fatalf("invalid error, expected %v, got %v", ErrInsufficientBudget, err)
+	}
+}
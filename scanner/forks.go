@@ -0,0 +1,120 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// Fork maps a fork to the repository it was forked from and compares their default branches.
+type Fork struct {
+	Repository string `json:"repository"`
+	// Parent is the repository the fork was created from, Source is the root of the fork network.
+	Parent string `json:"parent"`
+	Source string `json:"source"`
+	// BehindBy is the count of parent default branch commits missing in the fork default branch, AheadBy is the
+	// count of fork commits missing in the parent. A fork behind and not ahead carries nothing of its own.
+	BehindBy int  `json:"behind_by"`
+	AheadBy  int  `json:"ahead_by"`
+	Archived bool `json:"archived"`
+	// Error explains why the comparison is missing, e.g. the parent is deleted or private.
+	Error string `json:"error,omitempty"`
+}
+
+// Stale reports whether the fork has no commits of its own, so it could be deleted.
+func (f *Fork) Stale() bool {
+	return f.Error == "" && f.AheadBy == 0
+}
+
+// forkDetails is the repository response with the fork network fields, they are not listed with repositories.
+type forkDetails struct {
+	Repository
+	Parent *Repository `json:"parent"`
+	Source *Repository `json:"source"`
+}
+
+// GetForks returns the forks among the repositories of the account with their upstreams. The fork details and
+// the default branch comparison take two requests per fork.
+func (s *Scanner) GetForks(user string) ([]*Fork, error) {
+	ctx := context.Background()
+	repositories, err := s.getAllRepositories(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(repositories, func(i, j int) bool {
+		return repositories[i].FullName < repositories[j].FullName
+	})
+
+	var forks []*Fork
+	for _, repository := range repositories {
+		if !repository.Fork {
+			continue
+		}
+		fork, err := s.getFork(ctx, repositoryOwner(repository, user), repository.Name)
+		if err != nil {
+			return nil, err
+		}
+		forks = append(forks, fork)
+	}
+
+	return forks, nil
+}
+
+func (s *Scanner) getFork(ctx context.Context, owner, repository string) (*Fork, error) {
+	details, err := s.getForkDetails(ctx, owner, repository)
+	if err != nil {
+		return nil, err
+	}
+	fork := &Fork{Repository: details.FullName, Archived: details.Archived}
+	if details.Parent == nil {
+		fork.Error = "parent repository is not available"
+		return fork, nil
+	}
+	fork.Parent = details.Parent.FullName
+	if details.Source != nil {
+		fork.Source = details.Source.FullName
+	}
+
+	parentOwner := repositoryOwner(details.Parent, "")
+	comparison, err := s.getComparison(ctx, parentOwner, details.Parent.Name, details.Parent.DefaultBranch, owner+":"+details.DefaultBranch)
+	if err != nil {
+		fork.Error = fmt.Sprintf("could not compare with the parent: %v", err)
+		return fork, nil
+	}
+	fork.AheadBy, fork.BehindBy = comparison.AheadBy, comparison.BehindBy
+
+	return fork, nil
+}
+
+func (s *Scanner) getForkDetails(ctx context.Context, owner, repository string) (*forkDetails, error) {
+	if err := s.checkUser(owner); err != nil {
+		return nil, err
+	}
+	if err := s.checkRepository(repository); err != nil {
+		return nil, err
+	}
+	ctx, span := s.getTracer().Start(ctx, "GetForkDetails", StringAttribute("account", owner), StringAttribute("repository", repository))
+	defer span.End()
+
+	response, err := s.get(ctx, span, fmt.Sprintf("%s/repos/%s/%s", s.BaseUrl, owner, repository))
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil, newNotFoundError("repository %s/%s does not exist", owner, repository)
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not get the repository %s/%s: %w", owner, repository, s.newApiError(response))
+	}
+
+	var details forkDetails
+	if err := json.NewDecoder(response.Body).Decode(&details); err != nil {
+		return nil, err
+	}
+
+	return &details, nil
+}
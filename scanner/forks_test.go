@@ -0,0 +1,52 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetForks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/test/repos":
+			w.Write([]byte(`[
+				{"full_name": "test/own", "name": "own"},
+				{"full_name": "test/tool", "name": "tool", "fork": true},
+				{"full_name": "test/orphan", "name": "orphan", "fork": true}
+			]`))
+		case "/repos/test/tool":
+			w.Write([]byte(`{
+				"full_name": "test/tool", "name": "tool", "fork": true, "default_branch": "main",
+				"parent": {"full_name": "team/tool", "name": "tool", "default_branch": "master"},
+				"source": {"full_name": "upstream/tool", "name": "tool", "default_branch": "main"}
+			}`))
+		case "/repos/test/orphan":
+			w.Write([]byte(`{"full_name": "test/orphan", "name": "orphan", "fork": true, "default_branch": "main"}`))
+		case "/repos/team/tool/compare/master...test:main":
+			w.Write([]byte(`{"ahead_by": 0, "behind_by": 42, "commits": []}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL, PerPage: 100}
+	forks, err := scanner.GetForks("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(forks) != 2 {
+		t.Fatalf("invalid forks count, expected 2, got %d", len(forks))
+	}
+	if forks[0].Repository != "test/orphan" || forks[0].Error == "" || forks[0].Stale() {
+		t.Fatalf("invalid fork without a parent, got %+v", forks[0])
+	}
+	expected := Fork{Repository: "test/tool", Parent: "team/tool", Source: "upstream/tool", BehindBy: 42}
+	if *forks[1] != expected {
+		t.Fatalf("invalid fork, expected %+v, got %+v", expected, *forks[1])
+	}
+	if !forks[1].Stale() {
+		t.Fatalf("invalid fork staleness, expected the fork without own commits to be stale")
+	}
+}
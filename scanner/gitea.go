@@ -0,0 +1,144 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+)
+
+// giteaPerPage is the default MAX_RESPONSE_ITEMS of Gitea, larger pages are silently truncated by the server.
+const giteaPerPage = 50
+
+// GiteaProvider lists repositories and releases of a Gitea or Forgejo organization or user.
+// BaseUrl is the API root of the instance, e.g. https://gitea.example.com/api/v1.
+type GiteaProvider struct {
+	BaseUrl string
+	// Token is an access token sent as "Authorization: token ..." header.
+	Token string
+	// TokenSource provides the tokens instead of Token.
+	TokenSource TokenSource
+	PerPage     int
+	Logger      *slog.Logger
+	// Client sends the API requests, http.DefaultClient if it is nil.
+	Client *http.Client
+}
+
+var errGiteaNotFound = errors.New("not found")
+
+func (p *GiteaProvider) ListRepositories(ctx context.Context, account string) ([]*Repository, error) {
+	if account == "" {
+		return nil, errors.New("user name could not be empty")
+	}
+	if p.BaseUrl == "" {
+		return nil, errors.New("base url of the gitea instance is not specified")
+	}
+
+	var repositories []*Repository
+	err := giteaList(ctx, p, fmt.Sprintf("orgs/%s/repos", url.PathEscape(account)), &repositories)
+	if err == errGiteaNotFound {
+		repositories = nil
+		err = giteaList(ctx, p, fmt.Sprintf("users/%s/repos", url.PathEscape(account)), &repositories)
+	}
+	if err == errGiteaNotFound {
+		return nil, newNotFoundError("account %s does not exist", account)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not get repositories for the account %s: %w", account, err)
+	}
+
+	return repositories, nil
+}
+
+func (p *GiteaProvider) ListReleases(ctx context.Context, owner, repository string) ([]*Release, error) {
+	if repository == "" {
+		return nil, errors.New("repository name could not be empty")
+	}
+
+	var releases []*Release
+	if err := giteaList(ctx, p, fmt.Sprintf("repos/%s/%s/releases", url.PathEscape(owner), url.PathEscape(repository)), &releases); err != nil {
+		return nil, fmt.Errorf("could not get releases for the repository %s: %w", repository, err)
+	}
+
+	return releases, nil
+}
+
+// giteaList fetches all pages of the endpoint into the slice pointed by result, following the Link headers since
+// the server may cap the requested limit.
+func giteaList[T any](ctx context.Context, p *GiteaProvider, path string, result *[]T) error {
+	items, err := paginate(ctx, func(ctx context.Context, page int) ([]T, pageLinks, error) {
+		var chunk []T
+		links, err := p.getPage(ctx, path, page, &chunk)
+		return chunk, links, err
+	})
+	*result = items
+
+	return err
+}
+
+// getPage decodes the page into v and returns the links of its Link header.
+func (p *GiteaProvider) getPage(ctx context.Context, path string, page int, v interface{}) (pageLinks, error) {
+	apiUrl := fmt.Sprintf("%s/%s?limit=%d&page=%d", p.BaseUrl, path, p.getPerPage(), page)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, apiUrl, nil)
+	if err != nil {
+		return pageLinks{}, err
+	}
+	token, err := sourceToken(ctx, p.Token, p.TokenSource)
+	if err != nil {
+		return pageLinks{}, err
+	}
+	if token != "" {
+		request.Header.Set("Authorization", "token "+token)
+	}
+
+	p.getLogger().Debug("api request", "url", apiUrl)
+	response, err := p.getClient().Do(request)
+	if err != nil {
+		return pageLinks{}, wrapTransportError(err)
+	}
+	defer response.Body.Close()
+	p.getLogger().Debug("api response", "url", apiUrl, "status", response.StatusCode)
+
+	if response.StatusCode == http.StatusNotFound {
+		return pageLinks{}, errGiteaNotFound
+	}
+	if response.StatusCode != http.StatusOK {
+		message := struct {
+			Message string `json:"message"`
+		}{}
+		if err := json.NewDecoder(response.Body).Decode(&message); err != nil || message.Message == "" {
+			return pageLinks{}, newApiError(response, response.Status)
+		}
+		return pageLinks{}, newApiError(response, message.Message)
+	}
+
+	return parsePageLinks(response.Header), json.NewDecoder(response.Body).Decode(v)
+}
+
+func (p *GiteaProvider) getPerPage() int {
+	if p.PerPage <= 0 {
+		return giteaPerPage
+	}
+
+	return p.PerPage
+}
+
+func (p *GiteaProvider) getClient() *http.Client {
+	if p.Client == nil {
+		return http.DefaultClient
+	}
+
+	return p.Client
+}
+
+func (p *GiteaProvider) getLogger() *slog.Logger {
+	if p.Logger == nil {
+		return slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+
+	return p.Logger
+}
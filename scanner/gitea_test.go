@@ -0,0 +1,58 @@
+package scanner
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestScanRepositoriesGitea(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message": "unauthorized"}`))
+			return
+		}
+		page := r.URL.Query().Get("page")
+		switch r.URL.Path {
+		case "/orgs/test/repos":
+			// The server caps the page size at 2 regardless of the requested limit.
+			if page == "1" {
+				w.Header().Set("Link", fmt.Sprintf(`<%s/orgs/test/repos?limit=2&page=2>; rel="next", <%s/orgs/test/repos?limit=2&page=2>; rel="last"`, server.URL, server.URL))
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`[{"full_name": "test/repo1", "name": "repo1"}, {"full_name": "test/repo2", "name": "repo2"}]`))
+			} else {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`[{"full_name": "test/repo3", "name": "repo3"}]`))
+			}
+		case "/repos/test/repo1/releases", "/repos/test/repo2/releases", "/repos/test/repo3/releases":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"name": "v1.0.0", "tag_name": "v1.0.0"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{
+		Provider: &GiteaProvider{
+			BaseUrl:     server.URL,
+			TokenSource: StaticTokenSource("secret"),
+		},
+	}
+	items, err := scanner.ScanRepositories("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 3 {
+		t.Fatalf("invalid scanned repositories items count, expected 3, got %d", len(items))
+	}
+	for _, item := range items {
+		if len(item.Releases) != 1 || item.Releases[0].TagName != "v1.0.0" {
+			t.Fatalf("invalid releases for the repository %s: %v", item.Repository.FullName, item.Releases)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const GitLabApi = "https://gitlab.com/api/v4"
+
+// GitLabProvider lists projects of a GitLab group or user and their releases.
+type GitLabProvider struct {
+	BaseUrl string
+	// Token is a personal, group or project access token sent as PRIVATE-TOKEN header.
+	Token string
+	// TokenSource provides the tokens instead of Token.
+	TokenSource TokenSource
+	PerPage     int
+	Logger      *slog.Logger
+	// Client sends the API requests, http.DefaultClient if it is nil.
+	Client *http.Client
+}
+
+type gitLabProject struct {
+	PathWithNamespace string `json:"path_with_namespace"`
+	Path              string `json:"path"`
+	Description       string `json:"description"`
+	StarCount         int    `json:"star_count"`
+	Archived          bool   `json:"archived"`
+	// LastActivityAt is the closest to the last push time GitLab lists projects with.
+	LastActivityAt *time.Time `json:"last_activity_at"`
+	DefaultBranch  string     `json:"default_branch"`
+	Topics         []string   `json:"topics"`
+}
+
+type gitLabRelease struct {
+	Name        string    `json:"name"`
+	TagName     string    `json:"tag_name"`
+	Description string    `json:"description"`
+	ReleasedAt  time.Time `json:"released_at"`
+	Upcoming    bool      `json:"upcoming_release"`
+	Assets      struct {
+		Links []struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"links"`
+	} `json:"assets"`
+}
+
+func (p *GitLabProvider) ListRepositories(ctx context.Context, account string) ([]*Repository, error) {
+	if account == "" {
+		return nil, errors.New("user name could not be empty")
+	}
+	repositories, err := p.listProjects(ctx, fmt.Sprintf("groups/%s/projects?include_subgroups=true", url.PathEscape(account)))
+	if err == errGitLabNotFound {
+		repositories, err = p.listProjects(ctx, fmt.Sprintf("users/%s/projects", url.PathEscape(account)))
+	}
+	if err == errGitLabNotFound {
+		return nil, newNotFoundError("account %s does not exist", account)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not get repositories for the account %s: %w", account, err)
+	}
+
+	return repositories, nil
+}
+
+func (p *GitLabProvider) ListReleases(ctx context.Context, owner, repository string) ([]*Release, error) {
+	if repository == "" {
+		return nil, errors.New("repository name could not be empty")
+	}
+	path := fmt.Sprintf("projects/%s/releases", url.PathEscape(owner+"/"+repository))
+	releases, err := paginate(ctx, func(ctx context.Context, page int) ([]*Release, pageLinks, error) {
+		var chunk []*gitLabRelease
+		links, err := p.getPage(ctx, path, page, &chunk)
+		if err != nil {
+			return nil, links, err
+		}
+		releases := make([]*Release, 0, len(chunk))
+		for _, gitLabRelease := range chunk {
+			release := &Release{
+				Name:        gitLabRelease.Name,
+				TagName:     gitLabRelease.TagName,
+				Prerelease:  gitLabRelease.Upcoming,
+				Body:        gitLabRelease.Description,
+				PublishedAt: &gitLabRelease.ReleasedAt,
+			}
+			for _, link := range gitLabRelease.Assets.Links {
+				release.Assets = append(release.Assets, &Asset{Name: link.Name, BrowserDownloadURL: link.URL})
+			}
+			releases = append(releases, release)
+		}
+		return releases, links, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not get releases for the repository %s: %w", repository, err)
+	}
+
+	return releases, nil
+}
+
+func (p *GitLabProvider) listProjects(ctx context.Context, path string) ([]*Repository, error) {
+	return paginate(ctx, func(ctx context.Context, page int) ([]*Repository, pageLinks, error) {
+		var chunk []*gitLabProject
+		links, err := p.getPage(ctx, path, page, &chunk)
+		if err != nil {
+			return nil, links, err
+		}
+		repositories := make([]*Repository, 0, len(chunk))
+		for _, project := range chunk {
+			repositories = append(repositories, &Repository{
+				FullName:      project.PathWithNamespace,
+				Name:          project.Path,
+				Stars:         project.StarCount,
+				Description:   project.Description,
+				Archived:      project.Archived,
+				PushedAt:      project.LastActivityAt,
+				DefaultBranch: project.DefaultBranch,
+				Topics:        project.Topics,
+			})
+		}
+		return repositories, links, nil
+	})
+}
+
+var errGitLabNotFound = errors.New("not found")
+
+// getPage decodes the page into v and returns the links of its Link header.
+func (p *GitLabProvider) getPage(ctx context.Context, path string, page int, v interface{}) (pageLinks, error) {
+	separator := "?"
+	if u, err := url.Parse(path); err == nil && u.RawQuery != "" {
+		separator = "&"
+	}
+	apiUrl := fmt.Sprintf("%s/%s%sper_page=%d&page=%d", p.getBaseUrl(), path, separator, p.getPerPage(), page)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, apiUrl, nil)
+	if err != nil {
+		return pageLinks{}, err
+	}
+	token, err := sourceToken(ctx, p.Token, p.TokenSource)
+	if err != nil {
+		return pageLinks{}, err
+	}
+	if token != "" {
+		request.Header.Set("PRIVATE-TOKEN", token)
+	}
+
+	p.getLogger().Debug("api request", "url", apiUrl)
+	response, err := p.getClient().Do(request)
+	if err != nil {
+		return pageLinks{}, wrapTransportError(err)
+	}
+	defer response.Body.Close()
+	p.getLogger().Debug("api response", "url", apiUrl, "status", response.StatusCode)
+
+	if response.StatusCode == http.StatusNotFound {
+		return pageLinks{}, errGitLabNotFound
+	}
+	if response.StatusCode != http.StatusOK {
+		return pageLinks{}, newApiError(response, p.getApiErrorMessage(response.Body, response.Status))
+	}
+
+	return parsePageLinks(response.Header), json.NewDecoder(response.Body).Decode(v)
+}
+
+// getApiErrorMessage extracts the error from a GitLab response body, which is either {"message": ...} or {"error": ...}.
+func (p *GitLabProvider) getApiErrorMessage(reader io.Reader, defaultMessage string) string {
+	message := struct {
+		Message interface{} `json:"message"`
+		Error   string      `json:"error"`
+	}{}
+	if err := json.NewDecoder(reader).Decode(&message); err != nil {
+		return defaultMessage
+	}
+	if message.Message != nil {
+		return fmt.Sprint(message.Message)
+	}
+	if message.Error != "" {
+		return message.Error
+	}
+
+	return defaultMessage
+}
+
+func (p *GitLabProvider) getBaseUrl() string {
+	if p.BaseUrl == "" {
+		return GitLabApi
+	}
+
+	return p.BaseUrl
+}
+
+func (p *GitLabProvider) getPerPage() int {
+	if p.PerPage <= 0 {
+		return perPage
+	}
+
+	return p.PerPage
+}
+
+func (p *GitLabProvider) getClient() *http.Client {
+	if p.Client == nil {
+		return http.DefaultClient
+	}
+
+	return p.Client
+}
+
+func (p *GitLabProvider) getLogger() *slog.Logger {
+	if p.Logger == nil {
+		return slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+
+	return p.Logger
+}
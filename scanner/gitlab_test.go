@@ -0,0 +1,90 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestScanRepositoriesGitLab(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message": "401 Unauthorized"}`))
+			return
+		}
+		switch r.URL.EscapedPath() {
+		case "/groups/test/projects":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "404 Group Not Found"}`))
+		case "/users/test/projects":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"path_with_namespace": "test/project", "path": "project"}]`))
+		case "/projects/test%2Fproject/releases":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"name": "Release 1.0", "tag_name": "v1.0.0", "assets": {"links": [{"name": "tool_linux_amd64", "url": "https://example.com/tool"}]}}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{
+		Provider: &GitLabProvider{
+			BaseUrl: server.URL,
+			Token:   "secret",
+		},
+	}
+	items, err := scanner.ScanRepositories("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 1 {
+		t.Fatalf("invalid scanned repositories items count, expected 1, got %d", len(items))
+	}
+	if items[0].Repository.FullName != "test/project" {
+		t.Fatalf("invalid repository full name, expected 'test/project', got %s", items[0].Repository.FullName)
+	}
+	if len(items[0].Releases) != 1 || items[0].Releases[0].TagName != "v1.0.0" {
+		t.Fatalf("invalid releases for the scanned project: %v", items[0].Releases)
+	}
+	if len(items[0].Releases[0].Assets) != 1 || items[0].Releases[0].Assets[0].Name != "tool_linux_amd64" {
+		t.Fatalf("invalid release assets for the scanned project: %v", items[0].Releases[0].Assets)
+	}
+}
+
+func TestListRepositoriesGitLabPages(t *testing.T) {
+	var requests atomic.Int32
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != "/groups/test/projects" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		// The last page is full, the end of the list is only known from the Link header.
+		if r.URL.Query().Get("page") == "1" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/groups/test/projects?page=2>; rel="next", <%s/groups/test/projects?page=2>; rel="last"`, server.URL, server.URL))
+			w.Write([]byte(`[{"path_with_namespace": "test/a", "path": "a"}]`))
+			return
+		}
+		w.Write([]byte(`[{"path_with_namespace": "test/b", "path": "b"}]`))
+	}))
+	defer server.Close()
+
+	provider := &GitLabProvider{BaseUrl: server.URL, PerPage: 1}
+	repositories, err := provider.ListRepositories(context.Background(), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repositories) != 2 || repositories[1].FullName != "test/b" {
+		t.Fatalf("invalid repositories, expected test/a and test/b, got %d repositories", len(repositories))
+	}
+	if requests.Load() != 2 {
+		t.Fatalf("invalid requests count, expected 2, got %d", requests.Load())
+	}
+}
@@ -0,0 +1,199 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// GoModule is the Go module of the go.mod file at the root of a repository.
+type GoModule struct {
+	// Path is the module path, e.g. "github.com/acme/cli/v2".
+	Path string `json:"path"`
+	// GoVersion is the go directive, e.g. "1.22", empty if the go.mod has none.
+	GoVersion string `json:"go_version,omitempty"`
+	// Versions are the module versions of the release tags, only filled by scans.
+	Versions []*GoModuleVersion `json:"versions,omitempty"`
+	// Problem tells why the go.mod could not be read, e.g. a symlink or a file too large for the contents API.
+	// The module has no path then.
+	Problem string `json:"problem,omitempty"`
+}
+
+// GoModuleVersion maps a release tag to the Go module version it publishes.
+type GoModuleVersion struct {
+	Tag string `json:"tag"`
+	// Version is the module version of the tag, empty if the tag is not a valid module version.
+	Version string `json:"version,omitempty"`
+	// Problem tells why the tag is not a valid module version, e.g. "missing v prefix".
+	Problem string `json:"problem,omitempty"`
+}
+
+// GetGoModule returns the module of the go.mod at the root of the repository, nil if the repository has none.
+func (s *Scanner) GetGoModule(user, repository string) (*GoModule, error) {
+	return s.getGoModule(context.Background(), user, repository)
+}
+
+func (s *Scanner) getGoModule(ctx context.Context, user, repository string) (*GoModule, error) {
+	if err := s.checkUser(user); err != nil {
+		return nil, err
+	}
+	if err := s.checkRepository(repository); err != nil {
+		return nil, err
+	}
+	ctx, span := s.getTracer().Start(ctx, "GetGoModule", StringAttribute("account", user), StringAttribute("repository", repository))
+	defer span.End()
+
+	response, err := s.get(ctx, span, fmt.Sprintf("%s/repos/%s/%s/contents/go.mod", s.BaseUrl, user, repository))
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	// the repository exists when it is scanned, so it has no go.mod
+	if response.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not get go.mod of the repository %s/%s: %w", user, repository, s.newApiError(response))
+	}
+
+	var file struct {
+		Type     string `json:"type"`
+		Encoding string `json:"encoding"`
+		Content  string `json:"content"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&file); err != nil {
+		return nil, err
+	}
+	// A go.mod that can not be read is a problem of the repository, not a failure of the scan.
+	if file.Type != "file" || file.Encoding != "base64" {
+		return &GoModule{Problem: fmt.Sprintf("unexpected %s content with %q encoding", file.Type, file.Encoding)}, nil
+	}
+	// GitHub wraps the base64 content in lines
+	data, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(file.Content, "\n", ""))
+	if err != nil {
+		return &GoModule{Problem: fmt.Sprintf("could not decode the content: %v", err)}, nil
+	}
+	module, err := ParseGoMod(data)
+	if err != nil {
+		return &GoModule{Problem: err.Error()}, nil
+	}
+
+	return module, nil
+}
+
+// ParseGoMod reads the module path and the go directive of a go.mod file.
+func ParseGoMod(data []byte) (*GoModule, error) {
+	module := &GoModule{}
+	lines := bufio.NewScanner(bytes.NewReader(data))
+	for lines.Scan() {
+		line, _, _ := strings.Cut(lines.Text(), "//")
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		switch fields[0] {
+		case "module":
+			path := fields[1]
+			if unquoted, err := strconv.Unquote(path); err == nil {
+				path = unquoted
+			}
+			module.Path = path
+		case "go":
+			module.GoVersion = fields[1]
+		}
+	}
+	if err := lines.Err(); err != nil {
+		return nil, err
+	}
+	if module.Path == "" {
+		return nil, fmt.Errorf("module directive is not found")
+	}
+
+	return module, nil
+}
+
+var (
+	moduleVersionRegexp = regexp.MustCompile(`^v(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(-[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?(\+[0-9A-Za-z.-]+)?$`)
+	shortVersionRegexp  = regexp.MustCompile(`^v(0|[1-9]\d*)(\.(0|[1-9]\d*))?$`)
+	majorSuffixRegexp   = regexp.MustCompile(`/v([2-9]|[1-9]\d+)$`)
+	gopkgSuffixRegexp   = regexp.MustCompile(`\.v(0|[1-9]\d*)(-unstable)?$`)
+)
+
+// ModuleVersion returns the Go module version the tag publishes for the module path, or why the tag is not a valid
+// module version: Go only accepts full semver tags with the v prefix whose major version matches the major version
+// suffix of the module path, e.g. v2.1.0 for "github.com/acme/cli/v2".
+func (m *GoModule) ModuleVersion(tag string) (string, error) {
+	if strings.Contains(tag, "/") {
+		// e.g. "tools/v1.2.0" of the module in the tools directory
+		return "", fmt.Errorf("tag of a nested module")
+	}
+	match := moduleVersionRegexp.FindStringSubmatch(tag)
+	if match == nil {
+		if moduleVersionRegexp.MatchString("v" + tag) {
+			return "", fmt.Errorf("missing v prefix")
+		}
+		if shortVersionRegexp.MatchString(tag) {
+			return "", fmt.Errorf("not a full semver version")
+		}
+		return "", fmt.Errorf("not a semver version")
+	}
+	if match[6] != "" {
+		return "", fmt.Errorf("build metadata is not allowed")
+	}
+
+	major := match[1]
+	if suffix := gopkgSuffixRegexp.FindStringSubmatch(m.Path); suffix != nil && strings.HasPrefix(m.Path, "gopkg.in/") {
+		if major != suffix[1] {
+			return "", fmt.Errorf("major version v%s does not match the module path major version v%s", major, suffix[1])
+		}
+		return tag, nil
+	}
+	if suffix := majorSuffixRegexp.FindStringSubmatch(m.Path); suffix != nil {
+		if major != suffix[1] {
+			return "", fmt.Errorf("major version v%s does not match the module path major version v%s", major, suffix[1])
+		}
+		return tag, nil
+	}
+	if major != "0" && major != "1" {
+		// +incompatible versions are only allowed for repositories without a go.mod
+		return "", fmt.Errorf("major version v%s requires the module path suffix /v%s", major, major)
+	}
+
+	return tag, nil
+}
+
+// mapVersions maps the tags of the releases to module versions, drafts are skipped as their tags may not exist.
+func (m *GoModule) mapVersions(releases []*Release) {
+	m.Versions = nil
+	for _, release := range releases {
+		if release.Draft || release.TagName == "" {
+			continue
+		}
+		version := &GoModuleVersion{Tag: release.TagName}
+		var err error
+		if version.Version, err = m.ModuleVersion(release.TagName); err != nil {
+			version.Problem = err.Error()
+		}
+		m.Versions = append(m.Versions, version)
+	}
+}
+
+// InvalidVersions returns the versions of the tags that are not valid module versions.
+func (m *GoModule) InvalidVersions() []*GoModuleVersion {
+	var invalid []*GoModuleVersion
+	for _, version := range m.Versions {
+		if version.Problem != "" {
+			invalid = append(invalid, version)
+		}
+	}
+
+	return invalid
+}
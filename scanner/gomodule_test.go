@@ -0,0 +1,118 @@
+package scanner
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetGoModule(t *testing.T) {
+	goMod := "// the CLI module\nmodule \"github.com/test/test/v2\" // v2 since 2024\n\ngo 1.22\n\nrequire (\n\tgolang.org/x/sync v0.7.0\n)\n"
+	encoded := base64.StdEncoding.EncodeToString([]byte(goMod))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/test/test/contents/go.mod":
+			// GitHub wraps the content in lines
+			fmt.Fprintf(w, `{"type": "file", "encoding": "base64", "content": "%s\n%s\n"}`, encoded[:20], encoded[20:])
+		case "/repos/test/broken/contents/go.mod":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL}
+	module, err := scanner.GetGoModule("test", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if module.Path != "github.com/test/test/v2" || module.GoVersion != "1.22" {
+		t.Fatalf("invalid module, expected github.com/test/test/v2 with go 1.22, got %s with go %s", module.Path, module.GoVersion)
+	}
+
+	module, err = scanner.GetGoModule("test", "python")
+	if err != nil || module != nil {
+		t.Fatalf("invalid module of a repository without go.mod, expected nil, got %v (%v)", module, err)
+	}
+	if _, err := scanner.GetGoModule("test", "broken"); err == nil {
+		t.Fatalf("go module of a failing repository is expected to fail")
+	}
+}
+
+func TestGetGoModuleProblem(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/test/repos":
+			w.Write([]byte(`[{"full_name": "test/test", "name": "test"}]`))
+		case "/repos/test/test/contents/go.mod":
+			// the contents API leaves the content of large files out
+			w.Write([]byte(`{"type": "file", "encoding": "none", "content": ""}`))
+		default:
+			w.Write([]byte(`[{"tag_name": "v1.0.0"}]`))
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL, ScanGoModules: true}
+	items, err := scanner.ScanRepositories("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	module := items[0].GoModule
+	if module == nil || module.Problem != `unexpected file content with "none" encoding` || module.Versions != nil {
+		t.Fatalf("invalid module of the unreadable go.mod, expected its problem, got %+v", module)
+	}
+}
+
+func TestParseGoMod(t *testing.T) {
+	if _, err := ParseGoMod([]byte("go 1.22\n")); err == nil {
+		t.Fatalf("go.mod without module directive is expected to fail")
+	}
+}
+
+func TestModuleVersion(t *testing.T) {
+	tests := []struct {
+		path, tag, version, problem string
+	}{
+		{"github.com/test/test", "v1.2.3", "v1.2.3", ""},
+		{"github.com/test/test", "v0.1.0-rc.1", "v0.1.0-rc.1", ""},
+		{"github.com/test/test", "1.2.3", "", "missing v prefix"},
+		{"github.com/test/test", "v1.2", "", "not a full semver version"},
+		{"github.com/test/test", "v01.2.3", "", "not a semver version"},
+		{"github.com/test/test", "release-2024", "", "not a semver version"},
+		{"github.com/test/test", "v1.2.3+build.5", "", "build metadata is not allowed"},
+		{"github.com/test/test", "v2.0.0", "", "major version v2 requires the module path suffix /v2"},
+		{"github.com/test/test/v2", "v2.1.0", "v2.1.0", ""},
+		{"github.com/test/test/v2", "v3.0.0", "", "major version v3 does not match the module path major version v2"},
+		{"gopkg.in/yaml.v3", "v3.0.1", "v3.0.1", ""},
+		{"gopkg.in/yaml.v3", "v2.4.0", "", "major version v2 does not match the module path major version v3"},
+		{"github.com/test/test", "tools/v1.0.0", "", "tag of a nested module"},
+	}
+	for _, test := range tests {
+		module := &GoModule{Path: test.path}
+		version, err := module.ModuleVersion(test.tag)
+		problem := ""
+		if err != nil {
+			problem = err.Error()
+		}
+		if version != test.version || problem != test.problem {
+			t.Fatalf("invalid module version of %s for %s, expected %q (%s), got %q (%s)", test.tag, test.path, test.version, test.problem, version, problem)
+		}
+	}
+}
+
+func TestMapGoModuleVersions(t *testing.T) {
+	module := &GoModule{Path: "github.com/test/test"}
+	module.mapVersions([]*Release{{TagName: "v1.1.0"}, {TagName: "1.0.0"}, {TagName: "v1.2.0", Draft: true}})
+
+	if len(module.Versions) != 2 || module.Versions[0].Version != "v1.1.0" {
+		t.Fatalf("invalid versions, expected v1.1.0 and 1.0.0, got %v", module.Versions)
+	}
+	invalid := module.InvalidVersions()
+	if len(invalid) != 1 || invalid[0].Tag != "1.0.0" || invalid[0].Problem != "missing v prefix" {
+		t.Fatalf("invalid versions of the tags, expected 1.0.0 (missing v prefix), got %v", invalid)
+	}
+}
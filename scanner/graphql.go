@@ -0,0 +1,522 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const GitHubGraphQLApi = "https://api.github.com/graphql"
+
+// GraphQLProvider lists repositories and releases through the GitHub GraphQL API.
+// Releases of many repositories are fetched with a single query, see GraphQLPlanner.
+type GraphQLProvider struct {
+	Endpoint string
+	// Token is required, GitHub GraphQL API does not allow anonymous access.
+	Token string
+	// TokenSource provides the tokens instead of Token.
+	TokenSource TokenSource
+	Planner     GraphQLPlanner
+	Logger      *slog.Logger
+	// Client sends the API requests, http.DefaultClient if it is nil.
+	Client *http.Client
+	// RequestHandler performs the API requests instead of Client, e.g. Scanner.RequestHandler, so the requests go
+	// through the middleware of the scanner and are counted and paused with its own requests.
+	RequestHandler RequestHandler
+
+	rateLimit atomic.Pointer[RateLimit]
+}
+
+type graphQLError struct {
+	Type    string        `json:"type"`
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path"`
+}
+
+// apiError classifies the error by its type, GitHub reports GraphQL errors with the 200 status.
+func (e *graphQLError) apiError() *APIError {
+	apiError := &APIError{StatusCode: http.StatusOK, Message: e.Message}
+	switch e.Type {
+	case "RATE_LIMITED":
+		apiError.Class = ErrRateLimited
+	case "NOT_FOUND":
+		apiError.Class = ErrNotFound
+	case "FORBIDDEN":
+		apiError.Class = ErrBadCredentials
+		if strings.Contains(e.Message, "SAML") {
+			apiError.Class = ErrSSORequired
+		}
+	}
+
+	return apiError
+}
+
+type graphQLResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []*graphQLError            `json:"errors"`
+}
+
+// errGraphQLQueryTooLarge is returned when the server rejects or times out a query because of its size.
+var errGraphQLQueryTooLarge = errors.New("graphql query exceeds resource limits")
+
+type graphQLReleases struct {
+	PageInfo struct {
+		HasNextPage bool   `json:"hasNextPage"`
+		EndCursor   string `json:"endCursor"`
+	} `json:"pageInfo"`
+	Nodes []struct {
+		ID            string        `json:"id"`
+		Name          string        `json:"name"`
+		TagName       string        `json:"tagName"`
+		IsDraft       bool          `json:"isDraft"`
+		IsPrerelease  bool          `json:"isPrerelease"`
+		Description   string        `json:"description"`
+		PublishedAt   *time.Time    `json:"publishedAt"`
+		ReleaseAssets graphQLAssets `json:"releaseAssets"`
+	} `json:"nodes"`
+}
+
+type graphQLCount struct {
+	TotalCount int `json:"totalCount"`
+}
+
+type graphQLAssets struct {
+	PageInfo struct {
+		HasNextPage bool   `json:"hasNextPage"`
+		EndCursor   string `json:"endCursor"`
+	} `json:"pageInfo"`
+	Nodes []struct {
+		Name          string `json:"name"`
+		ContentType   string `json:"contentType"`
+		Size          int64  `json:"size"`
+		DownloadCount int    `json:"downloadCount"`
+		DownloadUrl   string `json:"downloadUrl"`
+	} `json:"nodes"`
+}
+
+func (a *graphQLAssets) assets() []*Asset {
+	assets := make([]*Asset, 0, len(a.Nodes))
+	for _, asset := range a.Nodes {
+		assets = append(assets, &Asset{
+			Name:               asset.Name,
+			ContentType:        asset.ContentType,
+			Size:               asset.Size,
+			DownloadCount:      asset.DownloadCount,
+			BrowserDownloadURL: asset.DownloadUrl,
+		})
+	}
+
+	return assets
+}
+
+func (r *graphQLReleases) releases() []*Release {
+	releases := make([]*Release, 0, len(r.Nodes))
+	for _, node := range r.Nodes {
+		release := &Release{
+			Name:        node.Name,
+			TagName:     node.TagName,
+			Draft:       node.IsDraft,
+			Prerelease:  node.IsPrerelease,
+			Body:        node.Description,
+			PublishedAt: node.PublishedAt,
+		}
+		if len(node.ReleaseAssets.Nodes) > 0 {
+			release.Assets = node.ReleaseAssets.assets()
+		}
+		releases = append(releases, release)
+	}
+
+	return releases
+}
+
+func (p *GraphQLProvider) ListRepositories(ctx context.Context, account string) ([]*Repository, error) {
+	if account == "" {
+		return nil, errors.New("user name could not be empty")
+	}
+
+	var repositories []*Repository
+	cursor := ""
+	for {
+		query := fmt.Sprintf(`query { `+graphQLRateLimitFields+` repositoryOwner(login: %s) { repositories(first: 100, after: %s, ownerAffiliations: OWNER) {
+			pageInfo { hasNextPage endCursor }
+			nodes { databaseId nameWithOwner name description isPrivate isArchived isFork stargazerCount pushedAt primaryLanguage { name } defaultBranchRef { name } repositoryTopics(first: 20) { nodes { topic { name } } } issues(states: OPEN) { totalCount } pullRequests(states: OPEN) { totalCount } }
+		} } }`, graphQLString(account), graphQLCursor(cursor))
+		response, err := p.query(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("could not get repositories for the account %s: %w", account, err)
+		}
+		if len(response.Errors) > 0 {
+			return nil, fmt.Errorf("could not get repositories for the account %s: %w", account, response.Errors[0].apiError())
+		}
+
+		var owner *struct {
+			Repositories struct {
+				PageInfo struct {
+					HasNextPage bool   `json:"hasNextPage"`
+					EndCursor   string `json:"endCursor"`
+				} `json:"pageInfo"`
+				Nodes []struct {
+					DatabaseId      int64      `json:"databaseId"`
+					NameWithOwner   string     `json:"nameWithOwner"`
+					Name            string     `json:"name"`
+					Description     string     `json:"description"`
+					IsPrivate       bool       `json:"isPrivate"`
+					IsArchived      bool       `json:"isArchived"`
+					IsFork          bool       `json:"isFork"`
+					StargazerCount  int        `json:"stargazerCount"`
+					PushedAt        *time.Time `json:"pushedAt"`
+					PrimaryLanguage *struct {
+						Name string `json:"name"`
+					} `json:"primaryLanguage"`
+					DefaultBranchRef *struct {
+						Name string `json:"name"`
+					} `json:"defaultBranchRef"`
+					RepositoryTopics struct {
+						Nodes []struct {
+							Topic struct {
+								Name string `json:"name"`
+							} `json:"topic"`
+						} `json:"nodes"`
+					} `json:"repositoryTopics"`
+					Issues       graphQLCount `json:"issues"`
+					PullRequests graphQLCount `json:"pullRequests"`
+				} `json:"nodes"`
+			} `json:"repositories"`
+		}
+		if err := json.Unmarshal(response.Data["repositoryOwner"], &owner); err != nil {
+			return nil, err
+		}
+		if owner == nil {
+			return nil, newNotFoundError("account %s does not exist", account)
+		}
+		for _, node := range owner.Repositories.Nodes {
+			repository := &Repository{
+				ID:          node.DatabaseId,
+				FullName:    node.NameWithOwner,
+				Name:        node.Name,
+				Private:     node.IsPrivate,
+				Archived:    node.IsArchived,
+				Fork:        node.IsFork,
+				Stars:       node.StargazerCount,
+				PushedAt:    node.PushedAt,
+				Description: node.Description,
+				// REST counts open pull requests as issues too
+				OpenIssues: node.Issues.TotalCount + node.PullRequests.TotalCount,
+			}
+			if node.PrimaryLanguage != nil {
+				repository.Language = node.PrimaryLanguage.Name
+			}
+			if node.DefaultBranchRef != nil {
+				repository.DefaultBranch = node.DefaultBranchRef.Name
+			}
+			for _, topic := range node.RepositoryTopics.Nodes {
+				repository.Topics = append(repository.Topics, topic.Topic.Name)
+			}
+			repositories = append(repositories, repository)
+		}
+		if !owner.Repositories.PageInfo.HasNextPage {
+			return repositories, nil
+		}
+		cursor = owner.Repositories.PageInfo.EndCursor
+	}
+}
+
+func (p *GraphQLProvider) ListReleases(ctx context.Context, owner, repository string) ([]*Release, error) {
+	if repository == "" {
+		return nil, errors.New("repository name could not be empty")
+	}
+
+	releases, err := p.ListReleasesBatch(ctx, []*Repository{{FullName: owner + "/" + repository, Name: repository}})
+	if err != nil {
+		return nil, err
+	}
+
+	return releases[owner+"/"+repository], nil
+}
+
+// ListReleasesBatch fetches releases of all repositories using as few queries as the planner limits allow.
+// Releases are keyed by repository full names, the ones fetched before a failure are returned with it.
+func (p *GraphQLProvider) ListReleasesBatch(ctx context.Context, repositories []*Repository) (map[string][]*Release, error) {
+	return p.Planner.run(ctx, p, repositories)
+}
+
+// fetchBatch queries the first page of releases of every repository in the batch. Repositories whose releases
+// do not fit in the first page are completed with follow-up single-repository queries. Per-repository
+// errors are returned separately, so the caller could retry only the failed repositories. The rate limit is the
+// one reported by the last query, nil if none reported it.
+func (p *GraphQLProvider) fetchBatch(ctx context.Context, batch []*Repository) (map[string][]*Release, map[string]error, *RateLimit, error) {
+	var b strings.Builder
+	b.WriteString("query { " + graphQLRateLimitFields)
+	for i, repository := range batch {
+		owner, name := splitFullName(repository.FullName)
+		fmt.Fprintf(&b, " r%d: repository(owner: %s, name: %s) { releases(%s) %s }", i, graphQLString(owner), graphQLString(name), p.Planner.releasesArguments(""), graphQLReleasesFields(p.Planner.getAssetsPerRelease()))
+	}
+	b.WriteString(" }")
+
+	response, err := p.query(ctx, b.String())
+	if err != nil {
+		return nil, nil, p.rateLimit.Load(), err
+	}
+	for _, graphQLError := range response.Errors {
+		if len(graphQLError.Path) == 0 {
+			if graphQLError.Type == "MAX_NODE_LIMIT_EXCEEDED" || graphQLError.Type == "RESOURCE_LIMITS_EXCEEDED" {
+				return nil, nil, p.rateLimit.Load(), errGraphQLQueryTooLarge
+			}
+			return nil, nil, p.rateLimit.Load(), graphQLError.apiError()
+		}
+	}
+
+	releases := make(map[string][]*Release, len(batch))
+	failures := map[string]error{}
+	for _, graphQLError := range response.Errors {
+		if alias, ok := graphQLError.Path[0].(string); ok {
+			if i, err := parseAlias(alias); err == nil && i < len(batch) {
+				failures[batch[i].FullName] = graphQLError.apiError()
+			}
+		}
+	}
+	for i, repository := range batch {
+		if _, ok := failures[repository.FullName]; ok {
+			continue
+		}
+		var data *struct {
+			Releases graphQLReleases `json:"releases"`
+		}
+		if err := json.Unmarshal(response.Data[fmt.Sprintf("r%d", i)], &data); err != nil || data == nil {
+			failures[repository.FullName] = newNotFoundError("repository %s does not exist", repository.FullName)
+			continue
+		}
+		repositoryReleases, err := p.releases(ctx, &data.Releases)
+		if err != nil {
+			failures[repository.FullName] = err
+			continue
+		}
+		if data.Releases.PageInfo.HasNextPage {
+			rest, err := p.fetchRemainingReleases(ctx, repository, data.Releases.PageInfo.EndCursor)
+			if err != nil {
+				failures[repository.FullName] = err
+				continue
+			}
+			repositoryReleases = append(repositoryReleases, rest...)
+		}
+		releases[repository.FullName] = repositoryReleases
+	}
+
+	return releases, failures, p.rateLimit.Load(), nil
+}
+
+func (p *GraphQLProvider) fetchRemainingReleases(ctx context.Context, repository *Repository, cursor string) ([]*Release, error) {
+	owner, name := splitFullName(repository.FullName)
+
+	var releases []*Release
+	for {
+		query := fmt.Sprintf("query { "+graphQLRateLimitFields+" repository(owner: %s, name: %s) { releases(%s) %s } }", graphQLString(owner), graphQLString(name), p.Planner.releasesArguments(cursor), graphQLReleasesFields(p.Planner.getAssetsPerRelease()))
+		response, err := p.query(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		if len(response.Errors) > 0 {
+			return nil, response.Errors[0].apiError()
+		}
+		var data struct {
+			Releases graphQLReleases `json:"releases"`
+		}
+		if err := json.Unmarshal(response.Data["repository"], &data); err != nil {
+			return nil, err
+		}
+		page, err := p.releases(ctx, &data.Releases)
+		if err != nil {
+			return nil, err
+		}
+		releases = append(releases, page...)
+		if !data.Releases.PageInfo.HasNextPage {
+			return releases, nil
+		}
+		cursor = data.Releases.PageInfo.EndCursor
+	}
+}
+
+// releases returns the releases of the page. Assets of releases with more assets than the page of the releases
+// query are completed with follow-up queries of the release.
+func (p *GraphQLProvider) releases(ctx context.Context, page *graphQLReleases) ([]*Release, error) {
+	releases := page.releases()
+	for i, node := range page.Nodes {
+		if !node.ReleaseAssets.PageInfo.HasNextPage {
+			continue
+		}
+		assets, err := p.fetchRemainingAssets(ctx, node.ID, node.ReleaseAssets.PageInfo.EndCursor)
+		if err != nil {
+			return nil, fmt.Errorf("could not list the assets of the release %s: %w", node.TagName, err)
+		}
+		releases[i].Assets = append(releases[i].Assets, assets...)
+	}
+
+	return releases, nil
+}
+
+// fetchRemainingAssets pages through the assets of the release after the cursor. The release is queried by its
+// node id, drafts could not be queried by their tags.
+func (p *GraphQLProvider) fetchRemainingAssets(ctx context.Context, releaseID, cursor string) ([]*Asset, error) {
+	var assets []*Asset
+	for {
+		query := fmt.Sprintf("query { "+graphQLRateLimitFields+" node(id: %s) { ... on Release { releaseAssets(first: %d, after: %s) %s } } }", graphQLString(releaseID), graphQLAssetsPerPage, graphQLCursor(cursor), graphQLAssetsFields)
+		response, err := p.query(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		if len(response.Errors) > 0 {
+			return nil, response.Errors[0].apiError()
+		}
+		var data *struct {
+			ReleaseAssets graphQLAssets `json:"releaseAssets"`
+		}
+		if err := json.Unmarshal(response.Data["node"], &data); err != nil {
+			return nil, err
+		}
+		if data == nil {
+			return nil, newNotFoundError("release does not exist")
+		}
+		assets = append(assets, data.ReleaseAssets.assets()...)
+		if !data.ReleaseAssets.PageInfo.HasNextPage {
+			return assets, nil
+		}
+		cursor = data.ReleaseAssets.PageInfo.EndCursor
+	}
+}
+
+func (p *GraphQLProvider) query(ctx context.Context, query string) (*graphQLResponse, error) {
+	body, err := json.Marshal(map[string]string{"query": query})
+	if err != nil {
+		return nil, err
+	}
+	endpoint := p.Endpoint
+	if endpoint == "" {
+		endpoint = GitHubGraphQLApi
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	token, err := sourceToken(ctx, p.Token, p.TokenSource)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Authorization", "bearer "+token)
+	request.Header.Set("Content-Type", "application/json")
+
+	p.getLogger().Debug("graphql request", "endpoint", endpoint, "query_size", len(query))
+	response, err := p.do(request)
+	if err != nil {
+		return nil, wrapTransportError(err)
+	}
+	defer response.Body.Close()
+	p.getLogger().Debug("graphql response", "endpoint", endpoint, "status", response.StatusCode, "rate_limit_remaining", response.Header.Get("X-RateLimit-Remaining"))
+
+	// GitHub responds with 502 when a query could not be completed in time, which usually means it is too large.
+	if response.StatusCode == http.StatusBadGateway || response.StatusCode == http.StatusGatewayTimeout {
+		return nil, errGraphQLQueryTooLarge
+	}
+	if response.StatusCode != http.StatusOK {
+		message := struct {
+			Message string `json:"message"`
+		}{}
+		if err := json.NewDecoder(response.Body).Decode(&message); err != nil || message.Message == "" {
+			return nil, newApiError(response, response.Status)
+		}
+		return nil, newApiError(response, message.Message)
+	}
+
+	var result graphQLResponse
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	p.updateRateLimit(result.Data["rateLimit"])
+
+	return &result, nil
+}
+
+func (p *GraphQLProvider) do(request *http.Request) (*http.Response, error) {
+	if p.RequestHandler != nil {
+		return p.RequestHandler(request)
+	}
+
+	return p.getClient().Do(request)
+}
+
+// updateRateLimit records the rate limit reported by the rateLimit field of a query.
+func (p *GraphQLProvider) updateRateLimit(data json.RawMessage) {
+	var rateLimit *struct {
+		Limit     int       `json:"limit"`
+		Cost      int       `json:"cost"`
+		Remaining int       `json:"remaining"`
+		ResetAt   time.Time `json:"resetAt"`
+	}
+	if err := json.Unmarshal(data, &rateLimit); err != nil || rateLimit == nil {
+		return
+	}
+	p.getLogger().Debug("graphql rate limit", "cost", rateLimit.Cost, "remaining", rateLimit.Remaining, "reset", rateLimit.ResetAt)
+	p.rateLimit.Store(&RateLimit{Limit: rateLimit.Limit, Remaining: rateLimit.Remaining, Reset: rateLimit.ResetAt})
+}
+
+func (p *GraphQLProvider) getClient() *http.Client {
+	if p.Client == nil {
+		return http.DefaultClient
+	}
+
+	return p.Client
+}
+
+func (p *GraphQLProvider) getLogger() *slog.Logger {
+	if p.Logger == nil {
+		return slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+
+	return p.Logger
+}
+
+// graphQLRateLimitFields are selected by every query, so batches are sized against the remaining points.
+const graphQLRateLimitFields = "rateLimit { limit cost remaining resetAt }"
+
+// graphQLAssetsFields are the fields of the release assets connection.
+const graphQLAssetsFields = `{ pageInfo { hasNextPage endCursor } nodes { name contentType size downloadCount downloadUrl } }`
+
+func graphQLReleasesFields(assetsPerRelease int) string {
+	return fmt.Sprintf(`{ pageInfo { hasNextPage endCursor } nodes { id name tagName isDraft isPrerelease description publishedAt releaseAssets(first: %d) %s } }`, assetsPerRelease, graphQLAssetsFields)
+}
+
+func graphQLString(value string) string {
+	data, _ := json.Marshal(value)
+
+	return string(data)
+}
+
+func graphQLCursor(cursor string) string {
+	if cursor == "" {
+		return "null"
+	}
+
+	return graphQLString(cursor)
+}
+
+func parseAlias(alias string) (int, error) {
+	var i int
+	_, err := fmt.Sscanf(alias, "r%d", &i)
+
+	return i, err
+}
+
+func splitFullName(fullName string) (string, string) {
+	if i := strings.LastIndex(fullName, "/"); i >= 0 {
+		return fullName[:i], fullName[i+1:]
+	}
+
+	return "", fullName
+}
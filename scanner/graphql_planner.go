@@ -0,0 +1,191 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	graphQLMaxNodes         = 500000
+	graphQLMaxRepositories  = 50
+	graphQLReleasesPerPage  = 100
+	graphQLAssetsPerRelease = 20
+	graphQLAssetsPerPage    = 100
+	graphQLMaxBatchRetries  = 2
+)
+
+// BatchReleasesLister is implemented by providers able to fetch releases of many repositories at once. On failure
+// the releases of the repositories listed before are still returned with the error, so callers could fall back
+// for the other repositories only.
+// The workers of the scanner look the releases of their repositories up in batches listed with it.
+type BatchReleasesLister interface {
+	ListReleasesBatch(ctx context.Context, repositories []*Repository) (map[string][]*Release, error)
+}
+
+// GraphQLPlanner splits repositories into batches fitting the GitHub GraphQL node limit and the remaining rate
+// limit points. Zero values use the GitHub defaults.
+type GraphQLPlanner struct {
+	// MaxNodes is the maximum number of nodes a single query may request.
+	MaxNodes int
+	// MaxRepositories caps the repositories per query to keep queries fast enough to avoid timeouts.
+	MaxRepositories  int
+	AssetsPerRelease int
+	// MaxRetries is how many times repositories that failed in a batch are retried in a new batch.
+	MaxRetries int
+}
+
+// Plan groups the repositories into batches.
+func (p GraphQLPlanner) Plan(repositories []*Repository) [][]*Repository {
+	size := p.batchSize()
+
+	var batches [][]*Repository
+	for start := 0; start < len(repositories); start += size {
+		end := start + size
+		if end > len(repositories) {
+			end = len(repositories)
+		}
+		batches = append(batches, repositories[start:end])
+	}
+
+	return batches
+}
+
+// batchSize is the largest number of repositories whose releases fit the node limit.
+func (p GraphQLPlanner) batchSize() int {
+	nodesPerRepository := 1 + graphQLReleasesPerPage + graphQLReleasesPerPage*p.getAssetsPerRelease()
+
+	size := p.getMaxNodes() / nodesPerRepository
+	if size > p.getMaxRepositories() {
+		size = p.getMaxRepositories()
+	}
+	if size < 1 {
+		size = 1
+	}
+
+	return size
+}
+
+// affordableRepositories is the largest number of repositories a batch query could cost with the points. A
+// repository costs one releases connection plus an assets connection per release, GitHub charges a point per 100
+// connections.
+func affordableRepositories(points int) int {
+	connectionsPerRepository := 1 + graphQLReleasesPerPage
+
+	return points * 100 / connectionsPerRepository
+}
+
+type graphQLBatchFetcher interface {
+	fetchBatch(ctx context.Context, batch []*Repository) (map[string][]*Release, map[string]error, *RateLimit, error)
+}
+
+// run fetches the planned batches. A batch rejected as too large is split in halves, repositories that failed
+// inside a successful batch are retried together in a new batch up to MaxRetries times, the failures of the ones
+// that still fail are joined once the other batches are fetched. Batches are shrunk to the rate limit points
+// remaining after the previous batch, the run fails with ErrRateLimited once they could not pay for a single
+// repository. The releases fetched before a failure are returned with it.
+func (p GraphQLPlanner) run(ctx context.Context, fetcher graphQLBatchFetcher, repositories []*Repository) (map[string][]*Release, error) {
+	releases := make(map[string][]*Release, len(repositories))
+	pending := p.Plan(repositories)
+	attempts := map[string]int{}
+	var rateLimit *RateLimit
+	var failed []error
+
+	for len(pending) > 0 {
+		batch := pending[0]
+		pending = pending[1:]
+		if rateLimit != nil && time.Now().Before(rateLimit.Reset) {
+			affordable := affordableRepositories(rateLimit.Remaining)
+			if affordable < 1 {
+				return releases, &APIError{
+					StatusCode: http.StatusOK,
+					Message:    fmt.Sprintf("graphql rate limit of %d points is exhausted until %s", rateLimit.Limit, rateLimit.Reset.Format(time.RFC3339)),
+					Class:      ErrRateLimited,
+				}
+			}
+			if len(batch) > affordable {
+				pending = append([][]*Repository{batch[affordable:]}, pending...)
+				batch = batch[:affordable]
+			}
+		}
+
+		batchReleases, failures, batchRateLimit, err := fetcher.fetchBatch(ctx, batch)
+		if batchRateLimit != nil {
+			rateLimit = batchRateLimit
+		}
+		if err == errGraphQLQueryTooLarge && len(batch) > 1 {
+			middle := len(batch) / 2
+			pending = append([][]*Repository{batch[:middle], batch[middle:]}, pending...)
+			continue
+		}
+		if err != nil {
+			return releases, err
+		}
+		for fullName, repositoryReleases := range batchReleases {
+			releases[fullName] = repositoryReleases
+		}
+
+		var retry []*Repository
+		for _, repository := range batch {
+			failure, ok := failures[repository.FullName]
+			if !ok {
+				continue
+			}
+			attempts[repository.FullName]++
+			if attempts[repository.FullName] > p.getMaxRetries() || !retryableFailure(failure) {
+				failed = append(failed, fmt.Errorf("could not get releases for the repository %s: %w", repository.FullName, failure))
+				continue
+			}
+			retry = append(retry, repository)
+		}
+		if len(retry) > 0 {
+			pending = append(pending, retry)
+		}
+	}
+
+	return releases, errors.Join(failed...)
+}
+
+// retryableFailure reports whether the failure of a repository in a batch could pass in another batch. Missing
+// repositories, exhausted rate limits and denied access fail the same way again.
+func retryableFailure(err error) bool {
+	return !errors.Is(err, ErrNotFound) && !errors.Is(err, ErrRateLimited) && !errors.Is(err, ErrBadCredentials) && !errors.Is(err, ErrSSORequired)
+}
+
+func (p GraphQLPlanner) releasesArguments(cursor string) string {
+	return fmt.Sprintf("first: %d, after: %s, orderBy: {field: CREATED_AT, direction: DESC}", graphQLReleasesPerPage, graphQLCursor(cursor))
+}
+
+func (p GraphQLPlanner) getMaxNodes() int {
+	if p.MaxNodes <= 0 {
+		return graphQLMaxNodes
+	}
+
+	return p.MaxNodes
+}
+
+func (p GraphQLPlanner) getMaxRepositories() int {
+	if p.MaxRepositories <= 0 {
+		return graphQLMaxRepositories
+	}
+
+	return p.MaxRepositories
+}
+
+func (p GraphQLPlanner) getAssetsPerRelease() int {
+	if p.AssetsPerRelease <= 0 {
+		return graphQLAssetsPerRelease
+	}
+
+	return p.AssetsPerRelease
+}
+
+func (p GraphQLPlanner) getMaxRetries() int {
+	if p.MaxRetries <= 0 {
+		return graphQLMaxBatchRetries
+	}
+
+	return p.MaxRetries
+}
@@ -0,0 +1,147 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type fakeBatchFetcher struct {
+	maxBatchSize int
+	// failures is how many times a repository fails before it succeeds.
+	failures map[string]int
+	// failure is the error of the failing repositories, a retryable one if it is nil.
+	failure error
+	// points are the remaining rate limit points, the rate limit is not reported if it is nil.
+	points  *int
+	batches []int
+}
+
+func (f *fakeBatchFetcher) fetchBatch(ctx context.Context, batch []*Repository) (map[string][]*Release, map[string]error, *RateLimit, error) {
+	f.batches = append(f.batches, len(batch))
+	if len(batch) > f.maxBatchSize {
+		return nil, nil, f.rateLimit(), errGraphQLQueryTooLarge
+	}
+
+	releases := map[string][]*Release{}
+	failures := map[string]error{}
+	for _, repository := range batch {
+		if f.failures[repository.FullName] > 0 {
+			f.failures[repository.FullName]--
+			failures[repository.FullName] = f.failure
+			if f.failure == nil {
+				failures[repository.FullName] = errors.New("something went wrong")
+			}
+			continue
+		}
+		releases[repository.FullName] = []*Release{{TagName: "v1.0.0"}}
+	}
+
+	return releases, failures, f.rateLimit(), nil
+}
+
+// rateLimit spends the cost of the batch from the points if they are set.
+func (f *fakeBatchFetcher) rateLimit() *RateLimit {
+	if f.points == nil {
+		return nil
+	}
+	batch := f.batches[len(f.batches)-1]
+	*f.points -= (batch*(1+graphQLReleasesPerPage) + 99) / 100
+
+	return &RateLimit{Limit: 5000, Remaining: *f.points, Reset: time.Now().Add(time.Hour)}
+}
+
+func newRepositories(count int) []*Repository {
+	var repositories []*Repository
+	for i := 0; i < count; i++ {
+		repositories = append(repositories, &Repository{FullName: fmt.Sprintf("test/repo%d", i)})
+	}
+
+	return repositories
+}
+
+func TestGraphQLPlannerPlan(t *testing.T) {
+	planner := GraphQLPlanner{MaxRepositories: 10}
+	batches := planner.Plan(newRepositories(25))
+	if len(batches) != 3 || len(batches[0]) != 10 || len(batches[2]) != 5 {
+		t.Fatalf("invalid batches planned by repositories limit: %d", len(batches))
+	}
+
+	// Every repository requests 1 + 100 + 100*20 nodes.
+	planner = GraphQLPlanner{MaxNodes: 2101 * 4}
+	if size := planner.batchSize(); size != 4 {
+		t.Fatalf("invalid batch size by nodes limit, expected 4, got %d", size)
+	}
+}
+
+func TestGraphQLPlannerSplitsLargeBatches(t *testing.T) {
+	fetcher := &fakeBatchFetcher{maxBatchSize: 3}
+	planner := GraphQLPlanner{MaxRepositories: 8}
+
+	releases, err := planner.run(context.Background(), fetcher, newRepositories(8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(releases) != 8 {
+		t.Fatalf("invalid repositories count with releases, expected 8, got %d", len(releases))
+	}
+
+	expectedBatches := []int{8, 4, 2, 2, 4, 2, 2}
+	if fmt.Sprint(fetcher.batches) != fmt.Sprint(expectedBatches) {
+		t.Fatalf("invalid batches, expected %v, got %v", expectedBatches, fetcher.batches)
+	}
+}
+
+func TestGraphQLPlannerRetriesFailedRepositories(t *testing.T) {
+	fetcher := &fakeBatchFetcher{maxBatchSize: 10, failures: map[string]int{"test/repo1": 2}}
+
+	releases, err := GraphQLPlanner{}.run(context.Background(), fetcher, newRepositories(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(releases) != 3 {
+		t.Fatalf("invalid repositories count with releases, expected 3, got %d", len(releases))
+	}
+	expectedBatches := []int{3, 1, 1}
+	if fmt.Sprint(fetcher.batches) != fmt.Sprint(expectedBatches) {
+		t.Fatalf("invalid batches, expected %v, got %v", expectedBatches, fetcher.batches)
+	}
+
+	fetcher = &fakeBatchFetcher{maxBatchSize: 10, failures: map[string]int{"test/repo1": 3}}
+	releases, err = GraphQLPlanner{}.run(context.Background(), fetcher, newRepositories(3))
+	if err == nil {
+		t.Fatal("error is expected when retries are exhausted")
+	}
+	// The releases of the other repositories are returned with the failure.
+	if len(releases) != 2 || releases["test/repo1"] != nil {
+		t.Fatalf("invalid releases of the failed run: %v", releases)
+	}
+}
+
+func TestGraphQLPlannerFailsMissingRepositories(t *testing.T) {
+	fetcher := &fakeBatchFetcher{maxBatchSize: 10, failures: map[string]int{"test/repo1": 1}, failure: newNotFoundError("repository test/repo1 does not exist")}
+
+	_, err := GraphQLPlanner{}.run(context.Background(), fetcher, newRepositories(3))
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("invalid error of a missing repository, expected %v, got %v", ErrNotFound, err)
+	}
+	if fmt.Sprint(fetcher.batches) != "[3]" {
+		t.Fatalf("missing repository must not be retried, got batches %v", fetcher.batches)
+	}
+}
+
+func TestGraphQLPlannerRateLimit(t *testing.T) {
+	points := 60
+	fetcher := &fakeBatchFetcher{maxBatchSize: 50, points: &points}
+
+	_, err := GraphQLPlanner{}.run(context.Background(), fetcher, newRepositories(100))
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("invalid error of exhausted points, expected %v, got %v", ErrRateLimited, err)
+	}
+	// The first batch costs 51 points, the second one is shrunk to the 9 remaining points.
+	if fmt.Sprint(fetcher.batches) != "[50 8]" {
+		t.Fatalf("invalid batches, expected [50 8], got %v", fetcher.batches)
+	}
+}
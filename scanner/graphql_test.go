@@ -0,0 +1,178 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestScanRepositoriesGraphQL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message": "Bad credentials"}`))
+			return
+		}
+		var request struct {
+			Query string `json:"query"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+
+		w.WriteHeader(http.StatusOK)
+		if strings.Contains(request.Query, "repositoryOwner") {
+			w.Write([]byte(`{"data": {"repositoryOwner": {"repositories": {
+				"pageInfo": {"hasNextPage": false},
+				"nodes": [
+					{"databaseId": 1, "nameWithOwner": "test/repo1", "name": "repo1"},
+					{"databaseId": 2, "nameWithOwner": "test/repo2", "name": "repo2", "isPrivate": true, "isFork": true, "issues": {"totalCount": 2}, "pullRequests": {"totalCount": 1}}
+				]
+			}}}}`))
+			return
+		}
+		w.Write([]byte(`{"data": {
+			"r0": {"releases": {"pageInfo": {"hasNextPage": false}, "nodes": [
+				{"name": "v1.0.0", "tagName": "v1.0.0", "releaseAssets": {"nodes": [{"name": "tool.tar.gz", "downloadCount": 3}]}}
+			]}},
+			"r1": {"releases": {"pageInfo": {"hasNextPage": false}, "nodes": []}}
+		}}`))
+	}))
+	defer server.Close()
+
+	scanner := Scanner{
+		Provider: &GraphQLProvider{
+			Endpoint: server.URL,
+			Token:    "secret",
+		},
+	}
+	items, err := scanner.ScanRepositories("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 2 {
+		t.Fatalf("invalid scanned repositories items count, expected 2, got %d", len(items))
+	}
+	if items[0].Repository.FullName != "test/repo1" || len(items[0].Releases) != 1 {
+		t.Fatalf("invalid first scanned item: %+v", items[0])
+	}
+	if items[0].Releases[0].Assets[0].DownloadCount != 3 {
+		t.Fatalf("invalid asset download count, expected 3, got %d", items[0].Releases[0].Assets[0].DownloadCount)
+	}
+	if repository := items[1].Repository; !repository.Private || !repository.Fork || repository.OpenIssues != 3 {
+		t.Fatalf("invalid second repository, expected a private fork with 3 open issues: %+v", repository)
+	}
+	if len(items[1].Releases) != 0 {
+		t.Fatalf("invalid releases count of the second item, expected 0, got %d", len(items[1].Releases))
+	}
+}
+
+func TestListReleasesGraphQLAssetPages(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Query string `json:"query"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		queries = append(queries, request.Query)
+
+		switch {
+		case strings.Contains(request.Query, `node(id: "R_1")`) && strings.Contains(request.Query, `after: "c1"`):
+			w.Write([]byte(`{"data": {"node": {"releaseAssets": {"pageInfo": {"hasNextPage": true, "endCursor": "c2"}, "nodes": [{"name": "tool_darwin.tar.gz"}]}}}}`))
+		case strings.Contains(request.Query, `node(id: "R_1")`) && strings.Contains(request.Query, `after: "c2"`):
+			w.Write([]byte(`{"data": {"node": {"releaseAssets": {"pageInfo": {"hasNextPage": false}, "nodes": [{"name": "tool_windows.zip"}]}}}}`))
+		case strings.Contains(request.Query, "releases("):
+			w.Write([]byte(`{"data": {"r0": {"releases": {"pageInfo": {"hasNextPage": false}, "nodes": [
+				{"id": "R_1", "tagName": "v1.0.0", "releaseAssets": {"pageInfo": {"hasNextPage": true, "endCursor": "c1"}, "nodes": [{"name": "tool_linux.tar.gz"}]}},
+				{"id": "R_0", "tagName": "v0.9.0", "releaseAssets": {"pageInfo": {"hasNextPage": false}, "nodes": [{"name": "tool.tar.gz"}]}}
+			]}}}}`))
+		default:
+			w.Write([]byte(`{"errors": [{"message": "unexpected query"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	provider := &GraphQLProvider{Endpoint: server.URL, Token: "secret", Planner: GraphQLPlanner{AssetsPerRelease: 1}}
+	releases, err := provider.ListReleases(context.Background(), "test", "repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(releases) != 2 {
+		t.Fatalf("invalid releases count, expected 2, got %d", len(releases))
+	}
+	var names []string
+	for _, asset := range releases[0].Assets {
+		names = append(names, asset.Name)
+	}
+	if strings.Join(names, ",") != "tool_linux.tar.gz,tool_darwin.tar.gz,tool_windows.zip" {
+		t.Fatalf("invalid assets of the release with asset pages: %v", names)
+	}
+	if len(releases[1].Assets) != 1 {
+		t.Fatalf("invalid assets count of the release without asset pages, expected 1, got %d", len(releases[1].Assets))
+	}
+	if len(queries) != 3 {
+		t.Fatalf("invalid queries count, expected 3, got %d", len(queries))
+	}
+}
+
+func TestGraphQLErrorClasses(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		class    error
+	}{
+		{"rate limited", `{"errors": [{"type": "RATE_LIMITED", "message": "API rate limit exceeded for user ID 1."}]}`, ErrRateLimited},
+		{"not found", `{"data": {"r0": null}, "errors": [{"type": "NOT_FOUND", "path": ["r0"], "message": "Could not resolve to a Repository with the name 'test/repo'."}]}`, ErrNotFound},
+		{"sso", `{"data": {"r0": null}, "errors": [{"type": "FORBIDDEN", "path": ["r0"], "message": "Resource protected by organization SAML enforcement."}]}`, ErrSSORequired},
+		{"forbidden", `{"data": {"r0": null}, "errors": [{"type": "FORBIDDEN", "path": ["r0"], "message": "Resource not accessible by integration"}]}`, ErrBadCredentials},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				w.Write([]byte(test.response))
+			}))
+			defer server.Close()
+
+			provider := &GraphQLProvider{Endpoint: server.URL, Token: "secret"}
+			_, err := provider.ListReleases(context.Background(), "test", "repo")
+			if !errors.Is(err, test.class) {
+				t.Fatalf("invalid error class, expected %v, got %v", test.class, err)
+			}
+			if requests != 1 {
+				t.Fatalf("failure must not be retried, got %d requests", requests)
+			}
+		})
+	}
+}
+
+func TestGraphQLProviderRequestHandler(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": {"rateLimit": {"limit": 5000, "cost": 1, "remaining": 4999, "resetAt": "2030-01-01T00:00:00Z"}, "r0": {"releases": {"pageInfo": {"hasNextPage": false}, "nodes": []}}}}`))
+	}))
+	defer server.Close()
+
+	scanner := &Scanner{}
+	var headers []string
+	scanner.Use(func(next RequestHandler) RequestHandler {
+		return func(request *http.Request) (*http.Response, error) {
+			headers = append(headers, request.Header.Get("Authorization"))
+			return next(request)
+		}
+	})
+	provider := &GraphQLProvider{Endpoint: server.URL, Token: "secret", RequestHandler: scanner.RequestHandler()}
+	if _, err := provider.ListReleases(context.Background(), "test", "repo"); err != nil {
+		t.Fatal(err)
+	}
+	if scanner.Requests() != 1 || len(headers) != 1 || headers[0] != "bearer secret" {
+		t.Fatalf("graphql request is not performed through the scanner: %d requests, middleware headers %v", scanner.Requests(), headers)
+	}
+	if rateLimit := provider.rateLimit.Load(); rateLimit == nil || rateLimit.Remaining != 4999 {
+		t.Fatalf("invalid graphql rate limit: %+v", rateLimit)
+	}
+}
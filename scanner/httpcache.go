@@ -0,0 +1,260 @@
+package scanner
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrOffline is returned for requests in the offline mode whose responses are not cached.
+var ErrOffline = errors.New("response is not available offline")
+
+// DiskCache caches API responses on disk, so repeated runs within minutes do not fetch the same pages again.
+// Fresh responses are served without requests. Stale responses with an ETag are revalidated with a conditional
+// request, GitHub does not count 304 responses against the rate limit. Add it to the scanner with
+// s.Use(cache.Middleware()).
+type DiskCache struct {
+	Dir string
+	// TTL is how long responses are served without revalidation.
+	TTL time.Duration
+	// MaxSize is the total size of the cached responses in bytes, the least recently stored responses are
+	// evicted above it. The size is not limited if it is 0.
+	MaxSize int64
+	// Offline serves all requests from the cache regardless of the TTL. Requests of responses not in the cache
+	// fail with ErrOffline instead of reaching the network.
+	Offline bool
+
+	mu sync.Mutex
+	// size is the total size of the entries. It is counted once and then only grows with stored entries, so the
+	// directory is scanned again only when the size could exceed the max size.
+	size    int64
+	counted bool
+}
+
+// cacheEntry is a cached response. Rate limit headers are not cached, they are stale once the response is served
+// from the cache.
+type cacheEntry struct {
+	URL      string      `json:"url"`
+	Status   int         `json:"status"`
+	Header   http.Header `json:"header"`
+	Body     []byte      `json:"body"`
+	StoredAt time.Time   `json:"stored_at"`
+}
+
+// Middleware returns the middleware serving the GET requests of the scanner from the cache. Asset downloads are
+// not cached.
+func (c *DiskCache) Middleware() RequestMiddleware {
+	return func(next RequestHandler) RequestHandler {
+		return func(request *http.Request) (*http.Response, error) {
+			if c.Offline {
+				return c.offline(request)
+			}
+			if !cacheable(request) {
+				return next(request)
+			}
+
+			key := cacheKey(request)
+			entry := c.load(key)
+			if entry != nil && time.Since(entry.StoredAt) < c.TTL {
+				return entry.response(request), nil
+			}
+			if etag := entry.etag(); etag != "" {
+				request.Header.Set("If-None-Match", etag)
+			}
+
+			response, err := next(request)
+			if err != nil {
+				return nil, err
+			}
+			if response.StatusCode == http.StatusNotModified && entry != nil {
+				response.Body.Close()
+				entry.StoredAt = time.Now()
+				c.store(key, entry)
+				cached := entry.response(request)
+				copyRateLimitHeaders(cached.Header, response.Header)
+				return cached, nil
+			}
+			if response.StatusCode != http.StatusOK {
+				return response, nil
+			}
+
+			body, err := io.ReadAll(response.Body)
+			response.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+			response.Body = io.NopCloser(bytes.NewReader(body))
+			header := response.Header.Clone()
+			for name := range header {
+				if strings.HasPrefix(strings.ToLower(name), "x-ratelimit-") {
+					header.Del(name)
+				}
+			}
+			c.store(key, &cacheEntry{URL: request.URL.String(), Status: response.StatusCode, Header: header, Body: body, StoredAt: time.Now()})
+
+			return response, nil
+		}
+	}
+}
+
+func (c *DiskCache) offline(request *http.Request) (*http.Response, error) {
+	var entry *cacheEntry
+	if cacheable(request) {
+		entry = c.load(cacheKey(request))
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("%s %s: %w", request.Method, request.URL.Redacted(), ErrOffline)
+	}
+
+	return entry.response(request), nil
+}
+
+// cacheable reports whether the response of the request could be cached. The rate limit is never cached, it is
+// matched by the path suffix as GitHub Enterprise Server serves the API under /api/v3.
+func cacheable(request *http.Request) bool {
+	return request.Method == http.MethodGet &&
+		request.Header.Get("Range") == "" &&
+		request.Header.Get("Accept") != "application/octet-stream" &&
+		!strings.HasSuffix(strings.TrimSuffix(request.URL.Path, "/"), "/rate_limit")
+}
+
+// cacheKey identifies the response by the url, the accepted media type and the token, so responses are never
+// shared between tokens.
+func cacheKey(request *http.Request) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		request.URL.String(),
+		request.Header.Get("Accept"),
+		request.Header.Get("Authorization"),
+	}, "\n")))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func (e *cacheEntry) etag() string {
+	if e == nil {
+		return ""
+	}
+
+	return e.Header.Get("ETag")
+}
+
+func (e *cacheEntry) response(request *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status)),
+		StatusCode:    e.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       request,
+	}
+}
+
+func copyRateLimitHeaders(dst, src http.Header) {
+	for name, values := range src {
+		if strings.HasPrefix(strings.ToLower(name), "x-ratelimit-") {
+			dst[name] = values
+		}
+	}
+}
+
+func (c *DiskCache) path(key string) string {
+	return filepath.Join(c.Dir, key+".json")
+}
+
+// load returns the cached entry, nil if there is none or it could not be read.
+func (c *DiskCache) load(key string) *cacheEntry {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil
+	}
+
+	return &entry
+}
+
+// store writes the entry and evicts old entries above the max size. Failures only disable caching of the
+// response, they are not worth failing the request.
+func (c *DiskCache) store(key string, entry *cacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.Dir, 0o700); err != nil {
+		return
+	}
+	// the entry is renamed into place, so concurrent runs never read a partially written entry
+	file, err := os.CreateTemp(c.Dir, key+".*.tmp")
+	if err != nil {
+		return
+	}
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil || os.Rename(file.Name(), c.path(key)) != nil {
+		os.Remove(file.Name())
+		return
+	}
+
+	if c.MaxSize <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.counted {
+		c.evict()
+		c.counted = true
+	} else if c.size += int64(len(data)); c.size > c.MaxSize {
+		c.evict()
+	}
+}
+
+// evict removes the least recently stored entries until the cache fits the max size and counts its size.
+func (c *DiskCache) evict() {
+	entries, err := os.ReadDir(c.Dir)
+	if err != nil {
+		return
+	}
+	var files []os.FileInfo
+	var size int64
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, info)
+		size += info.Size()
+	}
+	slices.SortFunc(files, func(a, b os.FileInfo) int {
+		return a.ModTime().Compare(b.ModTime())
+	})
+	for _, file := range files {
+		if size <= c.MaxSize {
+			break
+		}
+		if err := os.Remove(filepath.Join(c.Dir, file.Name())); err == nil || errors.Is(err, os.ErrNotExist) {
+			size -= file.Size()
+		}
+	}
+	c.size = size
+}
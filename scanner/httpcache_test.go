@@ -0,0 +1,167 @@
+package scanner
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestDiskCache(t *testing.T) {
+	requests, revalidations := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(5000-requests))
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			revalidations++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`[{"id": 1, "name": "test", "full_name": "test/test"}]`))
+	}))
+	defer server.Close()
+
+	cache := &DiskCache{Dir: t.TempDir(), TTL: time.Hour}
+	scanner := Scanner{BaseUrl: server.URL}
+	scanner.Use(cache.Middleware())
+	for range 2 {
+		repositories, err := scanner.GetRepositoriesPerPage("test", 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(repositories) != 1 {
+			t.Fatalf("invalid repositories count, expected 1, got %d", len(repositories))
+		}
+	}
+	if requests != 1 {
+		t.Fatalf("invalid requests count, expected 1, got %d", requests)
+	}
+	if remaining := scanner.RateLimit().Remaining; remaining != 4999 {
+		t.Fatalf("invalid rate limit, expected 4999 from the request, got %d", remaining)
+	}
+
+	// stale responses are revalidated by their etag
+	cache.TTL = 0
+	repositories, err := scanner.GetRepositoriesPerPage("test", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repositories) != 1 {
+		t.Fatalf("invalid revalidated repositories count, expected 1, got %d", len(repositories))
+	}
+	if requests != 2 || revalidations != 1 {
+		t.Fatalf("invalid requests, expected 2 with 1 revalidation, got %d with %d", requests, revalidations)
+	}
+	if remaining := scanner.RateLimit().Remaining; remaining != 4998 {
+		t.Fatalf("invalid rate limit, expected 4998 from the revalidation, got %d", remaining)
+	}
+
+	// responses are not shared between tokens
+	cache.TTL = time.Hour
+	scanner.Token = "token"
+	if _, err := scanner.GetRepositoriesPerPage("test", 1); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 3 {
+		t.Fatalf("invalid requests count, expected 3, got %d", requests)
+	}
+}
+
+func TestDiskCacheEviction(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	cache := &DiskCache{Dir: t.TempDir(), TTL: time.Hour, MaxSize: 1000}
+	scanner := Scanner{BaseUrl: server.URL}
+	scanner.Use(cache.Middleware())
+	for page := 1; page <= 20; page++ {
+		if _, err := scanner.GetRepositoriesPerPage("test", page); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := os.ReadDir(cache.Dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var size int64
+	for _, entry := range entries {
+		info, _ := entry.Info()
+		size += info.Size()
+	}
+	if size > cache.MaxSize || len(entries) == 0 {
+		t.Fatalf("invalid cache size, expected at most %d in at least 1 entry, got %d in %d", cache.MaxSize, size, len(entries))
+	}
+}
+
+func TestDiskCacheOffline(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`[{"id": 1, "name": "test", "full_name": "test/test"}]`))
+	}))
+	defer server.Close()
+
+	cache := &DiskCache{Dir: t.TempDir()}
+	scanner := Scanner{BaseUrl: server.URL}
+	scanner.Use(cache.Middleware())
+	if _, err := scanner.GetRepositoriesPerPage("test", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// expired responses are served offline
+	cache.Offline = true
+	repositories, err := scanner.GetRepositoriesPerPage("test", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repositories) != 1 || requests != 1 {
+		t.Fatalf("invalid offline repositories, expected 1 after 1 request, got %d after %d", len(repositories), requests)
+	}
+
+	_, err = scanner.GetRepositoriesPerPage("test", 2)
+	if !errors.Is(err, ErrOffline) {
+		t.Fatalf("invalid error, expected %v, got %v", ErrOffline, err)
+	}
+	if _, err := scanner.GetRateLimits(); !errors.Is(err, ErrOffline) {
+		t.Fatalf("invalid rate limits error, expected %v, got %v", ErrOffline, err)
+	}
+	if requests != 1 {
+		t.Fatalf("invalid requests count, expected 1, got %d", requests)
+	}
+}
+
+func TestCacheable(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		url       string
+		accept    string
+		cacheable bool
+	}{
+		{"repositories", http.MethodGet, "https://api.github.com/orgs/test/repos", "", true},
+		{"post", http.MethodPost, "https://api.github.com/graphql", "", false},
+		{"asset download", http.MethodGet, "https://api.github.com/repos/test/repo/releases/assets/1", "application/octet-stream", false},
+		{"rate limit", http.MethodGet, "https://api.github.com/rate_limit", "", false},
+		{"enterprise rate limit", http.MethodGet, "https://github.example.com/api/v3/rate_limit", "", false},
+		{"repository named rate_limit", http.MethodGet, "https://api.github.com/repos/test/rate_limit_tool/releases", "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(test.method, test.url, nil)
+			if test.accept != "" {
+				request.Header.Set("Accept", test.accept)
+			}
+			if got := cacheable(request); got != test.cacheable {
+				t.Fatalf("expected cacheable %v, got %v", test.cacheable, got)
+			}
+		})
+	}
+}
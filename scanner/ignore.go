@@ -0,0 +1,94 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+)
+
+// IgnoreRule is a line of an ignore list.
+type IgnoreRule struct {
+	// Pattern matches repository full names, e.g. "acme/meta" or "acme/archive-*". Patterns without a slash
+	// match the repository name in any account, e.g. ".github".
+	Pattern string
+	// Negate includes the matching repositories again, e.g. "!acme/archive-tools" after "acme/archive-*".
+	Negate bool
+}
+
+// IgnoreList skips repositories before their releases are fetched, e.g. meta and archive repositories nobody
+// wants in reports. The last matching rule decides, like in .gitignore files. Matching is case-insensitive,
+// as repository names are.
+type IgnoreList []*IgnoreRule
+
+// LoadIgnoreList reads an ignore list file: a pattern per line, see IgnoreRule. Blank lines and lines starting
+// with # are skipped, a leading ! negates the pattern.
+func LoadIgnoreList(filePath string) (IgnoreList, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	list, err := ParseIgnoreList(data)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the ignore list %s: %w", filePath, err)
+	}
+
+	return list, nil
+}
+
+// ParseIgnoreList parses the content of an ignore list file, see LoadIgnoreList.
+func ParseIgnoreList(data []byte) (IgnoreList, error) {
+	var list IgnoreList
+	lines := bufio.NewScanner(bytes.NewReader(data))
+	for number := 1; lines.Scan(); number++ {
+		line := strings.TrimSpace(lines.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rule := &IgnoreRule{Pattern: line}
+		if pattern, negated := strings.CutPrefix(line, "!"); negated {
+			rule.Pattern, rule.Negate = strings.TrimSpace(pattern), true
+		}
+		if _, err := path.Match(strings.ToLower(rule.Pattern), ""); err != nil || rule.Pattern == "" || strings.Count(rule.Pattern, "/") > 1 {
+			return nil, fmt.Errorf("line %d: invalid pattern %q", number, line)
+		}
+		list = append(list, rule)
+	}
+	if err := lines.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// Ignored reports whether the repository is skipped.
+func (l IgnoreList) Ignored(repositoryFullName string) bool {
+	fullName := strings.ToLower(repositoryFullName)
+	_, name, _ := strings.Cut(fullName, "/")
+	ignored := false
+	for _, rule := range l {
+		pattern, subject := strings.ToLower(rule.Pattern), fullName
+		if !strings.Contains(pattern, "/") {
+			subject = name
+		}
+		if matched, _ := path.Match(pattern, subject); matched {
+			ignored = !rule.Negate
+		}
+	}
+
+	return ignored
+}
+
+// Filter returns the repositories that are not ignored in their order.
+func (l IgnoreList) Filter(repositories []*Repository) []*Repository {
+	if len(l) == 0 {
+		return repositories
+	}
+
+	return slices.DeleteFunc(slices.Clone(repositories), func(repository *Repository) bool {
+		return l.Ignored(repository.FullName)
+	})
+}
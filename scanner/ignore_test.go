@@ -0,0 +1,72 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadIgnoreList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".scannerignore")
+	content := "# meta repositories\n.github\n\nacme/archive-*\n!acme/archive-tools\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := LoadIgnoreList(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 3 || !list[2].Negate || list[2].Pattern != "acme/archive-tools" {
+		t.Fatalf("invalid ignore list, expected 3 rules with the last negated, got %d", len(list))
+	}
+	for fullName, expected := range map[string]bool{
+		"acme/.github":       true,
+		"other/.GitHub":      true,
+		"acme/Archive-2019":  true,
+		"acme/archive-tools": false,
+		"other/archive-2019": false,
+		"acme/api":           false,
+	} {
+		if ignored := list.Ignored(fullName); ignored != expected {
+			t.Fatalf("invalid ignored state of %s, expected %v, got %v", fullName, expected, ignored)
+		}
+	}
+
+	for _, content := range []string{"acme/[", "!", "acme/api/extra"} {
+		if _, err := ParseIgnoreList([]byte(content)); err == nil {
+			t.Fatalf("invalid result of the pattern %q, expected an error", content)
+		}
+	}
+}
+
+func TestScanRepositoriesIgnore(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/test/repos":
+			w.Write([]byte(`[
+				{"full_name": "test/api", "name": "api", "pushed_at": "2024-01-01T00:00:00Z"},
+				{"full_name": "test/meta", "name": "meta", "pushed_at": "2024-03-01T00:00:00Z"},
+				{"full_name": "test/archive-old", "name": "archive-old", "pushed_at": "2024-02-01T00:00:00Z"}
+			]`))
+		case "/repos/test/api/releases":
+			w.Write([]byte(`[{"tag_name": "v1.0.0"}]`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	// ignored repositories do not count towards MaxRepositories
+	scanner := Scanner{BaseUrl: server.URL, PerPage: 100, MaxRepositories: 1, Ignore: IgnoreList{{Pattern: "meta"}, {Pattern: "test/archive-*"}}}
+	items, err := scanner.ScanRepositories("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Repository.FullName != "test/api" {
+		t.Fatalf("invalid scanned repositories, expected test/api, got %v", items)
+	}
+}
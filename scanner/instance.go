@@ -0,0 +1,121 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// instanceAccount is an account of the instance accounts lists, users are listed with organizations.
+type instanceAccount struct {
+	Member
+	Type string `json:"type"`
+}
+
+// ScanInstance scans the repositories of all organizations and users of the instance with a Coordinator, e.g. an
+// instance-wide release inventory of a GitHub Enterprise Server. See GetInstanceAccounts.
+func (s *Scanner) ScanInstance() ([]*ResultItem, []*SkippedAccount, error) {
+	accounts, err := s.GetInstanceAccounts()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s.ScanAccounts(accounts)
+}
+
+// GetInstanceAccounts returns the logins of all organizations and then all users of the instance. A site admin
+// token of a GitHub Enterprise Server sees all of them, on github.com the lists take hours to fetch.
+func (s *Scanner) GetInstanceAccounts() ([]string, error) {
+	ctx := context.Background()
+	organizations, err := s.getAllOrganizations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.getAllUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var accounts []string
+	for _, account := range append(organizations, users...) {
+		accounts = append(accounts, account.Login)
+	}
+
+	return accounts, nil
+}
+
+// GetAllOrganizations returns all organizations of the instance in the order of their creation.
+func (s *Scanner) GetAllOrganizations() ([]*Member, error) {
+	return s.getAllOrganizations(context.Background())
+}
+
+func (s *Scanner) getAllOrganizations(ctx context.Context) ([]*Member, error) {
+	accounts, err := s.getInstanceAccounts(ctx, "GetAllOrganizations", "organizations")
+	if err != nil {
+		return nil, err
+	}
+
+	organizations := make([]*Member, 0, len(accounts))
+	for _, account := range accounts {
+		organizations = append(organizations, &account.Member)
+	}
+
+	return organizations, nil
+}
+
+// GetAllUsers returns all users of the instance in the order of their sign up.
+func (s *Scanner) GetAllUsers() ([]*Member, error) {
+	return s.getAllUsers(context.Background())
+}
+
+func (s *Scanner) getAllUsers(ctx context.Context) ([]*Member, error) {
+	accounts, err := s.getInstanceAccounts(ctx, "GetAllUsers", "users")
+	if err != nil {
+		return nil, err
+	}
+
+	var users []*Member
+	for _, account := range accounts {
+		if account.Type == "" || account.Type == "User" {
+			users = append(users, &account.Member)
+		}
+	}
+
+	return users, nil
+}
+
+// getInstanceAccounts fetches all pages of an instance accounts list. The lists are paginated by the id of the last
+// listed account rather than page numbers, so the next page url is taken from the Link header.
+func (s *Scanner) getInstanceAccounts(ctx context.Context, spanName, endpoint string) ([]*instanceAccount, error) {
+	ctx, span := s.getTracer().Start(ctx, spanName)
+	defer span.End()
+
+	var accounts []*instanceAccount
+	url := fmt.Sprintf("%s/%s?per_page=%d", s.BaseUrl, endpoint, s.getPerPage())
+	for url != "" {
+		response, err := s.get(ctx, span, url)
+		if err != nil {
+			return nil, err
+		}
+		err = func() error {
+			defer response.Body.Close()
+			if response.StatusCode != http.StatusOK {
+				return fmt.Errorf("could not get %s of the instance: %w", endpoint, s.newApiError(response))
+			}
+			var chunk []*instanceAccount
+			if err := json.NewDecoder(response.Body).Decode(&chunk); err != nil {
+				return err
+			}
+			accounts = append(accounts, chunk...)
+			return nil
+		}()
+		if err != nil {
+			return nil, err
+		}
+		s.getLogger().Debug("instance accounts page fetched", "endpoint", endpoint, "count", len(accounts))
+		url = nextPage(response.Header.Get("Link"))
+	}
+
+	return accounts, nil
+}
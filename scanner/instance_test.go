@@ -0,0 +1,48 @@
+package scanner
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestScanInstance(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/organizations":
+			if r.URL.Query().Get("since") == "" {
+				w.Header().Set("Link", fmt.Sprintf(`<%s/organizations?per_page=100&since=1>; rel="next"`, server.URL))
+				w.Write([]byte(`[{"login": "org-a", "id": 1}]`))
+				return
+			}
+			w.Write([]byte(`[{"login": "org-b", "id": 2}]`))
+		case "/users":
+			w.Write([]byte(`[{"login": "alice", "id": 3, "type": "User"}, {"login": "org-a", "id": 1, "type": "Organization"}]`))
+		case "/users/org-a/repos", "/users/org-b/repos", "/users/alice/repos":
+			account := r.URL.Path[len("/users/") : len(r.URL.Path)-len("/repos")]
+			fmt.Fprintf(w, `[{"full_name": "%s/tool", "name": "tool"}]`, account)
+		default:
+			w.Write([]byte(`[{"tag_name": "v1.0.0"}]`))
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL}
+	accounts, err := scanner.GetInstanceAccounts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equal(accounts, []string{"org-a", "org-b", "alice"}) {
+		t.Fatalf("invalid instance accounts, expected [org-a org-b alice], got %v", accounts)
+	}
+
+	items, skipped, err := scanner.ScanInstance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 3 || len(skipped) != 0 || items[0].Repository.FullName != "alice/tool" {
+		t.Fatalf("invalid scanned items, expected alice/tool, org-a/tool and org-b/tool, got %v", items)
+	}
+}
@@ -0,0 +1,61 @@
+package scanner
+
+import (
+	"context"
+	"io"
+)
+
+// RepositoryScanner is the API of Scanner. Code embedding the scanner should depend on it, so tests can
+// replace the scanner with the fake of the scannertest package. Helpers computing results from already
+// scanned data, e.g. VersionDrift or EstimateScan, are not part of it.
+type RepositoryScanner interface {
+	Provider
+
+	ScanRepositories(user string) ([]*ResultItem, error)
+	StreamRepositories(user string, handle func(item *ResultItem) error) error
+	ScanAccounts(accounts []string) ([]*ResultItem, []*SkippedAccount, error)
+	ScanMine() ([]*ResultItem, error)
+	ScanStarred(user string) ([]*ResultItem, error)
+
+	GetAllRepositories(user string) ([]*Repository, error)
+	GetRepositoriesPerPage(user string, page int) ([]*Repository, error)
+	GetAllAccessibleRepositories() ([]*Repository, error)
+	GetAllStarredRepositories(user string) ([]*Repository, error)
+	GetStarredRepositoriesPerPage(user string, page int) ([]*Repository, error)
+	ListStarredRepositories(ctx context.Context, user string) ([]*Repository, error)
+	GetForks(user string) ([]*Fork, error)
+
+	GetAllReleases(user, repository string) ([]*Release, error)
+	GetReleasesPerPage(user, repository string, page int) ([]*Release, error)
+	GetReleaseByTag(ctx context.Context, owner, repository, tag string) (*Release, error)
+	GetChangelog(user, repository, from, to string) (*Changelog, error)
+	GetCompareCommits(user, repository, base, head string) ([]*Commit, error)
+	CompareReleases(owner, repository, base, head string) (*ReleaseComparison, error)
+	DownloadAsset(ctx context.Context, asset *Asset, w io.Writer) error
+
+	GetAllBranches(user, repository string) ([]*Branch, error)
+	GetAllContributors(user, repository string) ([]*Contributor, error)
+	GetLanguages(user, repository string) (map[string]int64, error)
+	GetRepositorySettings(owner, repository string) (*RepositorySettings, error)
+	GetAllIssues(user, repository string) ([]*Issue, error)
+	GetAllPullRequests(user, repository string) ([]*Issue, error)
+	GetWorkflowRuns(user, repository string) ([]*WorkflowRun, error)
+	GetCommitActivity(user, repository string) (*CommitActivity, error)
+	GetTraffic(user, repository string) (*Traffic, error)
+	GetGoModule(user, repository string) (*GoModule, error)
+	GetSBOM(user, repository string) (*SBOM, error)
+	GetDependabotAlerts(user, repository string) ([]*DependabotAlert, error)
+	GetCodeScanningAlerts(user, repository string) ([]*CodeScanningAlert, error)
+	GetSecretScanningAlerts(user, repository string) ([]*SecretScanningAlert, error)
+
+	GetOrgMembers(org string) ([]*Member, error)
+	GetTeams(org string) ([]*Team, error)
+	GetTeamRepositories(org, team string) (map[string]string, error)
+	GetOwnership(org string) (map[string][]*TeamAccess, error)
+	GetPackages(org string) ([]*Package, error)
+
+	GetRateLimits() (map[string]*RateLimit, error)
+	RateLimit() *RateLimit
+}
+
+var _ RepositoryScanner = (*Scanner)(nil)
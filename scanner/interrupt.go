@@ -0,0 +1,47 @@
+package scanner
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrInterrupted is returned by the scans stopped with Scanner.Interrupt together with the items scanned before.
+var ErrInterrupted = errors.New("scan interrupted")
+
+// Interrupt stops the running and following scans of the scanner gracefully: no more repositories are scanned,
+// the repositories being scanned are completed, and the scans return the items scanned so far with
+// ErrInterrupted. It is safe to call it concurrently and more than once.
+func (s *Scanner) Interrupt() {
+	s.interruption.interrupt()
+}
+
+// interruption is closed once the scans of a scanner are interrupted.
+type interruption struct {
+	once  sync.Once
+	close sync.Once
+	done  chan struct{}
+}
+
+func (i *interruption) channel() chan struct{} {
+	i.once.Do(func() {
+		i.done = make(chan struct{})
+	})
+
+	return i.done
+}
+
+func (i *interruption) interrupt() {
+	done := i.channel()
+	i.close.Do(func() {
+		close(done)
+	})
+}
+
+func (i *interruption) interrupted() bool {
+	select {
+	case <-i.channel():
+		return true
+	default:
+		return false
+	}
+}
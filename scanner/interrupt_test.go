@@ -0,0 +1,63 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestScanRepositoriesInterrupted(t *testing.T) {
+	const repositoriesCount = maxWorkersCount + 50
+	var scanner *Scanner
+	var once sync.Once
+	var first string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/users/test/repos" {
+			var repositories []string
+			for i := range repositoriesCount {
+				repositories = append(repositories, fmt.Sprintf(`{"full_name": "test/repo%03d", "name": "repo%03d"}`, i, i))
+			}
+			w.Write([]byte("[" + strings.Join(repositories, ",") + "]"))
+			return
+		}
+		// the scan is interrupted while the releases of the first repository are fetched
+		once.Do(func() {
+			first = strings.Split(r.URL.Path, "/")[3]
+			scanner.Interrupt()
+		})
+		w.Write([]byte(`[{"tag_name": "v1.0.0"}]`))
+	}))
+	defer server.Close()
+
+	scanner = &Scanner{BaseUrl: server.URL}
+	items, err := scanner.ScanRepositories("test")
+	if !errors.Is(err, ErrInterrupted) {
+		t.Fatalf("invalid error, expected %v, got %v", ErrInterrupted, err)
+	}
+	if len(items) == 0 || len(items) >= repositoriesCount {
+		t.Fatalf("invalid scanned items count, expected the ones being scanned, got %d", len(items))
+	}
+	scanned := false
+	for _, item := range items {
+		if len(item.Releases) != 1 {
+			t.Fatalf("invalid releases of %s, expected 1, got %d", item.Repository.FullName, len(item.Releases))
+		}
+		scanned = scanned || item.Repository.Name == first
+	}
+	if !scanned {
+		t.Fatalf("repository %s being scanned when interrupted is missing", first)
+	}
+
+	// following scans are interrupted before listing the repositories
+	requests := scanner.Requests()
+	if items, err := scanner.ScanRepositories("test"); !errors.Is(err, ErrInterrupted) || len(items) != 0 {
+		t.Fatalf("invalid interrupted scan, expected no items and %v, got %d items and %v", ErrInterrupted, len(items), err)
+	}
+	if scanner.Requests() != requests {
+		t.Fatalf("invalid requests count, expected %d, got %d", requests, scanner.Requests())
+	}
+}
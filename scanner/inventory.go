@@ -0,0 +1,120 @@
+package scanner
+
+import (
+	"sort"
+	"strings"
+)
+
+// AssetTotals are the counts, sizes and download counts of release assets.
+type AssetTotals struct {
+	// Releases counts the releases with assets.
+	Releases int   `json:"releases"`
+	Assets   int   `json:"assets"`
+	Bytes    int64 `json:"bytes"`
+	// OldBytes are the bytes of the assets of all releases but the latest published one, e.g. binaries nobody
+	// downloads anymore.
+	OldBytes  int64 `json:"old_bytes"`
+	Downloads int64 `json:"downloads"`
+}
+
+func (t *AssetTotals) add(other AssetTotals) {
+	t.Releases += other.Releases
+	t.Assets += other.Assets
+	t.Bytes += other.Bytes
+	t.OldBytes += other.OldBytes
+	t.Downloads += other.Downloads
+}
+
+// RepositoryAssets are the asset totals of a repository.
+type RepositoryAssets struct {
+	Repository string `json:"repository"`
+	AssetTotals
+}
+
+// AccountAssets are the asset totals of the repositories of an account.
+type AccountAssets struct {
+	Account string `json:"account"`
+	// Repositories counts the repositories with assets.
+	Repositories int `json:"repositories"`
+	AssetTotals
+}
+
+// AssetInventory is the storage use of release assets of the scanned repositories.
+type AssetInventory struct {
+	Repositories []*RepositoryAssets `json:"repositories"`
+	Accounts     []*AccountAssets    `json:"accounts"`
+	Total        AssetTotals         `json:"total"`
+}
+
+// NewAssetInventory sums the release assets of the items per repository and per repository owner, e.g. to find
+// the repositories hosting gigabytes of old binaries. Repositories without assets are left out. Repositories and
+// accounts are ordered by size, the largest first. Assets of drafts are counted, they are stored all the same.
+func NewAssetInventory(items []*ResultItem) *AssetInventory {
+	inventory := &AssetInventory{}
+	accounts := make(map[string]*AccountAssets)
+	for _, item := range items {
+		totals := releaseAssetTotals(item.Releases)
+		if totals.Assets == 0 {
+			continue
+		}
+		inventory.Repositories = append(inventory.Repositories, &RepositoryAssets{Repository: item.Repository.FullName, AssetTotals: totals})
+		inventory.Total.add(totals)
+
+		name, _, _ := strings.Cut(item.Repository.FullName, "/")
+		account := accounts[name]
+		if account == nil {
+			account = &AccountAssets{Account: name}
+			accounts[name] = account
+			inventory.Accounts = append(inventory.Accounts, account)
+		}
+		account.Repositories++
+		account.add(totals)
+	}
+
+	sort.Slice(inventory.Repositories, func(i, j int) bool {
+		a, b := inventory.Repositories[i], inventory.Repositories[j]
+		if a.Bytes != b.Bytes {
+			return a.Bytes > b.Bytes
+		}
+		return a.Repository < b.Repository
+	})
+	sort.Slice(inventory.Accounts, func(i, j int) bool {
+		a, b := inventory.Accounts[i], inventory.Accounts[j]
+		if a.Bytes != b.Bytes {
+			return a.Bytes > b.Bytes
+		}
+		return a.Account < b.Account
+	})
+
+	return inventory
+}
+
+func releaseAssetTotals(releases []*Release) AssetTotals {
+	var latest *Release
+	for _, release := range releases {
+		if release.Draft || release.PublishedAt == nil {
+			continue
+		}
+		if latest == nil || release.PublishedAt.After(*latest.PublishedAt) {
+			latest = release
+		}
+	}
+
+	var totals AssetTotals
+	for _, release := range releases {
+		if len(release.Assets) == 0 {
+			continue
+		}
+		totals.Releases++
+		for _, asset := range release.Assets {
+			totals.Assets++
+			totals.Bytes += asset.Size
+			totals.Downloads += int64(asset.DownloadCount)
+			if release != latest {
+				totals.OldBytes += asset.Size
+			}
+		}
+	}
+
+	return totals
+}
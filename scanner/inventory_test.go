@@ -0,0 +1,39 @@
+package scanner
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewAssetInventory(t *testing.T) {
+	older, newer := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	items := []*ResultItem{
+		{Repository: &Repository{FullName: "a/small"}, Releases: []*Release{
+			{TagName: "v1.0.0", PublishedAt: &newer, Assets: []*Asset{{Size: 10, DownloadCount: 5}}},
+		}},
+		{Repository: &Repository{FullName: "b/none"}, Releases: []*Release{{TagName: "v1.0.0", PublishedAt: &newer}}},
+		{Repository: &Repository{FullName: "a/large"}, Releases: []*Release{
+			{TagName: "v2.0.0", Draft: true, Assets: []*Asset{{Size: 300}}},
+			{TagName: "v1.1.0", PublishedAt: &newer, Assets: []*Asset{{Size: 100, DownloadCount: 1}, {Size: 50}}},
+			{TagName: "v1.0.0", PublishedAt: &older, Assets: []*Asset{{Size: 200, DownloadCount: 7}}},
+		}},
+		{Repository: &Repository{FullName: "b/medium"}, Releases: []*Release{
+			{TagName: "v1.0.0", PublishedAt: &older, Assets: []*Asset{{Size: 100}}},
+		}},
+	}
+
+	inventory := NewAssetInventory(items)
+	if len(inventory.Repositories) != 3 || inventory.Repositories[0].Repository != "a/large" || inventory.Repositories[2].Repository != "a/small" {
+		t.Fatalf("invalid repositories, expected [a/large b/medium a/small], got %d", len(inventory.Repositories))
+	}
+	expected := AssetTotals{Releases: 3, Assets: 4, Bytes: 650, OldBytes: 500, Downloads: 8}
+	if large := inventory.Repositories[0].AssetTotals; large != expected {
+		t.Fatalf("invalid totals of a/large, expected %+v, got %+v", expected, large)
+	}
+	if len(inventory.Accounts) != 2 || inventory.Accounts[0].Account != "a" || inventory.Accounts[0].Repositories != 2 || inventory.Accounts[0].Bytes != 660 {
+		t.Fatalf("invalid accounts, expected a with 2 repositories of 660 bytes first, got %+v", inventory.Accounts[0])
+	}
+	if inventory.Total.Bytes != 760 || inventory.Total.Assets != 6 || inventory.Total.Downloads != 13 {
+		t.Fatalf("invalid total, expected 6 assets of 760 bytes with 13 downloads, got %+v", inventory.Total)
+	}
+}
@@ -0,0 +1,149 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Issue is an issue or a pull request.
+type Issue struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	State  string `json:"state"`
+	User   struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// PullRequest is set by the issues API for pull requests, which are listed as issues too.
+	PullRequest *struct{} `json:"pull_request,omitempty"`
+}
+
+// Activity is the open issues and pull requests of the repository. The lists are only filled if
+// Scanner.ScanIssueLists is set.
+type Activity struct {
+	OpenIssues       int      `json:"open_issues"`
+	OpenPullRequests int      `json:"open_pull_requests"`
+	Issues           []*Issue `json:"issues,omitempty"`
+	PullRequests     []*Issue `json:"pull_requests,omitempty"`
+}
+
+// GetAllIssues returns open issues of the repository, pull requests are not included.
+func (s *Scanner) GetAllIssues(user, repository string) ([]*Issue, error) {
+	return s.getAllIssues(context.Background(), user, repository)
+}
+
+// GetAllPullRequests returns open pull requests of the repository.
+func (s *Scanner) GetAllPullRequests(user, repository string) ([]*Issue, error) {
+	return s.getAllPullRequests(context.Background(), user, repository)
+}
+
+func (s *Scanner) getAllIssues(ctx context.Context, user, repository string) ([]*Issue, error) {
+	items, err := paginate(ctx, func(ctx context.Context, page int) ([]*Issue, pageLinks, error) {
+		return s.getIssuesPerPage(ctx, user, repository, "issues", page)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []*Issue
+	for _, issue := range items {
+		if issue.PullRequest == nil {
+			issues = append(issues, issue)
+		}
+	}
+
+	return issues, nil
+}
+
+func (s *Scanner) getAllPullRequests(ctx context.Context, user, repository string) ([]*Issue, error) {
+	return paginate(ctx, func(ctx context.Context, page int) ([]*Issue, pageLinks, error) {
+		return s.getIssuesPerPage(ctx, user, repository, "pulls", page)
+	})
+}
+
+// getIssuesPerPage fetches a page of open items of the issues or pulls endpoint.
+func (s *Scanner) getIssuesPerPage(ctx context.Context, user, repository, endpoint string, page int) ([]*Issue, pageLinks, error) {
+	if err := s.checkPage(page); err != nil {
+		return nil, pageLinks{}, err
+	}
+	if err := s.checkUser(user); err != nil {
+		return nil, pageLinks{}, err
+	}
+	if err := s.checkRepository(repository); err != nil {
+		return nil, pageLinks{}, err
+	}
+	ctx, span := s.getTracer().Start(ctx, "GetIssuesPerPage", StringAttribute("account", user), StringAttribute("repository", repository), StringAttribute("endpoint", endpoint), IntAttribute("page", page))
+	defer span.End()
+
+	response, err := s.get(ctx, span, fmt.Sprintf("%s/repos/%s/%s/%s?state=open&per_page=%d&page=%d", s.BaseUrl, user, repository, endpoint, s.getPerPage(), page))
+	if err != nil {
+		return nil, pageLinks{}, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, pageLinks{}, fmt.Errorf("could not get %s for the repository %s: %w", endpoint, repository, s.newApiError(response))
+	}
+
+	var issues []*Issue
+	if err := json.NewDecoder(response.Body).Decode(&issues); err != nil {
+		return nil, pageLinks{}, err
+	}
+
+	return issues, parsePageLinks(response.Header), nil
+}
+
+// countPullRequests counts open pull requests with a single request: pages of one pull request are requested,
+// so the last page number in the Link header is the count.
+func (s *Scanner) countPullRequests(ctx context.Context, user, repository string) (int, error) {
+	ctx, span := s.getTracer().Start(ctx, "CountPullRequests", StringAttribute("account", user), StringAttribute("repository", repository))
+	defer span.End()
+
+	response, err := s.get(ctx, span, fmt.Sprintf("%s/repos/%s/%s/pulls?state=open&per_page=1", s.BaseUrl, user, repository))
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("could not get pulls for the repository %s: %w", repository, s.newApiError(response))
+	}
+	if count, ok := lastPage(response.Header.Get("Link")); ok {
+		return count, nil
+	}
+
+	var pullRequests []*Issue
+	if err := json.NewDecoder(response.Body).Decode(&pullRequests); err != nil {
+		return 0, err
+	}
+
+	return len(pullRequests), nil
+}
+
+// getActivity counts open issues and pull requests of the repository. The open issues count of a repository
+// includes pull requests, so they are subtracted.
+func (s *Scanner) getActivity(ctx context.Context, owner string, repository *Repository) (*Activity, error) {
+	if s.ScanIssueLists {
+		issues, err := s.getAllIssues(ctx, owner, repository.Name)
+		if err != nil {
+			return nil, err
+		}
+		pullRequests, err := s.getAllPullRequests(ctx, owner, repository.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Activity{OpenIssues: len(issues), OpenPullRequests: len(pullRequests), Issues: issues, PullRequests: pullRequests}, nil
+	}
+
+	pullRequests, err := s.countPullRequests(ctx, owner, repository.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Activity{OpenIssues: max(repository.OpenIssues-pullRequests, 0), OpenPullRequests: pullRequests}, nil
+}
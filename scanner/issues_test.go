@@ -0,0 +1,70 @@
+package scanner
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetAllIssues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/test/test/issues":
+			if r.URL.Query().Get("state") != "open" {
+				t.Errorf("invalid state, expected open, got %s", r.URL.Query().Get("state"))
+			}
+			w.Write([]byte(`[{"number": 1, "title": "bug"}, {"number": 2, "title": "fix", "pull_request": {}}]`))
+		case "/repos/test/test/pulls":
+			w.Write([]byte(`[{"number": 2, "title": "fix"}]`))
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL, PerPage: 100}
+	issues, err := scanner.GetAllIssues("test", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || issues[0].Number != 1 {
+		t.Fatalf("invalid issues, expected [1], got %v", issues)
+	}
+	pullRequests, err := scanner.GetAllPullRequests("test", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pullRequests) != 1 || pullRequests[0].Number != 2 {
+		t.Fatalf("invalid pull requests, expected [2], got %v", pullRequests)
+	}
+}
+
+func TestScanIssues(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/test/repos":
+			w.Write([]byte(`[{"full_name": "test/busy", "name": "busy", "open_issues_count": 10}, {"full_name": "test/quiet", "name": "quiet", "open_issues_count": 1}]`))
+		case "/repos/test/busy/releases", "/repos/test/quiet/releases":
+			w.Write([]byte(`[]`))
+		case "/repos/test/busy/pulls":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repos/test/busy/pulls?state=open&per_page=1&page=2>; rel="next", <%s/repos/test/busy/pulls?state=open&per_page=1&page=4>; rel="last"`, server.URL, server.URL))
+			w.Write([]byte(`[{"number": 1}]`))
+		case "/repos/test/quiet/pulls":
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL, PerPage: 100, ScanIssues: true}
+	items, err := scanner.ScanRepositories("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	busy, quiet := items[0].Activity, items[1].Activity
+	if busy.OpenIssues != 6 || busy.OpenPullRequests != 4 {
+		t.Fatalf("invalid activity of the busy repository, expected 6 issues and 4 pull requests, got %d and %d", busy.OpenIssues, busy.OpenPullRequests)
+	}
+	if quiet.OpenIssues != 1 || quiet.OpenPullRequests != 0 {
+		t.Fatalf("invalid activity of the quiet repository, expected 1 issue and 0 pull requests, got %d and %d", quiet.OpenIssues, quiet.OpenPullRequests)
+	}
+}
@@ -0,0 +1,45 @@
+package scanner
+
+import (
+	"context"
+	"iter"
+)
+
+// Repositories iterates over the repositories of the account. Pages are fetched as the iteration reaches them, so
+// stopping it early saves the requests of the rest. A failed page ends the iteration with its error.
+func (s *Scanner) Repositories(ctx context.Context, user string) iter.Seq2[*Repository, error] {
+	return iteratePages(ctx, func(ctx context.Context, page int) ([]*Repository, pageLinks, error) {
+		return s.getRepositoriesPerPage(ctx, user, page)
+	})
+}
+
+// Releases iterates over the releases of the repository newest first, see Repositories.
+func (s *Scanner) Releases(ctx context.Context, owner, repository string) iter.Seq2[*Release, error] {
+	return iteratePages(ctx, func(ctx context.Context, page int) ([]*Release, pageLinks, error) {
+		return s.getReleasesPerPage(ctx, owner, repository, page)
+	})
+}
+
+// iteratePages iterates over the items of a page numbered list endpoint, fetching the next page once the items of
+// the previous one are consumed.
+func iteratePages[T any](ctx context.Context, fetch func(ctx context.Context, page int) ([]T, pageLinks, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for page := 1; ; {
+			items, links, err := fetch(ctx, page)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if links.next <= page {
+				return
+			}
+			page = links.next
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRepositoriesIterator(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		if page == "3" {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"message": "server error"}`))
+			return
+		}
+		next := map[string]string{"1": "2", "2": "3"}[page]
+		w.Header().Set("Link", fmt.Sprintf(`<%s/users/test/repos?page=%s>; rel="next"`, server.URL, next))
+		fmt.Fprintf(w, `[{"full_name": "test/%s-a", "name": "%s-a"}, {"full_name": "test/%s-b", "name": "%s-b"}]`, page, page, page, page)
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL}
+	for repository, err := range scanner.Repositories(context.Background(), "test") {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if repository.Name == "1-b" {
+			break
+		}
+	}
+	if requests := scanner.Requests(); requests != 1 {
+		t.Fatalf("invalid requests count of the stopped iteration, expected 1, got %d", requests)
+	}
+
+	var names []string
+	var err error
+	for repository, repositoryErr := range scanner.Repositories(context.Background(), "test") {
+		if repositoryErr != nil {
+			err = repositoryErr
+			break
+		}
+		names = append(names, repository.Name)
+	}
+	if !equal(names, []string{"1-a", "1-b", "2-a", "2-b"}) {
+		t.Fatalf("invalid repositories, expected [1-a 1-b 2-a 2-b], got %v", names)
+	}
+	if err == nil || !strings.Contains(err.Error(), "server error") {
+		t.Fatalf("invalid error of the failed page, expected 'server error', got %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// LanguageTotal is the size of the code in the language.
+type LanguageTotal struct {
+	Language string  `json:"language"`
+	Bytes    int64   `json:"bytes"`
+	Percent  float64 `json:"percent"`
+}
+
+// AccountLanguages is the language breakdown of all scanned repositories of the account.
+type AccountLanguages struct {
+	Account   string           `json:"account"`
+	Languages []*LanguageTotal `json:"languages"`
+}
+
+// GetLanguages returns bytes of code per language of the repository.
+func (s *Scanner) GetLanguages(user, repository string) (map[string]int64, error) {
+	return s.getLanguages(context.Background(), user, repository)
+}
+
+func (s *Scanner) getLanguages(ctx context.Context, user, repository string) (map[string]int64, error) {
+	if err := s.checkUser(user); err != nil {
+		return nil, err
+	}
+	if err := s.checkRepository(repository); err != nil {
+		return nil, err
+	}
+	ctx, span := s.getTracer().Start(ctx, "GetLanguages", StringAttribute("account", user), StringAttribute("repository", repository))
+	defer span.End()
+
+	response, err := s.get(ctx, span, fmt.Sprintf("%s/repos/%s/%s/languages", s.BaseUrl, user, repository))
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil, newNotFoundError("repository %s/%s does not exist", user, repository)
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not get languages of the repository %s/%s: %w", user, repository, s.newApiError(response))
+	}
+
+	var languages map[string]int64
+	if err := json.NewDecoder(response.Body).Decode(&languages); err != nil {
+		return nil, err
+	}
+
+	return languages, nil
+}
+
+// LanguagesByAccount sums the scanned languages of the items per repository owner. Accounts are ordered by name,
+// languages of an account are ordered by size, the largest first.
+func LanguagesByAccount(items []*ResultItem) []*AccountLanguages {
+	totals := make(map[string]map[string]int64)
+	for _, item := range items {
+		if len(item.Languages) == 0 {
+			continue
+		}
+		account, _, _ := strings.Cut(item.Repository.FullName, "/")
+		if totals[account] == nil {
+			totals[account] = make(map[string]int64)
+		}
+		for language, bytes := range item.Languages {
+			totals[account][language] += bytes
+		}
+	}
+
+	accounts := make([]*AccountLanguages, 0, len(totals))
+	for account, languages := range totals {
+		accountLanguages := &AccountLanguages{Account: account}
+		var sum int64
+		for language, bytes := range languages {
+			accountLanguages.Languages = append(accountLanguages.Languages, &LanguageTotal{Language: language, Bytes: bytes})
+			sum += bytes
+		}
+		for _, total := range accountLanguages.Languages {
+			if sum > 0 {
+				total.Percent = float64(total.Bytes) * 100 / float64(sum)
+			}
+		}
+		sort.Slice(accountLanguages.Languages, func(i, j int) bool {
+			a, b := accountLanguages.Languages[i], accountLanguages.Languages[j]
+			if a.Bytes != b.Bytes {
+				return a.Bytes > b.Bytes
+			}
+			return a.Language < b.Language
+		})
+		accounts = append(accounts, accountLanguages)
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].Account < accounts[j].Account
+	})
+
+	return accounts
+}
@@ -0,0 +1,49 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetLanguages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/repos/test/test/languages" {
+			w.Write([]byte(`{"Go": 12000, "Shell": 300}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL}
+	languages, err := scanner.GetLanguages("test", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if languages["Go"] != 12000 || languages["Shell"] != 300 {
+		t.Fatalf("invalid languages, expected map[Go:12000 Shell:300], got %v", languages)
+	}
+
+	if _, err := scanner.GetLanguages("test", "missing"); err == nil {
+		t.Fatalf("languages of a missing repository are expected to fail")
+	}
+}
+
+func TestLanguagesByAccount(t *testing.T) {
+	items := []*ResultItem{
+		{Repository: &Repository{FullName: "b/one"}, Languages: map[string]int64{"Go": 100}},
+		{Repository: &Repository{FullName: "a/one"}, Languages: map[string]int64{"Go": 100, "Python": 50}},
+		{Repository: &Repository{FullName: "a/two"}, Languages: map[string]int64{"Python": 250}},
+		{Repository: &Repository{FullName: "c/one"}},
+	}
+
+	accounts := LanguagesByAccount(items)
+	if len(accounts) != 2 || accounts[0].Account != "a" || accounts[1].Account != "b" {
+		t.Fatalf("invalid accounts, expected [a b], got %v", accounts)
+	}
+	languages := accounts[0].Languages
+	if languages[0].Language != "Python" || languages[0].Bytes != 300 || languages[0].Percent != 75 {
+		t.Fatalf("invalid top language, expected Python with 300 bytes (75%%), got %s with %d bytes (%v%%)", languages[0].Language, languages[0].Bytes, languages[0].Percent)
+	}
+}
@@ -0,0 +1,113 @@
+package scanner
+
+import (
+	"sort"
+	"time"
+)
+
+// DownloadLeaderboardSize is the count of releases and assets of the download leaderboards of snapshots and reports.
+const DownloadLeaderboardSize = 10
+
+// ReleaseDownloads is the download count of all assets of a release.
+type ReleaseDownloads struct {
+	Repository  string     `json:"repository"`
+	Release     string     `json:"release"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	Downloads   int        `json:"downloads"`
+}
+
+// AssetDownloads is the download count of a release asset.
+type AssetDownloads struct {
+	Repository string `json:"repository"`
+	Release    string `json:"release"`
+	Asset      string `json:"asset"`
+	// Platform is the OS/arch target detected in the asset name, empty if none is.
+	Platform  string `json:"platform,omitempty"`
+	Downloads int    `json:"downloads"`
+}
+
+// PlatformDownloads is the download count of the assets built for a platform.
+type PlatformDownloads struct {
+	// Platform is the OS/arch target of the assets, empty for the assets without a detected one, e.g. checksums.
+	Platform  string `json:"platform"`
+	Assets    int    `json:"assets"`
+	Downloads int    `json:"downloads"`
+}
+
+// DownloadLeaderboard is the most downloaded releases and assets of the scanned repositories, e.g. to decide which
+// platforms and builds are worth shipping.
+type DownloadLeaderboard struct {
+	Downloads int                 `json:"downloads"`
+	Releases  []*ReleaseDownloads `json:"releases"`
+	Assets    []*AssetDownloads   `json:"assets"`
+	// Platforms are the download counts of all platforms, not only of the top assets.
+	Platforms []*PlatformDownloads `json:"platforms"`
+}
+
+// NewDownloadLeaderboard returns the size most downloaded releases and assets of the items, the most downloaded
+// first, from the asset download counts. Releases and assets never downloaded are left out. It returns nil if
+// nothing was downloaded, e.g. if the repositories have no assets.
+func NewDownloadLeaderboard(items []*ResultItem, size int) *DownloadLeaderboard {
+	leaderboard := &DownloadLeaderboard{}
+	platforms := make(map[string]*PlatformDownloads)
+	for _, item := range items {
+		for _, release := range item.Releases {
+			releaseDownloads := &ReleaseDownloads{Repository: item.Repository.FullName, Release: release.Version(), PublishedAt: release.PublishedAt}
+			for _, asset := range release.Assets {
+				platform := asset.Platform()
+				name := ""
+				if platform.OS != "" {
+					name = platform.String()
+				}
+				total := platforms[name]
+				if total == nil {
+					total = &PlatformDownloads{Platform: name}
+					platforms[name] = total
+				}
+				total.Assets++
+				total.Downloads += asset.DownloadCount
+				releaseDownloads.Downloads += asset.DownloadCount
+				if asset.DownloadCount > 0 {
+					leaderboard.Assets = append(leaderboard.Assets, &AssetDownloads{
+						Repository: item.Repository.FullName,
+						Release:    release.Version(),
+						Asset:      asset.Name,
+						Platform:   name,
+						Downloads:  asset.DownloadCount,
+					})
+				}
+			}
+			if releaseDownloads.Downloads > 0 {
+				leaderboard.Releases = append(leaderboard.Releases, releaseDownloads)
+				leaderboard.Downloads += releaseDownloads.Downloads
+			}
+		}
+	}
+	if leaderboard.Downloads == 0 {
+		return nil
+	}
+
+	sort.SliceStable(leaderboard.Releases, func(i, j int) bool {
+		return leaderboard.Releases[i].Downloads > leaderboard.Releases[j].Downloads
+	})
+	sort.SliceStable(leaderboard.Assets, func(i, j int) bool {
+		return leaderboard.Assets[i].Downloads > leaderboard.Assets[j].Downloads
+	})
+	leaderboard.Releases = leaderboard.Releases[:min(size, len(leaderboard.Releases))]
+	leaderboard.Assets = leaderboard.Assets[:min(size, len(leaderboard.Assets))]
+
+	for _, total := range platforms {
+		if total.Downloads > 0 {
+			leaderboard.Platforms = append(leaderboard.Platforms, total)
+		}
+	}
+	sort.Slice(leaderboard.Platforms, func(i, j int) bool {
+		a, b := leaderboard.Platforms[i], leaderboard.Platforms[j]
+		if a.Downloads != b.Downloads {
+			return a.Downloads > b.Downloads
+		}
+		return a.Platform < b.Platform
+	})
+
+	return leaderboard
+}
@@ -0,0 +1,48 @@
+package scanner
+
+import "testing"
+
+func TestNewDownloadLeaderboard(t *testing.T) {
+	items := []*ResultItem{
+		{Repository: &Repository{FullName: "a/cli"}, Releases: []*Release{
+			{TagName: "v2.0.0", Assets: []*Asset{
+				{Name: "cli-linux-amd64.tar.gz", DownloadCount: 50},
+				{Name: "cli-darwin-arm64.tar.gz", DownloadCount: 20},
+				{Name: "checksums.txt", DownloadCount: 5},
+			}},
+			{TagName: "v1.0.0", Assets: []*Asset{
+				{Name: "cli-linux-amd64.tar.gz", DownloadCount: 100},
+				{Name: "cli-windows-386.zip"},
+			}},
+		}},
+		{Repository: &Repository{FullName: "a/lib"}, Releases: []*Release{
+			{TagName: "v0.1.0", Assets: []*Asset{{Name: "lib-linux-arm64.so", DownloadCount: 60}}},
+		}},
+	}
+
+	leaderboard := NewDownloadLeaderboard(items, 2)
+	if leaderboard.Downloads != 235 {
+		t.Fatalf("invalid downloads, expected 235, got %d", leaderboard.Downloads)
+	}
+	if len(leaderboard.Releases) != 2 || leaderboard.Releases[0].Release != "v1.0.0" || leaderboard.Releases[1].Release != "v2.0.0" || leaderboard.Releases[1].Downloads != 75 {
+		t.Fatalf("invalid top releases, expected v1.0.0 and v2.0.0 with 75 downloads, got %v and %v", leaderboard.Releases[0].Release, leaderboard.Releases[1].Release)
+	}
+	if len(leaderboard.Assets) != 2 || leaderboard.Assets[0].Downloads != 100 || leaderboard.Assets[1].Asset != "lib-linux-arm64.so" || leaderboard.Assets[1].Platform != "linux/arm64" {
+		t.Fatalf("invalid top assets, expected the v1.0.0 linux/amd64 asset and lib-linux-arm64.so, got %+v", leaderboard.Assets)
+	}
+	var platforms []string
+	for _, platform := range leaderboard.Platforms {
+		platforms = append(platforms, platform.Platform)
+	}
+	// the never downloaded windows asset is left out, checksums have no platform
+	if expected := []string{"linux/amd64", "linux/arm64", "darwin/arm64", ""}; !equal(platforms, expected) {
+		t.Fatalf("invalid platforms, expected %q, got %q", expected, platforms)
+	}
+	if leaderboard.Platforms[0].Assets != 2 || leaderboard.Platforms[0].Downloads != 150 {
+		t.Fatalf("invalid linux/amd64 downloads, expected 150 of 2 assets, got %+v", leaderboard.Platforms[0])
+	}
+
+	if leaderboard := NewDownloadLeaderboard([]*ResultItem{{Repository: &Repository{FullName: "a/docs"}}}, 2); leaderboard != nil {
+		t.Fatalf("invalid leaderboard without downloads, expected nil, got %+v", leaderboard)
+	}
+}
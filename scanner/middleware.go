@@ -0,0 +1,74 @@
+package scanner
+
+import "net/http"
+
+// RequestHandler performs an API request of the scanner.
+type RequestHandler func(request *http.Request) (*http.Response, error)
+
+// RequestMiddleware wraps the handler performing the API requests. It could change the requests before passing
+// them to the next handler, observe or replace the responses, or respond without calling the next handler at all,
+// e.g. from a cache.
+type RequestMiddleware func(next RequestHandler) RequestHandler
+
+// Use adds middleware wrapping the API requests and asset downloads of the scanner. The first added middleware
+// is the outermost one, it sees the requests first and the responses last. Use must not be called while the
+// scanner is scanning.
+func (s *Scanner) Use(middleware ...RequestMiddleware) {
+	s.middleware = append(s.middleware, middleware...)
+}
+
+// WithHeader returns middleware setting the header on every request.
+func WithHeader(name, value string) RequestMiddleware {
+	return func(next RequestHandler) RequestHandler {
+		return func(request *http.Request) (*http.Response, error) {
+			request.Header.Set(name, value)
+			return next(request)
+		}
+	}
+}
+
+// roundTrip performs the request with the client of the scanner wrapped by the middleware.
+func (s *Scanner) roundTrip(request *http.Request) (*http.Response, error) {
+	handler := RequestHandler(func(request *http.Request) (*http.Response, error) {
+		s.requests.Add(1)
+		return s.getClient().Do(request)
+	})
+	for i := len(s.middleware) - 1; i >= 0; i-- {
+		handler = s.middleware[i](handler)
+	}
+
+	return handler(request)
+}
+
+// RequestHandler returns the handler performing the requests of other providers the way the scanner performs its
+// own API requests: through the middleware, counted in Requests, and paused and retried on the secondary rate
+// limit. The bodies of retried requests are read again with GetBody.
+func (s *Scanner) RequestHandler() RequestHandler {
+	return func(request *http.Request) (*http.Response, error) {
+		for attempt := 0; ; attempt++ {
+			if err := s.pause.wait(request.Context()); err != nil {
+				return nil, err
+			}
+			if attempt > 0 && request.GetBody != nil {
+				body, err := request.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				request = request.Clone(request.Context())
+				request.Body = body
+			}
+			response, err := s.roundTrip(request)
+			if err != nil {
+				return nil, wrapTransportError(err)
+			}
+			s.updateRateLimit(response.Header)
+			delay, limited := secondaryRateLimit(response)
+			if !limited || attempt >= secondaryRateLimitRetries {
+				return response, nil
+			}
+			response.Body.Close()
+			s.pause.extend(delay)
+			s.getLogger().Warn("secondary rate limit exceeded, requests are paused", "url", request.URL.Redacted(), "delay", delay, "attempt", attempt+1)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package scanner
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestUse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Test") != "test" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`[{"id": 1, "name": "test", "full_name": "test/test"}]`))
+	}))
+	defer server.Close()
+
+	var calls []string
+	observe := func(name string) RequestMiddleware {
+		return func(next RequestHandler) RequestHandler {
+			return func(request *http.Request) (*http.Response, error) {
+				calls = append(calls, name+" request")
+				response, err := next(request)
+				if err == nil {
+					calls = append(calls, name+" response "+response.Status)
+				}
+				return response, err
+			}
+		}
+	}
+
+	scanner := Scanner{BaseUrl: server.URL}
+	scanner.Use(observe("first"), WithHeader("X-Test", "test"))
+	scanner.Use(observe("second"))
+	repositories, err := scanner.GetRepositoriesPerPage("test", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repositories) != 1 {
+		t.Fatalf("invalid repositories count, expected 1, got %d", len(repositories))
+	}
+	expected := []string{"first request", "second request", "second response 200 OK", "first response 200 OK"}
+	if !slices.Equal(calls, expected) {
+		t.Fatalf("invalid middleware calls, expected %v, got %v", expected, calls)
+	}
+}
+
+func TestUseRespondsWithoutRequest(t *testing.T) {
+	scanner := Scanner{BaseUrl: "http://127.0.0.1:0"}
+	scanner.Use(func(next RequestHandler) RequestHandler {
+		return func(request *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Status:     "200 OK",
+				Header:     make(http.Header),
+				Body:       io.NopCloser(strings.NewReader(`[{"id": 2, "name": "cached", "full_name": "test/cached"}]`)),
+				Request:    request,
+			}, nil
+		}
+	})
+
+	repositories, err := scanner.GetRepositoriesPerPage("test", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repositories) != 1 || repositories[0].FullName != "test/cached" {
+		t.Fatalf("invalid repositories, expected test/cached, got %v", repositories)
+	}
+}
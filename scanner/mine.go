@@ -0,0 +1,63 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// authenticatedUser stands for the token owner in logs and errors of ScanMine.
+const authenticatedUser = "@me"
+
+// ScanMine scans releases of all repositories the token owner can access: owned ones, repositories they
+// collaborate on and repositories of their organizations, including private ones.
+func (s *Scanner) ScanMine() ([]*ResultItem, error) {
+	return s.scan(context.Background(), authenticatedUser, "ScanMine", func(ctx context.Context, user string) ([]*Repository, error) {
+		return s.getAllAccessibleRepositories(ctx)
+	})
+}
+
+func (s *Scanner) GetAllAccessibleRepositories() ([]*Repository, error) {
+	return s.getAllAccessibleRepositories(context.Background())
+}
+
+func (s *Scanner) getAllAccessibleRepositories(ctx context.Context) ([]*Repository, error) {
+	if !s.authenticated() {
+		return nil, errors.New("token is required to list repositories of the authenticated user")
+	}
+
+	return paginate(ctx, func(ctx context.Context, page int) ([]*Repository, pageLinks, error) {
+		repositoriesChunk, links, err := s.getAccessibleRepositoriesPerPage(ctx, page)
+		if err == nil {
+			s.getLogger().Debug("accessible repositories page fetched", "page", page, "count", len(repositoriesChunk))
+		}
+		return repositoriesChunk, links, err
+	})
+}
+
+func (s *Scanner) getAccessibleRepositoriesPerPage(ctx context.Context, page int) ([]*Repository, pageLinks, error) {
+	if err := s.checkPage(page); err != nil {
+		return nil, pageLinks{}, err
+	}
+	ctx, span := s.getTracer().Start(ctx, "GetAccessibleRepositoriesPerPage", IntAttribute("page", page))
+	defer span.End()
+
+	response, err := s.get(ctx, span, fmt.Sprintf("%s/user/repos?affiliation=owner,collaborator,organization_member&per_page=%d&page=%d", s.BaseUrl, s.getPerPage(), page))
+	if err != nil {
+		return nil, pageLinks{}, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, pageLinks{}, fmt.Errorf("could not get repositories of the authenticated user: %w", s.newApiError(response))
+	}
+
+	var repositories []*Repository
+	if err := json.NewDecoder(response.Body).Decode(&repositories); err != nil {
+		return nil, pageLinks{}, err
+	}
+
+	return repositories, parsePageLinks(response.Header), nil
+}
@@ -0,0 +1,57 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestScanMineSuccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message": "Requires authentication"}`))
+			return
+		}
+		if r.URL.Path == "/user/repos" {
+			if r.URL.Query().Get("affiliation") != "owner,collaborator,organization_member" {
+				t.Errorf("invalid affiliation: %s", r.URL.Query().Get("affiliation"))
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[
+				{"full_name": "test/private", "name": "private", "private": true},
+				{"full_name": "org/internal", "name": "internal", "private": true}
+				]`))
+		}
+		if r.URL.Path == "/repos/test/private/releases" || r.URL.Path == "/repos/org/internal/releases" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"name": "v1.0.0"}]`))
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{
+		BaseUrl: server.URL,
+		Token:   "secret",
+	}
+	items, err := scanner.ScanMine()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 2 {
+		t.Fatalf("invalid scanned repositories count, expected 2, got %d", len(items))
+	}
+	for _, item := range items {
+		if !item.Repository.Private || len(item.Releases) != 1 {
+			t.Fatalf("invalid scanned item for the repository %s", item.Repository.FullName)
+		}
+	}
+}
+
+func TestScanMineWithoutToken(t *testing.T) {
+	scanner := Scanner{BaseUrl: "http://localhost"}
+	if _, err := scanner.ScanMine(); err == nil {
+		t.Fatal("error is expected without a token")
+	}
+}
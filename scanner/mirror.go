@@ -0,0 +1,228 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// partialDownloadSuffix is the suffix of the partial files of the downloads, partialETagSuffix of the files
+// recording the etags of the contents they were started with.
+const (
+	partialDownloadSuffix = ".part"
+	partialETagSuffix     = ".part.etag"
+)
+
+var checksumsAssetNames = []string{"checksums.txt", "sha256sums", "sha256sums.txt"}
+
+// AssetDownload is the result of the download of an asset.
+type AssetDownload struct {
+	Asset *Asset
+	Path  string
+	// Err is set if the asset could not be downloaded, nothing is written to the path then.
+	Err error
+}
+
+// DownloadAssets downloads the assets of the release into the directory in parallel and returns the result of
+// each asset, the error joins the errors of the assets. Interrupted downloads are resumed from the partial files
+// left in the directory. Assets without digests are verified with sha256 sums of the release checksums file if it
+// is present.
+func (s *Scanner) DownloadAssets(ctx context.Context, release *Release, assets []*Asset, dir string) ([]AssetDownload, error) {
+	checksums, err := s.getReleaseChecksums(ctx, release)
+	if err != nil {
+		return nil, err
+	}
+
+	downloads := make([]AssetDownload, len(assets))
+	var wg sync.WaitGroup
+	for i, asset := range assets {
+		downloads[i] = AssetDownload{Asset: asset, Path: filepath.Join(dir, filepath.Base(asset.Name))}
+		if asset.Digest == "" && checksums[asset.Name] != "" {
+			assetCopy := *asset
+			assetCopy.Digest = "sha256:" + checksums[asset.Name]
+			asset = &assetCopy
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Downloads share the worker slots of the scanner with the scans.
+			if downloads[i].Err = s.workers.acquire(ctx); downloads[i].Err != nil {
+				return
+			}
+			defer s.workers.release()
+			downloads[i].Err = s.DownloadAssetToFile(ctx, asset, downloads[i].Path)
+		}()
+	}
+	wg.Wait()
+
+	errs := make([]error, len(downloads))
+	for i, download := range downloads {
+		errs[i] = download.Err
+	}
+
+	return downloads, errors.Join(errs...)
+}
+
+// DownloadAssetToFile downloads the asset into the file. The content is written to a partial file first,
+// which is resumed with a Range request if it exists, and renamed to the path after successful verification.
+// A partial file is only resumed if it could be verified: against the digest of the asset, or against the etag
+// of the content it was started with, which the server compares with If-Range. Otherwise, or if the verification
+// of a resumed file fails, the asset is downloaded from the start.
+func (s *Scanner) DownloadAssetToFile(ctx context.Context, asset *Asset, path string) error {
+	ctx, span := s.getTracer().Start(ctx, "DownloadAssetToFile", StringAttribute("asset", asset.Name))
+	defer span.End()
+
+	partPath := path + partialDownloadSuffix
+	resumed, err := s.downloadPart(ctx, span, asset, partPath, true)
+	if err != nil {
+		return err
+	}
+	err = verifyFile(partPath, asset.Digest)
+	if err != nil && resumed {
+		// The partial file may be left by another content of the asset, e.g. a re-uploaded one.
+		s.getLogger().Debug("resumed asset download is not verified", "asset", asset.Name, "error", err)
+		if _, err := s.downloadPart(ctx, span, asset, partPath, false); err != nil {
+			return err
+		}
+		err = verifyFile(partPath, asset.Digest)
+	}
+	if err != nil {
+		os.Remove(partPath)
+		os.Remove(path + partialETagSuffix)
+		return fmt.Errorf("could not verify the asset %s: %v", asset.Name, err)
+	}
+	if err := os.Rename(partPath, path); err != nil {
+		return err
+	}
+	os.Remove(path + partialETagSuffix)
+
+	return nil
+}
+
+// downloadPart writes the asset content to the partial file and reports whether it is resumed, i.e. the file
+// content is not downloaded from the start by this call.
+func (s *Scanner) downloadPart(ctx context.Context, span Span, asset *Asset, partPath string, resume bool) (bool, error) {
+	file, err := os.OpenFile(partPath, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	etagPath := strings.TrimSuffix(partPath, partialDownloadSuffix) + partialETagSuffix
+	var offset int64
+	var etag string
+	if resume {
+		if offset, err = file.Seek(0, io.SeekEnd); err != nil {
+			return false, err
+		}
+		if data, err := os.ReadFile(etagPath); err == nil {
+			etag = string(data)
+		}
+		if asset.Size > 0 && offset > asset.Size || asset.Digest == "" && etag == "" {
+			offset = 0
+		}
+	}
+	// A complete partial file is verified against the digest without a request.
+	if offset > 0 && offset == asset.Size && asset.Digest != "" {
+		return true, file.Close()
+	}
+
+	response, err := s.openAsset(ctx, span, asset, offset, etag)
+	if err != nil {
+		return false, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+		return true, file.Close()
+	}
+	if response.StatusCode != http.StatusPartialContent {
+		offset = 0
+		// Weak etags do not identify the bytes of the content, so they could not be used with If-Range.
+		etag = response.Header.Get("ETag")
+		if etag == "" || strings.HasPrefix(etag, "W/") {
+			if err := os.Remove(etagPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return false, err
+			}
+		} else if err := os.WriteFile(etagPath, []byte(etag), 0644); err != nil {
+			return false, err
+		}
+	}
+	if err := file.Truncate(offset); err != nil {
+		return false, err
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return false, err
+	}
+	if _, err := io.Copy(file, response.Body); err != nil {
+		return false, fmt.Errorf("could not download the asset %s: %v", asset.Name, err)
+	}
+
+	return offset > 0, file.Close()
+}
+
+// getReleaseChecksums downloads and parses the checksums file of the release if it has one.
+// Sums are keyed by asset names.
+func (s *Scanner) getReleaseChecksums(ctx context.Context, release *Release) (map[string]string, error) {
+	var checksumsAsset *Asset
+	for _, asset := range release.Assets {
+		name := strings.ToLower(asset.Name)
+		if slices.ContainsFunc(checksumsAssetNames, func(checksumsName string) bool {
+			return name == checksumsName || strings.HasSuffix(name, "_"+checksumsName)
+		}) {
+			checksumsAsset = asset
+			break
+		}
+	}
+	if checksumsAsset == nil {
+		return nil, nil
+	}
+
+	var buf bytes.Buffer
+	if err := s.DownloadAsset(ctx, checksumsAsset, &buf); err != nil {
+		return nil, err
+	}
+
+	return parseChecksums(&buf), nil
+}
+
+// parseChecksums parses sha256sum output: "<hex>  <name>" lines, where the name may be prefixed with "*" in binary mode.
+func parseChecksums(reader io.Reader) map[string]string {
+	checksums := map[string]string{}
+	lines := bufio.NewScanner(reader)
+	for lines.Scan() {
+		fields := strings.Fields(lines.Text())
+		if len(fields) != 2 || len(fields[0]) != 64 {
+			continue
+		}
+		checksums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+	}
+
+	return checksums
+}
+
+func verifyFile(path string, digest string) error {
+	verifier, err := newDigestVerifier(digest)
+	if err != nil || verifier == nil {
+		return err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if _, err := io.Copy(verifier, file); err != nil {
+		return err
+	}
+
+	return verifier.verify()
+}
@@ -0,0 +1,172 @@
+package scanner
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDownloadAssets(t *testing.T) {
+	contents := map[string]string{
+		"/tool_linux.tar.gz":  "linux build content",
+		"/tool_darwin.tar.gz": "darwin build content",
+	}
+	var checksums strings.Builder
+	for path, content := range contents {
+		sum := sha256.Sum256([]byte(content))
+		fmt.Fprintf(&checksums, "%s  %s\n", hex.EncodeToString(sum[:]), strings.TrimPrefix(path, "/"))
+	}
+	contents["/checksums.txt"] = checksums.String()
+
+	var mu sync.Mutex
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, ok := contents[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("Range") != "" {
+			mu.Lock()
+			ranges = append(ranges, r.URL.Path+" "+r.Header.Get("Range"))
+			mu.Unlock()
+		}
+		http.ServeContent(w, r, r.URL.Path, time.Time{}, strings.NewReader(content))
+	}))
+	defer server.Close()
+
+	release := &Release{TagName: "v1.0.0"}
+	for _, name := range []string{"tool_linux.tar.gz", "tool_darwin.tar.gz", "checksums.txt"} {
+		release.Assets = append(release.Assets, &Asset{
+			Name:               name,
+			Size:               int64(len(contents["/"+name])),
+			BrowserDownloadURL: server.URL + "/" + name,
+		})
+	}
+
+	dir := t.TempDir()
+	// A partial download left by an interrupted run must be resumed.
+	if err := os.WriteFile(filepath.Join(dir, "tool_linux.tar.gz"+partialDownloadSuffix), []byte("linux "), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	scanner := Scanner{}
+	downloads, err := scanner.DownloadAssets(context.Background(), release, release.Assets[:2], dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(downloads) != 2 {
+		t.Fatalf("invalid downloads count, expected 2, got %d", len(downloads))
+	}
+	for _, download := range downloads {
+		if download.Err != nil {
+			t.Fatal(download.Err)
+		}
+		content, err := os.ReadFile(download.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(content) != contents["/"+download.Asset.Name] {
+			t.Fatalf("invalid content of %s: %s", download.Path, content)
+		}
+	}
+	if len(ranges) != 1 || ranges[0] != "/tool_linux.tar.gz bytes=6-" {
+		t.Fatalf("partial download is not resumed: %v", ranges)
+	}
+
+	contents["/tool_darwin.tar.gz"] = "tampered content"
+	os.Remove(filepath.Join(dir, "tool_darwin.tar.gz"))
+	downloads, err = scanner.DownloadAssets(context.Background(), release, release.Assets[:2], dir)
+	if err == nil {
+		t.Fatal("checksum mismatch error is expected")
+	}
+	// The existing file of the successful download must not hide the failed one.
+	if downloads[0].Err != nil || downloads[1].Err == nil {
+		t.Fatalf("invalid download errors: %v, %v", downloads[0].Err, downloads[1].Err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "tool_darwin.tar.gz")); err == nil {
+		t.Fatal("unverified asset must not be written")
+	}
+}
+
+func TestDownloadAssetToFileResume(t *testing.T) {
+	const content = "release build content"
+	tests := []struct {
+		name string
+		// part is the content of the partial file left by a previous run, etag the etag recorded with it.
+		part   string
+		etag   string
+		digest bool
+		// ranges are the Range headers of the requests, an empty one for a request of the whole content.
+		ranges []string
+	}{
+		{"no partial file", "", "", false, []string{""}},
+		{"digest", "release ", "", true, []string{"bytes=8-"}},
+		{"complete with digest", content, "", true, nil},
+		{"stale with digest", "stale build content..", "", true, []string{""}},
+		{"stale resumed with digest", "stale ", "", true, []string{"bytes=6-", ""}},
+		{"etag", "release ", `"v1"`, false, []string{"bytes=8-"}},
+		{"complete with etag", content, `"v1"`, false, []string{"bytes=21-"}},
+		{"stale with etag", "stale build content..", `"v0"`, false, []string{"bytes=21-"}},
+		{"no digest or etag", "stale build content..", "", false, []string{""}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var ranges []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ranges = append(ranges, r.Header.Get("Range"))
+				w.Header().Set("ETag", `"v1"`)
+				http.ServeContent(w, r, r.URL.Path, time.Time{}, strings.NewReader(content))
+			}))
+			defer server.Close()
+
+			asset := &Asset{Name: "tool.tar.gz", Size: int64(len(content)), BrowserDownloadURL: server.URL + "/tool.tar.gz"}
+			if test.digest {
+				sum := sha256.Sum256([]byte(content))
+				asset.Digest = "sha256:" + hex.EncodeToString(sum[:])
+			}
+			path := filepath.Join(t.TempDir(), asset.Name)
+			if test.part != "" {
+				if err := os.WriteFile(path+partialDownloadSuffix, []byte(test.part), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if test.etag != "" {
+				if err := os.WriteFile(path+partialETagSuffix, []byte(test.etag), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			scanner := Scanner{}
+			if err := scanner.DownloadAssetToFile(context.Background(), asset, path); err != nil {
+				t.Fatal(err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != content {
+				t.Fatalf("invalid content: %s", data)
+			}
+			if !slices.Equal(ranges, test.ranges) {
+				t.Fatalf("invalid requested ranges, expected %q, got %q", test.ranges, ranges)
+			}
+			for _, partPath := range []string{path + partialDownloadSuffix, path + partialETagSuffix} {
+				if _, err := os.Stat(partPath); err == nil {
+					t.Fatalf("partial file %s is not removed", partPath)
+				}
+			}
+		})
+	}
+}
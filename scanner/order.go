@@ -0,0 +1,128 @@
+package scanner
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SortResults puts the items in the documented output order, so successive outputs of the same data are
+// identical regardless of the order repositories were scanned in:
+//   - repositories are ordered by full name;
+//   - releases are ordered newest first by publication date, drafts go first and releases without a date go last,
+//     ties are ordered by tag, or by version greatest first if a version scheme is assigned to the repository;
+//   - assets are ordered by name.
+func SortResults(items []*ResultItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Repository.FullName < items[j].Repository.FullName
+	})
+	SortReleases(items)
+}
+
+// SortReleases puts the releases and assets of the items in the SortResults order and keeps the order of the
+// items, so outputs honor the order the items were sorted in, e.g. by SortResultsBy.
+func SortReleases(items []*ResultItem) {
+	for _, item := range items {
+		sortReleases(item.Releases, assignedVersionScheme(item))
+	}
+}
+
+// sortReleases puts the releases in the SortResults order, ties are ordered by the versions of the scheme if it is
+// not nil.
+func sortReleases(releases []*Release, scheme VersionScheme) {
+	sort.SliceStable(releases, func(i, j int) bool {
+		if releases[i].Draft != releases[j].Draft {
+			return releases[i].Draft
+		}
+		a, b := releases[i].PublishedAt, releases[j].PublishedAt
+		switch {
+		case a != nil && b == nil:
+			return true
+		case a == nil && b != nil:
+			return false
+		case a != nil && !a.Equal(*b):
+			return a.After(*b)
+		}
+
+		if scheme != nil {
+			if result, err := scheme.Compare(releases[i].Version(), releases[j].Version()); err == nil && result != 0 {
+				return result > 0
+			}
+		}
+
+		return releases[i].TagName < releases[j].TagName
+	})
+	for _, release := range releases {
+		sort.SliceStable(release.Assets, func(i, j int) bool {
+			return release.Assets[i].Name < release.Assets[j].Name
+		})
+	}
+}
+
+// Comparator orders scanned items, it returns a negative number if a goes before b, a positive one if after and
+// 0 if their order is undefined.
+type Comparator func(a, b *ResultItem) int
+
+// Comparators are the sort keys of SortResultsBy by their names. Programs embedding the scanner could add theirs.
+var Comparators = map[string]Comparator{
+	"name": func(a, b *ResultItem) int {
+		return strings.Compare(a.Repository.FullName, b.Repository.FullName)
+	},
+	"stars": func(a, b *ResultItem) int {
+		return cmp.Compare(a.Repository.Stars, b.Repository.Stars)
+	},
+	"last-release": func(a, b *ResultItem) int {
+		return lastPublished(a).Compare(lastPublished(b))
+	},
+	"release-count": func(a, b *ResultItem) int {
+		return cmp.Compare(len(a.Releases), len(b.Releases))
+	},
+}
+
+// lastPublished returns the publication time of the latest published release, zero if there is none.
+func lastPublished(item *ResultItem) time.Time {
+	var last time.Time
+	for _, release := range item.Releases {
+		if !release.Draft && release.PublishedAt != nil && release.PublishedAt.After(last) {
+			last = *release.PublishedAt
+		}
+	}
+
+	return last
+}
+
+// ParseSort returns the comparator of the "key" or "key:asc|desc" sort spec, e.g. "stars:desc". Keys are sorted
+// ascending by default.
+func ParseSort(spec string) (Comparator, error) {
+	key, direction, _ := strings.Cut(spec, ":")
+	comparator, ok := Comparators[key]
+	if !ok {
+		keys := make([]string, 0, len(Comparators))
+		for name := range Comparators {
+			keys = append(keys, name)
+		}
+		sort.Strings(keys)
+		return nil, fmt.Errorf("unknown sort key %s, expected one of %s", key, strings.Join(keys, ", "))
+	}
+
+	switch direction {
+	case "", "asc":
+		return comparator, nil
+	case "desc":
+		return func(a, b *ResultItem) int {
+			return comparator(b, a)
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown sort direction %s, expected asc or desc", direction)
+}
+
+// SortResultsBy puts the items in the order of the comparator, ties are ordered by full name. Releases and assets
+// are ordered like by SortResults.
+func SortResultsBy(items []*ResultItem, comparator Comparator) {
+	SortResults(items)
+	slices.SortStableFunc(items, comparator)
+}
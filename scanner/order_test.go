@@ -0,0 +1,130 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSortResults(t *testing.T) {
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	items := []*ResultItem{
+		{Repository: &Repository{FullName: "test/b"}},
+		{
+			Repository: &Repository{FullName: "test/a"},
+			Releases: []*Release{
+				{TagName: "v1.0.0", PublishedAt: &older},
+				{TagName: "v1.1.1", PublishedAt: &newer},
+				{TagName: "v1.1.0", PublishedAt: &newer, Assets: []*Asset{{Name: "b.tar.gz"}, {Name: "a.tar.gz"}}},
+				{TagName: "v2.0.0", Draft: true},
+				{TagName: "v0.1.0"},
+			},
+		},
+	}
+	SortResults(items)
+
+	if items[0].Repository.FullName != "test/a" {
+		t.Fatalf("invalid first repository, expected test/a, got %s", items[0].Repository.FullName)
+	}
+	var tags []string
+	for _, release := range items[0].Releases {
+		tags = append(tags, release.TagName)
+	}
+	if !equal(tags, []string{"v2.0.0", "v1.1.0", "v1.1.1", "v1.0.0", "v0.1.0"}) {
+		t.Fatalf("invalid releases order, expected [v2.0.0 v1.1.0 v1.1.1 v1.0.0 v0.1.0], got %v", tags)
+	}
+	if assets := items[0].Releases[1].Assets; assets[0].Name != "a.tar.gz" {
+		t.Fatalf("invalid first asset, expected a.tar.gz, got %s", assets[0].Name)
+	}
+}
+
+func TestSortResultsVersionScheme(t *testing.T) {
+	items := []*ResultItem{
+		{Repository: &Repository{FullName: "test/calver"}, Releases: []*Release{{TagName: "2023.12"}, {TagName: "2024.2"}}, VersionScheme: "calver"},
+		{Repository: &Repository{FullName: "test/default"}, Releases: []*Release{{TagName: "2023.12"}, {TagName: "2024.2"}}},
+	}
+	SortResults(items)
+
+	if tag := items[0].Releases[0].TagName; tag != "2024.2" {
+		t.Fatalf("invalid first release of the calver repository, expected 2024.2, got %s", tag)
+	}
+	if tag := items[1].Releases[0].TagName; tag != "2023.12" {
+		t.Fatalf("invalid first release without a version scheme, expected 2023.12, got %s", tag)
+	}
+}
+
+func TestStreamRepositoriesOrder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/test/repos":
+			w.Write([]byte(`[{"full_name": "test/c", "name": "c"}, {"full_name": "test/a", "name": "a"}, {"full_name": "test/b", "name": "b"}]`))
+		case "/repos/test/a/releases":
+			// The first repository completes last, the rest of the results are held back until it does.
+			time.Sleep(50 * time.Millisecond)
+			w.Write([]byte(`[]`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL, PerPage: 100}
+	var names []string
+	err := scanner.StreamRepositories("test", func(item *ResultItem) error {
+		names = append(names, item.Repository.FullName)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equal(names, []string{"test/a", "test/b", "test/c"}) {
+		t.Fatalf("invalid streamed repositories order, expected [test/a test/b test/c], got %v", names)
+	}
+}
+
+func TestSortResultsBy(t *testing.T) {
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	items := []*ResultItem{
+		{Repository: &Repository{FullName: "test/c", Stars: 5}, Releases: []*Release{{TagName: "v1", PublishedAt: &older}}},
+		{Repository: &Repository{FullName: "test/b", Stars: 10}},
+		{Repository: &Repository{FullName: "test/a", Stars: 5}, Releases: []*Release{{TagName: "v1", PublishedAt: &older}, {TagName: "v2", PublishedAt: &newer}}},
+	}
+	cases := []struct {
+		spec     string
+		expected []string
+	}{
+		{"name", []string{"test/a", "test/b", "test/c"}},
+		{"name:desc", []string{"test/c", "test/b", "test/a"}},
+		{"stars:desc", []string{"test/b", "test/a", "test/c"}},
+		{"stars", []string{"test/a", "test/c", "test/b"}},
+		{"last-release:desc", []string{"test/a", "test/c", "test/b"}},
+		{"release-count:asc", []string{"test/b", "test/c", "test/a"}},
+	}
+	for _, c := range cases {
+		comparator, err := ParseSort(c.spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		SortResultsBy(items, comparator)
+		var names []string
+		for _, item := range items {
+			names = append(names, item.Repository.FullName)
+		}
+		if !slices.Equal(names, c.expected) {
+			t.Fatalf("invalid order of %s, expected %v, got %v", c.spec, c.expected, names)
+		}
+	}
+	if items[2].Releases[0].TagName != "v2" {
+		t.Fatalf("invalid first release, expected v2, got %s", items[2].Releases[0].TagName)
+	}
+
+	for _, spec := range []string{"size", "stars:up"} {
+		if _, err := ParseSort(spec); err == nil {
+			t.Fatalf("invalid error of the sort %s, expected error, got nil", spec)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Package otel adapts OpenTelemetry tracer providers to the tracing of the scanner, so scans, page fetches and
+// workers are exported as OpenTelemetry spans:
+//
+//	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter))
+//	defer provider.Shutdown(ctx)
+//	s := &scanner.Scanner{TracerProvider: otel.NewTracerProvider(provider)}
+package otel
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"githubscanner/scanner"
+)
+
+// NewTracerProvider returns the scanner tracer provider creating the spans with the OpenTelemetry provider.
+func NewTracerProvider(provider trace.TracerProvider) scanner.TracerProvider {
+	return tracerProvider{provider: provider}
+}
+
+type tracerProvider struct {
+	provider trace.TracerProvider
+}
+
+func (p tracerProvider) Tracer(name string) scanner.Tracer {
+	return tracer{tracer: p.provider.Tracer(name)}
+}
+
+type tracer struct {
+	tracer trace.Tracer
+}
+
+func (t tracer) Start(ctx context.Context, spanName string, attributes ...scanner.Attribute) (context.Context, scanner.Span) {
+	ctx, otelSpan := t.tracer.Start(ctx, spanName, trace.WithAttributes(convertAttributes(attributes)...))
+
+	return ctx, span{span: otelSpan}
+}
+
+type span struct {
+	span trace.Span
+}
+
+func (s span) SetAttributes(attributes ...scanner.Attribute) {
+	s.span.SetAttributes(convertAttributes(attributes)...)
+}
+
+// RecordError records the error as an exception event and marks the span failed.
+func (s span) RecordError(err error) {
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+func (s span) End() {
+	s.span.End()
+}
+
+func convertAttributes(attributes []scanner.Attribute) []attribute.KeyValue {
+	converted := make([]attribute.KeyValue, 0, len(attributes))
+	for _, a := range attributes {
+		switch value := a.Value.(type) {
+		case string:
+			converted = append(converted, attribute.String(a.Key, value))
+		case int:
+			converted = append(converted, attribute.Int(a.Key, value))
+		case int64:
+			converted = append(converted, attribute.Int64(a.Key, value))
+		case bool:
+			converted = append(converted, attribute.Bool(a.Key, value))
+		case float64:
+			converted = append(converted, attribute.Float64(a.Key, value))
+		default:
+			converted = append(converted, attribute.String(a.Key, fmt.Sprint(value)))
+		}
+	}
+
+	return converted
+}
@@ -0,0 +1,69 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"githubscanner/scanner"
+)
+
+func TestTracerProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/test/repos":
+			w.Write([]byte(`[{"full_name": "test/a", "name": "a"}]`))
+		default:
+			w.Write([]byte(`[{"tag_name": "v1.0.0"}]`))
+		}
+	}))
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	s := &scanner.Scanner{BaseUrl: server.URL, TracerProvider: NewTracerProvider(provider)}
+	if _, err := s.ScanRepositories("test"); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	root, repository := spans["ScanRepositories"], spans["scanRepository"]
+	if root == nil || repository == nil {
+		t.Fatalf("invalid spans, expected ScanRepositories and scanRepository, got %d spans", len(recorder.Ended()))
+	}
+	if repository.SpanContext().TraceID() != root.SpanContext().TraceID() {
+		t.Fatalf("invalid trace of the scanRepository span, expected the trace of the scan")
+	}
+	attributes := make(map[string]string)
+	for _, attribute := range repository.Attributes() {
+		attributes[string(attribute.Key)] = attribute.Value.Emit()
+	}
+	if attributes["account"] != "test" || attributes["repository"] != "a" {
+		t.Fatalf("invalid attributes of the scanRepository span: %v", attributes)
+	}
+}
+
+func TestSpanRecordError(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	_, span := NewTracerProvider(provider).Tracer("test").Start(context.Background(), "failing", scanner.IntAttribute("page", 2))
+	span.RecordError(errors.New("server error"))
+	span.End()
+
+	ended := recorder.Ended()
+	if len(ended) != 1 || ended[0].Status().Code != codes.Error || ended[0].Status().Description != "server error" {
+		t.Fatalf("invalid status of the failed span, got %+v", ended)
+	}
+	if len(ended[0].Events()) != 1 || ended[0].Events()[0].Name != "exception" {
+		t.Fatalf("invalid events of the failed span, expected the exception, got %v", ended[0].Events())
+	}
+}
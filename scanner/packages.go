@@ -0,0 +1,119 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// errPackagesNotFound is returned for packages of accounts that are not organizations, and of missing accounts.
+var errPackagesNotFound = errors.New("packages not found")
+
+// PackageTypes are the GitHub Packages types, the packages API lists a single type at a time.
+var PackageTypes = []string{"container", "docker", "npm", "maven", "rubygems", "nuget"}
+
+type Package struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	PackageType string `json:"package_type"`
+	Visibility  string `json:"visibility"`
+	HTMLURL     string `json:"html_url"`
+	Repository  *struct {
+		FullName string `json:"full_name"`
+	} `json:"repository,omitempty"`
+	VersionCount int       `json:"version_count"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	// Versions are ordered newest first.
+	Versions []*PackageVersion `json:"versions"`
+}
+
+type PackageVersion struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	HTMLURL   string    `json:"html_url,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	Metadata  struct {
+		Container *struct {
+			Tags []string `json:"tags"`
+		} `json:"container,omitempty"`
+	} `json:"metadata"`
+}
+
+// GetPackages returns packages of all types published by the organization with their versions.
+// Packages of a user account are returned if the organization does not exist.
+func (s *Scanner) GetPackages(org string) ([]*Package, error) {
+	return s.getPackages(context.Background(), org, PackageTypes)
+}
+
+func (s *Scanner) getPackages(ctx context.Context, org string, packageTypes []string) ([]*Package, error) {
+	if err := s.checkUser(org); err != nil {
+		return nil, err
+	}
+	ctx, span := s.getTracer().Start(ctx, "GetPackages", StringAttribute("account", org))
+	defer span.End()
+
+	owner := "orgs/" + org
+	var packages []*Package
+	for _, packageType := range packageTypes {
+		listPackages := func(ctx context.Context, page int) ([]*Package, pageLinks, error) {
+			var chunk []*Package
+			links, err := s.getPackagesJSON(ctx, span, fmt.Sprintf("%s/%s/packages?package_type=%s&per_page=%d&page=%d", s.BaseUrl, owner, packageType, s.getPerPage(), page), &chunk)
+			return chunk, links, err
+		}
+		chunk, err := paginate(ctx, listPackages)
+		if errors.Is(err, errPackagesNotFound) && owner == "orgs/"+org {
+			owner = "users/" + org
+			chunk, err = paginate(ctx, listPackages)
+		}
+		if errors.Is(err, errPackagesNotFound) {
+			return nil, newNotFoundError("account %s does not exist", org)
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, pkg := range chunk {
+			if pkg.Versions, err = s.getPackageVersions(ctx, span, owner, pkg); err != nil {
+				return nil, err
+			}
+		}
+		packages = append(packages, chunk...)
+	}
+
+	return packages, nil
+}
+
+func (s *Scanner) getPackageVersions(ctx context.Context, span Span, owner string, pkg *Package) ([]*PackageVersion, error) {
+	versions, err := paginate(ctx, func(ctx context.Context, page int) ([]*PackageVersion, pageLinks, error) {
+		var chunk []*PackageVersion
+		links, err := s.getPackagesJSON(ctx, span, fmt.Sprintf("%s/%s/packages/%s/%s/versions?per_page=%d&page=%d", s.BaseUrl, owner, pkg.PackageType, url.PathEscape(pkg.Name), s.getPerPage(), page), &chunk)
+		return chunk, links, err
+	})
+	if errors.Is(err, errPackagesNotFound) {
+		return nil, newNotFoundError("package %s does not exist", pkg.Name)
+	}
+
+	return versions, err
+}
+
+// getPackagesJSON decodes the response into the result. errPackagesNotFound is returned for the not found status,
+// as it is expected for accounts that are not organizations.
+func (s *Scanner) getPackagesJSON(ctx context.Context, span Span, url string, result any) (pageLinks, error) {
+	response, err := s.get(ctx, span, url)
+	if err != nil {
+		return pageLinks{}, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return pageLinks{}, errPackagesNotFound
+	}
+	if response.StatusCode != http.StatusOK {
+		return pageLinks{}, fmt.Errorf("could not get packages: %w", s.newApiError(response))
+	}
+
+	return parsePageLinks(response.Header), json.NewDecoder(response.Body).Decode(result)
+}
@@ -0,0 +1,60 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetPackages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/orgs/test/packages":
+			switch r.URL.Query().Get("package_type") {
+			case "container":
+				w.Write([]byte(`[{"id": 1, "name": "tools/scanner", "package_type": "container", "repository": {"full_name": "test/scanner"}}]`))
+			case "npm":
+				w.Write([]byte(`[{"id": 2, "name": "sdk", "package_type": "npm"}]`))
+			default:
+				w.Write([]byte(`[]`))
+			}
+		case "/orgs/test/packages/container/tools%2Fscanner/versions":
+			w.Write([]byte(`[{"id": 11, "name": "sha256:abc", "metadata": {"container": {"tags": ["v1.0.0", "latest"]}}}]`))
+		case "/orgs/test/packages/npm/sdk/versions":
+			w.Write([]byte(`[{"id": 21, "name": "1.2.0"}, {"id": 20, "name": "1.1.0"}]`))
+		case "/users/alice/packages":
+			w.Write([]byte(`[]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL, PerPage: 100}
+	packages, err := scanner.GetPackages("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(packages) != 2 {
+		t.Fatalf("invalid packages count, expected 2, got %d", len(packages))
+	}
+	container := packages[0]
+	if container.Name != "tools/scanner" || container.Repository.FullName != "test/scanner" || len(container.Versions) != 1 {
+		t.Fatalf("invalid container package, expected tools/scanner of test/scanner with 1 version, got %v", container)
+	}
+	if tags := container.Versions[0].Metadata.Container.Tags; !equal(tags, []string{"v1.0.0", "latest"}) {
+		t.Fatalf("invalid container tags, expected [v1.0.0 latest], got %v", tags)
+	}
+	if len(packages[1].Versions) != 2 {
+		t.Fatalf("invalid npm package versions count, expected 2, got %d", len(packages[1].Versions))
+	}
+
+	// Packages of user accounts are listed when the organization does not exist.
+	packages, err = scanner.GetPackages("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(packages) != 0 {
+		t.Fatalf("invalid user packages count, expected 0, got %d", len(packages))
+	}
+}
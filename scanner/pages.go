@@ -0,0 +1,97 @@
+package scanner
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// ErrInvalidPageToken is returned for page tokens that are malformed or were returned for another list.
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// PageOptions selects the page of a list fetched page by page.
+type PageOptions struct {
+	// Token is the continuation token returned with the previous page, the first page is fetched if it is empty.
+	Token string
+}
+
+// GetRepositoriesPage fetches a page of the repositories of the account, so consumers could drive the pagination
+// themselves, e.g. one page per job of their scheduler. It returns the token of the next page, empty with the last
+// page. Tokens are opaque and stay valid, a failed page is fetched again with the same token.
+func (s *Scanner) GetRepositoriesPage(ctx context.Context, user string, opts PageOptions) ([]*Repository, string, error) {
+	return getPage(ctx, s, "repos/"+user, opts, func(ctx context.Context, page int) ([]*Repository, pageLinks, error) {
+		return s.getRepositoriesPerPage(ctx, user, page)
+	})
+}
+
+// GetStarredRepositoriesPage fetches a page of the repositories starred by the user, see GetRepositoriesPage.
+func (s *Scanner) GetStarredRepositoriesPage(ctx context.Context, user string, opts PageOptions) ([]*Repository, string, error) {
+	return getPage(ctx, s, "starred/"+user, opts, func(ctx context.Context, page int) ([]*Repository, pageLinks, error) {
+		return s.getStarredRepositoriesPerPage(ctx, user, page)
+	})
+}
+
+// GetReleasesPage fetches a page of the releases of the repository, newest first, see GetRepositoriesPage.
+func (s *Scanner) GetReleasesPage(ctx context.Context, user, repository string, opts PageOptions) ([]*Release, string, error) {
+	return getPage(ctx, s, "releases/"+user+"/"+repository, opts, func(ctx context.Context, page int) ([]*Release, pageLinks, error) {
+		return s.getReleasesPerPage(ctx, user, repository, page)
+	})
+}
+
+// getPage fetches the page of the list the token continues and returns the token of the next one.
+func getPage[T any](ctx context.Context, s *Scanner, list string, opts PageOptions, fetch func(ctx context.Context, page int) ([]T, pageLinks, error)) ([]T, string, error) {
+	page, err := decodePageToken(opts.Token, list, s.getPerPage())
+	if err != nil {
+		return nil, "", err
+	}
+	items, links, err := fetch(ctx, page)
+	if err != nil {
+		return nil, "", err
+	}
+	if links.next <= page {
+		return items, "", nil
+	}
+
+	return items, encodePageToken(list, links.next, s.getPerPage()), nil
+}
+
+// encodePageToken encodes the page with the list it belongs to and the page size, pages of another size start
+// with other items.
+func encodePageToken(list string, page, perPage int) string {
+	values := url.Values{}
+	values.Set("list", list)
+	values.Set("page", strconv.Itoa(page))
+	values.Set("per_page", strconv.Itoa(perPage))
+
+	return base64.RawURLEncoding.EncodeToString([]byte(values.Encode()))
+}
+
+// decodePageToken returns the page of the token, 1 if it is empty.
+func decodePageToken(token, list string, perPage int) (int, error) {
+	if token == "" {
+		return 1, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, ErrInvalidPageToken
+	}
+	values, err := url.ParseQuery(string(data))
+	if err != nil {
+		return 0, ErrInvalidPageToken
+	}
+	if values.Get("list") != list {
+		return 0, fmt.Errorf("%w: it continues another list", ErrInvalidPageToken)
+	}
+	if values.Get("per_page") != strconv.Itoa(perPage) {
+		return 0, fmt.Errorf("%w: it was returned for pages of %s items, not %d", ErrInvalidPageToken, values.Get("per_page"), perPage)
+	}
+	page, err := strconv.Atoi(values.Get("page"))
+	if err != nil || page < 1 {
+		return 0, ErrInvalidPageToken
+	}
+
+	return page, nil
+}
@@ -0,0 +1,63 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetRepositoriesPage(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		if page != "3" {
+			next := map[string]string{"1": "2", "2": "3"}[page]
+			w.Header().Set("Link", fmt.Sprintf(`<%s/users/test/repos?per_page=2&page=%s>; rel="next"`, server.URL, next))
+		}
+		fmt.Fprintf(w, `[{"full_name": "test/%s", "name": "%s"}]`, page, page)
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL, PerPage: 2}
+	var names []string
+	token := ""
+	for {
+		repositories, next, err := scanner.GetRepositoriesPage(context.Background(), "test", PageOptions{Token: token})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, repository := range repositories {
+			names = append(names, repository.Name)
+		}
+		if next == "" {
+			break
+		}
+		token = next
+	}
+	if !equal(names, []string{"1", "2", "3"}) {
+		t.Fatalf("invalid repositories, expected [1 2 3], got %v", names)
+	}
+
+	// the token of the last page fetches it again
+	if repositories, _, err := scanner.GetRepositoriesPage(context.Background(), "test", PageOptions{Token: token}); err != nil || repositories[0].Name != "3" {
+		t.Fatalf("invalid repositories of the page token, expected [3], got %v, %v", repositories, err)
+	}
+
+	for _, invalid := range []struct {
+		scanner *Scanner
+		token   string
+	}{
+		{&scanner, "not a token"},
+		{&Scanner{BaseUrl: server.URL, PerPage: 5}, token},
+	} {
+		if _, _, err := invalid.scanner.GetRepositoriesPage(context.Background(), "test", PageOptions{Token: invalid.token}); !errors.Is(err, ErrInvalidPageToken) {
+			t.Fatalf("invalid error, expected %v, got %v", ErrInvalidPageToken, err)
+		}
+	}
+	if _, _, err := scanner.GetRepositoriesPage(context.Background(), "other", PageOptions{Token: token}); !errors.Is(err, ErrInvalidPageToken) {
+		t.Fatalf("invalid error of the token of another list, expected %v, got %v", ErrInvalidPageToken, err)
+	}
+}
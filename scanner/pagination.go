@@ -0,0 +1,144 @@
+package scanner
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// pageConcurrency is the count of pages of a list fetched at once, once the first page reports the last one.
+const pageConcurrency = 4
+
+// pageLinks are the relations of the RFC 5988 Link header of a list response, e.g.
+// `<https://api.github.com/user/repos?page=2>; rel="next", <https://api.github.com/user/repos?page=5>; rel="last"`.
+// The page numbers are 0 if the relation is missing or its url has no page number.
+type pageLinks struct {
+	next int
+	last int
+}
+
+// parseLinks returns the urls of the Link header by their relations.
+func parseLinks(header string) map[string]string {
+	links := make(map[string]string)
+	for _, link := range strings.Split(header, ",") {
+		target, params, ok := strings.Cut(strings.TrimSpace(link), ";")
+		target = strings.TrimSpace(target)
+		if !ok || !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+			continue
+		}
+		for _, param := range strings.Split(params, ";") {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if !strings.EqualFold(strings.TrimSpace(name), "rel") {
+				continue
+			}
+			// a link could have several space separated relations, e.g. rel="next last"
+			for _, rel := range strings.Fields(strings.Trim(strings.TrimSpace(value), `"`)) {
+				links[strings.ToLower(rel)] = target[1 : len(target)-1]
+			}
+		}
+	}
+
+	return links
+}
+
+func parsePageLinks(header http.Header) pageLinks {
+	links := parseLinks(header.Get("Link"))
+
+	return pageLinks{next: linkPage(links["next"]), last: linkPage(links["last"])}
+}
+
+// linkPage returns the page number of the link url, 0 if it has none.
+func linkPage(link string) int {
+	if link == "" {
+		return 0
+	}
+	linkUrl, err := url.Parse(link)
+	if err != nil {
+		return 0
+	}
+	page, err := strconv.Atoi(linkUrl.Query().Get("page"))
+	if err != nil || page < 1 {
+		return 0
+	}
+
+	return page
+}
+
+// nextPage returns the url of the next page of the Link header, an empty string on the last page.
+// Cursor paginated endpoints are followed by the url.
+func nextPage(link string) string {
+	return parseLinks(link)["next"]
+}
+
+// lastPage returns the number of the last page of the Link header.
+func lastPage(link string) (int, bool) {
+	page := linkPage(parseLinks(link)["last"])
+
+	return page, page > 0
+}
+
+// paginate fetches all pages of a page numbered list endpoint, following the Link headers rather than guessing
+// the end from the page size. Once the first page reports the last page, the rest are fetched concurrently.
+// Otherwise the next pages are fetched one by one until a page has no next link.
+func paginate[T any](ctx context.Context, fetch func(ctx context.Context, page int) ([]T, pageLinks, error)) ([]T, error) {
+	fetch = checkpointPages(ctx, fetch)
+	first, links, err := fetch(ctx, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	if links.next == 2 && links.last > 2 {
+		pages := make([][]T, links.last+1)
+		pages[1] = first
+		group, groupCtx := errgroup.WithContext(ctx)
+		group.SetLimit(pageConcurrency)
+		for page := 2; page <= links.last; page++ {
+			group.Go(func() error {
+				chunk, _, err := fetch(groupCtx, page)
+				pages[page] = chunk
+				return err
+			})
+		}
+		if err := group.Wait(); err != nil {
+			return nil, err
+		}
+		return slices.Concat(pages...), nil
+	}
+
+	// empty lists stay nil like lists of several pages
+	var items []T
+	items = append(items, first...)
+	for page := 1; links.next > page; {
+		page = links.next
+		var chunk []T
+		if chunk, links, err = fetch(ctx, page); err != nil {
+			return nil, err
+		}
+		items = append(items, chunk...)
+	}
+
+	return items, nil
+}
+
+// paginateUntil fetches the pages of a page numbered list endpoint one by one until the done function reports that
+// the fetched items are enough, e.g. of lists ordered newest first, or a page has no next link.
+func paginateUntil[T any](ctx context.Context, fetch func(ctx context.Context, page int) ([]T, pageLinks, error), done func(items []T) bool) ([]T, error) {
+	fetch = checkpointPages(ctx, fetch)
+	var items []T
+	for page := 1; ; {
+		chunk, links, err := fetch(ctx, page)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, chunk...)
+		if done(items) || links.next <= page {
+			return items, nil
+		}
+		page = links.next
+	}
+}
@@ -0,0 +1,141 @@
+package scanner
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// setPageLinks sets the Link header of a list response like GitHub does, with the next and the last page of the
+// requested url.
+func setPageLinks(w http.ResponseWriter, r *http.Request, next, last int) {
+	pageUrl := func(page int) string {
+		query := r.URL.Query()
+		query.Set("page", strconv.Itoa(page))
+		return fmt.Sprintf("https://api.github.com%s?%s", r.URL.Path, query.Encode())
+	}
+	w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next", <%s>; rel="last"`, pageUrl(next), pageUrl(last)))
+}
+
+func TestParseLinks(t *testing.T) {
+	links := parseLinks(`<https://api.github.com/user/repos?page=3&per_page=100>; rel="next", ` +
+		`<https://api.github.com/user/repos?page=1&per_page=100>; rel="prev"; title="previous", ` +
+		`<https://api.github.com/user/repos?page=50&per_page=100>; rel="last first"`)
+
+	expected := map[string]string{
+		"next":  "https://api.github.com/user/repos?page=3&per_page=100",
+		"prev":  "https://api.github.com/user/repos?page=1&per_page=100",
+		"last":  "https://api.github.com/user/repos?page=50&per_page=100",
+		"first": "https://api.github.com/user/repos?page=50&per_page=100",
+	}
+	if len(links) != len(expected) {
+		t.Fatalf("invalid links, expected %v, got %v", expected, links)
+	}
+	for rel, link := range expected {
+		if links[rel] != link {
+			t.Fatalf("invalid %s link, expected %s, got %s", rel, link, links[rel])
+		}
+	}
+
+	header := http.Header{"Link": {`<https://api.github.com/user/repos?page=3&per_page=100>; rel="next", <https://api.github.com/user/repos?page=50&per_page=100>; rel="last"`}}
+	if pageLinks := parsePageLinks(header); pageLinks.next != 3 || pageLinks.last != 50 {
+		t.Fatalf("invalid page links, expected next 3 and last 50, got %+v", pageLinks)
+	}
+	if pageLinks := parsePageLinks(http.Header{}); pageLinks.next != 0 || pageLinks.last != 0 {
+		t.Fatalf("invalid page links without the header, expected none, got %+v", pageLinks)
+	}
+	if page, ok := lastPage(`<https://api.github.com/user/repos?after=abc>; rel="last"`); ok {
+		t.Fatalf("invalid last page of a cursor link, expected none, got %d", page)
+	}
+}
+
+func TestPaginateFollowsLinks(t *testing.T) {
+	var requests, concurrent, maxConcurrent int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		current := atomic.AddInt32(&concurrent, 1)
+		defer atomic.AddInt32(&concurrent, -1)
+		for {
+			seen := atomic.LoadInt32(&maxConcurrent)
+			if current <= seen || atomic.CompareAndSwapInt32(&maxConcurrent, seen, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page < 10 {
+			setPageLinks(w, r, page+1, 10)
+		}
+		// the pages are short, the page size must not end the pagination
+		fmt.Fprintf(w, `[{"name": "release%d"}]`, page)
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL, PerPage: 100}
+	releases, err := scanner.GetAllReleases("test", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(releases) != 10 || requests != 10 {
+		t.Fatalf("invalid releases, expected 10 releases of 10 requests, got %d of %d", len(releases), requests)
+	}
+	for i, release := range releases {
+		if expected := fmt.Sprintf("release%d", i+1); release.Name != expected {
+			t.Fatalf("invalid release %d, expected %s, got %s", i, expected, release.Name)
+		}
+	}
+	if maxConcurrent < 2 || maxConcurrent > pageConcurrency {
+		t.Fatalf("invalid concurrent page requests, expected 2 to %d, got %d", pageConcurrency, maxConcurrent)
+	}
+}
+
+func TestPaginateWithoutLastLink(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page < 3 {
+			w.Header().Set("Link", fmt.Sprintf(`<https://api.github.com/orgs/test/members?page=%d>; rel="next"`, page+1))
+		}
+		fmt.Fprintf(w, `[{"login": "member%d"}]`, page)
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL, PerPage: 1}
+	members, err := scanner.GetOrgMembers("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(members) != 3 || members[2].Login != "member3" {
+		t.Fatalf("invalid members, expected member1 to member3, got %d members", len(members))
+	}
+}
+
+func TestPaginateMax(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page < 10 {
+			setPageLinks(w, r, page+1, 10)
+		}
+		fmt.Fprintf(w, `[{"name": "release%d-1"}, {"name": "release%d-2"}]`, page, page)
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL, PerPage: 2, MaxReleases: 3}
+	releases, err := scanner.GetAllReleases("test", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(releases) != 3 || requests != 2 {
+		t.Fatalf("invalid releases, expected 3 releases of 2 requests, got %d of %d", len(releases), requests)
+	}
+	if releases[2].Name != "release2-1" {
+		t.Fatalf("invalid last release, expected release2-1, got %s", releases[2].Name)
+	}
+}
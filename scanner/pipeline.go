@@ -0,0 +1,210 @@
+package scanner
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Filter decides whether a scanned item is kept.
+type Filter interface {
+	Keep(item *ResultItem) bool
+}
+
+// FilterFunc adapts a function to the Filter interface.
+type FilterFunc func(item *ResultItem) bool
+
+func (f FilterFunc) Keep(item *ResultItem) bool {
+	return f(item)
+}
+
+// Transformer is a post-processing step of scanned items, e.g. an enrichment or a sort. It could return new items
+// or change the passed ones.
+type Transformer interface {
+	Transform(items []*ResultItem) ([]*ResultItem, error)
+}
+
+// TransformerFunc adapts a function to the Transformer interface.
+type TransformerFunc func(items []*ResultItem) ([]*ResultItem, error)
+
+func (f TransformerFunc) Transform(items []*ResultItem) ([]*ResultItem, error) {
+	return f(items)
+}
+
+// FilterStep returns the step keeping the items all filters keep.
+func FilterStep(filters ...Filter) Transformer {
+	return TransformerFunc(func(items []*ResultItem) ([]*ResultItem, error) {
+		var kept []*ResultItem
+		for _, item := range items {
+			keep := true
+			for _, filter := range filters {
+				if keep = filter.Keep(item); !keep {
+					break
+				}
+			}
+			if keep {
+				kept = append(kept, item)
+			}
+		}
+		return kept, nil
+	})
+}
+
+// Pipeline applies post-processing steps to scanned items in the order they are added.
+type Pipeline struct {
+	steps []Transformer
+}
+
+func NewPipeline(steps ...Transformer) *Pipeline {
+	return &Pipeline{steps: steps}
+}
+
+// Add appends the steps to the pipeline.
+func (p *Pipeline) Add(steps ...Transformer) *Pipeline {
+	p.steps = append(p.steps, steps...)
+
+	return p
+}
+
+// Filter appends a step keeping the items all filters keep.
+func (p *Pipeline) Filter(filters ...Filter) *Pipeline {
+	return p.Add(FilterStep(filters...))
+}
+
+// Run applies the steps to the items, stopping at the first failed step.
+func (p *Pipeline) Run(items []*ResultItem) ([]*ResultItem, error) {
+	var err error
+	for _, step := range p.steps {
+		if items, err = step.Transform(items); err != nil {
+			return nil, err
+		}
+	}
+
+	return items, nil
+}
+
+// AnnotationFilter keeps the items whose annotation has the value.
+func AnnotationFilter(key, value string) Filter {
+	return FilterFunc(func(item *ResultItem) bool {
+		return item.Annotations[key] == value
+	})
+}
+
+// TopicFilter keeps the items of repositories tagged with the topic.
+func TopicFilter(topic string) Filter {
+	return FilterFunc(func(item *ResultItem) bool {
+		return slices.Contains(item.Repository.Topics, topic)
+	})
+}
+
+// PlatformStep keeps only the assets matching any of the platforms, see FilterAssetsByPlatform.
+func PlatformStep(platforms []Platform) Transformer {
+	return TransformerFunc(func(items []*ResultItem) ([]*ResultItem, error) {
+		return FilterAssetsByPlatform(items, platforms), nil
+	})
+}
+
+// SortStep puts the items in the documented output order, see SortResults.
+func SortStep() Transformer {
+	return TransformerFunc(func(items []*ResultItem) ([]*ResultItem, error) {
+		SortResults(items)
+		return items, nil
+	})
+}
+
+// SortByStep puts the items in the order of the comparator, see SortResultsBy.
+func SortByStep(comparator Comparator) Transformer {
+	return TransformerFunc(func(items []*ResultItem) ([]*ResultItem, error) {
+		SortResultsBy(items, comparator)
+		return items, nil
+	})
+}
+
+// StepFactory creates a step of the pipeline from its argument, an empty string if the step has none.
+type StepFactory func(arg string) (Transformer, error)
+
+var (
+	stepsMu sync.RWMutex
+	steps   = map[string]StepFactory{
+		"where": func(arg string) (Transformer, error) {
+			key, value, ok := strings.Cut(arg, "=")
+			if !ok {
+				return nil, fmt.Errorf("invalid condition %s, expected key=value", arg)
+			}
+			return FilterStep(AnnotationFilter(key, value)), nil
+		},
+		"topic": func(arg string) (Transformer, error) {
+			if arg == "" {
+				return nil, fmt.Errorf("topic is not specified")
+			}
+			return FilterStep(TopicFilter(arg)), nil
+		},
+		"platform": func(arg string) (Transformer, error) {
+			var platforms []Platform
+			for _, value := range strings.Split(arg, ",") {
+				platform, err := ParsePlatform(value)
+				if err != nil {
+					return nil, err
+				}
+				platforms = append(platforms, platform)
+			}
+			return PlatformStep(platforms), nil
+		},
+		"sort": func(arg string) (Transformer, error) {
+			if arg == "" {
+				return SortStep(), nil
+			}
+			comparator, err := ParseSort(arg)
+			if err != nil {
+				return nil, err
+			}
+			return SortByStep(comparator), nil
+		},
+	}
+)
+
+// RegisterStep makes the step available to NewStep by its name, so programs embedding the scanner could offer
+// their own steps. It panics if the name is already registered.
+func RegisterStep(name string, factory StepFactory) {
+	stepsMu.Lock()
+	defer stepsMu.Unlock()
+
+	if _, ok := steps[name]; ok {
+		panic(fmt.Sprintf("pipeline step %s is already registered", name))
+	}
+	steps[name] = factory
+}
+
+// StepNames returns the names of the registered steps.
+func StepNames() []string {
+	stepsMu.RLock()
+	defer stepsMu.RUnlock()
+
+	names := make([]string, 0, len(steps))
+	for name := range steps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// NewStep creates the registered step of the "name" or "name=argument" spec, e.g. "topic=go" or "sort".
+func NewStep(spec string) (Transformer, error) {
+	name, arg, _ := strings.Cut(spec, "=")
+	stepsMu.RLock()
+	factory, ok := steps[name]
+	stepsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown pipeline step %s, expected one of %s", name, strings.Join(StepNames(), ", "))
+	}
+
+	step, err := factory(arg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pipeline step %s: %w", spec, err)
+	}
+
+	return step, nil
+}
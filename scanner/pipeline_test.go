@@ -0,0 +1,77 @@
+package scanner
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestPipeline(t *testing.T) {
+	items := []*ResultItem{
+		{Repository: &Repository{FullName: "test/c", Topics: []string{"go"}}, Annotations: map[string]string{"team": "platform"}},
+		{Repository: &Repository{FullName: "test/a", Topics: []string{"go"}}, Annotations: map[string]string{"team": "platform"}},
+		{Repository: &Repository{FullName: "test/b", Topics: []string{"go"}}, Annotations: map[string]string{"team": "web"}},
+		{Repository: &Repository{FullName: "test/d"}, Annotations: map[string]string{"team": "platform"}},
+	}
+
+	where, err := NewStep("where=team=platform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sortStep, err := NewStep("sort")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var enriched []string
+	enrich := TransformerFunc(func(items []*ResultItem) ([]*ResultItem, error) {
+		for _, item := range items {
+			enriched = append(enriched, item.Repository.FullName)
+		}
+		return items, nil
+	})
+
+	result, err := NewPipeline(where).Filter(TopicFilter("go")).Add(sortStep, enrich).Run(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"test/a", "test/c"}
+	if !slices.Equal(enriched, expected) || len(result) != 2 {
+		t.Fatalf("invalid pipeline result, expected %v, got %v", expected, enriched)
+	}
+
+	failure := errors.New("failure")
+	_, err = NewPipeline(TransformerFunc(func(items []*ResultItem) ([]*ResultItem, error) {
+		return nil, failure
+	}), enrich).Run(items)
+	if !errors.Is(err, failure) || len(enriched) != 2 {
+		t.Fatalf("invalid error, expected %v without further steps, got %v", failure, err)
+	}
+}
+
+func TestRegisterStep(t *testing.T) {
+	RegisterStep("test-limit", func(arg string) (Transformer, error) {
+		return TransformerFunc(func(items []*ResultItem) ([]*ResultItem, error) {
+			return items[:1], nil
+		}), nil
+	})
+	if !slices.Contains(StepNames(), "test-limit") {
+		t.Fatalf("invalid step names, expected test-limit in %v", StepNames())
+	}
+	step, err := NewStep("test-limit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, err := step.Transform([]*ResultItem{{}, {}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("invalid items count, expected 1, got %d", len(items))
+	}
+
+	for _, spec := range []string{"unknown", "where=team", "topic", "platform=nope/nope/nope"} {
+		if _, err := NewStep(spec); err == nil {
+			t.Fatalf("invalid error of the step %s, expected error, got nil", spec)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package scanner
+
+import (
+	"fmt"
+	"strings"
+)
+
+const archAll = "all"
+
+// Platform is an OS/arch target of a release asset. An empty Arch in a filter matches any architecture.
+type Platform struct {
+	OS   string `json:"os"`
+	Arch string `json:"arch"`
+}
+
+var osAliases = map[string]string{
+	"linux":   "linux",
+	"darwin":  "darwin",
+	"macos":   "darwin",
+	"mac":     "darwin",
+	"osx":     "darwin",
+	"apple":   "darwin",
+	"windows": "windows",
+	"win":     "windows",
+	"win32":   "windows",
+	"win64":   "windows",
+	"exe":     "windows",
+	"msi":     "windows",
+	"freebsd": "freebsd",
+	"openbsd": "openbsd",
+	"netbsd":  "netbsd",
+	"android": "android",
+}
+
+var archAliases = map[string]string{
+	"amd64":     "amd64",
+	"x86_64":    "amd64",
+	"x64":       "amd64",
+	"win64":     "amd64",
+	"arm64":     "arm64",
+	"aarch64":   "arm64",
+	"386":       "386",
+	"i386":      "386",
+	"i686":      "386",
+	"x86":       "386",
+	"win32":     "386",
+	"arm":       "arm",
+	"armv6":     "arm",
+	"armv7":     "arm",
+	"armhf":     "arm",
+	"ppc64le":   "ppc64le",
+	"s390x":     "s390x",
+	"riscv64":   "riscv64",
+	"universal": archAll,
+	"all":       archAll,
+}
+
+func ParsePlatform(value string) (Platform, error) {
+	os, arch, _ := strings.Cut(strings.ToLower(strings.TrimSpace(value)), "/")
+	platform := Platform{OS: osAliases[os], Arch: arch}
+	if platform.OS == "" {
+		return Platform{}, fmt.Errorf("unknown platform os: %s", value)
+	}
+	if arch != "" {
+		if platform.Arch = archAliases[arch]; platform.Arch == "" {
+			return Platform{}, fmt.Errorf("unknown platform arch: %s", value)
+		}
+	}
+
+	return platform, nil
+}
+
+func (p Platform) String() string {
+	if p.Arch == "" {
+		return p.OS
+	}
+
+	return p.OS + "/" + p.Arch
+}
+
+// Matches reports whether an asset built for the platform p satisfies the filter.
+func (p Platform) Matches(filter Platform) bool {
+	if p.OS == "" || p.OS != filter.OS {
+		return false
+	}
+
+	return filter.Arch == "" || p.Arch == archAll || p.Arch == filter.Arch
+}
+
+// Platform classifies the asset by the OS and arch keywords found in its name.
+// Fields that could not be detected are left empty.
+func (a *Asset) Platform() Platform {
+	name := strings.ToLower(a.Name)
+	name = strings.NewReplacer("x86_64", "amd64", "x86-64", "amd64").Replace(name)
+	tokens := strings.FieldsFunc(name, func(r rune) bool {
+		return r == '-' || r == '_' || r == '.' || r == ' ' || r == '+'
+	})
+
+	var platform Platform
+	for _, token := range tokens {
+		if os, ok := osAliases[token]; ok && platform.OS == "" {
+			platform.OS = os
+		}
+		if arch, ok := archAliases[token]; ok && platform.Arch == "" {
+			platform.Arch = arch
+		}
+	}
+
+	return platform
+}
+
+// FilterAssetsByPlatform returns copies of the items whose releases only contain assets matching any of the platforms.
+// Items are returned as is if no platforms are specified.
+func FilterAssetsByPlatform(items []*ResultItem, platforms []Platform) []*ResultItem {
+	if len(platforms) == 0 {
+		return items
+	}
+
+	filteredItems := make([]*ResultItem, 0, len(items))
+	for _, item := range items {
+		filteredItem := *item
+		filteredItem.Releases = make([]*Release, 0, len(item.Releases))
+		for _, release := range item.Releases {
+			filteredRelease := *release
+			filteredRelease.Assets = FilterReleaseAssets(release, platforms)
+			filteredItem.Releases = append(filteredItem.Releases, &filteredRelease)
+		}
+		filteredItems = append(filteredItems, &filteredItem)
+	}
+
+	return filteredItems
+}
+
+func FilterReleaseAssets(release *Release, platforms []Platform) []*Asset {
+	var assets []*Asset
+	for _, asset := range release.Assets {
+		assetPlatform := asset.Platform()
+		for _, platform := range platforms {
+			if assetPlatform.Matches(platform) {
+				assets = append(assets, asset)
+				break
+			}
+		}
+	}
+
+	return assets
+}
@@ -0,0 +1,58 @@
+package scanner
+
+import "testing"
+
+func TestAssetPlatform(t *testing.T) {
+	cases := map[string]Platform{
+		"tool_1.0.0_linux_amd64.tar.gz":   {OS: "linux", Arch: "amd64"},
+		"tool-1.0.0-x86_64-linux.tar.gz":  {OS: "linux", Arch: "amd64"},
+		"tool-1.0.0-aarch64-apple-darwin": {OS: "darwin", Arch: "arm64"},
+		"tool_1.0.0_darwin_universal.zip": {OS: "darwin", Arch: archAll},
+		"tool-setup.exe":                  {OS: "windows"},
+		"checksums.txt":                   {},
+	}
+	for name, expected := range cases {
+		platform := (&Asset{Name: name}).Platform()
+		if platform != expected {
+			t.Errorf("invalid platform for the asset %s, expected %v, got %v", name, expected, platform)
+		}
+	}
+}
+
+func TestFilterAssetsByPlatform(t *testing.T) {
+	items := []*ResultItem{{
+		Repository: &Repository{FullName: "test/test", Name: "test"},
+		Releases: []*Release{{
+			Name: "v1.0.0",
+			Assets: []*Asset{
+				{Name: "tool_linux_amd64.tar.gz"},
+				{Name: "tool_linux_arm64.tar.gz"},
+				{Name: "tool_darwin_universal.zip"},
+				{Name: "tool_windows_amd64.zip"},
+				{Name: "checksums.txt"},
+			},
+		}},
+	}}
+
+	linux, err := ParsePlatform("linux/amd64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	darwin, err := ParsePlatform("macos/arm64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	filtered := FilterAssetsByPlatform(items, []Platform{linux, darwin})
+
+	names := []string{}
+	for _, asset := range filtered[0].Releases[0].Assets {
+		names = append(names, asset.Name)
+	}
+	expectedNames := []string{"tool_linux_amd64.tar.gz", "tool_darwin_universal.zip"}
+	if !equal(names, expectedNames) {
+		t.Fatalf("invalid filtered assets, expected %v, got %v", expectedNames, names)
+	}
+	if len(items[0].Releases[0].Assets) != 5 {
+		t.Fatal("original release assets must not be modified")
+	}
+}
@@ -0,0 +1,38 @@
+package scanner
+
+import (
+	"context"
+	"strings"
+)
+
+// Provider lists repositories and releases of a code hosting service.
+// Scanner implements it for GitHub and uses itself if no other provider is configured.
+type Provider interface {
+	ListRepositories(ctx context.Context, account string) ([]*Repository, error)
+	ListReleases(ctx context.Context, owner, repository string) ([]*Release, error)
+}
+
+func (s *Scanner) ListRepositories(ctx context.Context, account string) ([]*Repository, error) {
+	return s.getAllRepositories(ctx, account)
+}
+
+func (s *Scanner) ListReleases(ctx context.Context, owner, repository string) ([]*Release, error) {
+	return s.getAllReleases(ctx, owner, repository)
+}
+
+func (s *Scanner) getProvider() Provider {
+	if s.Provider == nil {
+		return s
+	}
+
+	return s.Provider
+}
+
+// repositoryOwner returns the namespace part of the repository full name, e.g. "group/subgroup" for "group/subgroup/project".
+func repositoryOwner(repository *Repository, account string) string {
+	if i := strings.LastIndex(repository.FullName, "/"); i > 0 {
+		return repository.FullName[:i]
+	}
+
+	return account
+}
@@ -0,0 +1,82 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimitResources are the rate limit resources most scans spend.
+var RateLimitResources = []string{"core", "search", "graphql"}
+
+// RateLimit is the API rate limit state reported by the last response.
+type RateLimit struct {
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Reset     time.Time `json:"reset"`
+}
+
+// GetRateLimits returns the rate limits of the token by resource, e.g. "core", "search" or "graphql".
+// The request does not count against the limits.
+func (s *Scanner) GetRateLimits() (map[string]*RateLimit, error) {
+	return s.getRateLimits(context.Background())
+}
+
+func (s *Scanner) getRateLimits(ctx context.Context) (map[string]*RateLimit, error) {
+	ctx, span := s.getTracer().Start(ctx, "GetRateLimits")
+	defer span.End()
+
+	response, err := s.get(ctx, span, s.BaseUrl+"/rate_limit")
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not get rate limits: %w", s.newApiError(response))
+	}
+
+	var body struct {
+		Resources map[string]struct {
+			Limit     int   `json:"limit"`
+			Remaining int   `json:"remaining"`
+			Reset     int64 `json:"reset"`
+		} `json:"resources"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	rateLimits := make(map[string]*RateLimit, len(body.Resources))
+	for resource, limit := range body.Resources {
+		rateLimits[resource] = &RateLimit{Limit: limit.Limit, Remaining: limit.Remaining, Reset: time.Unix(limit.Reset, 0)}
+	}
+
+	return rateLimits, nil
+}
+
+// RateLimit returns the core rate limit state of the last API response, or nil if no response reported it yet.
+func (s *Scanner) RateLimit() *RateLimit {
+	return s.rateLimit.Load()
+}
+
+func (s *Scanner) updateRateLimit(header http.Header) {
+	// other resources, e.g. search, have separate limits
+	if resource := header.Get("X-RateLimit-Resource"); resource != "" && resource != "core" {
+		return
+	}
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+
+	rateLimit := &RateLimit{Remaining: remaining}
+	rateLimit.Limit, _ = strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		rateLimit.Reset = time.Unix(reset, 0)
+	}
+	s.rateLimit.Store(rateLimit)
+}
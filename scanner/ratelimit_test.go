@@ -0,0 +1,38 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetRateLimits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rate_limit" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"resources": {
+			"core": {"limit": 5000, "used": 10, "remaining": 4990, "reset": 1700000000},
+			"search": {"limit": 30, "used": 0, "remaining": 30, "reset": 1700000060},
+			"graphql": {"limit": 5000, "used": 0, "remaining": 5000, "reset": 1700003600}
+		}, "rate": {"limit": 5000, "used": 10, "remaining": 4990, "reset": 1700000000}}`))
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL}
+	rateLimits, err := scanner.GetRateLimits()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rateLimits) != 3 {
+		t.Fatalf("invalid resources count, expected 3, got %d", len(rateLimits))
+	}
+	core := rateLimits["core"]
+	if core.Limit != 5000 || core.Remaining != 4990 || core.Reset.Unix() != 1700000000 {
+		t.Fatalf("invalid core rate limit, expected 4990/5000, got %+v", core)
+	}
+	if search := rateLimits["search"]; search.Remaining != 30 || search.Reset.Unix() != 1700000060 {
+		t.Fatalf("invalid search rate limit, expected 30/30, got %+v", search)
+	}
+}
@@ -0,0 +1,225 @@
+// Package recorder records API responses to fixture files and replays them, so integration tests run without
+// network access. A test records its fixture once against the real API and replays it afterwards:
+//
+//	rec, err := recorder.New("testdata/releases.json", recorder.ModeFromEnv())
+//	...
+//	defer rec.Save()
+//	s := &scanner.Scanner{BaseUrl: scanner.GitHuhApi, Token: os.Getenv("GITHUB_TOKEN"), Client: rec.Client()}
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// RecordEnv is the environment variable switching ModeFromEnv to recording.
+const RecordEnv = "GITHUBSCANNER_RECORD"
+
+// Mode is whether the recorder replays or records the fixture.
+type Mode int
+
+const (
+	// ModeReplay responds with the recorded interactions and fails requests not recorded in the fixture.
+	ModeReplay Mode = iota
+	// ModeRecord performs the requests and records the responses, Save writes them to the fixture.
+	ModeRecord
+)
+
+// ModeFromEnv returns ModeRecord if GITHUBSCANNER_RECORD=1 is set, ModeReplay otherwise.
+func ModeFromEnv() Mode {
+	if os.Getenv(RecordEnv) == "1" {
+		return ModeRecord
+	}
+
+	return ModeReplay
+}
+
+// DefaultScrubbedHeaders are the headers carrying credentials, their values are never written to fixtures.
+var DefaultScrubbedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-GitHub-Token"}
+
+const scrubbed = "[scrubbed]"
+
+// Interaction is a recorded request and its response. The url is the request path with the query, so fixtures
+// replay against any host.
+type Interaction struct {
+	Method          string            `json:"method"`
+	URL             string            `json:"url"`
+	RequestHeaders  map[string]string `json:"request_headers,omitempty"`
+	Status          int               `json:"status"`
+	ResponseHeaders map[string]string `json:"response_headers,omitempty"`
+	Body            string            `json:"body"`
+}
+
+// Fixture is the content of a fixture file.
+type Fixture struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Recorder is an http.RoundTripper recording or replaying the interactions of a fixture file.
+// It is safe for concurrent use.
+type Recorder struct {
+	Path string
+	Mode Mode
+	// Transport performs the requests in the record mode, http.DefaultTransport is used if it is nil.
+	Transport http.RoundTripper
+	// ScrubHeaders are the request and response headers whose values are replaced before they are recorded,
+	// DefaultScrubbedHeaders if it is nil.
+	ScrubHeaders []string
+
+	mu      sync.Mutex
+	fixture *Fixture
+}
+
+// New creates a recorder of the fixture file. The fixture is loaded in the replay mode, it must exist.
+func New(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{Path: path, Mode: mode, fixture: &Fixture{}}
+	if mode == ModeRecord {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, r.fixture); err != nil {
+		return nil, fmt.Errorf("could not parse the fixture %s: %v", path, err)
+	}
+
+	return r, nil
+}
+
+// Client returns an http client using the recorder as its transport.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Interactions returns the recorded or loaded interactions.
+func (r *Recorder) Interactions() []*Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]*Interaction(nil), r.fixture.Interactions...)
+}
+
+func (r *Recorder) RoundTrip(request *http.Request) (*http.Response, error) {
+	if r.Mode == ModeRecord {
+		return r.record(request)
+	}
+
+	return r.replay(request)
+}
+
+// Save writes the recorded interactions to the fixture file, it does nothing in the replay mode.
+func (r *Recorder) Save() error {
+	if r.Mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.fixture, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(r.Path, append(data, '\n'), 0o644)
+}
+
+// replay responds with the recorded interaction of the same method and url. The same request always gets the
+// same response.
+func (r *Recorder) replay(request *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, interaction := range r.fixture.Interactions {
+		if interaction.Method != request.Method || interaction.URL != request.URL.RequestURI() {
+			continue
+		}
+		response := &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Status, http.StatusText(interaction.Status)),
+			StatusCode:    interaction.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        make(http.Header, len(interaction.ResponseHeaders)),
+			Body:          io.NopCloser(strings.NewReader(interaction.Body)),
+			ContentLength: int64(len(interaction.Body)),
+			Request:       request,
+		}
+		for name, value := range interaction.ResponseHeaders {
+			response.Header.Set(name, value)
+		}
+		return response, nil
+	}
+
+	return nil, fmt.Errorf("request %s %s is not recorded in the fixture %s", request.Method, request.URL.RequestURI(), r.Path)
+}
+
+// record performs the request and records the response with the credentials scrubbed. Repeated requests are
+// recorded once.
+func (r *Recorder) record(request *http.Request) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	response, err := transport.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	response.Body = io.NopCloser(bytes.NewReader(body))
+
+	interaction := &Interaction{
+		Method:          request.Method,
+		URL:             request.URL.RequestURI(),
+		RequestHeaders:  r.scrub(request.Header),
+		Status:          response.StatusCode,
+		ResponseHeaders: r.scrub(response.Header),
+		Body:            string(body),
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, recorded := range r.fixture.Interactions {
+		if recorded.Method == interaction.Method && recorded.URL == interaction.URL {
+			return response, nil
+		}
+	}
+	r.fixture.Interactions = append(r.fixture.Interactions, interaction)
+
+	return response, nil
+}
+
+// scrub flattens the headers with the values of the scrubbed headers replaced.
+func (r *Recorder) scrub(header http.Header) map[string]string {
+	scrubHeaders := r.ScrubHeaders
+	if scrubHeaders == nil {
+		scrubHeaders = DefaultScrubbedHeaders
+	}
+
+	flattened := make(map[string]string, len(header))
+	for name, values := range header {
+		value := strings.Join(values, ", ")
+		for _, scrubHeader := range scrubHeaders {
+			if strings.EqualFold(name, scrubHeader) {
+				value = scrubbed
+			}
+		}
+		flattened[name] = value
+	}
+	if len(flattened) == 0 {
+		return nil
+	}
+
+	return flattened
+}
@@ -0,0 +1,82 @@
+package recorder
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"githubscanner/scanner"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	var requests int
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		if r.URL.Path == "/repos/test/a/releases" {
+			w.Write([]byte(`[{"name": "A 1.0.0", "tag_name": "v1.0.0", "assets": []}]`))
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer github.Close()
+
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	recording, err := New(path, ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &scanner.Scanner{BaseUrl: github.URL, Token: "secret-token", Client: recording.Client()}
+	for i := 0; i < 2; i++ {
+		if _, err := s.GetAllReleases("test", "a"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := recording.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if interactions := recording.Interactions(); len(interactions) != 1 || requests != 2 {
+		t.Fatalf("invalid recording, expected 1 interaction of 2 requests, got %d of %d", len(interactions), requests)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "secret") || !strings.Contains(string(data), `"Authorization": "[scrubbed]"`) {
+		t.Fatalf("invalid fixture, expected the credentials scrubbed, got:\n%s", data)
+	}
+
+	replaying, err := New(path, ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s = &scanner.Scanner{BaseUrl: "https://api.example.com", Client: replaying.Client()}
+	releases, err := s.GetAllReleases("test", "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(releases) != 1 || releases[0].TagName != "v1.0.0" {
+		t.Fatalf("invalid replayed releases: %+v", releases)
+	}
+	if rateLimit := s.RateLimit(); rateLimit == nil || rateLimit.Remaining != 4999 {
+		t.Fatalf("invalid replayed rate limit, expected 4999 remaining, got %+v", rateLimit)
+	}
+	if requests != 2 {
+		t.Fatalf("invalid requests count, expected no requests on replay, got %d", requests-2)
+	}
+
+	if _, err := s.GetAllReleases("test", "b"); err == nil || !strings.Contains(err.Error(), "is not recorded") {
+		t.Fatalf("invalid error, expected a request missing from the fixture, got %v", err)
+	}
+}
+
+func TestNewMissingFixture(t *testing.T) {
+	if _, err := New(filepath.Join(t.TempDir(), "missing.json"), ModeReplay); !os.IsNotExist(err) {
+		t.Fatalf("invalid error, expected a missing fixture, got %v", err)
+	}
+}
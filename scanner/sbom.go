@@ -0,0 +1,132 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const sbomDocumentID = "SPDXRef-DOCUMENT"
+
+// SBOM is an SPDX 2.3 document as exported by the GitHub dependency graph.
+type SBOM struct {
+	SPDXID            string              `json:"SPDXID"`
+	SPDXVersion       string              `json:"spdxVersion"`
+	Name              string              `json:"name"`
+	DataLicense       string              `json:"dataLicense"`
+	DocumentNamespace string              `json:"documentNamespace"`
+	DocumentDescribes []string            `json:"documentDescribes,omitempty"`
+	CreationInfo      SBOMCreationInfo    `json:"creationInfo"`
+	Packages          []*SBOMPackage      `json:"packages"`
+	Relationships     []*SBOMRelationship `json:"relationships,omitempty"`
+}
+
+type SBOMCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type SBOMPackage struct {
+	SPDXID           string             `json:"SPDXID"`
+	Name             string             `json:"name"`
+	VersionInfo      string             `json:"versionInfo,omitempty"`
+	DownloadLocation string             `json:"downloadLocation"`
+	FilesAnalyzed    bool               `json:"filesAnalyzed"`
+	LicenseConcluded string             `json:"licenseConcluded,omitempty"`
+	LicenseDeclared  string             `json:"licenseDeclared,omitempty"`
+	CopyrightText    string             `json:"copyrightText,omitempty"`
+	Supplier         string             `json:"supplier,omitempty"`
+	ExternalRefs     []*SBOMExternalRef `json:"externalRefs,omitempty"`
+}
+
+type SBOMExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type SBOMRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+	RelationshipType   string `json:"relationshipType"`
+}
+
+// GetSBOM returns the SPDX SBOM of the repository generated from its dependency graph.
+func (s *Scanner) GetSBOM(user, repository string) (*SBOM, error) {
+	return s.getSBOM(context.Background(), user, repository)
+}
+
+func (s *Scanner) getSBOM(ctx context.Context, user, repository string) (*SBOM, error) {
+	if err := s.checkUser(user); err != nil {
+		return nil, err
+	}
+	if err := s.checkRepository(repository); err != nil {
+		return nil, err
+	}
+	ctx, span := s.getTracer().Start(ctx, "GetSBOM", StringAttribute("account", user), StringAttribute("repository", repository))
+	defer span.End()
+
+	response, err := s.get(ctx, span, fmt.Sprintf("%s/repos/%s/%s/dependency-graph/sbom", s.BaseUrl, user, repository))
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil, newNotFoundError("dependency graph of the repository %s/%s does not exist", user, repository)
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not get sbom of the repository %s/%s: %w", user, repository, s.newApiError(response))
+	}
+
+	var result struct {
+		SBOM *SBOM `json:"sbom"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	return result.SBOM, nil
+}
+
+// MergeSBOMs merges SBOMs of repositories into a single document describing all of them. Packages shared by
+// the repositories are listed once.
+func MergeSBOMs(name string, sboms []*SBOM) *SBOM {
+	now := time.Now().UTC()
+	merged := &SBOM{
+		SPDXID:            sbomDocumentID,
+		SPDXVersion:       "SPDX-2.3",
+		Name:              name,
+		DataLicense:       "CC0-1.0",
+		DocumentNamespace: fmt.Sprintf("https://spdx.org/spdxdocs/githubscanner-%s-%d", name, now.Unix()),
+		CreationInfo: SBOMCreationInfo{
+			Created:  now.Format(time.RFC3339),
+			Creators: []string{"Tool: githubscanner"},
+		},
+	}
+
+	packages := make(map[string]bool)
+	relationships := make(map[SBOMRelationship]bool)
+	for _, sbom := range sboms {
+		for _, pkg := range sbom.Packages {
+			if !packages[pkg.SPDXID] {
+				packages[pkg.SPDXID] = true
+				merged.Packages = append(merged.Packages, pkg)
+			}
+		}
+		for _, relationship := range sbom.Relationships {
+			if relationship.SPDXElementID == sbomDocumentID && relationship.RelationshipType == "DESCRIBES" {
+				merged.DocumentDescribes = append(merged.DocumentDescribes, relationship.RelatedSPDXElement)
+			}
+			if !relationships[*relationship] {
+				relationships[*relationship] = true
+				merged.Relationships = append(merged.Relationships, relationship)
+			}
+		}
+		merged.DocumentDescribes = append(merged.DocumentDescribes, sbom.DocumentDescribes...)
+	}
+
+	return merged
+}
@@ -0,0 +1,73 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetSBOM(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/test/test/dependency-graph/sbom" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"sbom": {
+			"SPDXID": "SPDXRef-DOCUMENT", "spdxVersion": "SPDX-2.3", "name": "com.github.test/test",
+			"packages": [
+				{"SPDXID": "SPDXRef-github-test-test", "name": "com.github.test/test", "downloadLocation": "git+https://github.com/test/test"},
+				{"SPDXID": "SPDXRef-go-golang.org-x-sync-0.7.0", "name": "go:golang.org/x/sync", "versionInfo": "0.7.0", "downloadLocation": "NOASSERTION",
+				 "externalRefs": [{"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:golang/golang.org/x/sync@0.7.0"}]}
+			],
+			"relationships": [
+				{"spdxElementId": "SPDXRef-DOCUMENT", "relatedSpdxElement": "SPDXRef-github-test-test", "relationshipType": "DESCRIBES"},
+				{"spdxElementId": "SPDXRef-github-test-test", "relatedSpdxElement": "SPDXRef-go-golang.org-x-sync-0.7.0", "relationshipType": "DEPENDS_ON"}
+			]
+		}}`))
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL}
+	sbom, err := scanner.GetSBOM("test", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sbom.Packages) != 2 || sbom.Packages[1].ExternalRefs[0].ReferenceLocator != "pkg:golang/golang.org/x/sync@0.7.0" {
+		t.Fatalf("invalid sbom packages, expected 2 with the sync purl, got %v", sbom.Packages)
+	}
+
+	if _, err := scanner.GetSBOM("test", "missing"); err == nil {
+		t.Fatalf("sbom of a missing repository is expected to fail")
+	}
+}
+
+func TestMergeSBOMs(t *testing.T) {
+	shared := &SBOMPackage{SPDXID: "SPDXRef-shared", Name: "shared"}
+	sboms := []*SBOM{
+		{
+			Packages: []*SBOMPackage{{SPDXID: "SPDXRef-a", Name: "a"}, shared},
+			Relationships: []*SBOMRelationship{
+				{SPDXElementID: sbomDocumentID, RelatedSPDXElement: "SPDXRef-a", RelationshipType: "DESCRIBES"},
+				{SPDXElementID: "SPDXRef-a", RelatedSPDXElement: "SPDXRef-shared", RelationshipType: "DEPENDS_ON"},
+			},
+		},
+		{
+			Packages: []*SBOMPackage{{SPDXID: "SPDXRef-b", Name: "b"}, shared},
+			Relationships: []*SBOMRelationship{
+				{SPDXElementID: sbomDocumentID, RelatedSPDXElement: "SPDXRef-b", RelationshipType: "DESCRIBES"},
+				{SPDXElementID: "SPDXRef-b", RelatedSPDXElement: "SPDXRef-shared", RelationshipType: "DEPENDS_ON"},
+			},
+		},
+	}
+
+	merged := MergeSBOMs("test", sboms)
+	if len(merged.Packages) != 3 {
+		t.Fatalf("invalid merged packages count, expected 3, got %d", len(merged.Packages))
+	}
+	if !equal(merged.DocumentDescribes, []string{"SPDXRef-a", "SPDXRef-b"}) {
+		t.Fatalf("invalid described packages, expected [SPDXRef-a SPDXRef-b], got %v", merged.DocumentDescribes)
+	}
+	if len(merged.Relationships) != 4 {
+		t.Fatalf("invalid merged relationships count, expected 4, got %d", len(merged.Relationships))
+	}
+}
@@ -6,8 +6,17 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"runtime/pprof"
+	"slices"
 	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/errgroup"
 )
 
 const (
@@ -17,22 +26,185 @@ const (
 )
 
 type ResultItem struct {
-	Repository *Repository
-	Releases   []*Release
+	Repository *Repository `json:"repository"`
+	Releases   []*Release  `json:"releases"`
+	// Source is the backend the releases were fetched from when a ChainProvider is used.
+	Source string `json:"source,omitempty"`
+	// Unchanged is set when releases are not fetched as the repository was not pushed since Scanner.Since.
+	Unchanged bool `json:"unchanged,omitempty"`
+	// Contributors are only filled if contributors are scanned.
+	Contributors []*Contributor `json:"contributors,omitempty"`
+	// Warnings are the issues found by the results validation.
+	Warnings []string `json:"warnings,omitempty"`
+	// Annotations are the organizational metadata joined from Scanner.Annotations.
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Settings are only filled if settings are scanned.
+	Settings *RepositorySettings `json:"settings,omitempty"`
+	// Languages are bytes of code per language, only filled if languages are scanned.
+	Languages map[string]int64 `json:"languages,omitempty"`
+	// Branches are only filled if branches are scanned.
+	Branches []*Branch `json:"branches,omitempty"`
+	// Activity is only filled if issues are scanned.
+	Activity *Activity `json:"activity,omitempty"`
+	// WorkflowRuns are the latest runs of the default branch workflows, only filled if workflows are scanned.
+	WorkflowRuns []*WorkflowRun `json:"workflow_runs,omitempty"`
+	CI           *CIHealth      `json:"ci,omitempty"`
+	// Alerts are only filled if security alerts are scanned.
+	Alerts *SecurityAlerts `json:"alerts,omitempty"`
+	// Cadence is the release frequency, it is computed for JSON snapshots and reports.
+	Cadence *ReleaseCadence `json:"cadence,omitempty"`
+	// CommitActivity is only filled if commit activity is scanned and GitHub has computed it.
+	CommitActivity *CommitActivity `json:"commit_activity,omitempty"`
+	// Traffic is only filled if traffic is scanned and the token has push access to the repository.
+	Traffic *Traffic `json:"traffic,omitempty"`
+	// Teams are the organization teams with access to the repository, only filled if ownership is scanned.
+	Teams []*TeamAccess `json:"teams,omitempty"`
+	// GoModule is the module of the root go.mod with the module versions of the release tags, only filled if Go
+	// modules are scanned and the repository has a go.mod.
+	GoModule *GoModule `json:"go_module,omitempty"`
+	// VersionScheme is the name of the version scheme assigned to the repository by Scanner.VersionSchemes, empty
+	// if none is assigned and the versions are semver.
+	VersionScheme string `json:"version_scheme,omitempty"`
 }
 
 type Repository struct {
-	FullName string `json:"full_name"`
-	Name     string `json:"name"`
+	ID          int64  `json:"id"`
+	FullName    string `json:"full_name"`
+	Name        string `json:"name"`
+	Private     bool   `json:"private"`
+	Archived    bool   `json:"archived"`
+	Fork        bool   `json:"fork,omitempty"`
+	Stars       int    `json:"stargazers_count"`
+	Description string `json:"description,omitempty"`
+	// Language is the primary language of the repository code.
+	Language      string     `json:"language,omitempty"`
+	PushedAt      *time.Time `json:"pushed_at,omitempty"`
+	DefaultBranch string     `json:"default_branch,omitempty"`
+	// OpenIssues counts both open issues and pull requests.
+	OpenIssues int      `json:"open_issues_count,omitempty"`
+	Topics     []string `json:"topics,omitempty"`
+}
+
+type Contributor struct {
+	Login         string `json:"login"`
+	Contributions int    `json:"contributions"`
 }
 
 type Release struct {
-	Name string `json:"name"`
+	Name       string   `json:"name"`
+	TagName    string   `json:"tag_name"`
+	Draft      bool     `json:"draft"`
+	Prerelease bool     `json:"prerelease"`
+	Assets     []*Asset `json:"assets"`
+	// Body is the release notes.
+	Body string `json:"body,omitempty"`
+	// PublishedAt is nil for draft releases.
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	// HTMLURL is the release page, it is empty if the provider does not report it.
+	HTMLURL string `json:"html_url,omitempty"`
+	// Verification is the signature verification status, it is only set by scanners verifying signatures.
+	Verification *ReleaseVerification `json:"verification,omitempty"`
+}
+
+type Asset struct {
+	// URL is the API url of the asset, it allows downloading assets of private repositories.
+	URL                string `json:"url"`
+	Name               string `json:"name"`
+	ContentType        string `json:"content_type"`
+	Size               int64  `json:"size"`
+	DownloadCount      int    `json:"download_count"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+	// Digest is the asset checksum in the "sha256:<hex>" format if GitHub computed it.
+	Digest string `json:"digest,omitempty"`
 }
 
 type Scanner struct {
 	BaseUrl string
 	PerPage int
+	// Token is sent as a bearer token. Anonymous requests are made if it is empty.
+	Token string
+	// TokenSource provides the tokens instead of Token, e.g. short-lived GitHub App installation tokens. Sources
+	// requesting new tokens on every call should be wrapped with ReuseTokenSource.
+	TokenSource TokenSource
+	// Client performs the API requests, http.DefaultClient is used if it is nil.
+	Client *http.Client
+	// RequestTimeout bounds every API request including the read of its response body, so a hung connection
+	// fails the request with ErrRequestTimeout instead of stalling the scan. Requests are not bounded if it is 0.
+	RequestTimeout time.Duration
+	// Logger receives debug logs of API calls and rate-limit state. Logging is disabled if it is nil.
+	Logger *slog.Logger
+	// TracerProvider is used to trace scans, page fetches and workers. Tracing is disabled if it is nil.
+	TracerProvider TracerProvider
+	// VersionSchemes assigns version schemes to repositories, the first matching rule wins. Semver is used by default.
+	VersionSchemes []VersionSchemeRule
+	// Provider lists repositories and releases. The scanner itself is used for GitHub if it is nil.
+	Provider Provider
+	// Annotations are joined into the scanned items.
+	Annotations Annotations
+	// ScanSettings enables capturing of GitHub repository settings with every scanned repository.
+	ScanSettings bool
+	// ScanContributors enables capturing of contributors with every scanned repository.
+	ScanContributors bool
+	// ScanLanguages enables capturing of the language breakdown with every scanned repository.
+	ScanLanguages bool
+	// ScanBranches enables capturing of branches with every scanned repository.
+	ScanBranches bool
+	// ScanIssues enables capturing of open issue and pull request counts with every scanned repository.
+	ScanIssues bool
+	// ScanIssueLists captures the full lists of open issues and pull requests instead of the counts.
+	ScanIssueLists bool
+	// ScanWorkflows enables capturing of the latest GitHub Actions runs with every scanned repository.
+	ScanWorkflows bool
+	// ScanAlerts are the alert sources whose open alert counts are captured with every scanned repository,
+	// see AlertSources.
+	ScanAlerts []string
+	// ScanTraffic enables capturing of the 14-day views and clones with every scanned repository.
+	ScanTraffic bool
+	// ScanCommitActivity enables capturing of the weekly commit counts of the last year with every scanned repository.
+	ScanCommitActivity bool
+	// ScanOwnership enables mapping of scanned organization repositories to the teams with access to them.
+	ScanOwnership bool
+	// ScanGoModules enables detection of the Go module of every scanned repository from its root go.mod.
+	ScanGoModules bool
+	// VerifySignatures enables verification of the signature and attestation assets of every scanned release with
+	// the SignatureVerifiers, signed assets are downloaded for it.
+	VerifySignatures bool
+	// SignatureVerifiers check the signatures of release assets, see SignatureVerifier.
+	SignatureVerifiers []SignatureVerifier
+	// Since makes scans incremental: releases of repositories not pushed since the time are not fetched, and only
+	// releases published after it are kept. Everything is scanned if it is zero. GitHub lists releases newest
+	// first, so the pages of releases older than Since or the start of the ReleaseWindow are not fetched.
+	Since time.Time
+	// ReleaseWindow keeps only the releases published within it.
+	ReleaseWindow ReleaseWindow
+	// Ignore skips the matching repositories of scans before their releases are fetched.
+	Ignore IgnoreList
+	// MaxRepositories bounds scans to the most recently pushed repositories, e.g. exploratory scans of huge
+	// accounts. The repositories are not limited if it is 0.
+	MaxRepositories int
+	// MaxReleases keeps only the most recent releases of every repository within the ReleaseWindow. GitHub lists
+	// releases newest first, so the pages after the limit are not fetched. The releases are not limited if it is 0.
+	MaxReleases int
+	// CheckBudget makes scans fail with ErrInsufficientBudget before fetching releases if the remaining rate limit
+	// is lower than the estimated requests of the scan, see EstimateScan.
+	CheckBudget bool
+	// ScanDeadline fails the scans still running at the time with ErrDeadlineExceeded, e.g. to bound scheduled
+	// jobs. Scans are not bounded if it is zero.
+	ScanDeadline time.Time
+	// Checkpoint records the scan progress, so an interrupted scan is resumed with the recorded repositories and
+	// items instead of fetching them again. Progress is not recorded if it is nil.
+	Checkpoint *Checkpoint
+	// OnProgress is called after each repository of a scan is scanned with the count of scanned repositories
+	// and the total count. Calls are not concurrent.
+	OnProgress func(done, total int, repository string)
+
+	rateLimit    atomic.Pointer[RateLimit]
+	requests     atomic.Int64
+	pause        pause
+	budget       budget
+	workers      workers
+	interruption interruption
+	middleware   []RequestMiddleware
 }
 
 func GetDefaultScanner() *Scanner {
@@ -43,41 +215,177 @@ func GetDefaultScanner() *Scanner {
 }
 
 func (s *Scanner) ScanRepositories(user string) (items []*ResultItem, err error) {
-	repositories, err := s.GetAllRepositories(user)
+	return s.ScanRepositoriesContext(context.Background(), user)
+}
+
+// ScanRepositoriesContext scans the repositories of the account like ScanRepositories, but stops the scan with the
+// context error once the context is done.
+func (s *Scanner) ScanRepositoriesContext(ctx context.Context, user string) ([]*ResultItem, error) {
+	return s.scan(ctx, user, "ScanRepositories", s.getProvider().ListRepositories)
+}
+
+// StreamRepositories scans the repositories of the account like ScanRepositories, but passes every item to the
+// handle function as soon as its releases are fetched instead of collecting them. Items are passed one at a time
+// in no particular order. The scan is stopped if the handle function returns an error.
+func (s *Scanner) StreamRepositories(user string, handle func(item *ResultItem) error) error {
+	return s.stream(context.Background(), user, "StreamRepositories", s.getProvider().ListRepositories, handle, s.reportProgress)
+}
+
+// scan fetches releases of the repositories returned by the list function. An interrupted scan returns the items
+// scanned before together with ErrInterrupted.
+func (s *Scanner) scan(ctx context.Context, user, spanName string, list func(ctx context.Context, user string) ([]*Repository, error)) ([]*ResultItem, error) {
+	var items []*ResultItem
+	err := s.stream(ctx, user, spanName, list, func(item *ResultItem) error {
+		items = append(items, item)
+		return nil
+	}, s.reportProgress)
+	if err != nil && !errors.Is(err, ErrInterrupted) {
+		return nil, err
+	}
+	SortResults(items)
+
+	return items, err
+}
+
+// stream fetches releases of the repositories returned by the list function and passes the items to handle. The
+// progress function is called after every handled item.
+func (s *Scanner) stream(ctx context.Context, user, spanName string, list func(ctx context.Context, user string) ([]*Repository, error), handle func(item *ResultItem) error, progress func(done, total int, repository string)) (err error) {
+	ctx, span := s.getTracer().Start(ctx, spanName, StringAttribute("account", user))
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+		}
+		span.End()
+	}()
+	if !s.ScanDeadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadlineCause(ctx, s.ScanDeadline, ErrDeadlineExceeded)
+		defer cancel()
+		defer func() {
+			if err != nil && context.Cause(ctx) == ErrDeadlineExceeded && !errors.Is(err, ErrDeadlineExceeded) {
+				err = fmt.Errorf("%w at %s: %w", ErrDeadlineExceeded, s.ScanDeadline.Format(time.RFC3339), err)
+			}
+		}()
+	}
+
+	if s.interruption.interrupted() {
+		err = fmt.Errorf("could not scan the account %s: %w", user, ErrInterrupted)
+		return
+	}
+	scan := spanName + ":" + user
+	repositories, err := s.listRepositories(ctx, scan, user, list)
 	if err != nil {
 		return
 	}
+	if kept := s.Ignore.Filter(repositories); len(kept) < len(repositories) {
+		s.getLogger().Debug("repositories are ignored", "account", user, "count", len(repositories)-len(kept))
+		repositories = kept
+	}
+	repositories = s.limitRepositories(repositories)
+	// requests of the repositories are released from the budget as they are scanned, the rest once the scan ends
+	var reserved, released atomic.Int64
+	if s.CheckBudget {
+		var requests int
+		if requests, err = s.checkBudget(user, repositories); err != nil {
+			return
+		}
+		reserved.Store(int64(requests))
+		defer func() {
+			s.budget.release(int(reserved.Load() - released.Load()))
+		}()
+	}
+	if s.ScanOwnership {
+		var ownership map[string][]*TeamAccess
+		if ownership, err = s.getOwnership(ctx, user); err != nil {
+			return
+		}
+		next := handle
+		handle = func(item *ResultItem) error {
+			item.Teams = ownership[item.Repository.FullName]
+			return next(item)
+		}
+	}
+
+	// Repositories are handled in the output order, results of workers that complete ahead of it are held back.
+	repositories = slices.Clone(repositories)
+	sort.SliceStable(repositories, func(i, j int) bool {
+		return repositories[i].FullName < repositories[j].FullName
+	})
+	// Providers listing releases in batches fetch them in the output order as the workers reach the repositories.
+	batches := newReleaseBatches(s.getProvider(), repositories, func(repository *Repository) bool {
+		return !s.unchangedSince(repository) && s.Checkpoint.Item(scan, repository.FullName) == nil
+	})
 
 	jobsCount := len(repositories)
 	jobs := make(chan *Repository, jobsCount)
-	results := make(chan *ResultItem, jobsCount)
-	errors := make(chan error)
-	ctx, cancel := context.WithCancel(context.Background())
-	defer func() {
-		cancel()
-		close(results)
-		close(errors)
-	}()
+	for _, repository := range repositories {
+		jobs <- repository
+	}
+	close(jobs)
 
-	worker := func(jobs <-chan *Repository, results chan<- *ResultItem, errors chan<- error) {
+	// Workers stop once the jobs are done, a repository fails or the scan is interrupted. The results are closed
+	// when all of them return, so the scan never ends with workers running. The first failure cancels the other
+	// repositories, the failures of the ones scanned meanwhile are reported together.
+	results := make(chan *ResultItem, jobsCount)
+	scanCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	group, scanCtx := errgroup.WithContext(scanCtx)
+	var mu sync.Mutex
+	var failures []error
+	interrupted := s.interruption.channel()
+	run := runResultsFrom(ctx)
+	worker := func() error {
 		for repository := range jobs {
 			select {
-			case <-ctx.Done():
-				return
+			case <-scanCtx.Done():
+				return nil
+			case <-interrupted:
+				return nil
 			default:
 			}
-			releases, err := s.GetAllReleases(user, repository.Name)
-			if err != nil {
-				errors <- err
-				cancel()
-				return
+			if item := s.Checkpoint.Item(scan, repository.FullName); item != nil {
+				// The recorded item references the repository the collector waits for.
+				item.Repository = repository
+				results <- item
+				continue
+			}
+			var item *ResultItem
+			var err error
+			if result := run.claim(repository.FullName); result != nil {
+				s.getLogger().Debug("repository is scanned for another account of the run, its result is reused", "account", user, "repository", repository.FullName)
+				item, err = result.wait(scanCtx, repository)
+			} else {
+				// Workers of concurrent scans share the slots of the scanner.
+				if err = s.workers.acquire(scanCtx); err == nil {
+					// Labels attribute profile samples of huge scans to the account and repository being scanned.
+					pprof.Do(scanCtx, pprof.Labels("account", user, "repository", repository.FullName), func(ctx context.Context) {
+						item, err = s.scanRepository(ctx, user, repository, batches)
+					})
+					s.workers.release()
+				}
+				run.complete(repository.FullName, item, err)
+			}
+			if err == nil && s.CheckBudget {
+				requests := s.estimateRepositoryRequests(repository)
+				s.budget.release(requests)
+				released.Add(int64(requests))
 			}
-			item := &ResultItem{
-				Repository: repository,
-				Releases:   releases,
+			if err == nil && s.Checkpoint != nil {
+				err = s.Checkpoint.AddItem(scan, item)
+			}
+			if err != nil {
+				// errors caused by the end of the scan are not failures of the repository, the failure of a batch is
+				// reported for the repository it is fetched for
+				if (scanCtx.Err() == nil || !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)) && !errors.Is(err, errReleasesBatchFailed) {
+					mu.Lock()
+					failures = append(failures, fmt.Errorf("%s: %w", repository.FullName, err))
+					mu.Unlock()
+				}
+				return err
 			}
 			results <- item
 		}
+		return nil
 	}
 
 	workersCount := maxWorkersCount
@@ -85,125 +393,538 @@ func (s *Scanner) ScanRepositories(user string) (items []*ResultItem, err error)
 		workersCount = jobsCount
 	}
 	for i := 0; i < workersCount; i++ {
-		go worker(jobs, results, errors)
+		group.Go(worker)
 	}
+	go func() {
+		group.Wait()
+		close(results)
+	}()
 
-	for _, repository := range repositories {
-		jobs <- repository
+	pending := make(map[*Repository]*ResultItem)
+	next := 0
+	for item := range results {
+		// results of the workers are drained once the handle function fails
+		if err != nil {
+			continue
+		}
+		pending[item.Repository] = item
+		for ; next < jobsCount && pending[repositories[next]] != nil; next++ {
+			item := pending[repositories[next]]
+			delete(pending, repositories[next])
+			if err = s.emit(item, handle); err != nil {
+				cancel()
+				break
+			}
+			progress(next+1, jobsCount, item.Repository.FullName)
+		}
+	}
+	if err != nil {
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	if len(failures) > 0 {
+		err = fmt.Errorf("could not scan repositories of the account %s: %w", user, errors.Join(failures...))
+		return
 	}
-	close(jobs)
 
-	for i := 0; i < jobsCount; i++ {
-		select {
-		case err = <-errors:
-			err = fmt.Errorf("could not scan repository for the account %s: %v", user, err)
-			return
-		case item := <-results:
-			items = append(items, item)
+	// Workers stop taking repositories once the scan is interrupted, the completed ones are still emitted.
+	done := next
+	for _, repository := range repositories[next:] {
+		if item := pending[repository]; item != nil {
+			if err = s.emit(item, handle); err != nil {
+				return
+			}
+			done++
+			progress(done, jobsCount, repository.FullName)
 		}
 	}
-	s.sortResultItems(items)
+	if done < jobsCount {
+		err = fmt.Errorf("%w: %d of %d repositories of the account %s are scanned", ErrInterrupted, done, jobsCount, user)
+	}
 
 	return
 }
 
-func (s *Scanner) GetAllReleases(user, repository string) ([]*Release, error) {
-	var releases []*Release
-	page := 1
-	for {
-		releasesChunk, err := s.GetReleasesPerPage(user, repository, page)
-		if err != nil {
+// listRepositories lists the repositories of the scan, or returns the ones recorded in the checkpoint. The pages
+// of an interrupted listing recorded in the checkpoint are not fetched again.
+func (s *Scanner) listRepositories(ctx context.Context, scan, user string, list func(ctx context.Context, user string) ([]*Repository, error)) ([]*Repository, error) {
+	if repositories := s.Checkpoint.Repositories(scan); repositories != nil {
+		s.getLogger().Info("repositories are resumed from the checkpoint", "account", user, "count", len(repositories))
+		return repositories, nil
+	}
+	repositories, err := list(withPageCheckpoint(ctx, s.Checkpoint, scan), user)
+	if err != nil || s.Checkpoint == nil {
+		return repositories, err
+	}
+
+	return repositories, s.Checkpoint.AddRepositories(scan, repositories)
+}
+
+// limitRepositories returns the Scanner.MaxRepositories most recently pushed repositories in their order.
+// Repositories without a known push date are the least recent ones.
+func (s *Scanner) limitRepositories(repositories []*Repository) []*Repository {
+	if s.MaxRepositories <= 0 || len(repositories) <= s.MaxRepositories {
+		return repositories
+	}
+
+	recent := slices.Clone(repositories)
+	sort.SliceStable(recent, func(i, j int) bool {
+		a, b := recent[i].PushedAt, recent[j].PushedAt
+		return a != nil && (b == nil || a.After(*b))
+	})
+	kept := make(map[*Repository]bool, s.MaxRepositories)
+	for _, repository := range recent[:s.MaxRepositories] {
+		kept[repository] = true
+	}
+
+	return slices.DeleteFunc(slices.Clone(repositories), func(repository *Repository) bool {
+		return !kept[repository]
+	})
+}
+
+func (s *Scanner) emit(item *ResultItem, handle func(*ResultItem) error) error {
+	if scheme := s.matchVersionScheme(item.Repository.FullName); scheme != nil {
+		item.VersionScheme = scheme.Name()
+	}
+	sortReleases(item.Releases, assignedVersionScheme(item))
+	if s.MaxReleases > 0 && len(item.Releases) > s.MaxReleases {
+		item.Releases = item.Releases[:s.MaxReleases]
+	}
+	item.Annotations = s.Annotations.Get(item.Repository.FullName)
+
+	return handle(item)
+}
+
+// scanRepository fetches the releases and the enabled data of the repository. Releases are looked up in the batches
+// if they are not nil.
+func (s *Scanner) scanRepository(ctx context.Context, user string, repository *Repository, batches *releaseBatches) (*ResultItem, error) {
+	ctx, span := s.getTracer().Start(ctx, "scanRepository", StringAttribute("account", user), StringAttribute("repository", repository.Name))
+	defer span.End()
+
+	var (
+		releases []*Release
+		source   string
+		err      error
+	)
+	owner := repositoryOwner(repository, user)
+	if s.unchangedSince(repository) {
+		item := &ResultItem{Repository: repository, Unchanged: true}
+		if err := s.enrich(ctx, owner, item); err != nil {
+			span.RecordError(err)
 			return nil, err
 		}
-		releases = append(releases, releasesChunk...)
-		if len(releasesChunk) < s.getPerPage() {
-			break
+		return item, nil
+	}
+	if batches != nil {
+		releases, source, err = batches.releases(ctx, user, repository)
+	} else if chain, ok := s.getProvider().(*ChainProvider); ok {
+		releases, source, err = chain.listReleasesWithSource(ctx, owner, repository.Name)
+	} else {
+		releases, err = s.getProvider().ListReleases(ctx, owner, repository.Name)
+	}
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	item := &ResultItem{
+		Repository: repository,
+		Releases:   s.filterReleases(releases),
+		Source:     source,
+	}
+	if err := s.enrich(ctx, owner, item); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// unchangedSince reports whether the repository was not pushed since Scanner.Since. Repositories without a known
+// push date are considered changed.
+func (s *Scanner) unchangedSince(repository *Repository) bool {
+	return !s.Since.IsZero() && repository.PushedAt != nil && repository.PushedAt.Before(s.Since)
+}
+
+// filterReleases keeps the releases published after Scanner.Since and within Scanner.ReleaseWindow. Drafts are
+// kept since the cutoff, they are not published yet.
+func (s *Scanner) filterReleases(releases []*Release) []*Release {
+	if s.Since.IsZero() {
+		return s.ReleaseWindow.Filter(releases)
+	}
+
+	var kept []*Release
+	for _, release := range releases {
+		if release.PublishedAt == nil || release.PublishedAt.After(s.Since) {
+			kept = append(kept, release)
 		}
-		page++
+	}
+
+	return s.ReleaseWindow.Filter(kept)
+}
+
+// releasesCutoff returns the time releases published before are not kept: the later of Scanner.Since and the
+// start of Scanner.ReleaseWindow, zero if neither is set.
+func (s *Scanner) releasesCutoff() time.Time {
+	if s.Since.After(s.ReleaseWindow.After) {
+		return s.Since
+	}
+
+	return s.ReleaseWindow.After
+}
+
+// releasesPassed reports whether the releases listed newest first reached the releases published before the
+// cutoff, so no later page has releases the scan keeps. Drafts are listed first and are skipped.
+func (s *Scanner) releasesPassed(releases []*Release) bool {
+	cutoff := s.releasesCutoff()
+	if cutoff.IsZero() {
+		return false
+	}
+	for i := len(releases) - 1; i >= 0; i-- {
+		if releases[i].PublishedAt != nil {
+			return releases[i].PublishedAt.Before(cutoff)
+		}
+	}
+
+	return false
+}
+
+// enrich fetches the optional repository data enabled in the scanner.
+func (s *Scanner) enrich(ctx context.Context, owner string, item *ResultItem) error {
+	var err error
+	if s.ScanSettings {
+		if item.Settings, err = s.getRepositorySettings(ctx, owner, item.Repository.Name); err != nil {
+			return err
+		}
+	}
+	if s.ScanContributors {
+		if item.Contributors, err = s.getAllContributors(ctx, owner, item.Repository.Name); err != nil {
+			return err
+		}
+	}
+	if s.ScanLanguages {
+		if item.Languages, err = s.getLanguages(ctx, owner, item.Repository.Name); err != nil {
+			return err
+		}
+	}
+	if s.ScanBranches {
+		if item.Branches, err = s.getAllBranches(ctx, owner, item.Repository.Name); err != nil {
+			return err
+		}
+	}
+	if s.ScanIssues || s.ScanIssueLists {
+		if item.Activity, err = s.getActivity(ctx, owner, item.Repository); err != nil {
+			return err
+		}
+	}
+	if s.ScanWorkflows {
+		if item.WorkflowRuns, err = s.getWorkflowRuns(ctx, owner, item.Repository.Name, item.Repository.DefaultBranch); err != nil {
+			return err
+		}
+		item.CI = NewCIHealth(item.WorkflowRuns)
+	}
+	if len(s.ScanAlerts) > 0 {
+		if item.Alerts, err = s.getSecurityAlerts(ctx, owner, item.Repository.Name); err != nil {
+			return err
+		}
+	}
+	if s.ScanCommitActivity {
+		item.CommitActivity, err = s.getCommitActivity(ctx, owner, item.Repository.Name)
+		if errors.Is(err, errStatsPending) {
+			s.getLogger().Warn("commit activity skipped: statistics are still being computed", "repository", item.Repository.FullName)
+		} else if err != nil {
+			return err
+		}
+	}
+	if s.ScanTraffic {
+		item.Traffic, err = s.getTraffic(ctx, owner, item.Repository.Name)
+		if errors.Is(err, errTrafficUnavailable) {
+			s.getLogger().Warn("traffic skipped: token has no push access", "repository", item.Repository.FullName)
+		} else if err != nil {
+			return err
+		}
+	}
+	if s.ScanGoModules {
+		if item.GoModule, err = s.getGoModule(ctx, owner, item.Repository.Name); err != nil {
+			return err
+		}
+		if item.GoModule != nil && item.GoModule.Problem == "" {
+			item.GoModule.mapVersions(item.Releases)
+		}
+	}
+	if s.VerifySignatures {
+		for _, release := range item.Releases {
+			if release.Verification, err = s.VerifyRelease(ctx, release); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Scanner) GetAllReleases(user, repository string) ([]*Release, error) {
+	return s.getAllReleases(context.Background(), user, repository)
+}
+
+func (s *Scanner) getAllReleases(ctx context.Context, user, repository string) ([]*Release, error) {
+	fetch := func(ctx context.Context, page int) ([]*Release, pageLinks, error) {
+		releasesChunk, links, err := s.getReleasesPerPage(ctx, user, repository, page)
+		if err == nil {
+			s.getLogger().Debug("releases page fetched", "account", user, "repository", repository, "page", page, "count", len(releasesChunk))
+		}
+		return releasesChunk, links, err
+	}
+	if s.MaxReleases <= 0 && s.releasesCutoff().IsZero() {
+		return paginate(ctx, fetch)
+	}
+
+	// releases are listed newest first, the pages after the limit or the cutoff are not needed
+	releases, err := paginateUntil(ctx, fetch, func(releases []*Release) bool {
+		return s.MaxReleases > 0 && len(s.filterReleases(releases)) >= s.MaxReleases || s.releasesPassed(releases)
+	})
+	if err != nil {
+		return nil, err
+	}
+	releases = s.filterReleases(releases)
+	if s.MaxReleases > 0 && len(releases) > s.MaxReleases {
+		releases = releases[:s.MaxReleases]
 	}
 
 	return releases, nil
 }
 
 func (s *Scanner) GetReleasesPerPage(user, repository string, page int) ([]*Release, error) {
+	releases, _, err := s.getReleasesPerPage(context.Background(), user, repository, page)
+
+	return releases, err
+}
+
+func (s *Scanner) getReleasesPerPage(ctx context.Context, user, repository string, page int) ([]*Release, pageLinks, error) {
 	if err := s.checkPage(page); err != nil {
-		return nil, err
+		return nil, pageLinks{}, err
 	}
 	if err := s.checkUser(user); err != nil {
-		return nil, err
+		return nil, pageLinks{}, err
 	}
 	if err := s.checkRepository(repository); err != nil {
-		return nil, err
+		return nil, pageLinks{}, err
 	}
-	response, err := http.Get(fmt.Sprintf("%s/repos/%s/%s/releases?per_page=%d&page=%d", s.BaseUrl, user, repository, s.getPerPage(), page))
+	ctx, span := s.getTracer().Start(ctx, "GetReleasesPerPage", StringAttribute("account", user), StringAttribute("repository", repository), IntAttribute("page", page))
+	defer span.End()
+
+	response, err := s.get(ctx, span, fmt.Sprintf("%s/repos/%s/%s/releases?per_page=%d&page=%d", s.BaseUrl, user, repository, s.getPerPage(), page))
 	if err != nil {
-		return nil, err
+		return nil, pageLinks{}, err
 	}
 	defer response.Body.Close()
 
 	if response.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("could not get releases for the repository %s: %s", repository, s.getApiErrorMessage(response.Body, response.Status))
+		return nil, pageLinks{}, fmt.Errorf("could not get releases for the repository %s: %w", repository, s.newApiError(response))
 	}
 
 	var releases []*Release
 	if err := json.NewDecoder(response.Body).Decode(&releases); err != nil {
-		return nil, err
+		return nil, pageLinks{}, err
 	}
 
-	return releases, nil
+	return releases, parsePageLinks(response.Header), nil
 }
 
 func (s *Scanner) GetAllRepositories(user string) ([]*Repository, error) {
-	var repositories []*Repository
-	page := 1
-	for {
-		repositoriesChunk, err := s.GetRepositoriesPerPage(user, page)
-		if err != nil {
-			return nil, err
-		}
-		repositories = append(repositories, repositoriesChunk...)
-		if len(repositoriesChunk) < s.getPerPage() {
-			break
-		}
-		page++
-	}
+	return s.getAllRepositories(context.Background(), user)
+}
 
-	return repositories, nil
+func (s *Scanner) getAllRepositories(ctx context.Context, user string) ([]*Repository, error) {
+	return paginate(ctx, func(ctx context.Context, page int) ([]*Repository, pageLinks, error) {
+		repositoriesChunk, links, err := s.getRepositoriesPerPage(ctx, user, page)
+		if err == nil {
+			s.getLogger().Debug("repositories page fetched", "account", user, "page", page, "count", len(repositoriesChunk))
+		}
+		return repositoriesChunk, links, err
+	})
 }
 
 func (s *Scanner) GetRepositoriesPerPage(user string, page int) ([]*Repository, error) {
+	repositories, _, err := s.getRepositoriesPerPage(context.Background(), user, page)
+
+	return repositories, err
+}
+
+func (s *Scanner) getRepositoriesPerPage(ctx context.Context, user string, page int) ([]*Repository, pageLinks, error) {
 	if err := s.checkPage(page); err != nil {
-		return nil, err
+		return nil, pageLinks{}, err
 	}
 	if err := s.checkUser(user); err != nil {
-		return nil, err
+		return nil, pageLinks{}, err
 	}
-	response, err := http.Get(fmt.Sprintf("%s/users/%s/repos?per_page=%d&page=%d", s.BaseUrl, user, s.getPerPage(), page))
+	ctx, span := s.getTracer().Start(ctx, "GetRepositoriesPerPage", StringAttribute("account", user), IntAttribute("page", page))
+	defer span.End()
+
+	response, err := s.get(ctx, span, fmt.Sprintf("%s/users/%s/repos?per_page=%d&page=%d", s.BaseUrl, user, s.getPerPage(), page))
 	if err != nil {
-		return nil, err
+		return nil, pageLinks{}, err
 	}
 	defer response.Body.Close()
 
 	if response.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("account %s does not exist", user)
+		return nil, pageLinks{}, newNotFoundError("account %s does not exist", user)
 	}
 
 	if response.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("could not get repositories for the account %s: %s", user, s.getApiErrorMessage(response.Body, response.Status))
+		return nil, pageLinks{}, fmt.Errorf("could not get repositories for the account %s: %w", user, s.newApiError(response))
 	}
 
 	var repositories []*Repository
 	if err := json.NewDecoder(response.Body).Decode(&repositories); err != nil {
+		return nil, pageLinks{}, err
+	}
+
+	return repositories, parsePageLinks(response.Header), nil
+}
+
+// get performs the API request. Requests hitting the secondary rate limit pause the requests of all workers and
+// are retried, see secondaryRateLimit.
+func (s *Scanner) get(ctx context.Context, span Span, url string) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if err := s.pause.wait(ctx); err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+		response, err := s.do(ctx, span, url)
+		if err != nil {
+			return nil, err
+		}
+		delay, limited := secondaryRateLimit(response)
+		if !limited || attempt >= secondaryRateLimitRetries {
+			return response, nil
+		}
+		response.Body.Close()
+		s.pause.extend(delay)
+		s.getLogger().Warn("secondary rate limit exceeded, requests are paused", "url", url, "delay", delay, "attempt", attempt+1)
+	}
+}
+
+// do performs the API request within Scanner.RequestTimeout. The timeout ends once the response body is closed.
+func (s *Scanner) do(ctx context.Context, span Span, url string) (*http.Response, error) {
+	if s.RequestTimeout <= 0 {
+		return s.send(ctx, span, url)
+	}
+	ctx, cancel := context.WithTimeoutCause(ctx, s.RequestTimeout, ErrRequestTimeout)
+	response, err := s.send(ctx, span, url)
+	if err != nil {
+		err = timeoutError(ctx, url, err)
+		cancel()
 		return nil, err
 	}
+	response.Body = &timeoutBody{ReadCloser: response.Body, ctx: ctx, url: url, cancel: cancel}
+
+	return response, nil
+}
+
+// timeoutBody is the response body of a request with a timeout, reads fail with ErrRequestTimeout once it expires.
+type timeoutBody struct {
+	io.ReadCloser
+	ctx    context.Context
+	url    string
+	cancel context.CancelFunc
+}
+
+func (b *timeoutBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF {
+		err = timeoutError(b.ctx, b.url, err)
+	}
+
+	return n, err
+}
+
+func (b *timeoutBody) Close() error {
+	defer b.cancel()
+
+	return b.ReadCloser.Close()
+}
+
+// timeoutError classifies the error of the request as ErrRequestTimeout if its timeout expired.
+func timeoutError(ctx context.Context, url string, err error) error {
+	if context.Cause(ctx) != ErrRequestTimeout {
+		return err
+	}
 
-	return repositories, nil
+	return fmt.Errorf("%w: %s: %w", ErrRequestTimeout, url, err)
 }
 
-func (s *Scanner) sortResultItems(items []*ResultItem) {
-	sort.SliceStable(items, func(i, j int) bool {
-		return items[i].Repository.FullName < items[j].Repository.FullName
+func (s *Scanner) send(ctx context.Context, span Span, url string) (*http.Response, error) {
+	logger := s.getLogger()
+	logger.Debug("api request", "url", url)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	token, err := s.token(ctx)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	start := time.Now()
+	var response *http.Response
+	pprof.Do(ctx, pprof.Labels("endpoint", endpointLabel(request.URL.Path)), func(context.Context) {
+		response, err = s.roundTrip(request)
 	})
+	if err != nil {
+		logger.Debug("api request failed", "url", url, "error", err)
+		span.RecordError(err)
+		return nil, wrapTransportError(err)
+	}
+	span.SetAttributes(IntAttribute("http.status_code", response.StatusCode))
+	s.updateRateLimit(response.Header)
+
+	logger.Debug(
+		"api response",
+		"url", url,
+		"status", response.StatusCode,
+		"duration", time.Since(start),
+		"rate_limit_remaining", response.Header.Get("X-RateLimit-Remaining"),
+		"rate_limit_reset", response.Header.Get("X-RateLimit-Reset"),
+	)
+
+	return response, nil
+}
+
+// endpointLabel returns the API endpoint of the path with account and repository names replaced by placeholders,
+// so the profile label has a bounded number of values.
+func endpointLabel(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 0; i < len(segments); i++ {
+		switch segments[i] {
+		case "users", "orgs":
+			if i+1 < len(segments) {
+				segments[i+1] = "{owner}"
+				i++
+			}
+		case "repos":
+			if i+2 < len(segments) {
+				segments[i+1], segments[i+2] = "{owner}", "{repo}"
+				i += 2
+			}
+		}
+	}
+
+	return "/" + strings.Join(segments, "/")
+}
+
+func (s *Scanner) reportProgress(done, total int, repository string) {
+	if s.OnProgress != nil {
+		s.OnProgress(done, total, repository)
+	}
 }
 
 func (s *Scanner) getPerPage() int {
@@ -214,6 +935,22 @@ func (s *Scanner) getPerPage() int {
 	return s.PerPage
 }
 
+func (s *Scanner) getClient() *http.Client {
+	if s.Client == nil {
+		return http.DefaultClient
+	}
+
+	return s.Client
+}
+
+func (s *Scanner) getLogger() *slog.Logger {
+	if s.Logger == nil {
+		return slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+
+	return s.Logger
+}
+
 func (s *Scanner) checkPage(page int) error {
 	if page < 1 {
 		return errors.New("page could not be less than 1")
@@ -238,6 +975,10 @@ func (s *Scanner) checkRepository(repository string) error {
 	return nil
 }
 
+func (s *Scanner) newApiError(response *http.Response) *APIError {
+	return newApiError(response, s.getApiErrorMessage(response.Body, response.Status))
+}
+
 func (s *Scanner) getApiErrorMessage(reader io.Reader, defaultMessage string) string {
 	mBytes, err := io.ReadAll(reader)
 	if err != nil {
@@ -1,16 +1,27 @@
 package scanner
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestGetAllRepositoriesSuccess(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path == "/users/test/repos" {
 			page := r.URL.Query().Get("page")
+			if page == "1" {
+				setPageLinks(w, r, 2, 2)
+			}
 			w.WriteHeader(http.StatusOK)
 
 			if page == "1" {
@@ -49,6 +60,30 @@ func TestGetAllRepositoriesSuccess(t *testing.T) {
 	}
 }
 
+func TestGetAllRepositoriesInventory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"full_name": "test/repo1", "name": "repo1", "stargazers_count": 42, "language": "Go", "pushed_at": "2024-03-01T10:00:00Z", "archived": true}]`))
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL}
+	repositories, err := scanner.GetAllRepositories("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repositories) != 1 {
+		t.Fatalf("invalid repositories count, expected 1, got %d", len(repositories))
+	}
+
+	repository := repositories[0]
+	if repository.Stars != 42 || repository.Language != "Go" || !repository.Archived {
+		t.Fatalf("invalid repository inventory, expected 42 stars, Go, archived, got %d, %s, %v", repository.Stars, repository.Language, repository.Archived)
+	}
+	if repository.PushedAt == nil || repository.PushedAt.Format(time.DateOnly) != "2024-03-01" {
+		t.Fatalf("invalid repository last push, expected 2024-03-01, got %v", repository.PushedAt)
+	}
+}
+
 func TestGetAllRepositoriesError(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path == "/users/test/repos" {
@@ -75,6 +110,9 @@ func TestGetAllReleasesSuccess(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path == "/repos/test/test/releases" {
 			page := r.URL.Query().Get("page")
+			if page == "1" {
+				setPageLinks(w, r, 2, 2)
+			}
 			w.WriteHeader(http.StatusOK)
 
 			if page == "1" {
@@ -164,6 +202,86 @@ func TestScanRepositoriesError(t *testing.T) {
 	}
 }
 
+func TestScanRepositoriesErrors(t *testing.T) {
+	var failing sync.WaitGroup
+	failing.Add(2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/test/repos":
+			w.Write([]byte(`[{"full_name": "test/a", "name": "a"}, {"full_name": "test/b", "name": "b"}, {"full_name": "test/c", "name": "c"}]`))
+		case "/repos/test/a/releases", "/repos/test/b/releases":
+			// both repositories fail while the other one is being scanned
+			failing.Done()
+			failing.Wait()
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message": "forbidden"}`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL}
+	_, err := scanner.ScanRepositories("test")
+	if err == nil {
+		t.Fatal("invalid response for during scanning of repositories: error is expected")
+	}
+	for _, repository := range []string{"test/a", "test/b"} {
+		if !strings.Contains(err.Error(), repository) {
+			t.Fatalf("invalid error message, expected the failure of %s, got %s", repository, err.Error())
+		}
+	}
+	if len(scanner.workers.slots) != 0 {
+		t.Fatalf("invalid workers count after the scan, expected 0, got %d", len(scanner.workers.slots))
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/repos/test/body/releases" {
+			// the headers are sent, the body hangs
+			w.Write([]byte(`[`))
+			w.(http.Flusher).Flush()
+		}
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL, RequestTimeout: 50 * time.Millisecond}
+	for _, repository := range []string{"headers", "body"} {
+		if _, err := scanner.GetAllReleases("test", repository); !errors.Is(err, ErrRequestTimeout) {
+			t.Fatalf("invalid error of the hung %s, expected %v, got %v", repository, ErrRequestTimeout, err)
+		}
+	}
+}
+
+func TestScanDeadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/users/test/repos" {
+			w.Write([]byte(`[{"full_name": "test/a", "name": "a"}, {"full_name": "test/b", "name": "b"}]`))
+			return
+		}
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL, ScanDeadline: time.Now().Add(100 * time.Millisecond)}
+	_, err := scanner.ScanRepositories("test")
+	if !errors.Is(err, ErrDeadlineExceeded) {
+		t.Fatalf("invalid error, expected %v, got %v", ErrDeadlineExceeded, err)
+	}
+	// following scans fail right away
+	if _, err := scanner.ScanRepositories("test"); !errors.Is(err, ErrDeadlineExceeded) {
+		t.Fatalf("invalid error after the deadline, expected %v, got %v", ErrDeadlineExceeded, err)
+	}
+}
+
 func TestScanRepositoriesSuccess(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path == "/users/test/repos" {
@@ -209,6 +327,217 @@ func TestScanRepositoriesSuccess(t *testing.T) {
 	}
 }
 
+func TestScanRepositoriesSince(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/test/repos":
+			w.Write([]byte(`[
+				{"full_name": "test/active", "name": "active", "pushed_at": "2024-03-01T00:00:00Z"},
+				{"full_name": "test/idle", "name": "idle", "pushed_at": "2023-06-01T00:00:00Z"}
+			]`))
+		case "/repos/test/active/releases":
+			w.Write([]byte(`[
+				{"tag_name": "v1.2.0", "draft": true},
+				{"tag_name": "v1.1.0", "published_at": "2024-02-01T00:00:00Z"},
+				{"tag_name": "v1.0.0", "published_at": "2023-10-01T00:00:00Z"}
+			]`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL, PerPage: 100, Since: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	items, err := scanner.ScanRepositories("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tags []string
+	for _, release := range items[0].Releases {
+		tags = append(tags, release.TagName)
+	}
+	if items[0].Unchanged || !equal(tags, []string{"v1.2.0", "v1.1.0"}) {
+		t.Fatalf("invalid releases of the pushed repository, expected [v1.2.0 v1.1.0], got %v", tags)
+	}
+	if !items[1].Unchanged || items[1].Releases != nil {
+		t.Fatalf("invalid releases of the repository not pushed since the cutoff, expected none, got %v", items[1].Releases)
+	}
+}
+
+func TestGetAllReleasesSince(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page < 10 {
+			setPageLinks(w, r, page+1, 10)
+		}
+		if page == 1 {
+			w.Write([]byte(`[{"tag_name": "v2.0.0", "draft": true}, {"tag_name": "v1.9.0", "published_at": "2024-03-01T00:00:00Z"}]`))
+			return
+		}
+		fmt.Fprintf(w, `[{"tag_name": "v1.%d.0", "published_at": "2023-%02d-01T00:00:00Z"}]`, 10-page, 12-page)
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL, PerPage: 2, Since: time.Date(2023, 9, 15, 0, 0, 0, 0, time.UTC)}
+	releases, err := scanner.GetAllReleases("test", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tags []string
+	for _, release := range releases {
+		tags = append(tags, release.TagName)
+	}
+	if !equal(tags, []string{"v2.0.0", "v1.9.0", "v1.8.0"}) {
+		t.Fatalf("invalid releases since the cutoff, expected [v2.0.0 v1.9.0 v1.8.0], got %v", tags)
+	}
+	// the page of the first release before the cutoff is the last one fetched
+	if requests != 3 {
+		t.Fatalf("invalid requests count, expected 3, got %d", requests)
+	}
+}
+
+func TestScanRepositoriesMax(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/test/repos":
+			w.Write([]byte(`[
+				{"full_name": "test/old", "name": "old", "pushed_at": "2023-06-01T00:00:00Z"},
+				{"full_name": "test/recent", "name": "recent", "pushed_at": "2024-03-01T00:00:00Z"},
+				{"full_name": "test/unknown", "name": "unknown"},
+				{"full_name": "test/active", "name": "active", "pushed_at": "2024-02-01T00:00:00Z"}
+			]`))
+		case "/repos/test/recent/releases", "/repos/test/active/releases":
+			w.Write([]byte(`[
+				{"tag_name": "v1.2.0", "published_at": "2024-01-03T00:00:00Z"},
+				{"tag_name": "v1.1.0", "published_at": "2024-01-02T00:00:00Z"},
+				{"tag_name": "v1.0.0", "published_at": "2024-01-01T00:00:00Z"}
+			]`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL, PerPage: 100, MaxRepositories: 2, MaxReleases: 2}
+	items, err := scanner.ScanRepositories("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 2 || items[0].Repository.Name != "active" || items[1].Repository.Name != "recent" {
+		t.Fatalf("invalid scanned repositories, expected the most recently pushed active and recent, got %v", items)
+	}
+	for _, item := range items {
+		if len(item.Releases) != 2 || item.Releases[1].TagName != "v1.1.0" {
+			t.Fatalf("invalid releases of %s, expected v1.2.0 and v1.1.0, got %d", item.Repository.Name, len(item.Releases))
+		}
+	}
+	var repositories []*Repository
+	for i := range 150 {
+		repositories = append(repositories, &Repository{FullName: fmt.Sprintf("test/%d", i)})
+	}
+	if estimate := scanner.EstimateScan(repositories); estimate.Repositories != 2 || estimate.ListRequests != 2 {
+		t.Fatalf("invalid estimate, expected 2 repositories of 2 list requests, got %+v", estimate)
+	}
+}
+
+func TestStreamRepositories(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/test/repos":
+			w.Write([]byte(`[{"full_name": "test/a", "name": "a"}, {"full_name": "test/b", "name": "b"}]`))
+		case "/repos/test/a/releases", "/repos/test/b/releases":
+			w.Write([]byte(`[{"name": "test"}]`))
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL, PerPage: 100}
+	var names []string
+	err := scanner.StreamRepositories("test", func(item *ResultItem) error {
+		names = append(names, item.Repository.FullName)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(names)
+	if !equal(names, []string{"test/a", "test/b"}) {
+		t.Fatalf("invalid streamed repositories, expected [test/a test/b], got %v", names)
+	}
+
+	stop := errors.New("stop")
+	err = scanner.StreamRepositories("test", func(item *ResultItem) error {
+		return stop
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("invalid error, expected %v, got %v", stop, err)
+	}
+	// the stream returns once its workers do
+	if len(scanner.workers.slots) != 0 {
+		t.Fatalf("invalid workers count after the stream, expected 0, got %d", len(scanner.workers.slots))
+	}
+}
+
+func TestScanProgress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4990")
+		w.Header().Set("X-RateLimit-Reset", "1700000000")
+		switch r.URL.Path {
+		case "/users/test/repos":
+			w.Write([]byte(`[{"full_name": "test/a", "name": "a"}, {"full_name": "test/b", "name": "b"}]`))
+		case "/repos/test/a/releases", "/repos/test/b/releases":
+			w.Write([]byte(`[{"name": "test"}]`))
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL, PerPage: 100}
+	if scanner.RateLimit() != nil {
+		t.Fatalf("invalid rate limit before the scan, expected nil, got %v", scanner.RateLimit())
+	}
+	var done []int
+	scanner.OnProgress = func(d, total int, repository string) {
+		if total != 2 {
+			t.Fatalf("invalid total, expected 2, got %d", total)
+		}
+		done = append(done, d)
+	}
+	if _, err := scanner.ScanRepositories("test"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(done) != 2 || done[0] != 1 || done[1] != 2 {
+		t.Fatalf("invalid progress, expected [1 2], got %v", done)
+	}
+	rateLimit := scanner.RateLimit()
+	if rateLimit == nil || rateLimit.Limit != 5000 || rateLimit.Remaining != 4990 || rateLimit.Reset.Unix() != 1700000000 {
+		t.Fatalf("invalid rate limit, expected 4990/5000, got %v", rateLimit)
+	}
+}
+
+func TestEndpointLabel(t *testing.T) {
+	tests := map[string]string{
+		"/users/test/repos":              "/users/{owner}/repos",
+		"/repos/test/test/releases":      "/repos/{owner}/{repo}/releases",
+		"/repos/test/test":               "/repos/{owner}/{repo}",
+		"/api/v3/repos/test/test/assets": "/api/v3/repos/{owner}/{repo}/assets",
+		"/user/repos":                    "/user/repos",
+	}
+	for path, expected := range tests {
+		if label := endpointLabel(path); label != expected {
+			t.Fatalf("invalid endpoint label of %s, expected %s, got %s", path, expected, label)
+		}
+	}
+}
+
 func equal(a, b []string) bool {
 	if len(a) != len(b) {
 		return false
@@ -220,3 +549,28 @@ func equal(a, b []string) bool {
 	}
 	return true
 }
+
+func TestLoggerLogsApiCalls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "42")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	scanner := Scanner{
+		BaseUrl: server.URL,
+		Logger:  slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	}
+	if _, err := scanner.GetAllRepositories("test"); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(logs.String(), "rate_limit_remaining=42") {
+		t.Fatalf("rate-limit state is not logged: %s", logs.String())
+	}
+	if !strings.Contains(logs.String(), "/users/test/repos") {
+		t.Fatalf("api request is not logged: %s", logs.String())
+	}
+}
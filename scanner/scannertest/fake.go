@@ -0,0 +1,477 @@
+// Package scannertest provides a fake scanner.RepositoryScanner for tests of code embedding the scanner.
+package scannertest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"githubscanner/scanner"
+)
+
+const defaultPerPage = 100
+
+// Fake serves canned data instead of calling an API. Repository data is keyed by the repository full name,
+// e.g. "octocat/hello", organization data by the organization name. Repositories missing from Repositories,
+// Starred and Accessible, and accounts missing from Repositories, result in scanner.ErrNotFound errors like the
+// real API. The canned data is returned as is, it must not be changed while the fake is in use.
+// The fake is safe for concurrent use.
+type Fake struct {
+	// Repositories are the repositories of the accounts.
+	Repositories map[string][]*scanner.Repository
+	// Starred are the repositories starred by the users.
+	Starred map[string][]*scanner.Repository
+	// Accessible are the repositories accessible with the token, returned by ScanMine.
+	Accessible []*scanner.Repository
+
+	Releases       map[string][]*scanner.Release
+	Changelogs     map[string]*scanner.Changelog
+	Commits        map[string][]*scanner.Commit
+	Comparisons    map[string]*scanner.ReleaseComparison
+	Branches       map[string][]*scanner.Branch
+	Contributors   map[string][]*scanner.Contributor
+	Languages      map[string]map[string]int64
+	Settings       map[string]*scanner.RepositorySettings
+	Issues         map[string][]*scanner.Issue
+	PullRequests   map[string][]*scanner.Issue
+	WorkflowRuns   map[string][]*scanner.WorkflowRun
+	CommitActivity map[string]*scanner.CommitActivity
+	Traffic        map[string]*scanner.Traffic
+	GoModules      map[string]*scanner.GoModule
+	SBOMs          map[string]*scanner.SBOM
+	// Assets are the asset contents by the asset API url.
+	Assets map[string][]byte
+
+	DependabotAlerts     map[string][]*scanner.DependabotAlert
+	CodeScanningAlerts   map[string][]*scanner.CodeScanningAlert
+	SecretScanningAlerts map[string][]*scanner.SecretScanningAlert
+
+	// Forks are the forks owned by the accounts.
+	Forks    map[string][]*scanner.Fork
+	Members  map[string][]*scanner.Member
+	Teams    map[string][]*scanner.Team
+	Packages map[string][]*scanner.Package
+	// TeamRepositories are the permissions of the team repositories keyed by "org/team".
+	TeamRepositories map[string]map[string]string
+	Ownership        map[string]map[string][]*scanner.TeamAccess
+
+	// RateLimits are the rate limits by resource, RateLimit returns the "core" one.
+	RateLimits map[string]*scanner.RateLimit
+
+	// Errors are returned instead of the canned data. An error is looked up by the method and its subject, e.g.
+	// "GetAllReleases octocat/hello", then by the method, e.g. "GetAllReleases", then by the subject alone.
+	Errors map[string]error
+	// Latency delays every call. Calls taking a context return early when it is done.
+	Latency time.Duration
+	// PerPage is the page size of the paginated methods, 100 by default.
+	PerPage int
+
+	mu    sync.Mutex
+	calls []string
+}
+
+var _ scanner.RepositoryScanner = (*Fake)(nil)
+
+// Calls returns the calls made so far, as the method followed by its subject, e.g. "GetAllReleases octocat/hello".
+func (f *Fake) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]string(nil), f.calls...)
+}
+
+// call records the call, waits for the latency and returns the injected error.
+func (f *Fake) call(ctx context.Context, method, subject string) error {
+	call := strings.TrimSpace(method + " " + subject)
+	f.mu.Lock()
+	f.calls = append(f.calls, call)
+	f.mu.Unlock()
+
+	if f.Latency > 0 {
+		timer := time.NewTimer(f.Latency)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	for _, key := range []string{call, method, subject} {
+		if err, ok := f.Errors[key]; ok {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func notFound(format string, args ...any) error {
+	return &scanner.APIError{StatusCode: http.StatusNotFound, Message: fmt.Sprintf(format, args...), Class: scanner.ErrNotFound}
+}
+
+// knownRepository reports whether the repository is in the canned repository lists.
+func (f *Fake) knownRepository(fullName string) bool {
+	lists := [][]*scanner.Repository{f.Accessible}
+	for _, repositories := range f.Repositories {
+		lists = append(lists, repositories)
+	}
+	for _, repositories := range f.Starred {
+		lists = append(lists, repositories)
+	}
+	for _, repositories := range lists {
+		for _, repository := range repositories {
+			if repository.FullName == fullName {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// repositoryData returns the canned data of the repository, the zero value if it has none.
+func repositoryData[T any](f *Fake, ctx context.Context, method, owner, repository string, data map[string]T) (T, error) {
+	var value T
+	fullName := owner + "/" + repository
+	if err := f.call(ctx, method, fullName); err != nil {
+		return value, err
+	}
+	if !f.knownRepository(fullName) {
+		return value, notFound("repository %s does not exist", fullName)
+	}
+
+	return data[fullName], nil
+}
+
+// orgData returns the canned data of the organization, the zero value if it has none.
+func orgData[T any](f *Fake, method, org string, data map[string]T) (T, error) {
+	var value T
+	if err := f.call(context.Background(), method, org); err != nil {
+		return value, err
+	}
+
+	return data[org], nil
+}
+
+func page[T any](values []T, page, perPage int) []T {
+	if perPage <= 0 {
+		perPage = defaultPerPage
+	}
+	start := (page - 1) * perPage
+	if page < 1 || start >= len(values) {
+		return nil
+	}
+
+	return values[start:min(start+perPage, len(values))]
+}
+
+func (f *Fake) accountRepositories(ctx context.Context, method, account string) ([]*scanner.Repository, error) {
+	if err := f.call(ctx, method, account); err != nil {
+		return nil, err
+	}
+	repositories, ok := f.Repositories[account]
+	if !ok {
+		return nil, notFound("account %s does not exist", account)
+	}
+
+	return repositories, nil
+}
+
+// scan creates the items of the repositories with their releases, in the scanner output order.
+func (f *Fake) scan(ctx context.Context, repositories []*scanner.Repository) ([]*scanner.ResultItem, error) {
+	items := make([]*scanner.ResultItem, 0, len(repositories))
+	for _, repository := range repositories {
+		owner, name, _ := strings.Cut(repository.FullName, "/")
+		releases, err := repositoryData(f, ctx, "GetAllReleases", owner, name, f.Releases)
+		if err != nil {
+			return nil, err
+		}
+		// the items are sorted with their releases, the canned releases stay untouched
+		items = append(items, &scanner.ResultItem{Repository: repository, Releases: slices.Clone(releases)})
+	}
+	scanner.SortResults(items)
+
+	return items, nil
+}
+
+func (f *Fake) ListRepositories(ctx context.Context, account string) ([]*scanner.Repository, error) {
+	return f.accountRepositories(ctx, "ListRepositories", account)
+}
+
+func (f *Fake) ListReleases(ctx context.Context, owner, repository string) ([]*scanner.Release, error) {
+	return repositoryData(f, ctx, "ListReleases", owner, repository, f.Releases)
+}
+
+func (f *Fake) ScanRepositories(user string) ([]*scanner.ResultItem, error) {
+	repositories, err := f.accountRepositories(context.Background(), "ScanRepositories", user)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.scan(context.Background(), repositories)
+}
+
+func (f *Fake) StreamRepositories(user string, handle func(item *scanner.ResultItem) error) error {
+	repositories, err := f.accountRepositories(context.Background(), "StreamRepositories", user)
+	if err != nil {
+		return err
+	}
+	items, err := f.scan(context.Background(), repositories)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := handle(item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ScanAccounts skips accounts failing with scanner.ErrSSORequired like the scanner does.
+func (f *Fake) ScanAccounts(accounts []string) ([]*scanner.ResultItem, []*scanner.SkippedAccount, error) {
+	var items []*scanner.ResultItem
+	var skipped []*scanner.SkippedAccount
+	for _, account := range accounts {
+		accountItems, err := f.ScanRepositories(account)
+		if errors.Is(err, scanner.ErrSSORequired) {
+			skippedAccount := &scanner.SkippedAccount{Account: account, Reason: err.Error()}
+			var apiError *scanner.APIError
+			if errors.As(err, &apiError) {
+				skippedAccount.AuthorizationURL = apiError.SSOAuthorizationURL
+			}
+			skipped = append(skipped, skippedAccount)
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		items = append(items, accountItems...)
+	}
+	scanner.SortResults(items)
+
+	return items, skipped, nil
+}
+
+func (f *Fake) ScanMine() ([]*scanner.ResultItem, error) {
+	if err := f.call(context.Background(), "ScanMine", ""); err != nil {
+		return nil, err
+	}
+
+	return f.scan(context.Background(), f.Accessible)
+}
+
+func (f *Fake) ScanStarred(user string) ([]*scanner.ResultItem, error) {
+	repositories, err := f.ListStarredRepositories(context.Background(), user)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.scan(context.Background(), repositories)
+}
+
+func (f *Fake) GetAllRepositories(user string) ([]*scanner.Repository, error) {
+	return f.accountRepositories(context.Background(), "GetAllRepositories", user)
+}
+
+func (f *Fake) GetRepositoriesPerPage(user string, pageNumber int) ([]*scanner.Repository, error) {
+	repositories, err := f.accountRepositories(context.Background(), "GetRepositoriesPerPage", user)
+	if err != nil {
+		return nil, err
+	}
+
+	return page(repositories, pageNumber, f.PerPage), nil
+}
+
+func (f *Fake) GetAllAccessibleRepositories() ([]*scanner.Repository, error) {
+	if err := f.call(context.Background(), "GetAllAccessibleRepositories", ""); err != nil {
+		return nil, err
+	}
+
+	return f.Accessible, nil
+}
+
+func (f *Fake) GetAllStarredRepositories(user string) ([]*scanner.Repository, error) {
+	return orgData(f, "GetAllStarredRepositories", user, f.Starred)
+}
+
+func (f *Fake) GetStarredRepositoriesPerPage(user string, pageNumber int) ([]*scanner.Repository, error) {
+	repositories, err := orgData(f, "GetStarredRepositoriesPerPage", user, f.Starred)
+	if err != nil {
+		return nil, err
+	}
+
+	return page(repositories, pageNumber, f.PerPage), nil
+}
+
+func (f *Fake) ListStarredRepositories(ctx context.Context, user string) ([]*scanner.Repository, error) {
+	if err := f.call(ctx, "ListStarredRepositories", user); err != nil {
+		return nil, err
+	}
+
+	return f.Starred[user], nil
+}
+
+func (f *Fake) GetForks(user string) ([]*scanner.Fork, error) {
+	return orgData(f, "GetForks", user, f.Forks)
+}
+
+func (f *Fake) GetAllReleases(user, repository string) ([]*scanner.Release, error) {
+	return repositoryData(f, context.Background(), "GetAllReleases", user, repository, f.Releases)
+}
+
+func (f *Fake) GetReleasesPerPage(user, repository string, pageNumber int) ([]*scanner.Release, error) {
+	releases, err := repositoryData(f, context.Background(), "GetReleasesPerPage", user, repository, f.Releases)
+	if err != nil {
+		return nil, err
+	}
+
+	return page(releases, pageNumber, f.PerPage), nil
+}
+
+func (f *Fake) GetReleaseByTag(ctx context.Context, owner, repository, tag string) (*scanner.Release, error) {
+	releases, err := repositoryData(f, ctx, "GetReleaseByTag", owner, repository, f.Releases)
+	if err != nil {
+		return nil, err
+	}
+	for _, release := range releases {
+		if release.TagName == tag {
+			return release, nil
+		}
+	}
+
+	return nil, notFound("release %s of the repository %s/%s does not exist", tag, owner, repository)
+}
+
+func (f *Fake) GetChangelog(user, repository, from, to string) (*scanner.Changelog, error) {
+	return repositoryData(f, context.Background(), "GetChangelog", user, repository, f.Changelogs)
+}
+
+func (f *Fake) GetCompareCommits(user, repository, base, head string) ([]*scanner.Commit, error) {
+	return repositoryData(f, context.Background(), "GetCompareCommits", user, repository, f.Commits)
+}
+
+func (f *Fake) CompareReleases(owner, repository, base, head string) (*scanner.ReleaseComparison, error) {
+	return repositoryData(f, context.Background(), "CompareReleases", owner, repository, f.Comparisons)
+}
+
+func (f *Fake) DownloadAsset(ctx context.Context, asset *scanner.Asset, w io.Writer) error {
+	if err := f.call(ctx, "DownloadAsset", asset.URL); err != nil {
+		return err
+	}
+	content, ok := f.Assets[asset.URL]
+	if !ok {
+		return notFound("asset %s does not exist", asset.Name)
+	}
+	_, err := w.Write(content)
+
+	return err
+}
+
+func (f *Fake) GetAllBranches(user, repository string) ([]*scanner.Branch, error) {
+	return repositoryData(f, context.Background(), "GetAllBranches", user, repository, f.Branches)
+}
+
+func (f *Fake) GetAllContributors(user, repository string) ([]*scanner.Contributor, error) {
+	return repositoryData(f, context.Background(), "GetAllContributors", user, repository, f.Contributors)
+}
+
+func (f *Fake) GetLanguages(user, repository string) (map[string]int64, error) {
+	return repositoryData(f, context.Background(), "GetLanguages", user, repository, f.Languages)
+}
+
+func (f *Fake) GetRepositorySettings(owner, repository string) (*scanner.RepositorySettings, error) {
+	return repositoryData(f, context.Background(), "GetRepositorySettings", owner, repository, f.Settings)
+}
+
+func (f *Fake) GetAllIssues(user, repository string) ([]*scanner.Issue, error) {
+	return repositoryData(f, context.Background(), "GetAllIssues", user, repository, f.Issues)
+}
+
+func (f *Fake) GetAllPullRequests(user, repository string) ([]*scanner.Issue, error) {
+	return repositoryData(f, context.Background(), "GetAllPullRequests", user, repository, f.PullRequests)
+}
+
+func (f *Fake) GetWorkflowRuns(user, repository string) ([]*scanner.WorkflowRun, error) {
+	return repositoryData(f, context.Background(), "GetWorkflowRuns", user, repository, f.WorkflowRuns)
+}
+
+func (f *Fake) GetCommitActivity(user, repository string) (*scanner.CommitActivity, error) {
+	return repositoryData(f, context.Background(), "GetCommitActivity", user, repository, f.CommitActivity)
+}
+
+func (f *Fake) GetTraffic(user, repository string) (*scanner.Traffic, error) {
+	return repositoryData(f, context.Background(), "GetTraffic", user, repository, f.Traffic)
+}
+
+func (f *Fake) GetGoModule(user, repository string) (*scanner.GoModule, error) {
+	return repositoryData(f, context.Background(), "GetGoModule", user, repository, f.GoModules)
+}
+
+func (f *Fake) GetSBOM(user, repository string) (*scanner.SBOM, error) {
+	return repositoryData(f, context.Background(), "GetSBOM", user, repository, f.SBOMs)
+}
+
+func (f *Fake) GetDependabotAlerts(user, repository string) ([]*scanner.DependabotAlert, error) {
+	return repositoryData(f, context.Background(), "GetDependabotAlerts", user, repository, f.DependabotAlerts)
+}
+
+func (f *Fake) GetCodeScanningAlerts(user, repository string) ([]*scanner.CodeScanningAlert, error) {
+	return repositoryData(f, context.Background(), "GetCodeScanningAlerts", user, repository, f.CodeScanningAlerts)
+}
+
+func (f *Fake) GetSecretScanningAlerts(user, repository string) ([]*scanner.SecretScanningAlert, error) {
+	return repositoryData(f, context.Background(), "GetSecretScanningAlerts", user, repository, f.SecretScanningAlerts)
+}
+
+func (f *Fake) GetOrgMembers(org string) ([]*scanner.Member, error) {
+	return orgData(f, "GetOrgMembers", org, f.Members)
+}
+
+func (f *Fake) GetTeams(org string) ([]*scanner.Team, error) {
+	return orgData(f, "GetTeams", org, f.Teams)
+}
+
+func (f *Fake) GetTeamRepositories(org, team string) (map[string]string, error) {
+	return orgData(f, "GetTeamRepositories", org+"/"+team, f.TeamRepositories)
+}
+
+func (f *Fake) GetOwnership(org string) (map[string][]*scanner.TeamAccess, error) {
+	return orgData(f, "GetOwnership", org, f.Ownership)
+}
+
+func (f *Fake) GetPackages(org string) ([]*scanner.Package, error) {
+	return orgData(f, "GetPackages", org, f.Packages)
+}
+
+func (f *Fake) GetRateLimits() (map[string]*scanner.RateLimit, error) {
+	if err := f.call(context.Background(), "GetRateLimits", ""); err != nil {
+		return nil, err
+	}
+
+	return f.RateLimits, nil
+}
+
+func (f *Fake) RateLimit() *scanner.RateLimit {
+	return f.RateLimits["core"]
+}
+
+// Repository creates a canned repository of the full name, e.g. "octocat/hello".
+func Repository(id int64, fullName string) *scanner.Repository {
+	_, name, _ := strings.Cut(fullName, "/")
+
+	return &scanner.Repository{ID: id, FullName: fullName, Name: name}
+}
+
+// Release creates a canned release of the tag published at the time.
+func Release(tag string, publishedAt time.Time) *scanner.Release {
+	return &scanner.Release{Name: tag, TagName: tag, PublishedAt: &publishedAt, Assets: []*scanner.Asset{}}
+}
@@ -0,0 +1,121 @@
+package scannertest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"githubscanner/scanner"
+)
+
+func newFake() *Fake {
+	published := time.Date(2024, 1, 5, 9, 0, 0, 0, time.UTC)
+	return &Fake{
+		Repositories: map[string][]*scanner.Repository{
+			"test": {Repository(2, "test/b"), Repository(1, "test/a")},
+		},
+		Releases: map[string][]*scanner.Release{
+			"test/a": {Release("v1.0.0", published), Release("v1.1.0", published.AddDate(0, 1, 0))},
+		},
+		Assets:     map[string][]byte{"https://example.com/assets/1": []byte("content")},
+		RateLimits: map[string]*scanner.RateLimit{"core": {Limit: 5000, Remaining: 4999}},
+	}
+}
+
+func TestFakeScan(t *testing.T) {
+	fake := newFake()
+
+	items, err := fake.ScanRepositories("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 || items[0].Repository.FullName != "test/a" || items[1].Repository.FullName != "test/b" {
+		t.Fatalf("invalid items, expected test/a and test/b, got %+v", items)
+	}
+	if tag := items[0].Releases[0].TagName; tag != "v1.1.0" {
+		t.Fatalf("invalid first release, expected v1.1.0, got %s", tag)
+	}
+	if tag := fake.Releases["test/a"][0].TagName; tag != "v1.0.0" {
+		t.Fatalf("invalid canned releases, expected them unchanged, got %s first", tag)
+	}
+
+	expected := []string{"ScanRepositories test", "GetAllReleases test/b", "GetAllReleases test/a"}
+	if calls := fake.Calls(); !slices.Equal(calls, expected) {
+		t.Fatalf("invalid calls, expected %v, got %v", expected, calls)
+	}
+
+	if _, err := fake.ScanRepositories("unknown"); !errors.Is(err, scanner.ErrNotFound) {
+		t.Fatalf("invalid error, expected not found, got %v", err)
+	}
+	if _, err := fake.GetAllBranches("test", "unknown"); !errors.Is(err, scanner.ErrNotFound) {
+		t.Fatalf("invalid error, expected not found, got %v", err)
+	}
+	if rateLimit := fake.RateLimit(); rateLimit.Remaining != 4999 {
+		t.Fatalf("invalid rate limit, expected 4999 remaining, got %d", rateLimit.Remaining)
+	}
+}
+
+func TestFakeErrors(t *testing.T) {
+	fake := newFake()
+	releasesErr := errors.New("releases failed")
+	fake.Errors = map[string]error{"test/a": scanner.ErrRateLimited}
+	if _, err := fake.ScanRepositories("test"); !errors.Is(err, scanner.ErrRateLimited) {
+		t.Fatalf("invalid error, expected rate limited, got %v", err)
+	}
+
+	fake.Errors = map[string]error{"GetAllReleases test/b": releasesErr}
+	if _, err := fake.GetAllReleases("test", "b"); err != releasesErr {
+		t.Fatalf("invalid error, expected %v, got %v", releasesErr, err)
+	}
+	if _, err := fake.GetAllBranches("test", "b"); err != nil {
+		t.Fatalf("invalid error, expected none, got %v", err)
+	}
+
+	fake.Errors = map[string]error{"ScanRepositories": &scanner.APIError{Message: "sso", Class: scanner.ErrSSORequired, SSOAuthorizationURL: "https://example.com/sso"}}
+	items, skipped, err := fake.ScanAccounts([]string{"test"})
+	if err != nil || len(items) != 0 || len(skipped) != 1 || skipped[0].AuthorizationURL != "https://example.com/sso" {
+		t.Fatalf("invalid scan, expected a skipped account, got %v, %+v, %v", items, skipped, err)
+	}
+}
+
+func TestFakeLatency(t *testing.T) {
+	fake := newFake()
+	fake.Latency = time.Minute
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := fake.ListRepositories(ctx, "test"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("invalid error, expected deadline exceeded, got %v", err)
+	}
+
+	fake.Latency = 20 * time.Millisecond
+	started := time.Now()
+	var content bytes.Buffer
+	if err := fake.DownloadAsset(context.Background(), &scanner.Asset{URL: "https://example.com/assets/1"}, &content); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(started); elapsed < fake.Latency || content.String() != "content" {
+		t.Fatalf("invalid download, expected content after %v, got %q after %v", fake.Latency, content.String(), elapsed)
+	}
+}
+
+func TestFakePages(t *testing.T) {
+	fake := newFake()
+	fake.PerPage = 1
+
+	for pageNumber, expected := range []string{"test/b", "test/a"} {
+		repositories, err := fake.GetRepositoriesPerPage("test", pageNumber+1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(repositories) != 1 || repositories[0].FullName != expected {
+			t.Fatalf("invalid page %d, expected %s, got %+v", pageNumber+1, expected, repositories)
+		}
+	}
+	if repositories, _ := fake.GetRepositoriesPerPage("test", 3); len(repositories) != 0 {
+		t.Fatalf("invalid page 3, expected it empty, got %+v", repositories)
+	}
+}
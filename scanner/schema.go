@@ -0,0 +1,268 @@
+package scanner
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SchemaVersion is the "major.minor" version of the machine output formats. The minor version is bumped when
+// fields are added, the major version when fields are removed, renamed or change their type.
+const SchemaVersion = "1.3"
+
+// SnapshotSchema is the JSON Schema of the json scan output, the items of its "items" array are the ndjson lines.
+//
+//go:embed schema/snapshot.schema.json
+var SnapshotSchema []byte
+
+// ScanOptions are the scanner options a snapshot was scanned with.
+type ScanOptions struct {
+	Settings       bool       `json:"settings,omitempty"`
+	Contributors   bool       `json:"contributors,omitempty"`
+	Languages      bool       `json:"languages,omitempty"`
+	Branches       bool       `json:"branches,omitempty"`
+	Issues         bool       `json:"issues,omitempty"`
+	IssueLists     bool       `json:"issue_lists,omitempty"`
+	Workflows      bool       `json:"workflows,omitempty"`
+	Alerts         []string   `json:"alerts,omitempty"`
+	Traffic        bool       `json:"traffic,omitempty"`
+	CommitActivity bool       `json:"commit_activity,omitempty"`
+	Ownership      bool       `json:"ownership,omitempty"`
+	Signatures     bool       `json:"signatures,omitempty"`
+	GoModules      bool       `json:"go_modules,omitempty"`
+	Since          *time.Time `json:"since,omitempty"`
+	// ReleasedAfter and ReleasedBefore are the bounds of the release window of the scan, see ReleaseWindow.
+	ReleasedAfter  *time.Time `json:"released_after,omitempty"`
+	ReleasedBefore *time.Time `json:"released_before,omitempty"`
+	// MaxRepositories and MaxReleases are set if the scan was bounded, the snapshot is incomplete then.
+	MaxRepositories int `json:"max_repositories,omitempty"`
+	MaxReleases     int `json:"max_releases,omitempty"`
+}
+
+// Options returns the options of the scanner recorded in snapshots.
+func (s *Scanner) Options() *ScanOptions {
+	options := &ScanOptions{
+		Settings:        s.ScanSettings,
+		Contributors:    s.ScanContributors,
+		Languages:       s.ScanLanguages,
+		Branches:        s.ScanBranches,
+		Issues:          s.ScanIssues,
+		IssueLists:      s.ScanIssueLists,
+		Workflows:       s.ScanWorkflows,
+		Alerts:          s.ScanAlerts,
+		Traffic:         s.ScanTraffic,
+		CommitActivity:  s.ScanCommitActivity,
+		Ownership:       s.ScanOwnership,
+		Signatures:      s.VerifySignatures,
+		GoModules:       s.ScanGoModules,
+		MaxRepositories: s.MaxRepositories,
+		MaxReleases:     s.MaxReleases,
+	}
+	if !s.Since.IsZero() {
+		since := s.Since.UTC()
+		options.Since = &since
+	}
+	if after := s.ReleaseWindow.After.UTC(); !after.IsZero() {
+		options.ReleasedAfter = &after
+	}
+	if before := s.ReleaseWindow.Before.UTC(); !before.IsZero() {
+		options.ReleasedBefore = &before
+	}
+
+	return options
+}
+
+// ScannerVersion returns the module version of the running binary, "(devel)" for local builds.
+func ScannerVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok || info.Main.Version == "" {
+		return "(devel)"
+	}
+
+	return info.Main.Version
+}
+
+// Validate checks that the data is a json snapshot matching SnapshotSchema and written with a compatible
+// schema version.
+func Validate(data []byte) error {
+	return validate(data, "")
+}
+
+// ValidateItem checks that the data is a scan result item, e.g. an ndjson line, matching SnapshotSchema.
+func ValidateItem(data []byte) error {
+	return validate(data, "#/$defs/item")
+}
+
+func validate(data []byte, ref string) error {
+	var schema jsonSchema
+	if err := json.Unmarshal(SnapshotSchema, &schema); err != nil {
+		return fmt.Errorf("could not parse the schema: %w", err)
+	}
+
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("invalid json: %w", err)
+	}
+
+	root := &schema
+	if ref != "" {
+		var err error
+		if root, err = schema.resolve(ref); err != nil {
+			return err
+		}
+	}
+
+	var violations []string
+	schema.check(root, value, "$", &violations)
+	if object, ok := value.(map[string]any); ok {
+		if version, ok := object["schema_version"].(string); ok && !compatibleSchemaVersion(version) {
+			violations = append(violations, fmt.Sprintf("$.schema_version: incompatible version %s, expected %s", version, SchemaVersion))
+		}
+	}
+	if len(violations) > 0 {
+		return errors.New(strings.Join(violations, "; "))
+	}
+
+	return nil
+}
+
+// compatibleSchemaVersion reports whether data written with the version can be read as SchemaVersion.
+func compatibleSchemaVersion(version string) bool {
+	major, _, _ := strings.Cut(version, ".")
+	current, _, _ := strings.Cut(SchemaVersion, ".")
+
+	return major == current
+}
+
+// jsonSchema is the subset of JSON Schema used by SnapshotSchema.
+type jsonSchema struct {
+	Ref        string                 `json:"$ref"`
+	Type       schemaTypes            `json:"type"`
+	Required   []string               `json:"required"`
+	Properties map[string]*jsonSchema `json:"properties"`
+	Items      *jsonSchema            `json:"items"`
+	Enum       []any                  `json:"enum"`
+	Defs       map[string]*jsonSchema `json:"$defs"`
+}
+
+// schemaTypes is the "type" keyword, a single type or a list of types.
+type schemaTypes []string
+
+func (t *schemaTypes) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = schemaTypes{single}
+		return nil
+	}
+
+	return json.Unmarshal(data, (*[]string)(t))
+}
+
+func (root *jsonSchema) resolve(ref string) (*jsonSchema, error) {
+	name, ok := strings.CutPrefix(ref, "#/$defs/")
+	if !ok || root.Defs[name] == nil {
+		return nil, fmt.Errorf("unknown schema reference: %s", ref)
+	}
+
+	return root.Defs[name], nil
+}
+
+// check appends the violations of the value against the schema node to violations, root is the schema
+// resolving the references.
+func (root *jsonSchema) check(node *jsonSchema, value any, path string, violations *[]string) {
+	if node.Ref != "" {
+		resolved, err := root.resolve(node.Ref)
+		if err != nil {
+			*violations = append(*violations, fmt.Sprintf("%s: %v", path, err))
+			return
+		}
+		node = resolved
+	}
+
+	if len(node.Type) > 0 && !node.Type.match(value) {
+		*violations = append(*violations, fmt.Sprintf("%s: expected %s, got %s", path, strings.Join(node.Type, " or "), jsonType(value)))
+		return
+	}
+
+	if len(node.Enum) > 0 {
+		found := false
+		for _, allowed := range node.Enum {
+			found = found || allowed == value
+		}
+		if !found {
+			*violations = append(*violations, fmt.Sprintf("%s: %v is not one of %v", path, value, node.Enum))
+		}
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		for _, name := range node.Required {
+			if _, ok := value[name]; !ok {
+				*violations = append(*violations, fmt.Sprintf("%s: missing required property %s", path, name))
+			}
+		}
+		names := make([]string, 0, len(value))
+		for name := range value {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if property := node.Properties[name]; property != nil {
+				root.check(property, value[name], path+"."+name, violations)
+			}
+		}
+	case []any:
+		if node.Items != nil {
+			for i, element := range value {
+				root.check(node.Items, element, fmt.Sprintf("%s[%d]", path, i), violations)
+			}
+		}
+	}
+}
+
+func (t schemaTypes) match(value any) bool {
+	actual := jsonType(value)
+	for _, expected := range t {
+		if expected == actual || expected == "number" && actual == "integer" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// jsonType returns the JSON Schema type of a value decoded by encoding/json.
+func jsonType(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if value == float64(int64(value)) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// SchemaItem is a result item tagged with the schema version, the format of ndjson lines.
+type SchemaItem struct {
+	SchemaVersion string `json:"schema_version"`
+	*ResultItem
+}
+
+func NewSchemaItem(item *ResultItem) *SchemaItem {
+	return &SchemaItem{SchemaVersion: SchemaVersion, ResultItem: item}
+}
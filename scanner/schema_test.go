@@ -0,0 +1,74 @@
+package scanner
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	published := time.Date(2024, 1, 5, 9, 0, 0, 0, time.UTC)
+	s := &Scanner{ScanSettings: true, ScanAlerts: []string{"dependabot"}, Since: published}
+	snapshot := NewSnapshot("test", []*ResultItem{
+		{Repository: &Repository{ID: 1, FullName: "test/a", Name: "a"}, Releases: []*Release{
+			{Name: "A 1.0.0", TagName: "v1.0.0", PublishedAt: &published, Assets: []*Asset{{Name: "a.tar.gz", Size: 10}}},
+		}},
+		{Repository: &Repository{ID: 2, FullName: "test/b", Name: "b"}},
+	})
+	snapshot.Options = s.Options()
+	if snapshot.SchemaVersion != SchemaVersion || snapshot.ScannerVersion == "" {
+		t.Fatalf("invalid snapshot versions, expected %s and a scanner version, got %s and %s", SchemaVersion, snapshot.SchemaVersion, snapshot.ScannerVersion)
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Validate(data); err != nil {
+		t.Fatalf("invalid validation result, expected no error, got %v", err)
+	}
+
+	var loaded Snapshot
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Options == nil || !loaded.Options.Settings || loaded.Options.Since == nil || !loaded.Options.Since.Equal(published) {
+		t.Fatalf("invalid snapshot options, expected settings since %v, got %+v", published, loaded.Options)
+	}
+
+	invalid := []struct {
+		data      string
+		violation string
+	}{
+		{`{"account":"test","scanned_at":"2024-01-05T09:00:00Z","items":[]}`, "missing required property schema_version"},
+		{`{"schema_version":"2.0","account":"test","scanned_at":"2024-01-05T09:00:00Z","items":[]}`, "incompatible version 2.0"},
+		{`{"schema_version":"1.0","account":"test","scanned_at":"2024-01-05T09:00:00Z","items":[{"repository":{"id":"1","full_name":"test/a","name":"a","private":false,"archived":false,"stargazers_count":0},"releases":null}]}`, "$.items[0].repository.id: expected integer, got string"},
+		{`{"schema_version":"1.0","account":"test","scanned_at":"2024-01-05T09:00:00Z","items":[{"releases":[{"name":"A"}]}]}`, "$.items[0]: missing required property repository"},
+		{`[]`, "expected object, got array"},
+	}
+	for _, test := range invalid {
+		err := Validate([]byte(test.data))
+		if err == nil || !strings.Contains(err.Error(), test.violation) {
+			t.Fatalf("invalid validation result of %s, expected %q, got %v", test.data, test.violation, err)
+		}
+	}
+}
+
+func TestValidateItem(t *testing.T) {
+	data, err := json.Marshal(NewSchemaItem(&ResultItem{Repository: &Repository{ID: 1, FullName: "test/a", Name: "a"}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), `{"schema_version":"`+SchemaVersion+`","repository":`) {
+		t.Fatalf("invalid item, expected the schema version first, got %s", data)
+	}
+	if err := ValidateItem(data); err != nil {
+		t.Fatalf("invalid validation result, expected no error, got %v", err)
+	}
+
+	err = ValidateItem([]byte(`{"schema_version":"0.9","repository":{"id":1,"full_name":"test/a","name":"a","private":false,"archived":false,"stargazers_count":0},"releases":null}`))
+	if err == nil || !strings.Contains(err.Error(), "incompatible version 0.9") {
+		t.Fatalf("invalid validation result, expected an incompatible version, got %v", err)
+	}
+}
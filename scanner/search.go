@@ -0,0 +1,141 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// searchResultsCap is the count of results the search API serves at most, whatever the count of matches is.
+const searchResultsCap = 1000
+
+// searchRateLimitRetries is the count of times a search request waits for the reset of the search rate limit.
+const searchRateLimitRetries = 3
+
+// searchRateLimitWait bounds the wait for the reset of the search rate limit, it resets every minute. A variable
+// so tests could shorten it.
+var searchRateLimitWait = time.Minute
+
+// ErrSearchResultsCapped is returned with the first 1000 repositories of a search matching more of them.
+var ErrSearchResultsCapped = errors.New("search results are capped at 1000 repositories")
+
+type searchRepositoriesResult struct {
+	TotalCount        int           `json:"total_count"`
+	IncompleteResults bool          `json:"incomplete_results"`
+	Items             []*Repository `json:"items"`
+}
+
+// ScanSearch scans releases of the repositories matching the search query, see SearchRepositories. Only the
+// first 1000 of them are scanned if more match the query.
+func (s *Scanner) ScanSearch(query string) ([]*ResultItem, error) {
+	return s.scan(context.Background(), query, "ScanSearch", func(ctx context.Context, query string) ([]*Repository, error) {
+		repositories, err := s.searchRepositories(ctx, query)
+		if errors.Is(err, ErrSearchResultsCapped) {
+			s.getLogger().Warn("only the first repositories of the search are scanned, narrow the query to scan the rest", "query", query, "error", err)
+			return repositories, nil
+		}
+		return repositories, err
+	})
+}
+
+// SearchRepositories returns the repositories matching the search query, e.g. "org:foo topic:sdk language:go".
+// Filtering by the API takes far fewer requests than listing all repositories of the accounts. The search API
+// has a separate rate limit of 30 requests a minute, requests hitting it wait for its reset. The API serves at
+// most 1000 results: the first 1000 repositories are returned with ErrSearchResultsCapped if more match.
+func (s *Scanner) SearchRepositories(query string) ([]*Repository, error) {
+	return s.searchRepositories(context.Background(), query)
+}
+
+func (s *Scanner) searchRepositories(ctx context.Context, query string) ([]*Repository, error) {
+	var total int
+	var incomplete bool
+	// pages are fetched one by one, concurrent searches trip the secondary rate limit
+	repositories, err := paginateUntil(ctx, func(ctx context.Context, page int) ([]*Repository, pageLinks, error) {
+		result, links, err := s.searchRepositoriesPerPage(ctx, query, page)
+		if err != nil {
+			return nil, pageLinks{}, err
+		}
+		s.getLogger().Debug("search page fetched", "query", query, "page", page, "count", len(result.Items))
+		total = result.TotalCount
+		incomplete = incomplete || result.IncompleteResults
+		return result.Items, links, nil
+	}, func([]*Repository) bool {
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	if incomplete {
+		s.getLogger().Warn("search timed out, some matching repositories could be missing", "query", query)
+	}
+	if total > searchResultsCap && len(repositories) < total {
+		return repositories, fmt.Errorf("%w: %d repositories match the query %q", ErrSearchResultsCapped, total, query)
+	}
+
+	return repositories, nil
+}
+
+func (s *Scanner) searchRepositoriesPerPage(ctx context.Context, query string, page int) (*searchRepositoriesResult, pageLinks, error) {
+	if err := s.checkPage(page); err != nil {
+		return nil, pageLinks{}, err
+	}
+	if query == "" {
+		return nil, pageLinks{}, errors.New("search query could not be empty")
+	}
+	ctx, span := s.getTracer().Start(ctx, "SearchRepositoriesPerPage", StringAttribute("query", query), IntAttribute("page", page))
+	defer span.End()
+
+	apiUrl := fmt.Sprintf("%s/search/repositories?q=%s&per_page=%d&page=%d", s.BaseUrl, url.QueryEscape(query), s.getPerPage(), page)
+	for attempt := 0; ; attempt++ {
+		response, err := s.get(ctx, span, apiUrl)
+		if err != nil {
+			return nil, pageLinks{}, err
+		}
+		if delay, limited := searchRateLimitReset(response, time.Now()); limited && attempt < searchRateLimitRetries {
+			response.Body.Close()
+			s.getLogger().Warn("search rate limit exceeded, waiting for its reset", "query", query, "delay", delay)
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, pageLinks{}, ctx.Err()
+			case <-timer.C:
+			}
+			continue
+		}
+		defer response.Body.Close()
+
+		if response.StatusCode != http.StatusOK {
+			return nil, pageLinks{}, fmt.Errorf("could not search repositories for the query %q: %w", query, s.newApiError(response))
+		}
+
+		result := &searchRepositoriesResult{}
+		if err := json.NewDecoder(response.Body).Decode(result); err != nil {
+			return nil, pageLinks{}, err
+		}
+
+		return result, parsePageLinks(response.Header), nil
+	}
+}
+
+// searchRateLimitReset returns the wait until the reset of the exhausted search rate limit, bounded by
+// searchRateLimitWait.
+func searchRateLimitReset(response *http.Response, now time.Time) (time.Duration, bool) {
+	if response.StatusCode != http.StatusForbidden && response.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	if response.Header.Get("X-RateLimit-Resource") != "search" || response.Header.Get("X-RateLimit-Remaining") != "0" {
+		return 0, false
+	}
+	reset, err := strconv.ParseInt(response.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return searchRateLimitWait, true
+	}
+
+	return min(max(time.Unix(reset, 0).Sub(now), 0)+time.Second, searchRateLimitWait), true
+}
@@ -0,0 +1,83 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSearchRepositories(t *testing.T) {
+	wait := searchRateLimitWait
+	searchRateLimitWait = 10 * time.Millisecond
+	defer func() { searchRateLimitWait = wait }()
+
+	limited := true
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if query := r.URL.Query().Get("q"); query != "org:foo topic:sdk" {
+			t.Errorf("invalid search query, expected 'org:foo topic:sdk', got %s", query)
+		}
+		w.Header().Set("X-RateLimit-Resource", "search")
+		w.Header().Set("X-RateLimit-Remaining", "29")
+		if limited {
+			limited = false
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", fmt.Sprint(time.Now().Add(time.Minute).Unix()))
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message": "API rate limit exceeded"}`))
+			return
+		}
+		if r.URL.Query().Get("page") == "1" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/search/repositories?page=2>; rel="next", <%s/search/repositories?page=2>; rel="last"`, server.URL, server.URL))
+			w.Write([]byte(`{"total_count": 3, "items": [{"full_name": "foo/a", "name": "a"}, {"full_name": "foo/b", "name": "b"}]}`))
+			return
+		}
+		w.Write([]byte(`{"total_count": 3, "items": [{"full_name": "foo/c", "name": "c"}]}`))
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL}
+	repositories, err := scanner.SearchRepositories("org:foo topic:sdk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repositories) != 3 || repositories[2].FullName != "foo/c" {
+		t.Fatalf("invalid repositories, expected foo/a, foo/b and foo/c, got %v", repositories)
+	}
+	// the search rate limit is not the core one
+	if scanner.RateLimit() != nil {
+		t.Fatalf("invalid rate limit, expected nil, got %+v", scanner.RateLimit())
+	}
+}
+
+func TestSearchRepositoriesCapped(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/search/repositories" {
+			w.Write([]byte(`{"total_count": 1500, "items": [{"full_name": "foo/a", "name": "a"}]}`))
+			return
+		}
+		w.Write([]byte(`[{"tag_name": "v1.0.0"}]`))
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL}
+	repositories, err := scanner.SearchRepositories("org:foo")
+	if !errors.Is(err, ErrSearchResultsCapped) {
+		t.Fatalf("invalid error, expected %v, got %v", ErrSearchResultsCapped, err)
+	}
+	if len(repositories) != 1 {
+		t.Fatalf("invalid repositories count, expected 1, got %d", len(repositories))
+	}
+
+	// the capped results are scanned
+	items, err := scanner.ScanSearch("org:foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || len(items[0].Releases) != 1 {
+		t.Fatalf("invalid scanned items, expected foo/a with 1 release, got %v", items)
+	}
+}
@@ -0,0 +1,98 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// secondaryRateLimitRetries bounds the retries of a request hitting the secondary rate limit, the response is
+// returned as a rate limit error afterwards. secondaryRateLimitDelay is the pause if GitHub does not tell how
+// long to wait, it asks to wait at least a minute.
+var (
+	secondaryRateLimitRetries = 3
+	secondaryRateLimitDelay   = time.Minute
+)
+
+// pause holds back the requests of all workers of the scanner after a secondary rate limit response, so they
+// resume together instead of each worker hitting the limit again.
+type pause struct {
+	mu    sync.Mutex
+	until time.Time
+}
+
+// extend pauses the requests for the delay, unless they are already paused for longer.
+func (p *pause) extend(delay time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if until := time.Now().Add(delay); until.After(p.until) {
+		p.until = until
+	}
+}
+
+// wait blocks until the pause is over. The pause could be extended meanwhile by other workers.
+func (p *pause) wait(ctx context.Context) error {
+	for {
+		p.mu.Lock()
+		delay := time.Until(p.until)
+		p.mu.Unlock()
+		if delay <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// secondaryRateLimit reports whether the response is a secondary rate limit (abuse detection) response and how
+// long to wait before retrying. Exhausted primary rate limits are not secondary ones, they are reset hourly.
+// The body of a forbidden response is read to look for the message, it is restored for the caller.
+func secondaryRateLimit(response *http.Response) (time.Duration, bool) {
+	if response.StatusCode != http.StatusForbidden && response.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	if response.Header.Get("X-RateLimit-Remaining") == "0" {
+		return 0, false
+	}
+
+	delay, hasRetryAfter := parseRetryAfter(response.Header.Get("Retry-After"), time.Now())
+	if hasRetryAfter {
+		return delay, true
+	}
+
+	body, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	response.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil || !strings.Contains(strings.ToLower(string(body)), "secondary rate limit") {
+		return 0, false
+	}
+
+	return secondaryRateLimitDelay, true
+}
+
+// parseRetryAfter parses the Retry-After header value, either seconds or an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+
+	return 0, false
+}
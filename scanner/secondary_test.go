@@ -0,0 +1,148 @@
+package scanner
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{"", 0, false},
+		{"30", 30 * time.Second, true},
+		{"0", 0, true},
+		{"-1", 0, false},
+		{"Fri, 01 Mar 2024 12:01:00 GMT", time.Minute, true},
+		{"Fri, 01 Mar 2024 11:59:00 GMT", 0, true},
+		{"soon", 0, false},
+	}
+	for _, c := range cases {
+		delay, ok := parseRetryAfter(c.value, now)
+		if delay != c.expected || ok != c.ok {
+			t.Fatalf("invalid retry delay of %q, expected %v %v, got %v %v", c.value, c.expected, c.ok, delay, ok)
+		}
+	}
+}
+
+func TestSecondaryRateLimit(t *testing.T) {
+	secondaryRateLimitDelay = 5 * time.Millisecond
+	cases := []struct {
+		status   int
+		headers  map[string]string
+		body     string
+		expected bool
+	}{
+		{http.StatusForbidden, map[string]string{"Retry-After": "1"}, `{"message": "forbidden"}`, true},
+		{http.StatusTooManyRequests, map[string]string{"Retry-After": "1"}, `{"message": "slow down"}`, true},
+		{http.StatusForbidden, nil, `{"message": "You have exceeded a secondary rate limit."}`, true},
+		{http.StatusForbidden, map[string]string{"X-RateLimit-Remaining": "0"}, `{"message": "API rate limit exceeded"}`, false},
+		{http.StatusForbidden, nil, `{"message": "Resource not accessible by integration"}`, false},
+		{http.StatusOK, map[string]string{"Retry-After": "1"}, `[]`, false},
+	}
+	for _, c := range cases {
+		recorder := httptest.NewRecorder()
+		for name, value := range c.headers {
+			recorder.Header().Set(name, value)
+		}
+		recorder.WriteHeader(c.status)
+		recorder.WriteString(c.body)
+		response := recorder.Result()
+
+		if _, limited := secondaryRateLimit(response); limited != c.expected {
+			t.Fatalf("invalid secondary rate limit of %d %s, expected %v, got %v", c.status, c.body, c.expected, limited)
+		}
+		apiError := (&Scanner{}).newApiError(response)
+		if c.status != http.StatusOK && apiError.Message == "" {
+			t.Fatalf("invalid response body of %d %s, expected it restored", c.status, c.body)
+		}
+	}
+}
+
+func TestGetPausesOnSecondaryRateLimit(t *testing.T) {
+	secondaryRateLimitDelay = 20 * time.Millisecond
+	var (
+		mu        sync.Mutex
+		limitedAt time.Time
+		early     int
+		requests  atomic.Int32
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests.Add(1)
+		if limitedAt.IsZero() {
+			limitedAt = time.Now()
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message": "You have exceeded a secondary rate limit. Please wait a few minutes before you try again."}`))
+			return
+		}
+		if time.Since(limitedAt) < secondaryRateLimitDelay {
+			early++
+		}
+		w.Write([]byte(`[{"id": 1, "name": "test", "full_name": "test/test"}]`))
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL}
+	// the first request is limited, the concurrent requests must wait for the pause
+	repositories, err := scanner.GetRepositoriesPerPage("test", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repositories) != 1 {
+		t.Fatalf("invalid repositories count, expected 1, got %d", len(repositories))
+	}
+
+	scanner.pause.extend(secondaryRateLimitDelay)
+	mu.Lock()
+	limitedAt = time.Now()
+	mu.Unlock()
+	var group sync.WaitGroup
+	for range 5 {
+		group.Add(1)
+		go func() {
+			defer group.Done()
+			if _, err := scanner.GetRepositoriesPerPage("test", 1); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	group.Wait()
+
+	if early != 0 {
+		t.Fatalf("invalid requests during the pause, expected 0, got %d", early)
+	}
+	if count := requests.Load(); count != 7 {
+		t.Fatalf("invalid requests count, expected 7, got %d", count)
+	}
+}
+
+func TestGetSecondaryRateLimitRetriesExhausted(t *testing.T) {
+	secondaryRateLimitDelay = time.Millisecond
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message": "You have exceeded a secondary rate limit."}`))
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL}
+	_, err := scanner.GetRepositoriesPerPage("test", 1)
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("invalid error, expected %v, got %v", ErrRateLimited, err)
+	}
+	if requests != secondaryRateLimitRetries+1 {
+		t.Fatalf("invalid requests count, expected %d, got %d", secondaryRateLimitRetries+1, requests)
+	}
+}
@@ -0,0 +1,287 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Alert sources of SecurityAlerts.
+const (
+	AlertSourceDependabot     = "dependabot"
+	AlertSourceCodeScanning   = "code_scanning"
+	AlertSourceSecretScanning = "secret_scanning"
+)
+
+// AlertSources are all alert sources, the security scan profile.
+var AlertSources = []string{AlertSourceDependabot, AlertSourceCodeScanning, AlertSourceSecretScanning}
+
+// Severities are the alert severities from the most to the least severe.
+var Severities = []string{"critical", "high", "medium", "low", "none"}
+
+// codeScanningSeverities map code scanning rule severities of non-security rules to alert severities.
+var codeScanningSeverities = map[string]string{"error": "high", "warning": "medium", "note": "low", "none": "none"}
+
+// errAlertsUnavailable is returned when alerts are disabled for the repository or the token has no access to them.
+var errAlertsUnavailable = errors.New("alerts are unavailable")
+
+type DependabotAlert struct {
+	Number     int    `json:"number"`
+	State      string `json:"state"`
+	HTMLURL    string `json:"html_url"`
+	Dependency struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+		} `json:"package"`
+	} `json:"dependency"`
+	SecurityAdvisory struct {
+		Summary  string `json:"summary"`
+		Severity string `json:"severity"`
+	} `json:"security_advisory"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CodeScanningAlert struct {
+	Number  int    `json:"number"`
+	State   string `json:"state"`
+	HTMLURL string `json:"html_url"`
+	Rule    struct {
+		ID          string `json:"id"`
+		Description string `json:"description"`
+		// Severity is the rule severity: error, warning or note.
+		Severity string `json:"severity"`
+		// SecuritySeverityLevel is only set for security rules: critical, high, medium or low.
+		SecuritySeverityLevel string `json:"security_severity_level"`
+	} `json:"rule"`
+	Tool struct {
+		Name string `json:"name"`
+	} `json:"tool"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Severity returns the security severity of the alert, or the severity mapped from the rule severity
+// for non-security rules.
+func (a *CodeScanningAlert) Severity() string {
+	if a.Rule.SecuritySeverityLevel != "" {
+		return a.Rule.SecuritySeverityLevel
+	}
+
+	return codeScanningSeverities[a.Rule.Severity]
+}
+
+type SecretScanningAlert struct {
+	Number                int       `json:"number"`
+	State                 string    `json:"state"`
+	HTMLURL               string    `json:"html_url"`
+	SecretType            string    `json:"secret_type"`
+	SecretTypeDisplayName string    `json:"secret_type_display_name"`
+	CreatedAt             time.Time `json:"created_at"`
+}
+
+// SecurityAlerts are open alert counts of the repository by severity. Counts of a source are nil if it is
+// not scanned or unavailable.
+type SecurityAlerts struct {
+	Dependabot   map[string]int `json:"dependabot,omitempty"`
+	CodeScanning map[string]int `json:"code_scanning,omitempty"`
+	// SecretScanning alerts have no severity, leaked secrets are counted as critical.
+	SecretScanning map[string]int `json:"secret_scanning,omitempty"`
+	// Unavailable are the alert sources disabled for the repository or not accessible with the token.
+	Unavailable []string `json:"unavailable,omitempty"`
+}
+
+// GetDependabotAlerts returns open Dependabot alerts of the repository. The token needs the security_events scope.
+func (s *Scanner) GetDependabotAlerts(user, repository string) ([]*DependabotAlert, error) {
+	return s.getDependabotAlerts(context.Background(), user, repository)
+}
+
+func (s *Scanner) getDependabotAlerts(ctx context.Context, user, repository string) ([]*DependabotAlert, error) {
+	var alerts []*DependabotAlert
+	err := s.getAlerts(ctx, "GetDependabotAlerts", user, repository, "dependabot/alerts", func(response *http.Response) error {
+		var chunk []*DependabotAlert
+		if err := json.NewDecoder(response.Body).Decode(&chunk); err != nil {
+			return err
+		}
+		alerts = append(alerts, chunk...)
+		return nil
+	})
+
+	return alerts, err
+}
+
+// GetCodeScanningAlerts returns open code scanning alerts of the repository.
+func (s *Scanner) GetCodeScanningAlerts(user, repository string) ([]*CodeScanningAlert, error) {
+	return s.getCodeScanningAlerts(context.Background(), user, repository)
+}
+
+func (s *Scanner) getCodeScanningAlerts(ctx context.Context, user, repository string) ([]*CodeScanningAlert, error) {
+	var alerts []*CodeScanningAlert
+	err := s.getAlerts(ctx, "GetCodeScanningAlerts", user, repository, "code-scanning/alerts", func(response *http.Response) error {
+		var chunk []*CodeScanningAlert
+		if err := json.NewDecoder(response.Body).Decode(&chunk); err != nil {
+			return err
+		}
+		alerts = append(alerts, chunk...)
+		return nil
+	})
+
+	return alerts, err
+}
+
+// GetSecretScanningAlerts returns open secret scanning alerts of the repository.
+func (s *Scanner) GetSecretScanningAlerts(user, repository string) ([]*SecretScanningAlert, error) {
+	return s.getSecretScanningAlerts(context.Background(), user, repository)
+}
+
+func (s *Scanner) getSecretScanningAlerts(ctx context.Context, user, repository string) ([]*SecretScanningAlert, error) {
+	var alerts []*SecretScanningAlert
+	err := s.getAlerts(ctx, "GetSecretScanningAlerts", user, repository, "secret-scanning/alerts", func(response *http.Response) error {
+		var chunk []*SecretScanningAlert
+		if err := json.NewDecoder(response.Body).Decode(&chunk); err != nil {
+			return err
+		}
+		alerts = append(alerts, chunk...)
+		return nil
+	})
+
+	return alerts, err
+}
+
+// getAlerts fetches all pages of open alerts of the repository endpoint. Alert endpoints are paginated with
+// cursors, so the next page url is taken from the Link header.
+func (s *Scanner) getAlerts(ctx context.Context, spanName, user, repository, endpoint string, decode func(response *http.Response) error) error {
+	if err := s.checkUser(user); err != nil {
+		return err
+	}
+	if err := s.checkRepository(repository); err != nil {
+		return err
+	}
+	ctx, span := s.getTracer().Start(ctx, spanName, StringAttribute("account", user), StringAttribute("repository", repository))
+	defer span.End()
+
+	pageUrl := fmt.Sprintf("%s/repos/%s/%s/%s?state=open&per_page=%d", s.BaseUrl, user, repository, endpoint, s.getPerPage())
+	for pageUrl != "" {
+		response, err := s.get(ctx, span, pageUrl)
+		if err != nil {
+			return err
+		}
+		err = func() error {
+			defer response.Body.Close()
+			// Alerts that are disabled or not visible to the token are reported as forbidden or not found.
+			if response.StatusCode == http.StatusForbidden || response.StatusCode == http.StatusNotFound {
+				if apiError := s.newApiError(response); errors.Is(apiError, ErrRateLimited) || errors.Is(apiError, ErrSSORequired) {
+					return apiError
+				}
+				return errAlertsUnavailable
+			}
+			if response.StatusCode != http.StatusOK {
+				return fmt.Errorf("could not get %s for the repository %s: %w", endpoint, repository, s.newApiError(response))
+			}
+			return decode(response)
+		}()
+		if err != nil {
+			return err
+		}
+		pageUrl = nextPage(response.Header.Get("Link"))
+		// The token is sent with the next page request, so it must not leave the API host.
+		if pageUrl != "" && !s.sameOrigin(pageUrl) {
+			return fmt.Errorf("could not get %s for the repository %s: the next page %s is not on %s", endpoint, repository, pageUrl, s.BaseUrl)
+		}
+	}
+
+	return nil
+}
+
+// sameOrigin reports whether the url has the scheme and host of Scanner.BaseUrl.
+func (s *Scanner) sameOrigin(rawUrl string) bool {
+	target, err := url.Parse(rawUrl)
+	if err != nil {
+		return false
+	}
+	base, err := url.Parse(s.BaseUrl)
+	if err != nil {
+		return false
+	}
+
+	return strings.EqualFold(target.Scheme, base.Scheme) && strings.EqualFold(target.Host, base.Host)
+}
+
+// getSecurityAlerts counts open alerts of the scanned sources by severity. Unavailable alert sources are noted
+// instead of failing the scan, as most repositories do not have all of them enabled.
+func (s *Scanner) getSecurityAlerts(ctx context.Context, owner, repository string) (*SecurityAlerts, error) {
+	alerts := &SecurityAlerts{}
+	for _, source := range s.ScanAlerts {
+		counts := make(map[string]int)
+		var err error
+		switch source {
+		case AlertSourceDependabot:
+			var dependabotAlerts []*DependabotAlert
+			dependabotAlerts, err = s.getDependabotAlerts(ctx, owner, repository)
+			for _, alert := range dependabotAlerts {
+				counts[alert.SecurityAdvisory.Severity]++
+			}
+		case AlertSourceCodeScanning:
+			var codeScanningAlerts []*CodeScanningAlert
+			codeScanningAlerts, err = s.getCodeScanningAlerts(ctx, owner, repository)
+			for _, alert := range codeScanningAlerts {
+				counts[alert.Severity()]++
+			}
+		case AlertSourceSecretScanning:
+			var secretScanningAlerts []*SecretScanningAlert
+			secretScanningAlerts, err = s.getSecretScanningAlerts(ctx, owner, repository)
+			counts["critical"] = len(secretScanningAlerts)
+		default:
+			return nil, fmt.Errorf("unknown alert source: %s", source)
+		}
+		if errors.Is(err, errAlertsUnavailable) {
+			alerts.Unavailable = append(alerts.Unavailable, source)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch source {
+		case AlertSourceDependabot:
+			alerts.Dependabot = counts
+		case AlertSourceCodeScanning:
+			alerts.CodeScanning = counts
+		case AlertSourceSecretScanning:
+			alerts.SecretScanning = counts
+		}
+	}
+
+	return alerts, nil
+}
+
+// BySeverity sums the counts of all sources.
+func (a *SecurityAlerts) BySeverity() map[string]int {
+	total := make(map[string]int)
+	for _, counts := range []map[string]int{a.Dependabot, a.CodeScanning, a.SecretScanning} {
+		for severity, count := range counts {
+			total[severity] += count
+		}
+	}
+
+	return total
+}
+
+// FormatSeverityCounts formats the counts from the most severe, e.g. "critical 1, high 2".
+func FormatSeverityCounts(counts map[string]int) string {
+	var parts []string
+	for _, severity := range Severities {
+		if counts[severity] > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", severity, counts[severity]))
+		}
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,141 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGetDependabotAlerts(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/test/test/dependabot/alerts" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("after") == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repos/test/test/dependabot/alerts?state=open&per_page=2&after=c1>; rel="next"`, server.URL))
+			w.Write([]byte(`[{"number": 1, "security_advisory": {"severity": "high"}}, {"number": 2, "security_advisory": {"severity": "critical"}}]`))
+			return
+		}
+		w.Write([]byte(`[{"number": 3, "security_advisory": {"severity": "high"}}]`))
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL, PerPage: 2, ScanAlerts: []string{AlertSourceDependabot}}
+	alerts, err := scanner.GetDependabotAlerts("test", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 3 {
+		t.Fatalf("invalid alerts count, expected 3, got %d", len(alerts))
+	}
+
+	summary, err := scanner.getSecurityAlerts(context.Background(), "test", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if formatted := FormatSeverityCounts(summary.Dependabot); formatted != "critical 1, high 2" {
+		t.Fatalf("invalid severity counts, expected 'critical 1, high 2', got '%s'", formatted)
+	}
+}
+
+func TestGetAlertsForeignNextPage(t *testing.T) {
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("the token is sent to a foreign host, got the header %q", r.Header.Get("Authorization"))
+		w.Write([]byte(`[]`))
+	}))
+	defer other.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", fmt.Sprintf(`<%s/repos/test/test/dependabot/alerts?after=c1>; rel="next"`, other.URL))
+		w.Write([]byte(`[{"number": 1, "security_advisory": {"severity": "high"}}]`))
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL, Token: "token"}
+	if _, err := scanner.GetDependabotAlerts("test", "test"); err == nil || !strings.Contains(err.Error(), "is not on") {
+		t.Fatalf("next page on a foreign host is expected to fail, got %v", err)
+	}
+}
+
+func TestCodeScanningSeverity(t *testing.T) {
+	tests := []struct {
+		severity, securitySeverityLevel, expected string
+	}{
+		{"error", "", "high"},
+		{"note", "", "low"},
+		{"none", "", "none"},
+		{"warning", "critical", "critical"},
+	}
+	for _, test := range tests {
+		alert := &CodeScanningAlert{}
+		alert.Rule.Severity, alert.Rule.SecuritySeverityLevel = test.severity, test.securitySeverityLevel
+		if severity := alert.Severity(); severity != test.expected {
+			t.Fatalf("invalid severity of the %s rule, expected %s, got %s", test.severity, test.expected, severity)
+		}
+	}
+	if formatted := FormatSeverityCounts(map[string]int{"low": 1, "none": 2}); formatted != "low 1, none 2" {
+		t.Fatalf("invalid severity counts, expected 'low 1, none 2', got '%s'", formatted)
+	}
+}
+
+func TestSecurityAlertsUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/test/disabled/dependabot/alerts":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message": "Dependabot alerts are disabled for this repository."}`))
+		case "/repos/test/limited/dependabot/alerts":
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message": "API rate limit exceeded"}`))
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL, ScanAlerts: []string{AlertSourceDependabot}}
+	alerts, err := scanner.getSecurityAlerts(context.Background(), "test", "disabled")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alerts.Dependabot != nil || len(alerts.Unavailable) != 1 || alerts.Unavailable[0] != "dependabot" {
+		t.Fatalf("invalid alerts of the disabled repository, expected unavailable dependabot, got %v", alerts)
+	}
+
+	if _, err := scanner.getSecurityAlerts(context.Background(), "test", "limited"); err == nil {
+		t.Fatalf("rate limited alerts are expected to fail the scan")
+	}
+}
+
+func TestSecurityProfile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/test/test/dependabot/alerts":
+			w.Write([]byte(`[{"number": 1, "security_advisory": {"severity": "high"}}]`))
+		case "/repos/test/test/code-scanning/alerts":
+			w.Write([]byte(`[{"number": 1, "rule": {"severity": "error", "security_severity_level": "critical"}}, {"number": 2, "rule": {"severity": "warning"}}]`))
+		case "/repos/test/test/secret-scanning/alerts":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "Secret scanning is disabled on this repository."}`))
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL, ScanAlerts: AlertSources}
+	alerts, err := scanner.getSecurityAlerts(context.Background(), "test", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if formatted := FormatSeverityCounts(alerts.CodeScanning); formatted != "critical 1, medium 1" {
+		t.Fatalf("invalid code scanning counts, expected 'critical 1, medium 1', got '%s'", formatted)
+	}
+	if alerts.SecretScanning != nil || len(alerts.Unavailable) != 1 || alerts.Unavailable[0] != AlertSourceSecretScanning {
+		t.Fatalf("invalid secret scanning alerts, expected unavailable, got %v", alerts)
+	}
+	if formatted := FormatSeverityCounts(alerts.BySeverity()); formatted != "critical 1, high 1, medium 1" {
+		t.Fatalf("invalid total counts, expected 'critical 1, high 1, medium 1', got '%s'", formatted)
+	}
+}
@@ -0,0 +1,250 @@
+package scanner
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultWatchInterval        = 10 * time.Minute
+	subscriptionBufferSize      = 16
+	defaultMaxDeferredRefreshes = 3
+)
+
+// Update is published to subscribers when watch mode detects changes in an account.
+type Update struct {
+	Account   string        `json:"account"`
+	Changes   []*Change     `json:"changes"`
+	Items     []*ResultItem `json:"items"`
+	ScannedAt time.Time     `json:"scanned_at"`
+}
+
+// ScannerService periodically rescans subscribed accounts and notifies subscribers about changes.
+// It is safe for concurrent use.
+type ScannerService struct {
+	Scanner  *Scanner
+	Interval time.Duration
+	// MaxScansPerRefresh limits how many accounts are scanned by a refresh, the others are deferred to the next
+	// one. High priority accounts are scanned first. There is no limit if it is zero.
+	MaxScansPerRefresh int
+	// MaxDeferredRefreshes is how many refreshes in a row an account may be deferred before it is scanned ahead
+	// of high priority accounts, so they can not starve the others. It is 3 by default.
+	MaxDeferredRefreshes int
+
+	mu       sync.Mutex
+	accounts map[string]*watchedAccount
+}
+
+type watchedAccount struct {
+	items       []*ResultItem
+	scanned     bool
+	subscribers map[chan *Update]struct{}
+	priority    bool
+	// deferred is the number of refreshes in a row the account was not scanned in.
+	deferred int
+}
+
+func NewScannerService(s *Scanner, interval time.Duration) *ScannerService {
+	return &ScannerService{
+		Scanner:  s,
+		Interval: interval,
+		accounts: make(map[string]*watchedAccount),
+	}
+}
+
+// Subscribe registers a subscriber for changes of the account and returns the updates channel
+// together with a function cancelling the subscription and closing the channel.
+// Updates are dropped for subscribers that do not keep up with them.
+func (s *ScannerService) Subscribe(account string) (<-chan *Update, func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	watched, ok := s.accounts[account]
+	if !ok {
+		watched = &watchedAccount{subscribers: make(map[chan *Update]struct{})}
+		s.accounts[account] = watched
+	}
+	updates := make(chan *Update, subscriptionBufferSize)
+	watched.subscribers[updates] = struct{}{}
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			delete(watched.subscribers, updates)
+			close(updates)
+		})
+	}
+
+	return updates, unsubscribe
+}
+
+// Watch adds the account to watch mode without subscribing to it.
+func (s *ScannerService) Watch(account string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.accounts[account]; !ok {
+		s.accounts[account] = &watchedAccount{subscribers: make(map[chan *Update]struct{})}
+	}
+}
+
+// SetPriority pins the account as a high priority one, or unpins it. The account is added to watch mode.
+func (s *ScannerService) SetPriority(account string, high bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	watched, ok := s.accounts[account]
+	if !ok {
+		watched = &watchedAccount{subscribers: make(map[chan *Update]struct{})}
+		s.accounts[account] = watched
+	}
+	watched.priority = high
+}
+
+// Run scans watched accounts every interval until the context is cancelled, a refresh in progress is stopped then.
+// The first scan of an account is a baseline and is not published.
+func (s *ScannerService) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.getInterval())
+	defer ticker.Stop()
+
+	for {
+		s.Refresh(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh scans watched accounts once and publishes detected changes. Accounts beyond MaxScansPerRefresh and
+// all accounts after the rate limit is hit are deferred to the next refresh. The refresh stops once the context
+// is done, the account being scanned is not published then.
+func (s *ScannerService) Refresh(ctx context.Context) {
+	accounts := s.schedule()
+	for i, account := range accounts {
+		if s.MaxScansPerRefresh > 0 && i >= s.MaxScansPerRefresh {
+			s.deferAccounts(accounts[i:])
+			return
+		}
+
+		items, err := s.Scanner.ScanRepositoriesContext(ctx, account)
+		if ctx.Err() != nil {
+			return
+		}
+		if errors.Is(err, ErrRateLimited) {
+			s.Scanner.getLogger().Warn("rate limit is exceeded, remaining accounts are deferred", "account", account)
+			s.deferAccounts(accounts[i:])
+			return
+		}
+		if err != nil {
+			s.Scanner.getLogger().Warn("watched account scan failed", "account", account, "error", err)
+			continue
+		}
+		s.publish(account, items)
+	}
+}
+
+// schedule orders the accounts for a refresh: accounts deferred too many times first, then high priority ones,
+// then the rest, the longest deferred first.
+func (s *ScannerService) schedule() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	maxDeferred := s.getMaxDeferredRefreshes()
+	rank := func(watched *watchedAccount) int {
+		switch {
+		case watched.deferred >= maxDeferred:
+			return 0
+		case watched.priority:
+			return 1
+		default:
+			return 2
+		}
+	}
+
+	accounts := make([]string, 0, len(s.accounts))
+	for account := range s.accounts {
+		accounts = append(accounts, account)
+	}
+	slices.SortFunc(accounts, func(a, b string) int {
+		aWatched, bWatched := s.accounts[a], s.accounts[b]
+		if result := cmp.Compare(rank(aWatched), rank(bWatched)); result != 0 {
+			return result
+		}
+		if result := cmp.Compare(bWatched.deferred, aWatched.deferred); result != 0 {
+			return result
+		}
+		return strings.Compare(a, b)
+	})
+
+	return accounts
+}
+
+func (s *ScannerService) deferAccounts(accounts []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, account := range accounts {
+		if watched, ok := s.accounts[account]; ok {
+			watched.deferred++
+		}
+	}
+}
+
+func (s *ScannerService) publish(account string, items []*ResultItem) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	watched, ok := s.accounts[account]
+	if !ok {
+		return
+	}
+	previousItems, scanned := watched.items, watched.scanned
+	watched.items, watched.scanned, watched.deferred = items, true, 0
+	if !scanned {
+		return
+	}
+
+	changes := DiffResults(previousItems, items)
+	if len(changes) == 0 {
+		return
+	}
+	update := &Update{
+		Account:   account,
+		Changes:   changes,
+		Items:     items,
+		ScannedAt: time.Now(),
+	}
+	for subscriber := range watched.subscribers {
+		select {
+		case subscriber <- update:
+		default:
+		}
+	}
+}
+
+func (s *ScannerService) getMaxDeferredRefreshes() int {
+	if s.MaxDeferredRefreshes <= 0 {
+		return defaultMaxDeferredRefreshes
+	}
+
+	return s.MaxDeferredRefreshes
+}
+
+func (s *ScannerService) getInterval() time.Duration {
+	if s.Interval <= 0 {
+		return defaultWatchInterval
+	}
+
+	return s.Interval
+}
@@ -0,0 +1,137 @@
+package scanner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestScannerServiceSubscribe(t *testing.T) {
+	var releasesResponse atomic.Value
+	releasesResponse.Store(`[{"tag_name": "v1.0.0"}]`)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/users/test/repos" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"full_name": "test/test", "name": "test"}]`))
+		}
+		if r.URL.Path == "/repos/test/test/releases" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(releasesResponse.Load().(string)))
+		}
+	}))
+	defer server.Close()
+
+	service := NewScannerService(&Scanner{BaseUrl: server.URL}, 0)
+	updates, unsubscribe := service.Subscribe("test")
+
+	service.Refresh(context.Background())
+	service.Refresh(context.Background())
+	if len(updates) != 0 {
+		t.Fatalf("invalid updates count without changes, expected 0, got %d", len(updates))
+	}
+
+	releasesResponse.Store(`[{"tag_name": "v1.1.0"}, {"tag_name": "v1.0.0"}]`)
+	service.Refresh(context.Background())
+	if len(updates) != 1 {
+		t.Fatalf("invalid updates count after a new release, expected 1, got %d", len(updates))
+	}
+
+	update := <-updates
+	if update.Account != "test" || len(update.Changes) != 1 {
+		t.Fatalf("invalid update: %+v", update)
+	}
+	change := update.Changes[0]
+	if change.Kind != ChangeReleaseAdded || change.Repository != "test/test" || change.Release != "v1.1.0" {
+		t.Fatalf("invalid change: %+v", change)
+	}
+
+	unsubscribe()
+	if _, ok := <-updates; ok {
+		t.Fatal("updates channel must be closed after unsubscribing")
+	}
+}
+
+func TestScannerServicePriority(t *testing.T) {
+	var mu sync.Mutex
+	var scanned []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users/{account}/repos", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		scanned = append(scanned, r.PathValue("account"))
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[]`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	service := NewScannerService(&Scanner{BaseUrl: server.URL}, 0)
+	service.MaxScansPerRefresh = 1
+	service.MaxDeferredRefreshes = 2
+	service.Watch("a")
+	service.Watch("b")
+	service.SetPriority("c", true)
+
+	for i := 0; i < 4; i++ {
+		service.Refresh(context.Background())
+	}
+
+	expected := []string{"c", "c", "a", "b"}
+	if !equal(scanned, expected) {
+		t.Fatalf("invalid scan order, expected %v, got %v", expected, scanned)
+	}
+}
+
+func TestScannerServiceDefersAccountsOnRateLimit(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message": "API rate limit exceeded"}`))
+	}))
+	defer server.Close()
+
+	service := NewScannerService(&Scanner{BaseUrl: server.URL}, 0)
+	service.Watch("a")
+	service.Watch("b")
+	service.Refresh(context.Background())
+
+	if requests.Load() != 1 {
+		t.Fatalf("invalid requests count, expected 1, got %d", requests.Load())
+	}
+}
+
+func TestScannerServiceRefreshStopsWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var mu sync.Mutex
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.URL.Path)
+		mu.Unlock()
+		// the watch mode is stopped while the first account is scanned
+		cancel()
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[{"full_name": "a/test", "name": "test"}]`))
+	}))
+	defer server.Close()
+
+	service := NewScannerService(&Scanner{BaseUrl: server.URL}, 0)
+	updates, unsubscribe := service.Subscribe("a")
+	defer unsubscribe()
+	service.Watch("b")
+	if err := service.Run(ctx); err != context.Canceled {
+		t.Fatalf("invalid error, expected %v, got %v", context.Canceled, err)
+	}
+
+	if expected := []string{"/users/a/repos"}; !equal(requested, expected) {
+		t.Fatalf("invalid requests, expected %v, got %v", expected, requested)
+	}
+	if len(updates) != 0 || service.accounts["a"].scanned {
+		t.Fatal("the stopped scan of the account must not be published")
+	}
+}
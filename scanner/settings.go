@@ -0,0 +1,96 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+const ChangeSettingChanged = "setting_changed"
+
+// RepositorySettings are the repository settings tracked for policy drift. Merge settings are only visible
+// to tokens with push access to the repository, they are nil if unknown.
+type RepositorySettings struct {
+	DefaultBranch       string `json:"default_branch"`
+	AllowMergeCommit    *bool  `json:"allow_merge_commit,omitempty"`
+	AllowSquashMerge    *bool  `json:"allow_squash_merge,omitempty"`
+	AllowRebaseMerge    *bool  `json:"allow_rebase_merge,omitempty"`
+	DeleteBranchOnMerge *bool  `json:"delete_branch_on_merge,omitempty"`
+	HasDiscussions      *bool  `json:"has_discussions,omitempty"`
+}
+
+func (s *Scanner) GetRepositorySettings(owner, repository string) (*RepositorySettings, error) {
+	return s.getRepositorySettings(context.Background(), owner, repository)
+}
+
+func (s *Scanner) getRepositorySettings(ctx context.Context, owner, repository string) (*RepositorySettings, error) {
+	if err := s.checkUser(owner); err != nil {
+		return nil, err
+	}
+	if err := s.checkRepository(repository); err != nil {
+		return nil, err
+	}
+	ctx, span := s.getTracer().Start(ctx, "GetRepositorySettings", StringAttribute("account", owner), StringAttribute("repository", repository))
+	defer span.End()
+
+	response, err := s.get(ctx, span, fmt.Sprintf("%s/repos/%s/%s", s.BaseUrl, owner, repository))
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil, newNotFoundError("repository %s/%s does not exist", owner, repository)
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not get settings of the repository %s/%s: %w", owner, repository, s.newApiError(response))
+	}
+
+	var settings RepositorySettings
+	if err := json.NewDecoder(response.Body).Decode(&settings); err != nil {
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+// values returns the known settings by their names.
+func (r *RepositorySettings) values() map[string]string {
+	values := map[string]string{"default_branch": r.DefaultBranch}
+	for name, value := range map[string]*bool{
+		"allow_merge_commit":     r.AllowMergeCommit,
+		"allow_squash_merge":     r.AllowSquashMerge,
+		"allow_rebase_merge":     r.AllowRebaseMerge,
+		"delete_branch_on_merge": r.DeleteBranchOnMerge,
+		"has_discussions":        r.HasDiscussions,
+	} {
+		if value != nil {
+			values[name] = strconv.FormatBool(*value)
+		}
+	}
+
+	return values
+}
+
+// diffSettings returns changes of the settings known in both scans.
+func diffSettings(repository string, old, new *RepositorySettings) []*Change {
+	if old == nil || new == nil {
+		return nil
+	}
+
+	oldValues, newValues := old.values(), new.values()
+	var changes []*Change
+	for name, newValue := range newValues {
+		if oldValue, ok := oldValues[name]; ok && oldValue != newValue {
+			changes = append(changes, &Change{Kind: ChangeSettingChanged, Repository: repository, Setting: name, From: oldValue, To: newValue})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Setting < changes[j].Setting
+	})
+
+	return changes
+}
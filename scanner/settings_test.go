@@ -0,0 +1,58 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestScanRepositorySettings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/test/repos":
+			w.Write([]byte(`[{"full_name": "test/test", "name": "test"}]`))
+		case "/repos/test/test/releases":
+			w.Write([]byte(`[]`))
+		case "/repos/test/test":
+			w.Write([]byte(`{"default_branch": "main", "allow_squash_merge": true, "delete_branch_on_merge": false}`))
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL, ScanSettings: true}
+	items, err := scanner.ScanRepositories("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	settings := items[0].Settings
+	if settings == nil || settings.DefaultBranch != "main" || settings.AllowSquashMerge == nil || !*settings.AllowSquashMerge {
+		t.Fatalf("invalid repository settings: %+v", settings)
+	}
+	if settings.AllowMergeCommit != nil {
+		t.Fatalf("unknown setting must be nil, got %v", *settings.AllowMergeCommit)
+	}
+}
+
+func TestDiffSettings(t *testing.T) {
+	enabled, disabled := true, false
+	old := []*ResultItem{{
+		Repository: &Repository{FullName: "test/test"},
+		Settings:   &RepositorySettings{DefaultBranch: "master", AllowSquashMerge: &enabled},
+	}}
+	new := []*ResultItem{{
+		Repository: &Repository{FullName: "test/test"},
+		Settings:   &RepositorySettings{DefaultBranch: "main", AllowSquashMerge: &disabled, HasDiscussions: &enabled},
+	}}
+
+	changes := DiffResults(old, new)
+	if len(changes) != 2 {
+		t.Fatalf("invalid changes count, expected 2, got %d: %v", len(changes), changes)
+	}
+	if changes[0].Setting != "allow_squash_merge" || changes[0].From != "true" || changes[0].To != "false" {
+		t.Fatalf("invalid change: %+v", changes[0])
+	}
+	if changes[1].Kind != ChangeSettingChanged || changes[1].Setting != "default_branch" || changes[1].From != "master" || changes[1].To != "main" {
+		t.Fatalf("invalid change: %+v", changes[1])
+	}
+}
@@ -0,0 +1,553 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Signature verification statuses of releases and their signed assets.
+const (
+	// VerificationVerified is the status of signatures all checked by a verifier.
+	VerificationVerified = "verified"
+	// VerificationFailed is the status of releases with a signature a verifier rejected.
+	VerificationFailed = "failed"
+	// VerificationUnverified is the status of signatures no verifier could check.
+	VerificationUnverified = "unverified"
+	// VerificationUnsigned is the status of releases without signature or attestation assets.
+	VerificationUnsigned = "unsigned"
+)
+
+// signatureSuffixes are the name suffixes of the assets signing the asset named without them. Attestations of
+// a whole release, e.g. "multiple.intoto.jsonl", sign all of its assets.
+var signatureSuffixes = []string{".sig", ".asc", ".sigstore", ".sigstore.json", ".intoto.jsonl"}
+
+const (
+	certificateSuffix = ".pem"
+	attestationSuffix = ".intoto.jsonl"
+)
+
+// ErrUnsupportedSignature is returned by verifiers for signatures not in their format, the next verifier supporting
+// the signature asset is tried then.
+var ErrUnsupportedSignature = errors.New("unsupported signature format")
+
+// ErrUnverifiable is returned by verifiers for signatures they support but can not check with their configuration,
+// e.g. keyless signatures without trusted roots. The signature is reported as unverified instead of failed.
+var ErrUnverifiable = errors.New("signature could not be verified")
+
+// SignatureVerifier checks the signatures of release assets, e.g. cosign or GPG ones. Verifiers are tried in
+// order, the first one supporting the signature asset decides unless it returns ErrUnsupportedSignature.
+type SignatureVerifier interface {
+	// Name identifies the verifier in the results, e.g. "cosign".
+	Name() string
+	// Supports reports whether the verifier checks the signature asset, e.g. by its name suffix.
+	Supports(signature *Asset) bool
+	// Verify checks the signature of the downloaded asset. The paths are of the downloaded files, the certificate
+	// path is empty unless the release has a ".pem" certificate of the asset.
+	Verify(ctx context.Context, assetPath, signaturePath, certificatePath string) error
+}
+
+// AssetVerification is the verification result of a signature of a release asset.
+type AssetVerification struct {
+	Asset     string `json:"asset"`
+	Signature string `json:"signature"`
+	// Verifier is the name of the verifier that checked the signature, empty if none could.
+	Verifier string `json:"verifier,omitempty"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+}
+
+// ReleaseVerification is the signature verification status of a release.
+type ReleaseVerification struct {
+	// Status is VerificationFailed if any signature is rejected, VerificationVerified if all of them are
+	// verified, VerificationUnverified if some could not be checked and VerificationUnsigned if there are none.
+	Status string               `json:"status"`
+	Assets []*AssetVerification `json:"assets,omitempty"`
+}
+
+// signedAsset is an asset with one of its signatures.
+type signedAsset struct {
+	asset       *Asset
+	signature   *Asset
+	certificate *Asset
+}
+
+// signedAssets pairs the assets of the release with their signature assets by name.
+func signedAssets(release *Release) []*signedAsset {
+	byName := make(map[string]*Asset, len(release.Assets))
+	for _, asset := range release.Assets {
+		byName[asset.Name] = asset
+	}
+	isSignature := func(asset *Asset) bool {
+		for _, suffix := range signatureSuffixes {
+			if strings.HasSuffix(asset.Name, suffix) {
+				return true
+			}
+		}
+		return strings.HasSuffix(asset.Name, certificateSuffix)
+	}
+
+	var signed []*signedAsset
+	for _, signature := range release.Assets {
+		for _, suffix := range signatureSuffixes {
+			name, ok := strings.CutSuffix(signature.Name, suffix)
+			if !ok {
+				continue
+			}
+			if asset := byName[name]; asset != nil {
+				signed = append(signed, &signedAsset{asset: asset, signature: signature, certificate: byName[name+certificateSuffix]})
+			} else if suffix == attestationSuffix {
+				for _, asset := range release.Assets {
+					if !isSignature(asset) {
+						signed = append(signed, &signedAsset{asset: asset, signature: signature})
+					}
+				}
+			}
+			break
+		}
+	}
+
+	return signed
+}
+
+// VerifyRelease checks the signatures of the release assets with the verifiers of the scanner. Signed assets are
+// downloaded to a temporary directory, only if a verifier supports their signatures.
+func (s *Scanner) VerifyRelease(ctx context.Context, release *Release) (*ReleaseVerification, error) {
+	verification := &ReleaseVerification{Status: VerificationUnsigned}
+	signed := signedAssets(release)
+	if len(signed) == 0 {
+		return verification, nil
+	}
+
+	dir, err := os.MkdirTemp("", "githubscanner-signatures-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	downloaded := make(map[*Asset]string)
+	download := func(asset *Asset) (string, error) {
+		if asset == nil {
+			return "", nil
+		}
+		if path, ok := downloaded[asset]; ok {
+			return path, nil
+		}
+		path := filepath.Join(dir, fmt.Sprintf("%d-%s", len(downloaded), filepath.Base(asset.Name)))
+		if err := s.DownloadAssetToFile(ctx, asset, path); err != nil {
+			return "", err
+		}
+		downloaded[asset] = path
+		return path, nil
+	}
+
+	verified := 0
+	for _, pair := range signed {
+		result := &AssetVerification{Asset: pair.asset.Name, Signature: pair.signature.Name, Status: VerificationUnverified}
+		verification.Assets = append(verification.Assets, result)
+		for _, verifier := range s.SignatureVerifiers {
+			if !verifier.Supports(pair.signature) {
+				continue
+			}
+			paths := make([]string, 3)
+			for i, asset := range []*Asset{pair.asset, pair.signature, pair.certificate} {
+				if paths[i], err = download(asset); err != nil {
+					break
+				}
+			}
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				// the signature is not rejected, it could not be checked
+				result.Error = fmt.Sprintf("could not download %s: %v", pair.asset.Name, err)
+				break
+			}
+			err = verifier.Verify(ctx, paths[0], paths[1], paths[2])
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if errors.Is(err, ErrUnsupportedSignature) {
+				continue
+			}
+			result.Verifier = verifier.Name()
+			if errors.Is(err, ErrUnverifiable) {
+				result.Error = err.Error()
+			} else if err != nil {
+				result.Status, result.Error = VerificationFailed, err.Error()
+			} else {
+				result.Status = VerificationVerified
+				verified++
+			}
+			break
+		}
+		if result.Status == VerificationFailed {
+			verification.Status = VerificationFailed
+		}
+	}
+	if verification.Status != VerificationFailed {
+		verification.Status = VerificationUnverified
+		if verified == len(signed) {
+			verification.Status = VerificationVerified
+		}
+	}
+
+	return verification, nil
+}
+
+// CosignVerifier verifies cosign blob signatures: ".sig" assets with the base64 signature of the asset, e.g.
+// made by `cosign sign-blob`. Signatures are checked with the public keys if any are set, certificates shipped with
+// the release never replace them. Otherwise the ".pem" certificate of keyless signatures is used, only if it chains
+// to the Roots and matches both the expected Identities and Issuers: anybody can upload a self-signed certificate,
+// and any workflow gets a certificate of the GitHub issuer. Keyless signatures also require the Rekor log, the
+// short-lived certificate must be valid when the signature was recorded there.
+type CosignVerifier struct {
+	// PublicKeys verify the signatures, e.g. made with `cosign sign-blob --key`.
+	PublicKeys []crypto.PublicKey
+	// Roots verify the certificates, e.g. the Sigstore Fulcio roots. Keyless signatures are unverified without them.
+	Roots *x509.CertPool
+	// Intermediates are the intermediate certificates of the Roots.
+	Intermediates *x509.CertPool
+	// Identities are patterns of the expected email or URI subject alternative names of the certificates, e.g.
+	// "https://github.com/acme/*" for workflows of the acme organization. * matches any characters.
+	Identities []string
+	// Issuers are the expected OIDC issuers of the certificates, e.g. "https://token.actions.githubusercontent.com".
+	Issuers []string
+	// Rekor is the transparency log the signatures must be recorded in, e.g. https://rekor.sigstore.dev. The log
+	// is not checked for signatures of the public keys if it is nil, keyless signatures are unverified then. The
+	// entry must be of the signature and certificate, its inclusion proof is not checked.
+	Rekor *RekorClient
+}
+
+func (v *CosignVerifier) Name() string {
+	return "cosign"
+}
+
+func (v *CosignVerifier) Supports(signature *Asset) bool {
+	return strings.HasSuffix(signature.Name, ".sig")
+}
+
+func (v *CosignVerifier) Verify(ctx context.Context, assetPath, signaturePath, certificatePath string) error {
+	data, err := os.ReadFile(signaturePath)
+	if err != nil {
+		return err
+	}
+	signature, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(data)))
+	if err != nil {
+		// binary GPG signatures use the same suffix
+		return ErrUnsupportedSignature
+	}
+
+	keys := v.PublicKeys
+	var certificate *x509.Certificate
+	if len(keys) == 0 {
+		if certificatePath == "" {
+			return fmt.Errorf("%w: no public key to verify the signature with", ErrUnverifiable)
+		}
+		if v.Roots == nil {
+			return fmt.Errorf("%w: no trusted roots to verify the certificate with", ErrUnverifiable)
+		}
+		if len(v.Identities) == 0 || len(v.Issuers) == 0 {
+			return fmt.Errorf("%w: no expected certificate identity and issuer", ErrUnverifiable)
+		}
+		if v.Rekor == nil {
+			return fmt.Errorf("%w: no transparency log to check the certificate was valid at signing time with", ErrUnverifiable)
+		}
+		if certificate, err = v.loadCertificate(certificatePath); err != nil {
+			return err
+		}
+		keys = []crypto.PublicKey{certificate.PublicKey}
+	}
+
+	for _, key := range keys {
+		if err = verifyBlobSignature(key, assetPath, signature); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if v.Rekor == nil {
+		return nil
+	}
+
+	entry, err := v.transparencyLogEntry(ctx, assetPath, signature, certificate)
+	if err != nil {
+		return err
+	}
+	if certificate == nil {
+		return nil
+	}
+
+	return v.verifyCertificate(certificate, entry.IntegratedTime)
+}
+
+// transparencyLogEntry returns the entry of the signature of the asset in the Rekor log. The entry of a keyless
+// signature must be of its certificate, a certificate of another signer is not accepted.
+func (v *CosignVerifier) transparencyLogEntry(ctx context.Context, assetPath string, signature []byte, certificate *x509.Certificate) (*RekorEntry, error) {
+	file, err := os.Open(assetPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return nil, err
+	}
+	digest := hex.EncodeToString(hash.Sum(nil))
+
+	uuids, err := v.Rekor.Search(ctx, digest)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnverifiable, err)
+	}
+	for _, uuid := range uuids {
+		entry, err := v.Rekor.Entry(ctx, uuid)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnverifiable, err)
+		}
+		if entry.Hash != digest || !bytes.Equal(entry.Signature, signature) {
+			continue
+		}
+		if certificate != nil {
+			block, _ := pem.Decode(entry.PublicKey)
+			if block == nil || !bytes.Equal(block.Bytes, certificate.Raw) {
+				continue
+			}
+		}
+		return entry, nil
+	}
+
+	return nil, fmt.Errorf("no transparency log entry of the signature")
+}
+
+// verifyCertificate verifies the certificate with the roots at the time the signature was recorded in the
+// transparency log. Signing certificates are short-lived, they have expired since.
+func (v *CosignVerifier) verifyCertificate(certificate *x509.Certificate, signedAt time.Time) error {
+	_, err := certificate.Verify(x509.VerifyOptions{
+		Roots:         v.Roots,
+		Intermediates: v.Intermediates,
+		CurrentTime:   signedAt,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	})
+	if err != nil {
+		return fmt.Errorf("untrusted certificate: %v", err)
+	}
+
+	return nil
+}
+
+// loadCertificate reads the certificate of the signature, cosign writes it base64 encoded PEM, and checks its
+// identity and issuer. The certificate chain is verified once the signing time is known.
+func (v *CosignVerifier) loadCertificate(path string) (*x509.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		if decoded, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(data))); err == nil {
+			block, _ = pem.Decode(decoded)
+		}
+	}
+	if block == nil {
+		return nil, fmt.Errorf("invalid certificate: no PEM data")
+	}
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate: %v", err)
+	}
+
+	identities := certificate.EmailAddresses
+	for _, uri := range certificate.URIs {
+		identities = append(identities, uri.String())
+	}
+	if !matchesAny(v.Identities, identities) {
+		return nil, fmt.Errorf("unexpected certificate identity %s", strings.Join(identities, ", "))
+	}
+	if issuer := certificateIssuer(certificate); !slices.Contains(v.Issuers, issuer) {
+		return nil, fmt.Errorf("unexpected certificate issuer %q", issuer)
+	}
+
+	return certificate, nil
+}
+
+// Fulcio certificate extensions of the OIDC issuer: the deprecated raw value and the DER encoded one.
+var (
+	oidIssuerV1 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+	oidIssuerV2 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+)
+
+// certificateIssuer returns the OIDC issuer of a Fulcio certificate, empty if it has none.
+func certificateIssuer(certificate *x509.Certificate) string {
+	issuer := ""
+	for _, extension := range certificate.Extensions {
+		switch {
+		case extension.Id.Equal(oidIssuerV2):
+			var value string
+			if _, err := asn1.Unmarshal(extension.Value, &value); err == nil {
+				return value
+			}
+		case extension.Id.Equal(oidIssuerV1):
+			issuer = string(extension.Value)
+		}
+	}
+
+	return issuer
+}
+
+// matchesAny reports whether any value matches any of the patterns, * matches any characters including slashes.
+func matchesAny(patterns, values []string) bool {
+	for _, pattern := range patterns {
+		expression := regexp.MustCompile("^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$")
+		for _, value := range values {
+			if expression.MatchString(value) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// verifyBlobSignature checks the signature of the SHA-256 digest of the file, Ed25519 signatures are of the whole
+// content.
+func verifyBlobSignature(key crypto.PublicKey, path string, signature []byte) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if key, ok := key.(ed25519.PublicKey); ok {
+		content, err := io.ReadAll(file)
+		if err != nil {
+			return err
+		}
+		if !ed25519.Verify(key, content, signature) {
+			return fmt.Errorf("invalid signature")
+		}
+		return nil
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return err
+	}
+	digest := hash.Sum(nil)
+	switch key := key.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, digest, signature) {
+			return fmt.Errorf("invalid signature")
+		}
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest, signature); err != nil {
+			return fmt.Errorf("invalid signature: %v", err)
+		}
+	default:
+		return fmt.Errorf("unsupported public key type %T", key)
+	}
+
+	return nil
+}
+
+// LoadPublicKey reads a PEM encoded public key, e.g. the cosign.pub of `cosign generate-key-pair`.
+func LoadPublicKey(path string) (crypto.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("could not parse the public key %s: no PEM data", path)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the public key %s: %v", path, err)
+	}
+
+	return key, nil
+}
+
+// LoadCertificatePool reads PEM encoded certificates, e.g. the Sigstore Fulcio root and intermediate ones.
+func LoadCertificatePool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("could not parse the certificates %s: no PEM certificates", path)
+	}
+
+	return pool, nil
+}
+
+// CommandVerifier verifies signatures with an external command, e.g. GPG ones with
+// ["gpg", "--verify", "{signature}", "{asset}"]. The {asset}, {signature} and {certificate} arguments are replaced
+// with the paths of the downloaded files. A signature is verified if the command exits with 0.
+type CommandVerifier struct {
+	// Suffixes are the name suffixes of the supported signature assets, e.g. ".asc".
+	Suffixes []string
+	Command  []string
+}
+
+func (v *CommandVerifier) Name() string {
+	if len(v.Command) == 0 {
+		return "command"
+	}
+
+	return filepath.Base(v.Command[0])
+}
+
+func (v *CommandVerifier) Supports(signature *Asset) bool {
+	for _, suffix := range v.Suffixes {
+		if strings.HasSuffix(signature.Name, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (v *CommandVerifier) Verify(ctx context.Context, assetPath, signaturePath, certificatePath string) error {
+	if len(v.Command) == 0 {
+		return fmt.Errorf("verification command is not specified")
+	}
+	replacer := strings.NewReplacer("{asset}", assetPath, "{signature}", signaturePath, "{certificate}", certificatePath)
+	args := make([]string, len(v.Command))
+	for i, arg := range v.Command {
+		args[i] = replacer.Replace(arg)
+	}
+
+	output, err := exec.CommandContext(ctx, args[0], args[1:]...).CombinedOutput()
+	if err != nil {
+		if message := strings.TrimSpace(string(output)); message != "" {
+			return fmt.Errorf("%v: %s", err, lastLine(message))
+		}
+		return err
+	}
+
+	return nil
+}
+
+func lastLine(text string) string {
+	return text[strings.LastIndex(text, "\n")+1:]
+}
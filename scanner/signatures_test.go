@@ -0,0 +1,394 @@
+package scanner
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignedAssets(t *testing.T) {
+	release := &Release{}
+	for _, name := range []string{"tool.tar.gz", "tool.tar.gz.sig", "tool.tar.gz.pem", "tool.zip", "tool.zip.asc", "multiple.intoto.jsonl", "orphan.sig"} {
+		release.Assets = append(release.Assets, &Asset{Name: name})
+	}
+
+	var pairs []string
+	for _, signed := range signedAssets(release) {
+		pair := signed.asset.Name + " " + signed.signature.Name
+		if signed.certificate != nil {
+			pair += " " + signed.certificate.Name
+		}
+		pairs = append(pairs, pair)
+	}
+	expected := []string{
+		"tool.tar.gz tool.tar.gz.sig tool.tar.gz.pem",
+		"tool.zip tool.zip.asc",
+		"tool.tar.gz multiple.intoto.jsonl",
+		"tool.zip multiple.intoto.jsonl",
+	}
+	if !equal(pairs, expected) {
+		t.Fatalf("invalid signed assets, expected %v, got %v", expected, pairs)
+	}
+}
+
+func TestVerifyRelease(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sign := func(content string) string {
+		digest := sha256.Sum256([]byte(content))
+		signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.StdEncoding.EncodeToString(signature)
+	}
+	contents := map[string]string{
+		"/signed.tar.gz":         "signed build",
+		"/signed.tar.gz.sig":     sign("signed build"),
+		"/changed.tar.gz":        "changed build",
+		"/changed.tar.gz.sig":    sign("original build"),
+		"/gpg.tar.gz":            "gpg build",
+		"/gpg.tar.gz.asc":        "good",
+		"/bad.tar.gz":            "bad build",
+		"/bad.tar.gz.asc":        "bad",
+		"/other.tar.gz":          "other build",
+		"/other.tar.gz.sigstore": "{}",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, ok := contents[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(content))
+	}))
+	defer server.Close()
+	newRelease := func(names ...string) *Release {
+		release := &Release{TagName: "v1.0.0"}
+		for _, name := range names {
+			release.Assets = append(release.Assets, &Asset{Name: name, BrowserDownloadURL: server.URL + "/" + name})
+		}
+		return release
+	}
+
+	scanner := &Scanner{SignatureVerifiers: []SignatureVerifier{
+		// the signature content stands for a valid or invalid GPG signature
+		&CommandVerifier{Suffixes: []string{".asc"}, Command: []string{"sh", "-c", `grep -q good "$0" || { echo "BAD signature"; exit 1; }`, "{signature}"}},
+		&CosignVerifier{PublicKeys: []crypto.PublicKey{&key.PublicKey}},
+	}}
+	tests := []struct {
+		release  *Release
+		status   string
+		statuses []string
+	}{
+		{newRelease("signed.tar.gz", "signed.tar.gz.sig", "gpg.tar.gz", "gpg.tar.gz.asc"), VerificationVerified, []string{VerificationVerified, VerificationVerified}},
+		{newRelease("signed.tar.gz", "signed.tar.gz.sig", "changed.tar.gz", "changed.tar.gz.sig"), VerificationFailed, []string{VerificationVerified, VerificationFailed}},
+		{newRelease("bad.tar.gz", "bad.tar.gz.asc"), VerificationFailed, []string{VerificationFailed}},
+		{newRelease("signed.tar.gz", "signed.tar.gz.sig", "other.tar.gz", "other.tar.gz.sigstore"), VerificationUnverified, []string{VerificationVerified, VerificationUnverified}},
+		{newRelease("missing.tar.gz", "missing.tar.gz.sig"), VerificationUnverified, []string{VerificationUnverified}},
+		{newRelease("signed.tar.gz"), VerificationUnsigned, nil},
+	}
+	for _, test := range tests {
+		verification, err := scanner.VerifyRelease(context.Background(), test.release)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if verification.Status != test.status {
+			t.Fatalf("invalid status, expected %s, got %s: %+v", test.status, verification.Status, verification.Assets)
+		}
+		var statuses []string
+		for _, asset := range verification.Assets {
+			statuses = append(statuses, asset.Status)
+		}
+		if !equal(statuses, test.statuses) {
+			t.Fatalf("invalid asset statuses, expected %v, got %v", test.statuses, statuses)
+		}
+	}
+
+	verification, err := scanner.VerifyRelease(context.Background(), newRelease("bad.tar.gz", "bad.tar.gz.asc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := verification.Assets[0]; result.Verifier != "sh" || !strings.Contains(result.Error, "BAD signature") {
+		t.Fatalf("invalid failed verification: %+v", result)
+	}
+
+	// signatures the verifier can not check with its configuration are not rejected
+	scanner = &Scanner{SignatureVerifiers: []SignatureVerifier{&CosignVerifier{}}}
+	verification, err = scanner.VerifyRelease(context.Background(), newRelease("signed.tar.gz", "signed.tar.gz.sig"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := verification.Assets[0]; verification.Status != VerificationUnverified || result.Verifier != "cosign" || !strings.Contains(result.Error, "no public key") {
+		t.Fatalf("invalid unverifiable verification, expected unverified by cosign, got %s: %+v", verification.Status, result)
+	}
+}
+
+func TestCosignVerifierCertificate(t *testing.T) {
+	rootKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signingKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issued := time.Now().Add(-48 * time.Hour)
+	rootTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "root"},
+		NotBefore:             issued.Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	rootDER, err := x509.CreateCertificate(rand.Reader, rootTemplate, rootTemplate, &rootKey.PublicKey, rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := x509.ParseCertificate(rootDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// signing certificates expire minutes after they are issued
+	issuer, err := asn1.Marshal("https://token.actions.githubusercontent.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	workflow, err := url.Parse("https://github.com/acme/cli/.github/workflows/release.yml@refs/tags/v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	signingDER, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber:    big.NewInt(2),
+		NotBefore:       issued,
+		NotAfter:        issued.Add(10 * time.Minute),
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		URIs:            []*url.URL{workflow},
+		ExtraExtensions: []pkix.Extension{{Id: oidIssuerV2, Value: issuer}},
+	}, root, &signingKey.PublicKey, rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	digest := sha256.Sum256([]byte("build"))
+	signature, err := ecdsa.SignASN1(rand.Reader, signingKey, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	assetPath := write("tool.tar.gz", "build")
+	signaturePath := write("tool.tar.gz.sig", base64.StdEncoding.EncodeToString(signature))
+	// cosign writes the certificate base64 encoded PEM
+	certificatePEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: signingDER})
+	certificatePath := write("tool.tar.gz.pem", base64.StdEncoding.EncodeToString(certificatePEM))
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherDER, err := x509.CreateCertificate(rand.Reader, rootTemplate, rootTemplate, &otherKey.PublicKey, otherKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := x509.ParseCertificate(otherDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots, untrusted := x509.NewCertPool(), x509.NewCertPool()
+	roots.AddCert(root)
+	untrusted.AddCert(other)
+
+	logged := newRekorServer(&rekorTestEntry{"logged", digest, signature, certificatePEM, issued.Add(time.Minute)})
+	defer logged.Close()
+	expired := newRekorServer(&rekorTestEntry{"expired", digest, signature, certificatePEM, issued.Add(time.Hour)})
+	defer expired.Close()
+	otherPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: otherDER})
+	otherSigner := newRekorServer(&rekorTestEntry{"other", digest, signature, otherPEM, issued.Add(time.Minute)})
+	defer otherSigner.Close()
+	rekor := &RekorClient{BaseUrl: logged.URL}
+	identities := []string{"https://github.com/acme/cli/.github/workflows/*"}
+	issuers := []string{"https://token.actions.githubusercontent.com"}
+
+	tests := []struct {
+		verifier *CosignVerifier
+		// err is the expected error text, empty if the signature is verified
+		err          string
+		unverifiable bool
+	}{
+		{&CosignVerifier{Roots: roots, Identities: identities, Issuers: issuers, Rekor: rekor}, "", false},
+		{&CosignVerifier{Roots: roots, Identities: []string{"https://github.com/other/*"}, Issuers: issuers, Rekor: rekor}, "unexpected certificate identity", false},
+		{&CosignVerifier{Roots: roots, Identities: identities, Issuers: []string{"https://accounts.google.com"}, Rekor: rekor}, "unexpected certificate issuer", false},
+		{&CosignVerifier{Roots: untrusted, Identities: identities, Issuers: issuers, Rekor: rekor}, "untrusted certificate", false},
+		// a certificate of the release is not trusted by itself
+		{&CosignVerifier{Identities: identities, Issuers: issuers, Rekor: rekor}, "no trusted roots", true},
+		// any workflow gets a certificate of the issuer
+		{&CosignVerifier{Roots: roots, Issuers: issuers, Rekor: rekor}, "no expected certificate identity and issuer", true},
+		{&CosignVerifier{Roots: roots, Identities: identities, Rekor: rekor}, "no expected certificate identity and issuer", true},
+		// the certificate could have been used after it expired
+		{&CosignVerifier{Roots: roots, Identities: identities, Issuers: issuers}, "no transparency log", true},
+		{&CosignVerifier{Roots: roots, Identities: identities, Issuers: issuers, Rekor: &RekorClient{BaseUrl: expired.URL}}, "certificate has expired", false},
+		// the log entry of the signature must be of the same certificate
+		{&CosignVerifier{Roots: roots, Identities: identities, Issuers: issuers, Rekor: &RekorClient{BaseUrl: otherSigner.URL}}, "no transparency log entry of the signature", false},
+		// a certificate of the release never replaces the configured keys
+		{&CosignVerifier{PublicKeys: []crypto.PublicKey{&otherKey.PublicKey}, Roots: roots, Identities: identities, Issuers: issuers, Rekor: rekor}, "invalid signature", false},
+	}
+	for i, test := range tests {
+		err := test.verifier.Verify(context.Background(), assetPath, signaturePath, certificatePath)
+		if test.err == "" && err != nil {
+			t.Fatalf("signature %d is not verified: %v", i, err)
+		}
+		if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err) || errors.Is(err, ErrUnverifiable) != test.unverifiable) {
+			t.Fatalf("invalid error of the verification %d, expected %q (unverifiable: %v), got %v", i, test.err, test.unverifiable, err)
+		}
+	}
+
+	// binary signatures are left to the next verifier
+	binaryPath := write("tool.tar.gz.gpg.sig", "\x89\x01\x33binary")
+	if err := tests[0].verifier.Verify(context.Background(), assetPath, binaryPath, ""); err != ErrUnsupportedSignature {
+		t.Fatalf("invalid error of a binary signature, expected %v, got %v", ErrUnsupportedSignature, err)
+	}
+}
+
+func TestCosignVerifierTransparencyLog(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey})
+	sign := func(content string) []byte {
+		digest := sha256.Sum256([]byte(content))
+		signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signature
+	}
+	logged := sign("logged build")
+	server := newRekorServer(&rekorTestEntry{"24296fb24b8ad77a", sha256.Sum256([]byte("logged build")), logged, publicKeyPEM, time.Now()})
+	defer server.Close()
+
+	dir := t.TempDir()
+	verifier := &CosignVerifier{PublicKeys: []crypto.PublicKey{&key.PublicKey}, Rekor: &RekorClient{BaseUrl: server.URL}}
+	tests := []struct {
+		content   string
+		signature []byte
+		verified  bool
+	}{
+		{"logged build", logged, true},
+		{"unlogged build", sign("unlogged build"), false},
+		// the logged digest with a signature not in the log
+		{"logged build", sign("logged build"), false},
+	}
+	for i, test := range tests {
+		assetPath, signaturePath := filepath.Join(dir, "asset"), filepath.Join(dir, "asset.sig")
+		if err := os.WriteFile(assetPath, []byte(test.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(signaturePath, []byte(base64.StdEncoding.EncodeToString(test.signature)), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		err = verifier.Verify(context.Background(), assetPath, signaturePath, "")
+		if test.verified && err != nil {
+			t.Fatalf("logged signature %d is not verified: %v", i, err)
+		}
+		if !test.verified && (err == nil || !strings.Contains(err.Error(), "no transparency log entry")) {
+			t.Fatalf("invalid error of the unlogged signature %d: %v", i, err)
+		}
+	}
+}
+
+// rekorTestEntry is a hashedrekord entry of the test Rekor log.
+type rekorTestEntry struct {
+	uuid           string
+	digest         [32]byte
+	signature      []byte
+	publicKey      []byte
+	integratedTime time.Time
+}
+
+// newRekorServer serves the search and the entries of a Rekor log with the entries.
+func newRekorServer(entries ...*rekorTestEntry) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/index/retrieve" {
+			var query struct {
+				Hash string `json:"hash"`
+			}
+			if json.NewDecoder(r.Body).Decode(&query) != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			uuids := []string{}
+			for _, entry := range entries {
+				if query.Hash == "sha256:"+hex.EncodeToString(entry.digest[:]) {
+					uuids = append(uuids, entry.uuid)
+				}
+			}
+			json.NewEncoder(w).Encode(uuids)
+			return
+		}
+		for _, entry := range entries {
+			if r.URL.Path != "/api/v1/log/entries/"+entry.uuid {
+				continue
+			}
+			body, _ := json.Marshal(map[string]any{
+				"apiVersion": "0.0.1",
+				"kind":       "hashedrekord",
+				"spec": map[string]any{
+					"signature": map[string]any{
+						"content":   base64.StdEncoding.EncodeToString(entry.signature),
+						"publicKey": map[string]string{"content": base64.StdEncoding.EncodeToString(entry.publicKey)},
+					},
+					"data": map[string]any{
+						"hash": map[string]string{"algorithm": "sha256", "value": hex.EncodeToString(entry.digest[:])},
+					},
+				},
+			})
+			json.NewEncoder(w).Encode(map[string]any{
+				entry.uuid: map[string]any{"body": base64.StdEncoding.EncodeToString(body), "integratedTime": entry.integratedTime.Unix()},
+			})
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+}
@@ -0,0 +1,60 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Snapshot is a stored scan of an account. Its json format is described by SnapshotSchema.
+type Snapshot struct {
+	SchemaVersion  string    `json:"schema_version"`
+	ScannerVersion string    `json:"scanner_version,omitempty"`
+	Account        string    `json:"account"`
+	ScannedAt      time.Time `json:"scanned_at"`
+	// Options are the options of the scan, nil if they are unknown.
+	Options *ScanOptions `json:"options,omitempty"`
+	// Accounts and Summary are the totals of every account and of all of them, they are only set for scans of
+	// several accounts, see SummarizeAccounts.
+	Accounts []*AccountSummary `json:"accounts,omitempty"`
+	Summary  *AccountSummary   `json:"summary,omitempty"`
+	// Downloads are the most downloaded releases and assets of the scan, nil if nothing was downloaded.
+	Downloads *DownloadLeaderboard `json:"downloads,omitempty"`
+	// Stats are the statistics of the scan, nil if they are unknown.
+	Stats *ScanStats    `json:"stats,omitempty"`
+	Items []*ResultItem `json:"items"`
+}
+
+func NewSnapshot(account string, items []*ResultItem) *Snapshot {
+	SortReleases(items)
+	SetReleaseCadences(items, time.Now())
+
+	return &Snapshot{
+		SchemaVersion:  SchemaVersion,
+		ScannerVersion: ScannerVersion(),
+		Account:        account,
+		ScannedAt:      time.Now().UTC(),
+		Downloads:      NewDownloadLeaderboard(items, DownloadLeaderboardSize),
+		Items:          items,
+	}
+}
+
+// Summarize sets the totals of every account and of all of them.
+func (s *Snapshot) Summarize() {
+	s.Accounts, s.Summary = SummarizeAccounts(s.Items)
+}
+
+func LoadSnapshot(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("could not parse the snapshot %s: %v", path, err)
+	}
+
+	return &snapshot, nil
+}
@@ -0,0 +1,90 @@
+package scanner
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// StaleRepository is a repository without pushes, releases or commits for longer than the allowed period.
+type StaleRepository struct {
+	Repository string `json:"repository"`
+	Archived   bool   `json:"archived"`
+	// LastActivity is the latest of the last push, release and commit dates, nil if none is known.
+	LastActivity *time.Time `json:"last_activity,omitempty"`
+}
+
+// LastActivity returns the latest of the last push, the last published release and the last commit dates of the
+// scanned repository, nil if none is known. The last commit date is only known if commit activity is scanned.
+func (i *ResultItem) LastActivity() *time.Time {
+	var last *time.Time
+	later := func(date *time.Time) {
+		if date != nil && (last == nil || date.After(*last)) {
+			last = date
+		}
+	}
+	later(i.Repository.PushedAt)
+	for _, release := range i.Releases {
+		later(release.PublishedAt)
+	}
+	if i.CommitActivity != nil {
+		later(i.CommitActivity.LastCommitAt)
+	}
+
+	return last
+}
+
+// StaleRepositories returns the scanned repositories without any activity since the time, ordered by the last
+// activity, oldest first. Repositories without a known activity date go first.
+func StaleRepositories(items []*ResultItem, since time.Time) []*StaleRepository {
+	var stale []*StaleRepository
+	for _, item := range items {
+		lastActivity := item.LastActivity()
+		if lastActivity != nil && !lastActivity.Before(since) {
+			continue
+		}
+		stale = append(stale, &StaleRepository{
+			Repository:   item.Repository.FullName,
+			Archived:     item.Repository.Archived,
+			LastActivity: lastActivity,
+		})
+	}
+	sort.SliceStable(stale, func(i, j int) bool {
+		a, b := stale[i].LastActivity, stale[j].LastActivity
+		if a == nil || b == nil {
+			return a == nil && b != nil
+		}
+		if !a.Equal(*b) {
+			return a.Before(*b)
+		}
+		return stale[i].Repository < stale[j].Repository
+	})
+
+	return stale
+}
+
+// ParsePeriod parses a period of days, weeks, months or years, e.g. "90d", "6w", "18m" or "2y", and returns
+// the time the period before now.
+func ParsePeriod(period string, now time.Time) (time.Time, error) {
+	if len(period) < 2 {
+		return time.Time{}, fmt.Errorf("invalid period %q, expected a number with a d, w, m or y unit", period)
+	}
+	count, err := strconv.Atoi(period[:len(period)-1])
+	if err != nil || count <= 0 {
+		return time.Time{}, fmt.Errorf("invalid period %q, expected a positive number with a d, w, m or y unit", period)
+	}
+
+	switch period[len(period)-1] {
+	case 'd':
+		return now.AddDate(0, 0, -count), nil
+	case 'w':
+		return now.AddDate(0, 0, -7*count), nil
+	case 'm':
+		return now.AddDate(0, -count, 0), nil
+	case 'y':
+		return now.AddDate(-count, 0, 0), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid period %q, expected a d, w, m or y unit", period)
+}
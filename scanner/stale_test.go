@@ -0,0 +1,49 @@
+package scanner
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStaleRepositories(t *testing.T) {
+	date := func(year int, month time.Month) *time.Time {
+		d := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+	items := []*ResultItem{
+		{Repository: &Repository{FullName: "test/active", PushedAt: date(2024, 1)}},
+		{Repository: &Repository{FullName: "test/released", PushedAt: date(2021, 1)}, Releases: []*Release{{PublishedAt: date(2024, 2)}}},
+		{Repository: &Repository{FullName: "test/committed", PushedAt: date(2021, 1)}, CommitActivity: &CommitActivity{LastCommitAt: date(2023, 12)}},
+		{Repository: &Repository{FullName: "test/old", PushedAt: date(2022, 1), Archived: true}, Releases: []*Release{{PublishedAt: date(2021, 6)}}},
+		{Repository: &Repository{FullName: "test/older", PushedAt: date(2020, 5)}},
+		{Repository: &Repository{FullName: "test/unknown"}},
+	}
+
+	since, err := ParsePeriod("18m", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC); !since.Equal(expected) {
+		t.Fatalf("invalid period start, expected %v, got %v", expected, since)
+	}
+
+	stale := StaleRepositories(items, since)
+	var names []string
+	for _, repository := range stale {
+		names = append(names, repository.Repository)
+	}
+	if expected := []string{"test/unknown", "test/older", "test/old"}; !equal(names, expected) {
+		t.Fatalf("invalid stale repositories, expected %v, got %v", expected, names)
+	}
+	if !stale[2].Archived || !stale[2].LastActivity.Equal(*date(2022, 1)) {
+		t.Fatalf("invalid stale repository, expected archived with the 2022-01-01 activity, got %+v", stale[2])
+	}
+}
+
+func TestParsePeriodInvalid(t *testing.T) {
+	for _, period := range []string{"", "m", "18", "-3d", "5h"} {
+		if _, err := ParsePeriod(period, time.Now()); err == nil {
+			t.Fatalf("invalid result of the %q period, expected an error", period)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// StarredLister is implemented by providers able to list repositories starred by a user.
+type StarredLister interface {
+	ListStarredRepositories(ctx context.Context, user string) ([]*Repository, error)
+}
+
+// ScanStarred scans releases of the repositories starred by the user.
+func (s *Scanner) ScanStarred(user string) ([]*ResultItem, error) {
+	lister, ok := s.getProvider().(StarredLister)
+	if !ok {
+		return nil, fmt.Errorf("starred repositories are not supported by the provider")
+	}
+
+	return s.scan(context.Background(), user, "ScanStarred", lister.ListStarredRepositories)
+}
+
+func (s *Scanner) ListStarredRepositories(ctx context.Context, user string) ([]*Repository, error) {
+	return s.getAllStarredRepositories(ctx, user)
+}
+
+func (s *Scanner) GetAllStarredRepositories(user string) ([]*Repository, error) {
+	return s.getAllStarredRepositories(context.Background(), user)
+}
+
+func (s *Scanner) getAllStarredRepositories(ctx context.Context, user string) ([]*Repository, error) {
+	return paginate(ctx, func(ctx context.Context, page int) ([]*Repository, pageLinks, error) {
+		repositoriesChunk, links, err := s.getStarredRepositoriesPerPage(ctx, user, page)
+		if err == nil {
+			s.getLogger().Debug("starred repositories page fetched", "account", user, "page", page, "count", len(repositoriesChunk))
+		}
+		return repositoriesChunk, links, err
+	})
+}
+
+func (s *Scanner) GetStarredRepositoriesPerPage(user string, page int) ([]*Repository, error) {
+	repositories, _, err := s.getStarredRepositoriesPerPage(context.Background(), user, page)
+
+	return repositories, err
+}
+
+func (s *Scanner) getStarredRepositoriesPerPage(ctx context.Context, user string, page int) ([]*Repository, pageLinks, error) {
+	if err := s.checkPage(page); err != nil {
+		return nil, pageLinks{}, err
+	}
+	if err := s.checkUser(user); err != nil {
+		return nil, pageLinks{}, err
+	}
+	ctx, span := s.getTracer().Start(ctx, "GetStarredRepositoriesPerPage", StringAttribute("account", user), IntAttribute("page", page))
+	defer span.End()
+
+	response, err := s.get(ctx, span, fmt.Sprintf("%s/users/%s/starred?per_page=%d&page=%d", s.BaseUrl, user, s.getPerPage(), page))
+	if err != nil {
+		return nil, pageLinks{}, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil, pageLinks{}, newNotFoundError("account %s does not exist", user)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, pageLinks{}, fmt.Errorf("could not get starred repositories for the account %s: %w", user, s.newApiError(response))
+	}
+
+	var repositories []*Repository
+	if err := json.NewDecoder(response.Body).Decode(&repositories); err != nil {
+		return nil, pageLinks{}, err
+	}
+
+	return repositories, parsePageLinks(response.Header), nil
+}
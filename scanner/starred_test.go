@@ -0,0 +1,53 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestScanStarredSuccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/users/test/starred" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[
+				{"full_name": "other/tool", "name": "tool"},
+				{"full_name": "another/lib", "name": "lib"}
+				]`))
+		}
+		if r.URL.Path == "/repos/other/tool/releases" || r.URL.Path == "/repos/another/lib/releases" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"name": "v1.0.0"}]`))
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{
+		BaseUrl: server.URL,
+	}
+	items, err := scanner.ScanStarred("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 2 {
+		t.Fatalf("invalid scanned starred repositories count, expected 2, got %d", len(items))
+	}
+	if items[0].Repository.FullName != "another/lib" || items[1].Repository.FullName != "other/tool" {
+		t.Fatalf("invalid scanned starred repositories: %s, %s", items[0].Repository.FullName, items[1].Repository.FullName)
+	}
+	for _, item := range items {
+		if len(item.Releases) != 1 {
+			t.Fatalf("invalid releases count for the repository %s, expected 1, got %d", item.Repository.FullName, len(item.Releases))
+		}
+	}
+}
+
+func TestScanStarredUnsupportedProvider(t *testing.T) {
+	scanner := Scanner{
+		Provider: &GitLabProvider{},
+	}
+	if _, err := scanner.ScanStarred("test"); err == nil {
+		t.Fatal("error is expected for a provider without starred repositories")
+	}
+}
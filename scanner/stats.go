@@ -0,0 +1,66 @@
+package scanner
+
+import (
+	"fmt"
+	"time"
+)
+
+// ScanStats is the aggregate statistics of a scan reported with its output, e.g. to monitor scheduled scans.
+type ScanStats struct {
+	Repositories    int `json:"repositories"`
+	WithReleases    int `json:"with_releases"`
+	WithoutReleases int `json:"without_releases"`
+	Releases        int `json:"releases"`
+	// Requests are the API requests sent by the scanner, see Scanner.Requests.
+	Requests        int     `json:"requests"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	// Errors are the accounts left out of the scan, Warnings are the issues found by the results validation.
+	Errors   int `json:"errors"`
+	Warnings int `json:"warnings"`
+	// Partial is set if the scan was interrupted, the statistics are of the items scanned before.
+	Partial bool `json:"partial,omitempty"`
+}
+
+// NewScanStats counts the repositories and releases of the scanned items. The other statistics are known only
+// to the caller of the scan.
+func NewScanStats(items []*ResultItem) *ScanStats {
+	stats := &ScanStats{}
+	for _, item := range items {
+		stats.Add(item)
+	}
+
+	return stats
+}
+
+// Add counts the repository and the releases of the item, e.g. of a streamed scan.
+func (s *ScanStats) Add(item *ResultItem) {
+	s.Repositories++
+	if len(item.Releases) > 0 {
+		s.WithReleases++
+	} else {
+		s.WithoutReleases++
+	}
+	s.Releases += len(item.Releases)
+}
+
+// Duration returns the duration of the scan.
+func (s *ScanStats) Duration() time.Duration {
+	return time.Duration(s.DurationSeconds * float64(time.Second))
+}
+
+func (s *ScanStats) String() string {
+	prefix := ""
+	if s.Partial {
+		prefix = "partial scan, interrupted: "
+	}
+	return prefix + fmt.Sprintf(
+		"%d repositories scanned, %d with releases, %d without, %d releases, %d API requests in %.1fs, %d errors, %d warnings",
+		s.Repositories, s.WithReleases, s.WithoutReleases, s.Releases, s.Requests, s.DurationSeconds, s.Errors, s.Warnings,
+	)
+}
+
+// Requests returns the count of API requests and asset downloads sent by the scanner. Responses served by
+// middleware without calling the next handler, e.g. from DiskCache, are not counted.
+func (s *Scanner) Requests() int {
+	return int(s.requests.Load())
+}
@@ -0,0 +1,48 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestScanStats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	cache := &DiskCache{Dir: t.TempDir(), TTL: time.Hour}
+	scanner := Scanner{BaseUrl: server.URL}
+	scanner.Use(cache.Middleware())
+	for range 2 {
+		if _, err := scanner.GetRepositoriesPerPage("test", 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// cached responses are not counted
+	if requests := scanner.Requests(); requests != 1 {
+		t.Fatalf("invalid requests count, expected 1, got %d", requests)
+	}
+
+	stats := NewScanStats([]*ResultItem{
+		{Repository: &Repository{FullName: "test/a"}, Releases: []*Release{{}, {}}},
+		{Repository: &Repository{FullName: "test/b"}},
+	})
+	stats.Requests, stats.DurationSeconds = 3, 1.25
+	expected := ScanStats{Repositories: 2, WithReleases: 1, WithoutReleases: 1, Releases: 2, Requests: 3, DurationSeconds: 1.25}
+	if *stats != expected {
+		t.Fatalf("invalid stats, expected %+v, got %+v", expected, *stats)
+	}
+	if line := "2 repositories scanned, 1 with releases, 1 without, 2 releases, 3 API requests in 1.2s, 0 errors, 0 warnings"; stats.String() != line {
+		t.Fatalf("invalid stats line, expected %q, got %q", line, stats.String())
+	}
+	stats.Partial = true
+	if line := "partial scan, interrupted: 2 repositories scanned, 1 with releases, 1 without, 2 releases, 3 API requests in 1.2s, 0 errors, 0 warnings"; stats.String() != line {
+		t.Fatalf("invalid partial stats line, expected %q, got %q", line, stats.String())
+	}
+	if stats.Duration() != 1250*time.Millisecond {
+		t.Fatalf("invalid duration, expected 1.25s, got %v", stats.Duration())
+	}
+}
@@ -0,0 +1,147 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+type Member struct {
+	Login string `json:"login"`
+	ID    int64  `json:"id"`
+}
+
+type Team struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// TeamAccess is the access of a team to a repository. Teams with the admin role own the repository.
+type TeamAccess struct {
+	Team string `json:"team"`
+	Role string `json:"role"`
+}
+
+// teamRepository is a repository of the team repositories list with the role of the team.
+type teamRepository struct {
+	FullName string `json:"full_name"`
+	RoleName string `json:"role_name"`
+}
+
+// GetOrgMembers returns members of the organization visible to the token.
+func (s *Scanner) GetOrgMembers(org string) ([]*Member, error) {
+	return getOrgList[*Member](context.Background(), s, "GetOrgMembers", org, "members")
+}
+
+// GetTeams returns teams of the organization visible to the token.
+func (s *Scanner) GetTeams(org string) ([]*Team, error) {
+	return s.getTeams(context.Background(), org)
+}
+
+func (s *Scanner) getTeams(ctx context.Context, org string) ([]*Team, error) {
+	return getOrgList[*Team](ctx, s, "GetTeams", org, "teams")
+}
+
+// GetTeamRepositories returns the accesses of the team to the organization repositories keyed by repository full names.
+func (s *Scanner) GetTeamRepositories(org, team string) (map[string]string, error) {
+	return s.getTeamRepositories(context.Background(), org, team)
+}
+
+func (s *Scanner) getTeamRepositories(ctx context.Context, org, team string) (map[string]string, error) {
+	repositories, err := getOrgList[*teamRepository](ctx, s, "GetTeamRepositories", org, "teams/"+team+"/repos")
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make(map[string]string, len(repositories))
+	for _, repository := range repositories {
+		roles[repository.FullName] = repository.RoleName
+	}
+
+	return roles, nil
+}
+
+// GetOwnership maps repositories of the organization to the teams with access to them, owners first.
+func (s *Scanner) GetOwnership(org string) (map[string][]*TeamAccess, error) {
+	return s.getOwnership(context.Background(), org)
+}
+
+// getOwnership maps repositories of the organization to the teams with access to them, owners first.
+// Accounts that are not organizations have no teams, nil is returned for them.
+func (s *Scanner) getOwnership(ctx context.Context, org string) (map[string][]*TeamAccess, error) {
+	teams, err := s.getTeams(ctx, org)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ownership := make(map[string][]*TeamAccess)
+	for _, team := range teams {
+		roles, err := s.getTeamRepositories(ctx, org, team.Slug)
+		if err != nil {
+			return nil, err
+		}
+		for repository, role := range roles {
+			ownership[repository] = append(ownership[repository], &TeamAccess{Team: team.Slug, Role: role})
+		}
+	}
+	for _, accesses := range ownership {
+		sort.Slice(accesses, func(i, j int) bool {
+			if (accesses[i].Role == "admin") != (accesses[j].Role == "admin") {
+				return accesses[i].Role == "admin"
+			}
+			return accesses[i].Team < accesses[j].Team
+		})
+	}
+
+	return ownership, nil
+}
+
+// Owners returns the teams with the admin role on the repository.
+func (i *ResultItem) Owners() []string {
+	var owners []string
+	for _, access := range i.Teams {
+		if access.Role == "admin" {
+			owners = append(owners, access.Team)
+		}
+	}
+
+	return owners
+}
+
+// getOrgList fetches all pages of the organization list endpoint.
+func getOrgList[T any](ctx context.Context, s *Scanner, spanName, org, endpoint string) ([]T, error) {
+	if err := s.checkUser(org); err != nil {
+		return nil, err
+	}
+	ctx, span := s.getTracer().Start(ctx, spanName, StringAttribute("account", org))
+	defer span.End()
+
+	return paginate(ctx, func(ctx context.Context, page int) ([]T, pageLinks, error) {
+		response, err := s.get(ctx, span, fmt.Sprintf("%s/orgs/%s/%s?per_page=%d&page=%d", s.BaseUrl, org, endpoint, s.getPerPage(), page))
+		if err != nil {
+			return nil, pageLinks{}, err
+		}
+		defer response.Body.Close()
+
+		if response.StatusCode == http.StatusNotFound {
+			return nil, pageLinks{}, newNotFoundError("organization %s does not exist", org)
+		}
+		if response.StatusCode != http.StatusOK {
+			return nil, pageLinks{}, fmt.Errorf("could not get %s of the organization %s: %w", endpoint, org, s.newApiError(response))
+		}
+
+		var chunk []T
+		if err := json.NewDecoder(response.Body).Decode(&chunk); err != nil {
+			return nil, pageLinks{}, err
+		}
+
+		return chunk, parsePageLinks(response.Header), nil
+	})
+}
@@ -0,0 +1,73 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetOrgMembers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/orgs/test/members" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("page") == "1" {
+			setPageLinks(w, r, 2, 2)
+			w.Write([]byte(`[{"login": "alice"}, {"login": "bob"}]`))
+		} else {
+			w.Write([]byte(`[{"login": "carol"}]`))
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL, PerPage: 2}
+	members, err := scanner.GetOrgMembers("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(members) != 3 || members[2].Login != "carol" {
+		t.Fatalf("invalid members, expected [alice bob carol], got %v", members)
+	}
+}
+
+func TestScanOwnership(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/test/repos", "/users/alice/repos":
+			w.Write([]byte(`[{"full_name": "test/api", "name": "api"}, {"full_name": "test/docs", "name": "docs"}]`))
+		case "/repos/test/api/releases", "/repos/test/docs/releases":
+			w.Write([]byte(`[]`))
+		case "/orgs/test/teams":
+			w.Write([]byte(`[{"slug": "platform"}, {"slug": "backend"}]`))
+		case "/orgs/test/teams/platform/repos":
+			w.Write([]byte(`[{"full_name": "test/api", "role_name": "push"}]`))
+		case "/orgs/test/teams/backend/repos":
+			w.Write([]byte(`[{"full_name": "test/api", "role_name": "admin"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL, PerPage: 100, ScanOwnership: true}
+	items, err := scanner.ScanRepositories("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := items[0]
+	if len(api.Teams) != 2 || api.Teams[0].Team != "backend" || api.Teams[1].Team != "platform" {
+		t.Fatalf("invalid teams of the api repository, expected [backend platform], got %v", api.Teams)
+	}
+	if owners := api.Owners(); !equal(owners, []string{"backend"}) {
+		t.Fatalf("invalid owners of the api repository, expected [backend], got %v", owners)
+	}
+	if len(items[1].Teams) != 0 {
+		t.Fatalf("invalid teams of the docs repository, expected none, got %v", items[1].Teams)
+	}
+
+	// User accounts have no teams.
+	if _, err := scanner.ScanRepositories("alice"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,324 @@
+package scanner
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenExpiryMargin is how long before their expiry tokens are refreshed, so requests in flight do not fail.
+// fileTokenTTL is how often FileTokenSource re-reads the file when it is reused.
+var (
+	tokenExpiryMargin = time.Minute
+	fileTokenTTL      = time.Minute
+)
+
+// Token is an API token with its expiry. The token does not expire if the expiry is zero.
+type Token struct {
+	Value  string
+	Expiry time.Time
+}
+
+// valid reports whether the token could still be used.
+func (t *Token) valid() bool {
+	return t != nil && (t.Expiry.IsZero() || time.Now().Add(tokenExpiryMargin).Before(t.Expiry))
+}
+
+// TokenSource provides the tokens of the API requests. Sources of short-lived tokens return a new token when the
+// current one is about to expire, so long-running watch and serve modes keep working.
+type TokenSource interface {
+	Token(ctx context.Context) (*Token, error)
+}
+
+// StaticTokenSource returns the token that never expires.
+func StaticTokenSource(token string) TokenSource {
+	return staticTokenSource{token: &Token{Value: token}}
+}
+
+type staticTokenSource struct {
+	token *Token
+}
+
+func (s staticTokenSource) Token(ctx context.Context) (*Token, error) {
+	return s.token, nil
+}
+
+// EnvTokenSource returns the token of the env variable.
+type EnvTokenSource string
+
+func (s EnvTokenSource) Token(ctx context.Context) (*Token, error) {
+	token := os.Getenv(string(s))
+	if token == "" {
+		return nil, fmt.Errorf("env variable %s is not set", string(s))
+	}
+
+	return &Token{Value: token}, nil
+}
+
+// FileTokenSource returns the token of the file, e.g. a mounted secret rotated by an external process. The token
+// expires after a minute, so the file is read again when the source is reused.
+type FileTokenSource string
+
+func (s FileTokenSource) Token(ctx context.Context) (*Token, error) {
+	data, err := os.ReadFile(string(s))
+	if err != nil {
+		return nil, fmt.Errorf("could not read the token file: %w", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return nil, fmt.Errorf("token file %s is empty", string(s))
+	}
+
+	return &Token{Value: token, Expiry: time.Now().Add(fileTokenTTL + tokenExpiryMargin)}, nil
+}
+
+// ReuseTokenSource returns the token of the source until it is about to expire. It is safe for concurrent use,
+// the source is called by one request at a time.
+func ReuseTokenSource(source TokenSource) TokenSource {
+	if reuse, ok := source.(*reuseTokenSource); ok {
+		return reuse
+	}
+
+	return &reuseTokenSource{source: source}
+}
+
+type reuseTokenSource struct {
+	source TokenSource
+
+	mu    sync.Mutex
+	token *Token
+}
+
+func (s *reuseTokenSource) Token(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token.valid() {
+		return s.token, nil
+	}
+	token, err := s.source.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.token = token
+
+	return token, nil
+}
+
+// GitHubAppTokenSource returns installation tokens of a GitHub App. They expire in an hour, the source should be
+// wrapped with ReuseTokenSource to request a new one only then.
+type GitHubAppTokenSource struct {
+	// BaseUrl is the API url, GitHuhApi if it is empty.
+	BaseUrl        string
+	AppID          string
+	InstallationID string
+	PrivateKey     *rsa.PrivateKey
+	Client         *http.Client
+}
+
+// ParseGitHubAppKey parses the PEM encoded private key of a GitHub App.
+func ParseGitHubAppKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("could not parse the private key: no PEM block")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the private key: %v", err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("could not parse the private key: not an RSA key")
+	}
+
+	return rsaKey, nil
+}
+
+func (s *GitHubAppTokenSource) Token(ctx context.Context) (*Token, error) {
+	jwt, err := s.jwt(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	baseUrl := s.BaseUrl
+	if baseUrl == "" {
+		baseUrl = GitHuhApi
+	}
+	tokenUrl := fmt.Sprintf("%s/app/installations/%s/access_tokens", strings.TrimSuffix(baseUrl, "/"), url.PathEscape(s.InstallationID))
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Authorization", "Bearer "+jwt)
+	request.Header.Set("Accept", "application/vnd.github+json")
+
+	var token struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := doTokenRequest(s.Client, request, http.StatusCreated, &token); err != nil {
+		return nil, fmt.Errorf("could not request the installation token: %w", err)
+	}
+
+	return &Token{Value: token.Token, Expiry: token.ExpiresAt}, nil
+}
+
+// jwt returns the token authenticating as the app. It is issued a minute in the past against clock drift and
+// expires in 9 minutes, GitHub accepts at most 10.
+func (s *GitHubAppTokenSource) jwt(now time.Time) (string, error) {
+	if s.PrivateKey == nil {
+		return "", errors.New("github app private key is required")
+	}
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]any{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": s.AppID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	content := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(content))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.PrivateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("could not sign the github app token: %v", err)
+	}
+
+	return content + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// OAuthRefreshTokenSource returns user tokens of a GitHub App with expiring user tokens, refreshed with the
+// refresh token. GitHub rotates the refresh token on every refresh, the source keeps the current one. The current
+// access token is returned until it is about to expire, the source should still be wrapped with ReuseTokenSource.
+type OAuthRefreshTokenSource struct {
+	// BaseUrl is the GitHub host serving the OAuth endpoints, GitHubLogin if it is empty.
+	BaseUrl      string
+	ClientID     string
+	ClientSecret string
+	RefreshToken string
+	// AccessToken is the current access token, e.g. the one stored by a previous run. It is refreshed right away
+	// if it is nil.
+	AccessToken *Token
+	Client      *http.Client
+	// OnRefresh is called with the new access and refresh tokens, so they could be persisted for the next run.
+	OnRefresh func(token *Token, refreshToken string)
+
+	mu sync.Mutex
+}
+
+func (s *OAuthRefreshTokenSource) Token(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.AccessToken.valid() {
+		return s.AccessToken, nil
+	}
+
+	baseUrl := s.BaseUrl
+	if baseUrl == "" {
+		baseUrl = GitHubLogin
+	}
+	form := url.Values{
+		"client_id":     {s.ClientID},
+		"client_secret": {s.ClientSecret},
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {s.RefreshToken},
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(baseUrl, "/")+"/login/oauth/access_token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+
+	var token struct {
+		AccessToken      string `json:"access_token"`
+		ExpiresIn        int    `json:"expires_in"`
+		RefreshToken     string `json:"refresh_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := doTokenRequest(s.Client, request, http.StatusOK, &token); err != nil {
+		return nil, fmt.Errorf("could not refresh the token: %w", err)
+	}
+	if token.Error != "" {
+		return nil, fmt.Errorf("could not refresh the token: %s: %s", token.Error, token.ErrorDescription)
+	}
+	if token.RefreshToken != "" {
+		s.RefreshToken = token.RefreshToken
+	}
+
+	result := &Token{Value: token.AccessToken}
+	if token.ExpiresIn > 0 {
+		result.Expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+	s.AccessToken = result
+	if s.OnRefresh != nil {
+		s.OnRefresh(result, s.RefreshToken)
+	}
+
+	return result, nil
+}
+
+// doTokenRequest performs the token request and decodes the response of the expected status.
+func doTokenRequest(client *http.Client, request *http.Request, status int, result any) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return wrapTransportError(err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != status {
+		return newApiError(response, response.Status)
+	}
+
+	return json.NewDecoder(response.Body).Decode(result)
+}
+
+// token returns the token of the requests, an empty string for anonymous requests.
+func (s *Scanner) token(ctx context.Context) (string, error) {
+	return sourceToken(ctx, s.Token, s.TokenSource)
+}
+
+// sourceToken returns the token of the source if it is set, the static token otherwise.
+func sourceToken(ctx context.Context, token string, source TokenSource) (string, error) {
+	if source == nil {
+		return token, nil
+	}
+	sourced, err := source.Token(ctx)
+	if err != nil {
+		return "", fmt.Errorf("could not get the token: %w", err)
+	}
+
+	return sourced.Value, nil
+}
+
+// authenticated reports whether the requests are made with a token.
+func (s *Scanner) authenticated() bool {
+	return s.Token != "" || s.TokenSource != nil
+}
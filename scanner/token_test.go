@@ -0,0 +1,204 @@
+package scanner
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type countingTokenSource struct {
+	calls  int
+	expiry time.Duration
+}
+
+func (s *countingTokenSource) Token(ctx context.Context) (*Token, error) {
+	s.calls++
+	return &Token{Value: strings.Repeat("t", s.calls), Expiry: time.Now().Add(s.expiry)}, nil
+}
+
+func TestReuseTokenSource(t *testing.T) {
+	source := &countingTokenSource{expiry: time.Hour}
+	reuse := ReuseTokenSource(source)
+	for range 3 {
+		token, err := reuse.Token(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token.Value != "t" {
+			t.Fatalf("invalid token, expected t, got %s", token.Value)
+		}
+	}
+	if source.calls != 1 {
+		t.Fatalf("invalid source calls, expected 1, got %d", source.calls)
+	}
+
+	// tokens expiring within the margin are refreshed
+	source = &countingTokenSource{expiry: tokenExpiryMargin / 2}
+	reuse = ReuseTokenSource(source)
+	reuse.Token(context.Background())
+	token, err := reuse.Token(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.Value != "tt" || source.calls != 2 {
+		t.Fatalf("invalid refreshed token, expected tt after 2 calls, got %s after %d", token.Value, source.calls)
+	}
+}
+
+func TestFileTokenSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	os.WriteFile(path, []byte("first\n"), 0o600)
+	token, err := FileTokenSource(path).Token(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.Value != "first" || token.Expiry.IsZero() {
+		t.Fatalf("invalid token, expected first with an expiry, got %s expiring at %v", token.Value, token.Expiry)
+	}
+
+	os.WriteFile(path, nil, 0o600)
+	if _, err := FileTokenSource(path).Token(context.Background()); err == nil {
+		t.Fatalf("invalid error of an empty token file, expected error, got nil")
+	}
+
+	t.Setenv("GITHUBSCANNER_TEST_TOKEN", "env")
+	token, err = EnvTokenSource("GITHUBSCANNER_TEST_TOKEN").Token(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.Value != "env" {
+		t.Fatalf("invalid env token, expected env, got %s", token.Value)
+	}
+}
+
+func TestGitHubAppTokenSource(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/app/installations/42/access_tokens" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		parts := strings.Split(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), ".")
+		if len(parts) != 3 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		if rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature) != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		claims := struct {
+			Issuer string `json:"iss"`
+		}{}
+		data, _ := base64.RawURLEncoding.DecodeString(parts[1])
+		if json.Unmarshal(data, &claims) != nil || claims.Issuer != "7" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"token": "ghs_installation", "expires_at": "` + expiresAt.Format(time.RFC3339) + `"}`))
+	}))
+	defer server.Close()
+
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	parsedKey, err := ParseGitHubAppKey(pemKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := &GitHubAppTokenSource{BaseUrl: server.URL, AppID: "7", InstallationID: "42", PrivateKey: parsedKey}
+	token, err := source.Token(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.Value != "ghs_installation" || !token.Expiry.Equal(expiresAt) {
+		t.Fatalf("invalid token, expected ghs_installation expiring at %v, got %s expiring at %v", expiresAt, token.Value, token.Expiry)
+	}
+}
+
+func TestOAuthRefreshTokenSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.PostForm.Get("grant_type") != "refresh_token" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch r.PostForm.Get("refresh_token") {
+		case "ghr_first":
+			w.Write([]byte(`{"access_token": "ghu_first", "expires_in": 28800, "refresh_token": "ghr_second"}`))
+		case "ghr_second":
+			w.Write([]byte(`{"access_token": "ghu_second", "expires_in": 28800, "refresh_token": "ghr_third"}`))
+		default:
+			w.Write([]byte(`{"error": "bad_refresh_token", "error_description": "The refresh token passed is incorrect or expired."}`))
+		}
+	}))
+	defer server.Close()
+
+	var refreshed []string
+	source := &OAuthRefreshTokenSource{
+		BaseUrl:      server.URL,
+		ClientID:     "client",
+		RefreshToken: "ghr_first",
+		// the stored token is used until it expires
+		AccessToken: &Token{Value: "ghu_stored", Expiry: time.Now().Add(time.Hour)},
+		OnRefresh: func(token *Token, refreshToken string) {
+			refreshed = append(refreshed, token.Value+" "+refreshToken)
+		},
+	}
+	for _, expected := range []string{"ghu_stored", "ghu_first", "ghu_second"} {
+		token, err := source.Token(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token.Value != expected || token.Expiry.IsZero() {
+			t.Fatalf("invalid token, expected %s with an expiry, got %s expiring at %v", expected, token.Value, token.Expiry)
+		}
+		// the refreshed token expires right away
+		source.AccessToken.Expiry = time.Now()
+	}
+	if len(refreshed) != 2 || refreshed[0] != "ghu_first ghr_second" || refreshed[1] != "ghu_second ghr_third" {
+		t.Fatalf("invalid refreshed tokens, expected [ghu_first ghr_second ghu_second ghr_third], got %v", refreshed)
+	}
+	if _, err := source.Token(context.Background()); err == nil {
+		t.Fatalf("invalid error of an expired refresh token, expected error, got nil")
+	}
+}
+
+func TestScannerTokenSource(t *testing.T) {
+	var authorizations []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	source := &countingTokenSource{expiry: tokenExpiryMargin / 2}
+	scanner := Scanner{BaseUrl: server.URL, Token: "static", TokenSource: ReuseTokenSource(source)}
+	for range 2 {
+		if _, err := scanner.GetRepositoriesPerPage("test", 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected := []string{"Bearer t", "Bearer tt"}
+	if len(authorizations) != 2 || authorizations[0] != expected[0] || authorizations[1] != expected[1] {
+		t.Fatalf("invalid authorizations, expected %v, got %v", expected, authorizations)
+	}
+}
@@ -0,0 +1,48 @@
+package scanner
+
+import "sort"
+
+// TopicGroup is the items of repositories tagged with the topic.
+type TopicGroup struct {
+	// Name is the topic, empty for the repositories without topics.
+	Name  string
+	Items []*ResultItem
+}
+
+// FilterByTopic keeps the items of repositories tagged with the topic.
+func FilterByTopic(items []*ResultItem, topic string) []*ResultItem {
+	filtered, _ := FilterStep(TopicFilter(topic)).Transform(items)
+
+	return filtered
+}
+
+// GroupByTopic groups the items by repository topics, an item is in the group of every topic of its repository.
+// Groups are ordered by topic, the group of repositories without topics goes last.
+func GroupByTopic(items []*ResultItem) []*TopicGroup {
+	groups := make(map[string]*TopicGroup)
+	for _, item := range items {
+		topics := item.Repository.Topics
+		if len(topics) == 0 {
+			topics = []string{""}
+		}
+		for _, topic := range topics {
+			if groups[topic] == nil {
+				groups[topic] = &TopicGroup{Name: topic}
+			}
+			groups[topic].Items = append(groups[topic].Items, item)
+		}
+	}
+
+	sorted := make([]*TopicGroup, 0, len(groups))
+	for _, group := range groups {
+		sorted = append(sorted, group)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Name == "" || sorted[j].Name == "" {
+			return sorted[j].Name == ""
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	return sorted
+}
@@ -0,0 +1,35 @@
+package scanner
+
+import (
+	"testing"
+)
+
+func getTopicItems() []*ResultItem {
+	return []*ResultItem{
+		{Repository: &Repository{FullName: "test/operator", Topics: []string{"kubernetes", "operator"}}},
+		{Repository: &Repository{FullName: "test/chart", Topics: []string{"kubernetes"}}},
+		{Repository: &Repository{FullName: "test/docs"}},
+	}
+}
+
+func TestFilterByTopic(t *testing.T) {
+	items := FilterByTopic(getTopicItems(), "kubernetes")
+	if len(items) != 2 || items[0].Repository.FullName != "test/operator" || items[1].Repository.FullName != "test/chart" {
+		t.Fatalf("invalid filtered items, expected [test/operator test/chart], got %v", items)
+	}
+}
+
+func TestGroupByTopic(t *testing.T) {
+	groups := GroupByTopic(getTopicItems())
+
+	var names []string
+	for _, group := range groups {
+		names = append(names, group.Name)
+	}
+	if !equal(names, []string{"kubernetes", "operator", ""}) {
+		t.Fatalf("invalid groups, expected [kubernetes operator ], got %v", names)
+	}
+	if len(groups[0].Items) != 2 || len(groups[1].Items) != 1 || groups[2].Items[0].Repository.FullName != "test/docs" {
+		t.Fatalf("invalid grouped items")
+	}
+}
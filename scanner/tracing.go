@@ -0,0 +1,54 @@
+package scanner
+
+import "context"
+
+const tracerName = "githubscanner/scanner"
+
+// TracerProvider provides tracers for scan operations. It mirrors the OpenTelemetry tracer provider, the otel
+// package adapts OpenTelemetry providers to it.
+type TracerProvider interface {
+	Tracer(name string) Tracer
+}
+
+type Tracer interface {
+	Start(ctx context.Context, spanName string, attributes ...Attribute) (context.Context, Span)
+}
+
+type Span interface {
+	SetAttributes(attributes ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+func StringAttribute(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+func IntAttribute(key string, value int) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+type noopTracer struct{}
+
+func (noopTracer) Start(ctx context.Context, spanName string, attributes ...Attribute) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(attributes ...Attribute) {}
+func (noopSpan) RecordError(err error)                 {}
+func (noopSpan) End()                                  {}
+
+func (s *Scanner) getTracer() Tracer {
+	if s.TracerProvider == nil {
+		return noopTracer{}
+	}
+
+	return s.TracerProvider.Tracer(tracerName)
+}
@@ -0,0 +1,93 @@
+package scanner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordingSpan
+}
+
+func (t *recordingTracer) Tracer(name string) Tracer {
+	return t
+}
+
+func (t *recordingTracer) Start(ctx context.Context, spanName string, attributes ...Attribute) (context.Context, Span) {
+	span := &recordingSpan{name: spanName, attributes: map[string]interface{}{}}
+	span.SetAttributes(attributes...)
+	t.mu.Lock()
+	t.spans = append(t.spans, span)
+	t.mu.Unlock()
+
+	return ctx, span
+}
+
+type recordingSpan struct {
+	mu         sync.Mutex
+	name       string
+	attributes map[string]interface{}
+	ended      bool
+}
+
+func (s *recordingSpan) SetAttributes(attributes ...Attribute) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, attribute := range attributes {
+		s.attributes[attribute.Key] = attribute.Value
+	}
+}
+
+func (s *recordingSpan) RecordError(err error) {}
+
+func (s *recordingSpan) End() {
+	s.ended = true
+}
+
+func TestScanRepositoriesTracing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/users/test/repos" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"full_name": "test/test", "name": "test"}]`))
+		}
+		if r.URL.Path == "/repos/test/test/releases" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"name": "test"}]`))
+		}
+	}))
+	defer server.Close()
+
+	tracer := &recordingTracer{}
+	scanner := Scanner{
+		BaseUrl:        server.URL,
+		TracerProvider: tracer,
+	}
+	if _, err := scanner.ScanRepositories("test"); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := map[string]*recordingSpan{}
+	for _, span := range tracer.spans {
+		if !span.ended {
+			t.Fatalf("span %s is not ended", span.name)
+		}
+		spans[span.name] = span
+	}
+	for _, name := range []string{"ScanRepositories", "scanRepository", "GetRepositoriesPerPage", "GetReleasesPerPage"} {
+		if _, ok := spans[name]; !ok {
+			t.Fatalf("span %s is not recorded", name)
+		}
+	}
+
+	releasesSpan := spans["GetReleasesPerPage"]
+	if releasesSpan.attributes["repository"] != "test" || releasesSpan.attributes["page"] != 1 {
+		t.Fatalf("invalid releases page span attributes: %v", releasesSpan.attributes)
+	}
+	if releasesSpan.attributes["http.status_code"] != http.StatusOK {
+		t.Fatalf("invalid releases page span status code: %v", releasesSpan.attributes["http.status_code"])
+	}
+}
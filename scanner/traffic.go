@@ -0,0 +1,67 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Traffic is the repository traffic of the last 14 days.
+type Traffic struct {
+	Views          int `json:"views"`
+	UniqueVisitors int `json:"unique_visitors"`
+	Clones         int `json:"clones"`
+	UniqueCloners  int `json:"unique_cloners"`
+}
+
+// errTrafficUnavailable is returned when the token has no push access to the repository, which traffic requires.
+var errTrafficUnavailable = errors.New("traffic is unavailable")
+
+// GetTraffic returns views and clones of the repository for the last 14 days. The token needs push access.
+func (s *Scanner) GetTraffic(user, repository string) (*Traffic, error) {
+	return s.getTraffic(context.Background(), user, repository)
+}
+
+func (s *Scanner) getTraffic(ctx context.Context, user, repository string) (*Traffic, error) {
+	if err := s.checkUser(user); err != nil {
+		return nil, err
+	}
+	if err := s.checkRepository(repository); err != nil {
+		return nil, err
+	}
+	ctx, span := s.getTracer().Start(ctx, "GetTraffic", StringAttribute("account", user), StringAttribute("repository", repository))
+	defer span.End()
+
+	var views, clones struct {
+		Count   int `json:"count"`
+		Uniques int `json:"uniques"`
+	}
+	if err := s.getTrafficCounts(ctx, span, user, repository, "views", &views); err != nil {
+		return nil, err
+	}
+	if err := s.getTrafficCounts(ctx, span, user, repository, "clones", &clones); err != nil {
+		return nil, err
+	}
+
+	return &Traffic{Views: views.Count, UniqueVisitors: views.Uniques, Clones: clones.Count, UniqueCloners: clones.Uniques}, nil
+}
+
+func (s *Scanner) getTrafficCounts(ctx context.Context, span Span, user, repository, kind string, counts any) error {
+	response, err := s.get(ctx, span, fmt.Sprintf("%s/repos/%s/%s/traffic/%s", s.BaseUrl, user, repository, kind))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		apiError := s.newApiError(response)
+		if response.StatusCode == http.StatusForbidden && apiError.Class == nil {
+			return fmt.Errorf("could not get %s of the repository %s/%s: %w: %s", kind, user, repository, errTrafficUnavailable, apiError)
+		}
+		return fmt.Errorf("could not get %s of the repository %s/%s: %w", kind, user, repository, apiError)
+	}
+
+	return json.NewDecoder(response.Body).Decode(counts)
+}
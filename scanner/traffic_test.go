@@ -0,0 +1,61 @@
+package scanner
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetTraffic(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/test/test/traffic/views":
+			w.Write([]byte(`{"count": 140, "uniques": 30, "views": [{"timestamp": "2024-03-01T00:00:00Z", "count": 140, "uniques": 30}]}`))
+		case "/repos/test/test/traffic/clones":
+			w.Write([]byte(`{"count": 12, "uniques": 5, "clones": []}`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message": "Must have push access to repository"}`))
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL}
+	traffic, err := scanner.GetTraffic("test", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Traffic{Views: 140, UniqueVisitors: 30, Clones: 12, UniqueCloners: 5}
+	if *traffic != expected {
+		t.Fatalf("invalid traffic, expected %v, got %v", expected, *traffic)
+	}
+
+	if _, err := scanner.GetTraffic("test", "other"); !errors.Is(err, errTrafficUnavailable) {
+		t.Fatalf("invalid error, expected %v, got %v", errTrafficUnavailable, err)
+	}
+}
+
+func TestScanTrafficUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/test/repos":
+			w.Write([]byte(`[{"full_name": "test/test", "name": "test"}]`))
+		case "/repos/test/test/releases":
+			w.Write([]byte(`[]`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message": "Must have push access to repository"}`))
+		}
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL, PerPage: 100, ScanTraffic: true}
+	items, err := scanner.ScanRepositories("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if items[0].Traffic != nil {
+		t.Fatalf("invalid traffic of the repository without push access, expected nil, got %v", items[0].Traffic)
+	}
+}
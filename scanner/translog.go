@@ -0,0 +1,368 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// TransparencyLogEntry records the digest an asset had at scan time. Every entry is chained to the previous one
+// by its hash, so a rewritten or removed entry breaks the chain of all entries after it.
+type TransparencyLogEntry struct {
+	Index      int64     `json:"index"`
+	LoggedAt   time.Time `json:"logged_at"`
+	Repository string    `json:"repository"`
+	Release    string    `json:"release"`
+	Asset      string    `json:"asset"`
+	Digest     string    `json:"digest"`
+	PrevHash   string    `json:"prev_hash"`
+	Hash       string    `json:"hash"`
+	// RekorUUID is the uuid of the entry in the Rekor log if it was submitted there.
+	RekorUUID string `json:"rekor_uuid,omitempty"`
+}
+
+func (e *TransparencyLogEntry) computeHash() string {
+	content := strings.Join([]string{
+		fmt.Sprint(e.Index),
+		e.LoggedAt.UTC().Format(time.RFC3339Nano),
+		e.Repository,
+		e.Release,
+		e.Asset,
+		e.Digest,
+		e.PrevHash,
+	}, "\n")
+	sum := sha256.Sum256([]byte(content))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// TransparencyLog is an append-only hash-chained log of asset digests stored as json lines in a local file.
+type TransparencyLog struct {
+	Path string
+	// Rekor is an optional Rekor-compatible log the entries are submitted to.
+	Rekor *RekorClient
+
+	last *TransparencyLogEntry
+}
+
+// OpenTransparencyLog opens the log at the path, verifying its chain. The file is created on the first append.
+func OpenTransparencyLog(path string) (*TransparencyLog, error) {
+	entries, err := ReadTransparencyLog(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	log := &TransparencyLog{Path: path}
+	if len(entries) > 0 {
+		log.last = entries[len(entries)-1]
+	}
+
+	return log, nil
+}
+
+// ReadTransparencyLog reads the entries of the log at the path and verifies their chain.
+func ReadTransparencyLog(path string) ([]*TransparencyLogEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []*TransparencyLogEntry
+	var prevHash string
+	lines := bufio.NewScanner(file)
+	lines.Buffer(nil, 1024*1024)
+	for lines.Scan() {
+		var entry TransparencyLogEntry
+		if err := json.Unmarshal(lines.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("could not parse the transparency log entry %d: %v", len(entries), err)
+		}
+		if entry.Index != int64(len(entries)) || entry.PrevHash != prevHash || entry.Hash != entry.computeHash() {
+			return nil, fmt.Errorf("transparency log %s is tampered at the entry %d", path, len(entries))
+		}
+		prevHash = entry.Hash
+		entries = append(entries, &entry)
+	}
+	if err := lines.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// AppendItems logs the digests of all assets of the items. Assets without digests are ignored.
+func (l *TransparencyLog) AppendItems(ctx context.Context, items []*ResultItem) ([]*TransparencyLogEntry, error) {
+	file, err := os.OpenFile(l.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []*TransparencyLogEntry
+	loggedAt := time.Now().UTC()
+	for _, item := range items {
+		for _, release := range item.Releases {
+			for _, asset := range release.Assets {
+				if asset.Digest == "" {
+					continue
+				}
+
+				entry := &TransparencyLogEntry{
+					LoggedAt:   loggedAt,
+					Repository: item.Repository.FullName,
+					Release:    release.TagName,
+					Asset:      asset.Name,
+					Digest:     asset.Digest,
+				}
+				if err := l.append(ctx, file, entry); err != nil {
+					return entries, err
+				}
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	return entries, nil
+}
+
+func (l *TransparencyLog) append(ctx context.Context, file *os.File, entry *TransparencyLogEntry) error {
+	if l.last != nil {
+		entry.Index = l.last.Index + 1
+		entry.PrevHash = l.last.Hash
+	}
+	entry.Hash = entry.computeHash()
+
+	if l.Rekor != nil {
+		uuid, err := l.Rekor.Submit(ctx, entry.Hash)
+		if err != nil {
+			return err
+		}
+		entry.RekorUUID = uuid
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	l.last = entry
+
+	return nil
+}
+
+// FindTransparencyLogEntry returns the first logged entry proving the asset of the repository had the digest.
+func FindTransparencyLogEntry(entries []*TransparencyLogEntry, repository, asset, digest string) *TransparencyLogEntry {
+	for _, entry := range entries {
+		if entry.Repository == repository && entry.Asset == asset && entry.Digest == digest {
+			return entry
+		}
+	}
+
+	return nil
+}
+
+// RekorClient submits entry hashes to a Rekor-compatible transparency log as signed hashedrekord entries and
+// searches the log for entries of hashes.
+type RekorClient struct {
+	BaseUrl string
+	Signer  crypto.Signer
+	Client  *http.Client
+}
+
+// Submit signs the hex encoded sha256 hash and adds it to the Rekor log, returning the uuid of the log entry.
+func (c *RekorClient) Submit(ctx context.Context, hash string) (string, error) {
+	digest, err := hex.DecodeString(hash)
+	if err != nil {
+		return "", err
+	}
+	signature, err := c.Signer.Sign(rand.Reader, digest, crypto.SHA256)
+	if err != nil {
+		return "", fmt.Errorf("could not sign the transparency log entry: %v", err)
+	}
+	publicKey, err := x509.MarshalPKIXPublicKey(c.Signer.Public())
+	if err != nil {
+		return "", err
+	}
+	publicKeyPem := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey})
+
+	body, err := json.Marshal(map[string]any{
+		"apiVersion": "0.0.1",
+		"kind":       "hashedrekord",
+		"spec": map[string]any{
+			"signature": map[string]any{
+				"content":   base64.StdEncoding.EncodeToString(signature),
+				"publicKey": map[string]string{"content": base64.StdEncoding.EncodeToString(publicKeyPem)},
+			},
+			"data": map[string]any{
+				"hash": map[string]string{"algorithm": "sha256", "value": hash},
+			},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(c.BaseUrl, "/")+"/api/v1/log/entries", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := c.getClient().Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("could not submit the transparency log entry to rekor: %s", response.Status)
+	}
+
+	var entries map[string]json.RawMessage
+	if err := json.NewDecoder(response.Body).Decode(&entries); err != nil {
+		return "", err
+	}
+	for uuid := range entries {
+		return uuid, nil
+	}
+
+	return "", errors.New("rekor returned no log entry")
+}
+
+// Search returns the uuids of the log entries of the hex encoded sha256 hash.
+func (c *RekorClient) Search(ctx context.Context, hash string) ([]string, error) {
+	body, err := json.Marshal(map[string]string{"hash": "sha256:" + hash})
+	if err != nil {
+		return nil, err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(c.BaseUrl, "/")+"/api/v1/index/retrieve", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := c.getClient().Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not search the transparency log of rekor: %s", response.Status)
+	}
+
+	var uuids []string
+	if err := json.NewDecoder(response.Body).Decode(&uuids); err != nil {
+		return nil, err
+	}
+
+	return uuids, nil
+}
+
+// RekorEntry is a hashedrekord entry of the Rekor log.
+type RekorEntry struct {
+	UUID string
+	// IntegratedTime is when the entry was added to the log.
+	IntegratedTime time.Time
+	// Hash is the hex encoded sha256 hash of the signed data.
+	Hash      string
+	Signature []byte
+	// PublicKey is the PEM encoded public key or certificate the signature is verified with.
+	PublicKey []byte
+}
+
+// Entry returns the hashedrekord log entry with the uuid. The signed entry timestamp and the inclusion proof of the
+// entry are not checked, the log is trusted.
+func (c *RekorClient) Entry(ctx context.Context, uuid string) (*RekorEntry, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(c.BaseUrl, "/")+"/api/v1/log/entries/"+url.PathEscape(uuid), nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.getClient().Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not get the transparency log entry %s of rekor: %s", uuid, response.Status)
+	}
+
+	var entries map[string]struct {
+		Body           string `json:"body"`
+		IntegratedTime int64  `json:"integratedTime"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&entries); err != nil {
+		return nil, err
+	}
+	for entryUuid, entry := range entries {
+		data, err := base64.StdEncoding.DecodeString(entry.Body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transparency log entry %s: %v", entryUuid, err)
+		}
+		var body struct {
+			Kind string `json:"kind"`
+			Spec struct {
+				Signature struct {
+					Content   string `json:"content"`
+					PublicKey struct {
+						Content string `json:"content"`
+					} `json:"publicKey"`
+				} `json:"signature"`
+				Data struct {
+					Hash struct {
+						Algorithm string `json:"algorithm"`
+						Value     string `json:"value"`
+					} `json:"hash"`
+				} `json:"data"`
+			} `json:"spec"`
+		}
+		if err := json.Unmarshal(data, &body); err != nil {
+			return nil, fmt.Errorf("invalid transparency log entry %s: %v", entryUuid, err)
+		}
+		if body.Kind != "hashedrekord" || body.Spec.Data.Hash.Algorithm != "sha256" {
+			return nil, fmt.Errorf("unsupported transparency log entry %s of kind %s", entryUuid, body.Kind)
+		}
+		signature, err := base64.StdEncoding.DecodeString(body.Spec.Signature.Content)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature of the transparency log entry %s: %v", entryUuid, err)
+		}
+		publicKey, err := base64.StdEncoding.DecodeString(body.Spec.Signature.PublicKey.Content)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key of the transparency log entry %s: %v", entryUuid, err)
+		}
+
+		return &RekorEntry{
+			UUID:           entryUuid,
+			IntegratedTime: time.Unix(entry.IntegratedTime, 0),
+			Hash:           body.Spec.Data.Hash.Value,
+			Signature:      signature,
+			PublicKey:      publicKey,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("rekor returned no transparency log entry %s", uuid)
+}
+
+func (c *RekorClient) getClient() *http.Client {
+	if c.Client == nil {
+		return http.DefaultClient
+	}
+
+	return c.Client
+}
@@ -0,0 +1,123 @@
+package scanner
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func getTransparencyLogItems(digest string) []*ResultItem {
+	return []*ResultItem{{
+		Repository: &Repository{FullName: "user/repo"},
+		Releases: []*Release{{
+			TagName: "v1.0.0",
+			Assets: []*Asset{
+				{Name: "app-linux-amd64", Digest: digest},
+				{Name: "app-no-digest"},
+			},
+		}},
+	}}
+}
+
+func TestTransparencyLogChain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "assets.log")
+	log, err := OpenTransparencyLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := log.AppendItems(context.Background(), getTransparencyLogItems("sha256:aaa")); err != nil {
+		t.Fatal(err)
+	}
+
+	// A reopened log continues the chain.
+	log, err = OpenTransparencyLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := log.AppendItems(context.Background(), getTransparencyLogItems("sha256:bbb")); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := ReadTransparencyLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("invalid entries count, expected %d, got %d", 2, len(entries))
+	}
+	if entries[1].PrevHash != entries[0].Hash || entries[1].Index != 1 {
+		t.Fatalf("entries are not chained: %v", entries)
+	}
+	if entry := FindTransparencyLogEntry(entries, "user/repo", "app-linux-amd64", "sha256:aaa"); entry == nil {
+		t.Fatal("logged digest is not found")
+	}
+	if entry := FindTransparencyLogEntry(entries, "user/repo", "app-linux-amd64", "sha256:ccc"); entry != nil {
+		t.Fatalf("unexpected entry found: %v", entry)
+	}
+}
+
+func TestTransparencyLogDetectsTampering(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "assets.log")
+	log, err := OpenTransparencyLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := log.AppendItems(context.Background(), getTransparencyLogItems("sha256:aaa")); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Replace(string(data), "sha256:aaa", "sha256:bbb", 1)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ReadTransparencyLog(path); err == nil {
+		t.Fatal("tampered log is not detected")
+	}
+}
+
+func TestTransparencyLogRekorSubmission(t *testing.T) {
+	var submitted map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/log/entries" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&submitted)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"24296fb24b8ad77a": {"logIndex": 1}}`))
+	}))
+	defer server.Close()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	log, err := OpenTransparencyLog(filepath.Join(t.TempDir(), "assets.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Rekor = &RekorClient{BaseUrl: server.URL, Signer: key}
+
+	entries, err := log.AppendItems(context.Background(), getTransparencyLogItems("sha256:aaa"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries[0].RekorUUID != "24296fb24b8ad77a" {
+		t.Fatalf("invalid rekor uuid, expected %s, got %s", "24296fb24b8ad77a", entries[0].RekorUUID)
+	}
+	if submitted["kind"] != "hashedrekord" {
+		t.Fatalf("invalid rekor entry kind: %v", submitted["kind"])
+	}
+}
@@ -0,0 +1,77 @@
+package scanner
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// TransportConfig is the proxy and TLS configuration of the HTTP client for corporate networks, e.g. behind a
+// TLS-intercepting proxy or with a GitHub Enterprise Server instance signed by a private CA.
+type TransportConfig struct {
+	// Proxy is the url of the http, https or socks5 proxy, e.g. socks5://proxy.example.com:1080. The proxy of the
+	// HTTPS_PROXY, HTTP_PROXY and NO_PROXY env vars is used if it is empty.
+	Proxy string
+	// CAFile is a PEM bundle of certificates trusted in addition to the system ones.
+	CAFile string
+	// CertFile and KeyFile are the PEM client certificate and its key presented to servers requiring mutual TLS.
+	CertFile string
+	KeyFile  string
+}
+
+// IsZero reports whether the config changes nothing, so the default client could be used.
+func (c TransportConfig) IsZero() bool {
+	return c == TransportConfig{}
+}
+
+// NewClient returns an HTTP client with the proxy and TLS configuration.
+func (c TransportConfig) NewClient() (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if c.Proxy != "" {
+		proxy, err := url.Parse(c.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy url %s: %w", c.Proxy, err)
+		}
+		switch proxy.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, fmt.Errorf("invalid proxy url %s: expected http, https or socks5 scheme", c.Proxy)
+		}
+		if proxy.Host == "" {
+			return nil, fmt.Errorf("invalid proxy url %s: host is missing", c.Proxy)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if c.CAFile != "" {
+		data, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read the CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no PEM certificates found in the CA bundle %s", c.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if c.CertFile != "" || c.KeyFile != "" {
+		if c.CertFile == "" || c.KeyFile == "" {
+			return nil, fmt.Errorf("client certificate requires both the certificate and the key file")
+		}
+		certificate, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not load the client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	return &http.Client{Transport: transport}, nil
+}
@@ -0,0 +1,74 @@
+package scanner
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTransportConfigCAFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client, err := TransportConfig{}.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (&Scanner{BaseUrl: server.URL, Client: client}).GetRepositoriesPerPage("test", 1); err == nil {
+		t.Fatal("invalid response of the server signed by an unknown CA: error is expected")
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if client, err = (TransportConfig{CAFile: caFile}).NewClient(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (&Scanner{BaseUrl: server.URL, Client: client}).GetRepositoriesPerPage("test", 1); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTransportConfigProxy(t *testing.T) {
+	var host string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host = r.URL.Host
+		w.Write([]byte(`[]`))
+	}))
+	defer proxy.Close()
+
+	client, err := TransportConfig{Proxy: proxy.URL}.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (&Scanner{BaseUrl: "http://github.example.com/api/v3", Client: client}).GetRepositoriesPerPage("test", 1); err != nil {
+		t.Fatal(err)
+	}
+	if host != "github.example.com" {
+		t.Fatalf("invalid host of the proxied request, expected github.example.com, got %s", host)
+	}
+}
+
+func TestTransportConfigInvalid(t *testing.T) {
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	if err := os.WriteFile(empty, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, config := range []TransportConfig{
+		{Proxy: "ftp://proxy.example.com"},
+		{Proxy: "http://"},
+		{CAFile: empty},
+		{CAFile: filepath.Join(t.TempDir(), "missing.pem")},
+		{CertFile: "client.pem"},
+	} {
+		if _, err := config.NewClient(); err == nil {
+			t.Fatalf("invalid client of %+v: error is expected", config)
+		}
+	}
+}
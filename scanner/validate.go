@@ -0,0 +1,87 @@
+package scanner
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// githubLaunch is the earliest sane release date, nothing could be published on GitHub before it.
+var githubLaunch = time.Date(2008, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// maxClockSkew is how far in the future a release date may be before it is considered invalid.
+const maxClockSkew = 24 * time.Hour
+
+// ValidationPolicy configures the checks of ValidateResults.
+type ValidationPolicy struct {
+	// RequireParseableVersions reports releases whose versions do not follow the repository version scheme.
+	RequireParseableVersions bool
+	// Artifacts reports releases missing the assets the policy expects. Drafts are not checked, their assets
+	// could still be uploading.
+	Artifacts ArtifactPolicy
+}
+
+// ValidationIssue is a suspicious value found in the scan results.
+type ValidationIssue struct {
+	Repository string `json:"repository"`
+	Release    string `json:"release,omitempty"`
+	Message    string `json:"message"`
+}
+
+func (i *ValidationIssue) String() string {
+	if i.Release != "" {
+		return fmt.Sprintf("%s@%s: %s", i.Repository, i.Release, i.Message)
+	}
+
+	return fmt.Sprintf("%s: %s", i.Repository, i.Message)
+}
+
+// ValidateResults checks the scan results for duplicate repositories and releases, release dates out of sane
+// ranges and, if the policy requires, unparseable versions and missing assets. Issues are also added to the warnings of their items.
+func (s *Scanner) ValidateResults(items []*ResultItem, policy ValidationPolicy) []*ValidationIssue {
+	var issues []*ValidationIssue
+	now := time.Now()
+	repositories := make(map[string]bool)
+	for _, item := range items {
+		fullName := item.Repository.FullName
+		report := func(release, message string) {
+			issue := &ValidationIssue{Repository: fullName, Release: release, Message: message}
+			issues = append(issues, issue)
+			item.Warnings = append(item.Warnings, issue.String())
+		}
+
+		if repositories[fullName] {
+			report("", "duplicate repository")
+		}
+		repositories[fullName] = true
+
+		scheme := s.GetVersionScheme(fullName)
+		versions := make(map[string]bool)
+		for _, release := range item.Releases {
+			version := release.Version()
+			if versions[version] {
+				report(version, "duplicate release")
+			}
+			versions[version] = true
+
+			if release.PublishedAt != nil && release.PublishedAt.Before(githubLaunch) {
+				report(version, fmt.Sprintf("release date %s is too old", release.PublishedAt.Format(time.DateOnly)))
+			}
+			if release.PublishedAt != nil && release.PublishedAt.After(now.Add(maxClockSkew)) {
+				report(version, fmt.Sprintf("release date %s is in the future", release.PublishedAt.Format(time.DateOnly)))
+			}
+			if policy.RequireParseableVersions {
+				if _, err := scheme.Compare(version, version); err != nil {
+					report(version, fmt.Sprintf("version is not parseable by the %s scheme", scheme.Name()))
+				}
+			}
+			if !release.Draft {
+				if missing := policy.Artifacts.MissingAssets(fullName, release); len(missing) > 0 {
+					report(version, "missing expected assets "+strings.Join(missing, ", "))
+				}
+			}
+		}
+	}
+
+	return issues
+}
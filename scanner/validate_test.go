@@ -0,0 +1,56 @@
+package scanner
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidateResults(t *testing.T) {
+	old := time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+	future := time.Now().Add(72 * time.Hour)
+	valid := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	items := []*ResultItem{
+		{
+			Repository: &Repository{FullName: "user/repo"},
+			Releases: []*Release{
+				{TagName: "v1.0.0", PublishedAt: &valid},
+				{TagName: "v1.0.0"},
+				{TagName: "v0.1.0", PublishedAt: &old},
+				{TagName: "v2.0.0", PublishedAt: &future},
+				{TagName: "nightly"},
+			},
+		},
+		{Repository: &Repository{FullName: "user/repo"}},
+	}
+
+	scanner := Scanner{}
+	issues := scanner.ValidateResults(items, ValidationPolicy{})
+	if len(issues) != 4 {
+		t.Fatalf("invalid issues count, expected %d, got %d: %v", 4, len(issues), issues)
+	}
+
+	issues = scanner.ValidateResults(items, ValidationPolicy{RequireParseableVersions: true})
+	if len(issues) != 5 {
+		t.Fatalf("invalid issues count, expected %d, got %d: %v", 5, len(issues), issues)
+	}
+	if issues[3].Release != "nightly" {
+		t.Fatalf("invalid issue, expected unparseable version nightly, got %v", issues[3])
+	}
+	if len(items[1].Warnings) != 2 {
+		t.Fatalf("invalid warnings of the duplicate repository: %v", items[1].Warnings)
+	}
+
+	artifacts := ArtifactPolicy{{Pattern: "user/*", Assets: []string{"checksums.txt"}}}
+	items = []*ResultItem{{
+		Repository: &Repository{FullName: "user/cli"},
+		Releases: []*Release{
+			{TagName: "v1.1.0", PublishedAt: &valid, Assets: []*Asset{{Name: "checksums.txt"}}},
+			{TagName: "v1.0.0", PublishedAt: &valid},
+			{TagName: "v1.2.0", Draft: true},
+		},
+	}}
+	issues = scanner.ValidateResults(items, ValidationPolicy{Artifacts: artifacts})
+	if len(issues) != 1 || issues[0].Release != "v1.0.0" || issues[0].Message != "missing expected assets checksums.txt" {
+		t.Fatalf("invalid issues, expected v1.0.0 missing checksums.txt, got %v", issues)
+	}
+}
@@ -0,0 +1,368 @@
+package scanner
+
+import (
+	"cmp"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultVersionScheme = "semver"
+
+// VersionScheme compares release versions of a particular versioning scheme.
+type VersionScheme interface {
+	Name() string
+	// Compare returns -1, 0 or +1 if the version a is less than, equal to or greater than the version b.
+	// An error is returned if any of the versions does not follow the scheme.
+	Compare(a, b string) (int, error)
+}
+
+// VersionSchemeRule assigns a version scheme to repositories whose full name matches the pattern, e.g. "myorg/*".
+type VersionSchemeRule struct {
+	Pattern string
+	Scheme  VersionScheme
+}
+
+// NewVersionSchemeRule returns the rule assigning the scheme of the name to the repositories matching the pattern.
+func NewVersionSchemeRule(pattern, scheme string) (VersionSchemeRule, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return VersionSchemeRule{}, fmt.Errorf("invalid repository pattern %s: %w", pattern, err)
+	}
+	versionScheme, err := GetVersionScheme(scheme)
+	if err != nil {
+		return VersionSchemeRule{}, err
+	}
+
+	return VersionSchemeRule{Pattern: pattern, Scheme: versionScheme}, nil
+}
+
+// ParseVersionSchemeRule parses the "pattern=scheme" rule, e.g. "myorg/*=calver".
+func ParseVersionSchemeRule(spec string) (VersionSchemeRule, error) {
+	pattern, scheme, ok := strings.Cut(spec, "=")
+	if !ok || strings.TrimSpace(pattern) == "" {
+		return VersionSchemeRule{}, fmt.Errorf("invalid version scheme rule %q, expected <pattern>=<scheme>", spec)
+	}
+
+	return NewVersionSchemeRule(strings.TrimSpace(pattern), strings.TrimSpace(scheme))
+}
+
+var versionSchemes = map[string]VersionScheme{
+	"semver": semverScheme{},
+	"calver": calverScheme{},
+	"pep440": pep440Scheme{},
+	"date":   dateScheme{},
+}
+
+func GetVersionScheme(name string) (VersionScheme, error) {
+	scheme, ok := versionSchemes[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown version scheme: %s", name)
+	}
+
+	return scheme, nil
+}
+
+// Version returns the version of the release: its tag name or the release name if the tag is empty.
+func (r *Release) Version() string {
+	if r.TagName != "" {
+		return r.TagName
+	}
+
+	return r.Name
+}
+
+// GetVersionScheme returns the scheme of the first rule matching the repository full name. Semver is used by default.
+func (s *Scanner) GetVersionScheme(repositoryFullName string) VersionScheme {
+	if scheme := s.matchVersionScheme(repositoryFullName); scheme != nil {
+		return scheme
+	}
+
+	return versionSchemes[defaultVersionScheme]
+}
+
+// matchVersionScheme returns the scheme of the first rule matching the repository full name, nil if none does.
+func (s *Scanner) matchVersionScheme(repositoryFullName string) VersionScheme {
+	for _, rule := range s.VersionSchemes {
+		if matched, _ := path.Match(rule.Pattern, repositoryFullName); matched {
+			return rule.Scheme
+		}
+	}
+
+	return nil
+}
+
+// assignedVersionScheme returns the scheme assigned to the item by the scan, nil if none is assigned.
+func assignedVersionScheme(item *ResultItem) VersionScheme {
+	return versionSchemes[item.VersionScheme]
+}
+
+// followsScheme reports whether the version follows the scheme.
+func followsScheme(scheme VersionScheme, version string) bool {
+	_, err := scheme.Compare(version, version)
+
+	return err == nil
+}
+
+// LatestRelease returns the greatest published release of the item according to the repository version scheme.
+// Drafts, pre-releases and releases whose versions do not follow the scheme are ignored.
+func (s *Scanner) LatestRelease(item *ResultItem) *Release {
+	scheme := s.GetVersionScheme(item.Repository.FullName)
+
+	var latest *Release
+	for _, release := range item.Releases {
+		if release.Draft || release.Prerelease {
+			continue
+		}
+		if !followsScheme(scheme, release.Version()) {
+			continue
+		}
+		if latest == nil {
+			latest = release
+			continue
+		}
+		if result, _ := scheme.Compare(release.Version(), latest.Version()); result > 0 {
+			latest = release
+		}
+	}
+
+	return latest
+}
+
+type semverScheme struct{}
+
+var semverRegexp = regexp.MustCompile(`^v?(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+
+func (semverScheme) Name() string {
+	return "semver"
+}
+
+func (semverScheme) Compare(a, b string) (int, error) {
+	aMatch := semverRegexp.FindStringSubmatch(a)
+	if aMatch == nil {
+		return 0, fmt.Errorf("invalid semver version: %s", a)
+	}
+	bMatch := semverRegexp.FindStringSubmatch(b)
+	if bMatch == nil {
+		return 0, fmt.Errorf("invalid semver version: %s", b)
+	}
+
+	for i := 1; i <= 3; i++ {
+		if result := compareNumbers(aMatch[i], bMatch[i]); result != 0 {
+			return result, nil
+		}
+	}
+
+	return comparePrerelease(aMatch[4], bMatch[4]), nil
+}
+
+// comparePrerelease compares semver pre-release identifiers. A version without pre-release is greater.
+func comparePrerelease(a, b string) int {
+	if a == b {
+		return 0
+	}
+	if a == "" {
+		return 1
+	}
+	if b == "" {
+		return -1
+	}
+
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		aNumber, aErr := strconv.Atoi(aParts[i])
+		bNumber, bErr := strconv.Atoi(bParts[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if result := cmp.Compare(aNumber, bNumber); result != 0 {
+				return result
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if result := strings.Compare(aParts[i], bParts[i]); result != 0 {
+				return result
+			}
+		}
+	}
+
+	return cmp.Compare(len(aParts), len(bParts))
+}
+
+type calverScheme struct{}
+
+var calverRegexp = regexp.MustCompile(`^v?(\d{2,4})([.\-_]\d+)*$`)
+
+func (calverScheme) Name() string {
+	return "calver"
+}
+
+func (calverScheme) Compare(a, b string) (int, error) {
+	if !calverRegexp.MatchString(a) {
+		return 0, fmt.Errorf("invalid calver version: %s", a)
+	}
+	if !calverRegexp.MatchString(b) {
+		return 0, fmt.Errorf("invalid calver version: %s", b)
+	}
+
+	return compareSegments(splitNumbers(a), splitNumbers(b)), nil
+}
+
+type dateScheme struct{}
+
+var dateRegexp = regexp.MustCompile(`(\d{4})[.\-_]?(\d{2})[.\-_]?(\d{2})`)
+
+func (dateScheme) Name() string {
+	return "date"
+}
+
+func (dateScheme) Compare(a, b string) (int, error) {
+	aDate, err := parseDateVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	bDate, err := parseDateVersion(b)
+	if err != nil {
+		return 0, err
+	}
+
+	return aDate.Compare(bDate), nil
+}
+
+func parseDateVersion(version string) (time.Time, error) {
+	match := dateRegexp.FindStringSubmatch(version)
+	if match == nil {
+		return time.Time{}, fmt.Errorf("invalid date version: %s", version)
+	}
+	date, err := time.Parse("20060102", match[1]+match[2]+match[3])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date version: %s", version)
+	}
+
+	return date, nil
+}
+
+type pep440Scheme struct{}
+
+var pep440Regexp = regexp.MustCompile(`^v?(?:(\d+)!)?(\d+(?:\.\d+)*)(?:[-_.]?(a|alpha|b|beta|rc|c|pre|preview)[-_.]?(\d*))?((?:-\d+)|(?:[-_.]?(?:post|rev|r)[-_.]?\d*))?([-_.]?dev[-_.]?(\d*))?(?:\+[a-z0-9.]+)?$`)
+
+var digitsRegexp = regexp.MustCompile(`\d+`)
+
+var pep440PreReleases = map[string]int{
+	"a":       0,
+	"alpha":   0,
+	"b":       1,
+	"beta":    1,
+	"rc":      2,
+	"c":       2,
+	"pre":     2,
+	"preview": 2,
+}
+
+func (pep440Scheme) Name() string {
+	return "pep440"
+}
+
+func (pep440Scheme) Compare(a, b string) (int, error) {
+	aKey, err := pep440Key(a)
+	if err != nil {
+		return 0, err
+	}
+	bKey, err := pep440Key(b)
+	if err != nil {
+		return 0, err
+	}
+
+	return compareSegments(aKey, bKey), nil
+}
+
+// pep440Key builds a sort key of the version: epoch, release segments padded to a fixed length,
+// pre-release, post-release and development release markers.
+func pep440Key(version string) ([]int, error) {
+	match := pep440Regexp.FindStringSubmatch(strings.ToLower(version))
+	if match == nil {
+		return nil, fmt.Errorf("invalid pep440 version: %s", version)
+	}
+
+	const releaseSegments = 6
+	key := []int{atoi(match[1])}
+	release := splitNumbers(match[2])
+	for i := 0; i < releaseSegments; i++ {
+		if i < len(release) {
+			key = append(key, release[i])
+		} else {
+			key = append(key, 0)
+		}
+	}
+
+	hasPre := match[3] != ""
+	hasPost := match[5] != ""
+	hasDev := match[6] != ""
+
+	// A development release of a final version sorts before its pre-releases.
+	switch {
+	case hasPre:
+		key = append(key, pep440PreReleases[match[3]], atoi(match[4]))
+	case hasDev && !hasPost:
+		key = append(key, -1, 0)
+	default:
+		key = append(key, 3, 0)
+	}
+
+	if hasPost {
+		key = append(key, 1, atoi(digitsRegexp.FindString(match[5])))
+	} else {
+		key = append(key, 0, 0)
+	}
+
+	if hasDev {
+		key = append(key, 0, atoi(match[7]))
+	} else {
+		key = append(key, 1, 0)
+	}
+
+	return key, nil
+}
+
+func splitNumbers(version string) []int {
+	var numbers []int
+	for _, part := range strings.FieldsFunc(strings.TrimPrefix(version, "v"), func(r rune) bool {
+		return r == '.' || r == '-' || r == '_'
+	}) {
+		numbers = append(numbers, atoi(part))
+	}
+
+	return numbers
+}
+
+func compareSegments(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var aSegment, bSegment int
+		if i < len(a) {
+			aSegment = a[i]
+		}
+		if i < len(b) {
+			bSegment = b[i]
+		}
+		if result := cmp.Compare(aSegment, bSegment); result != 0 {
+			return result
+		}
+	}
+
+	return 0
+}
+
+func compareNumbers(a, b string) int {
+	return cmp.Compare(atoi(a), atoi(b))
+}
+
+func atoi(value string) int {
+	number, _ := strconv.Atoi(value)
+
+	return number
+}
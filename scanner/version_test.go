@@ -0,0 +1,123 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVersionSchemesCompare(t *testing.T) {
+	cases := []struct {
+		scheme   string
+		a, b     string
+		expected int
+	}{
+		{"semver", "v1.2.3", "v1.10.0", -1},
+		{"semver", "1.0.0", "1.0.0-rc.1", 1},
+		{"semver", "1.0.0-alpha.2", "1.0.0-alpha.10", -1},
+		{"semver", "1.0.0-alpha", "1.0.0-alpha.1", -1},
+		{"semver", "v2", "v1.9.9", 1},
+		{"calver", "2024.01.15", "2023.12.1", 1},
+		{"calver", "24.04", "24.04.1", -1},
+		{"pep440", "1.0.dev1", "1.0a1", -1},
+		{"pep440", "1.0a1", "1.0rc1", -1},
+		{"pep440", "1.0rc1", "1.0", -1},
+		{"pep440", "1.0", "1.0.post1", -1},
+		{"pep440", "1.0.0", "1.0", 0},
+		{"pep440", "1!0.1", "2.0", 1},
+		{"date", "release-2024-03-01", "release-2024-02-29", 1},
+		{"date", "20240301", "2024.03.01", 0},
+	}
+	for _, c := range cases {
+		scheme, err := GetVersionScheme(c.scheme)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := scheme.Compare(c.a, c.b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result != c.expected {
+			t.Errorf("invalid %s comparison of %s and %s, expected %d, got %d", c.scheme, c.a, c.b, c.expected, result)
+		}
+	}
+}
+
+func TestLatestRelease(t *testing.T) {
+	calver, err := GetVersionScheme("calver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanner := Scanner{
+		VersionSchemes: []VersionSchemeRule{{Pattern: "test/calver-*", Scheme: calver}},
+	}
+
+	item := &ResultItem{
+		Repository: &Repository{FullName: "test/semver"},
+		Releases: []*Release{
+			{TagName: "v1.2.0"},
+			{TagName: "v1.10.0"},
+			{TagName: "v2.0.0-rc.1", Prerelease: true},
+			{TagName: "nightly"},
+		},
+	}
+	if latest := scanner.LatestRelease(item); latest == nil || latest.TagName != "v1.10.0" {
+		t.Fatalf("invalid latest semver release, expected v1.10.0, got %v", latest)
+	}
+
+	item = &ResultItem{
+		Repository: &Repository{FullName: "test/calver-tool"},
+		Releases: []*Release{
+			{TagName: "2023.12.01"},
+			{TagName: "2024.1.3"},
+		},
+	}
+	if latest := scanner.LatestRelease(item); latest == nil || latest.TagName != "2024.1.3" {
+		t.Fatalf("invalid latest calver release, expected 2024.1.3, got %v", latest)
+	}
+}
+
+func TestParseVersionSchemeRule(t *testing.T) {
+	rule, err := ParseVersionSchemeRule("acme/infra-* = calver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rule.Pattern != "acme/infra-*" || rule.Scheme.Name() != "calver" {
+		t.Fatalf("invalid rule, expected acme/infra-*=calver, got %s=%s", rule.Pattern, rule.Scheme.Name())
+	}
+
+	for _, spec := range []string{"calver", "=calver", "acme/*=romver", "acme/[=semver"} {
+		if _, err := ParseVersionSchemeRule(spec); err == nil {
+			t.Errorf("invalid result of the rule %q, expected an error", spec)
+		}
+	}
+}
+
+func TestScanRepositoriesVersionScheme(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/test/repos":
+			w.Write([]byte(`[{"full_name": "test/calver-tool", "name": "calver-tool"}, {"full_name": "test/tool", "name": "tool"}]`))
+		default:
+			w.Write([]byte(`[{"tag_name": "2023.12"}, {"tag_name": "2024.2"}]`))
+		}
+	}))
+	defer server.Close()
+
+	rule, err := ParseVersionSchemeRule("test/calver-*=calver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanner := Scanner{BaseUrl: server.URL, VersionSchemes: []VersionSchemeRule{rule}}
+	items, err := scanner.ScanRepositories("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if items[0].VersionScheme != "calver" || items[0].Releases[0].TagName != "2024.2" {
+		t.Fatalf("invalid calver item, expected the calver scheme and 2024.2 first, got %s and %s", items[0].VersionScheme, items[0].Releases[0].TagName)
+	}
+	if items[1].VersionScheme != "" {
+		t.Fatalf("invalid version scheme of the item without a matching rule, expected none, got %s", items[1].VersionScheme)
+	}
+}
@@ -0,0 +1,78 @@
+package scanner
+
+import (
+	"fmt"
+	"time"
+)
+
+// ReleaseWindow is the publication date range of releases, e.g. a quarter or a sprint. A zero bound leaves the
+// window open on its side.
+type ReleaseWindow struct {
+	// After is the inclusive start of the window.
+	After time.Time
+	// Before is the exclusive end of the window.
+	Before time.Time
+}
+
+// ParseReleaseWindow parses the YYYY-MM-DD dates of the window bounds, empty dates leave the window open.
+func ParseReleaseWindow(after, before string) (ReleaseWindow, error) {
+	var window ReleaseWindow
+	for _, bound := range []struct {
+		date  string
+		value *time.Time
+	}{{after, &window.After}, {before, &window.Before}} {
+		if bound.date == "" {
+			continue
+		}
+		value, err := time.Parse(time.DateOnly, bound.date)
+		if err != nil {
+			return ReleaseWindow{}, fmt.Errorf("invalid date %s, expected YYYY-MM-DD", bound.date)
+		}
+		*bound.value = value
+	}
+	if !window.After.IsZero() && !window.Before.IsZero() && !window.After.Before(window.Before) {
+		return ReleaseWindow{}, fmt.Errorf("invalid release window, %s is not before %s", after, before)
+	}
+
+	return window, nil
+}
+
+func (w ReleaseWindow) IsZero() bool {
+	return w.After.IsZero() && w.Before.IsZero()
+}
+
+// Contains reports whether the release was published within the window. Drafts are only within open windows,
+// they are not published yet.
+func (w ReleaseWindow) Contains(release *Release) bool {
+	if w.IsZero() {
+		return true
+	}
+	if release.PublishedAt == nil {
+		return false
+	}
+
+	return !release.PublishedAt.Before(w.After) && (w.Before.IsZero() || release.PublishedAt.Before(w.Before))
+}
+
+// Filter keeps the releases published within the window.
+func (w ReleaseWindow) Filter(releases []*Release) []*Release {
+	if w.IsZero() {
+		return releases
+	}
+
+	var kept []*Release
+	for _, release := range releases {
+		if w.Contains(release) {
+			kept = append(kept, release)
+		}
+	}
+
+	return kept
+}
+
+// Apply keeps the releases of the items published within the window, e.g. of a stored snapshot.
+func (w ReleaseWindow) Apply(items []*ResultItem) {
+	for _, item := range items {
+		item.Releases = w.Filter(item.Releases)
+	}
+}
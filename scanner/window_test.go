@@ -0,0 +1,77 @@
+package scanner
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseReleaseWindow(t *testing.T) {
+	window, err := ParseReleaseWindow("2024-01-01", "2024-04-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	published := func(date string) *Release {
+		if date == "" {
+			return &Release{Draft: true}
+		}
+		at, _ := time.Parse(time.DateOnly, date)
+		return &Release{PublishedAt: &at}
+	}
+	for date, expected := range map[string]bool{"2023-12-31": false, "2024-01-01": true, "2024-03-31": true, "2024-04-01": false, "": false} {
+		if contains := window.Contains(published(date)); contains != expected {
+			t.Fatalf("invalid window of the release published on %q, expected %v, got %v", date, expected, contains)
+		}
+	}
+	if !(ReleaseWindow{}).Contains(published("")) {
+		t.Fatalf("invalid open window, expected drafts to be within it")
+	}
+
+	for _, bounds := range [][2]string{{"2024-04-01", "2024-01-01"}, {"2024-01-01", "2024-01-01"}, {"01/01/2024", ""}} {
+		if _, err := ParseReleaseWindow(bounds[0], bounds[1]); err == nil {
+			t.Fatalf("invalid error of the window %v, expected error, got nil", bounds)
+		}
+	}
+}
+
+func TestGetAllReleasesWithinWindow(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page < 12 {
+			setPageLinks(w, r, page+1, 12)
+		}
+		// a release per month of 2024, newest first
+		month := 13 - page
+		fmt.Fprintf(w, `[{"tag_name": "v1.%d.0", "published_at": "2024-%02d-15T00:00:00Z"}]`, month, month)
+	}))
+	defer server.Close()
+
+	window, _ := ParseReleaseWindow("2024-04-01", "2024-07-01")
+	scanner := Scanner{BaseUrl: server.URL, PerPage: 1, ReleaseWindow: window}
+	releases, err := scanner.GetAllReleases("test", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(releases) != 3 || releases[0].TagName != "v1.6.0" || releases[2].TagName != "v1.4.0" {
+		t.Fatalf("invalid releases, expected v1.6.0 to v1.4.0, got %d releases", len(releases))
+	}
+	// the pages up to the first release older than the window are fetched
+	if requests != 10 {
+		t.Fatalf("invalid requests count, expected 10, got %d", requests)
+	}
+
+	requests = 0
+	scanner.MaxReleases = 2
+	if releases, err = scanner.GetAllReleases("test", "test"); err != nil {
+		t.Fatal(err)
+	}
+	if len(releases) != 2 || releases[1].TagName != "v1.5.0" || requests != 8 {
+		t.Fatalf("invalid limited releases, expected v1.6.0 and v1.5.0 of 8 requests, got %d of %d", len(releases), requests)
+	}
+}
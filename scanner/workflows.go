@@ -0,0 +1,111 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	CIStatusPassing = "passing"
+	CIStatusFailing = "failing"
+	CIStatusPending = "pending"
+	CIStatusNone    = "none"
+)
+
+type WorkflowRun struct {
+	ID         int64     `json:"id"`
+	Name       string    `json:"name"`
+	WorkflowID int64     `json:"workflow_id"`
+	HeadBranch string    `json:"head_branch"`
+	Status     string    `json:"status"`
+	Conclusion string    `json:"conclusion"`
+	HTMLURL    string    `json:"html_url"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// CIHealth summarizes the latest runs of the repository workflows.
+type CIHealth struct {
+	Status    string `json:"status"`
+	Workflows int    `json:"workflows"`
+	// Failing are names of the workflows whose latest run failed.
+	Failing []string `json:"failing,omitempty"`
+}
+
+// GetWorkflowRuns returns the latest run of every workflow of the repository. Only the latest page of runs is
+// looked at, so workflows that have not run for a long time in a busy repository could be missing.
+func (s *Scanner) GetWorkflowRuns(user, repository string) ([]*WorkflowRun, error) {
+	return s.getWorkflowRuns(context.Background(), user, repository, "")
+}
+
+// getWorkflowRuns returns the latest run of every workflow, of the branch runs only if the branch is not empty.
+func (s *Scanner) getWorkflowRuns(ctx context.Context, user, repository, branch string) ([]*WorkflowRun, error) {
+	if err := s.checkUser(user); err != nil {
+		return nil, err
+	}
+	if err := s.checkRepository(repository); err != nil {
+		return nil, err
+	}
+	ctx, span := s.getTracer().Start(ctx, "GetWorkflowRuns", StringAttribute("account", user), StringAttribute("repository", repository))
+	defer span.End()
+
+	query := url.Values{"per_page": {fmt.Sprint(s.getPerPage())}}
+	if branch != "" {
+		query.Set("branch", branch)
+	}
+	response, err := s.get(ctx, span, fmt.Sprintf("%s/repos/%s/%s/actions/runs?%s", s.BaseUrl, user, repository, query.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not get workflow runs for the repository %s: %w", repository, s.newApiError(response))
+	}
+
+	var result struct {
+		WorkflowRuns []*WorkflowRun `json:"workflow_runs"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	// Runs are listed newest first, so the first run of a workflow is its latest one.
+	var runs []*WorkflowRun
+	seen := make(map[int64]bool)
+	for _, run := range result.WorkflowRuns {
+		if !seen[run.WorkflowID] {
+			seen[run.WorkflowID] = true
+			runs = append(runs, run)
+		}
+	}
+
+	return runs, nil
+}
+
+// NewCIHealth summarizes the latest workflow runs: CI is failing if a latest run failed, pending if a run is not
+// completed yet and passing otherwise.
+func NewCIHealth(runs []*WorkflowRun) *CIHealth {
+	health := &CIHealth{Status: CIStatusNone, Workflows: len(runs)}
+	if len(runs) == 0 {
+		return health
+	}
+
+	health.Status = CIStatusPassing
+	for _, run := range runs {
+		switch {
+		case run.Status != "completed":
+			if health.Status == CIStatusPassing {
+				health.Status = CIStatusPending
+			}
+		case run.Conclusion == "failure" || run.Conclusion == "timed_out" || run.Conclusion == "startup_failure":
+			health.Status = CIStatusFailing
+			health.Failing = append(health.Failing, run.Name)
+		}
+	}
+
+	return health
+}
@@ -0,0 +1,53 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetWorkflowRuns(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/test/test/actions/runs" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"total_count": 3, "workflow_runs": [
+			{"id": 3, "name": "build", "workflow_id": 1, "status": "completed", "conclusion": "failure"},
+			{"id": 2, "name": "lint", "workflow_id": 2, "status": "completed", "conclusion": "success"},
+			{"id": 1, "name": "build", "workflow_id": 1, "status": "completed", "conclusion": "success"}
+		]}`))
+	}))
+	defer server.Close()
+
+	scanner := Scanner{BaseUrl: server.URL}
+	runs, err := scanner.GetWorkflowRuns("test", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 || runs[0].ID != 3 || runs[1].ID != 2 {
+		t.Fatalf("invalid latest runs, expected [3 2], got %v", runs)
+	}
+
+	health := NewCIHealth(runs)
+	if health.Status != CIStatusFailing || health.Workflows != 2 || len(health.Failing) != 1 || health.Failing[0] != "build" {
+		t.Fatalf("invalid ci health, expected failing build of 2 workflows, got %s %v of %d", health.Status, health.Failing, health.Workflows)
+	}
+}
+
+func TestNewCIHealth(t *testing.T) {
+	tests := []struct {
+		runs     []*WorkflowRun
+		expected string
+	}{
+		{nil, CIStatusNone},
+		{[]*WorkflowRun{{Status: "completed", Conclusion: "success"}, {Status: "completed", Conclusion: "skipped"}}, CIStatusPassing},
+		{[]*WorkflowRun{{Status: "completed", Conclusion: "success"}, {Status: "in_progress"}}, CIStatusPending},
+		{[]*WorkflowRun{{Status: "in_progress"}, {Status: "completed", Conclusion: "timed_out"}}, CIStatusFailing},
+	}
+	for _, test := range tests {
+		if status := NewCIHealth(test.runs).Status; status != test.expected {
+			t.Fatalf("invalid ci status, expected %s, got %s", test.expected, status)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"githubscanner/scanner"
+)
+
+func schemaCommand(flags *flag.FlagSet) func(args []string) {
+	validate := flags.String("validate", "", "validate a json snapshot or ndjson file against the schema instead of printing it")
+	flags.BoolVar(&quiet, "quiet", false, "suppress non-error output")
+
+	return func(args []string) {
+		if *validate == "" {
+			os.Stdout.Write(scanner.SnapshotSchema)
+			return
+		}
+
+		data, err := os.ReadFile(*validate)
+		if err != nil {
+			fail(err)
+		}
+		if err := validateOutput(data); err != nil {
+			fail(fmt.Errorf("%s does not match the schema %s: %w", *validate, scanner.SchemaVersion, err))
+		}
+		if !quiet {
+			fmt.Printf("%s matches the schema %s\n", *validate, scanner.SchemaVersion)
+		}
+	}
+}
+
+// validateOutput validates a json snapshot, or every line of ndjson output if the data has several json values.
+func validateOutput(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	var first json.RawMessage
+	if err := decoder.Decode(&first); err != nil {
+		return fmt.Errorf("invalid json: %w", err)
+	}
+	if !decoder.More() {
+		return scanner.Validate(data)
+	}
+
+	lines := bufio.NewScanner(bytes.NewReader(data))
+	lines.Buffer(nil, 64<<20)
+	for line := 1; lines.Scan(); line++ {
+		if len(bytes.TrimSpace(lines.Bytes())) == 0 {
+			continue
+		}
+		if err := scanner.ValidateItem(lines.Bytes()); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+
+	return lines.Err()
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"githubscanner/scanner"
+	"githubscanner/server"
+)
+
+func serveCommand(flags *flag.FlagSet) func(args []string) {
+	addr := flags.String("addr", ":8080", "address to listen on")
+	cacheTTL := flags.Duration("cache-ttl", 10*time.Minute, "how long scan results are served from the cache")
+	approvalsPath := flags.String("approvals", "", "json file release approvals are stored in (in memory by default)")
+	profiling := flags.Bool("pprof", false, "expose net/http/pprof profiles under /debug/pprof/")
+	priority := flags.String("priority", "", "comma separated high priority accounts or groups, their queued scans start first")
+	options := addScannerFlags(flags)
+
+	return func(args []string) {
+		s, err := options.newScanner()
+		if err != nil {
+			fail(err)
+		}
+
+		approvals, err := scanner.OpenApprovalStore(*approvalsPath)
+		if err != nil {
+			fail(err)
+		}
+		srv := server.New(s, *cacheTTL, approvals)
+		srv.Logger = s.Logger
+		if *priority != "" {
+			config, err := options.loadConfig()
+			if err != nil {
+				fail(err)
+			}
+			for _, account := range config.ResolveAccounts(strings.Split(*priority, ",")) {
+				srv.SetPriority(account, true)
+			}
+		}
+		handler := srv.Handler()
+		if *profiling {
+			handler = withProfiling(handler)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if err := listenAndServe(ctx, &http.Server{Addr: *addr, Handler: handler}); err != nil {
+			fail(err)
+		}
+		// the queued scans are completed and their callbacks sent before the exit
+		warn("shutting down: completing the queued scans")
+		srv.Close()
+	}
+}
+
+const (
+	// shutdownTimeout bounds the wait for the requests in flight when the server shuts down.
+	shutdownTimeout = 30 * time.Second
+	// notificationTimeout bounds the requests to the group webhooks of the watch mode.
+	notificationTimeout = 10 * time.Second
+)
+
+// listenAndServe serves until the context is done, then stops accepting connections and waits for the requests
+// in flight.
+func listenAndServe(ctx context.Context, httpServer *http.Server) error {
+	errs := make(chan error, 1)
+	go func() {
+		errs <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	return httpServer.Shutdown(shutdownCtx)
+}
+
+// withProfiling serves pprof profiles next to the handler. Scan goroutines carry account, repository and
+// endpoint labels, so CPU and goroutine profiles could be broken down by them.
+func withProfiling(handler http.Handler) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/", handler)
+
+	return mux
+}
+
+func watchCommand(flags *flag.FlagSet) func(args []string) {
+	interval := flags.Duration("interval", 10*time.Minute, "how often the accounts are rescanned")
+	priority := flags.String("priority", "", "comma separated high priority accounts or groups, they are rescanned first")
+	maxScans := flags.Int("max-scans-per-refresh", 0, "maximum accounts rescanned per interval, the rest are deferred (no limit by default)")
+	options := addScannerFlags(flags)
+
+	return func(args []string) {
+		if len(args) < 1 {
+			usage("account is not specified")
+		}
+
+		s, err := options.newScanner()
+		if err != nil {
+			fail(err)
+		}
+
+		config, err := options.loadConfig()
+		if err != nil {
+			fail(err)
+		}
+
+		service := scanner.NewScannerService(s, *interval)
+		service.MaxScansPerRefresh = *maxScans
+		if *priority != "" {
+			for _, account := range config.ResolveAccounts(strings.Split(*priority, ",")) {
+				service.SetPriority(account, true)
+			}
+		}
+		for _, account := range config.ResolveAccounts(args) {
+			updates, unsubscribe := service.Subscribe(account)
+			defer unsubscribe()
+			label := account
+			if groups := config.AccountGroups(account); len(groups) > 0 {
+				label = fmt.Sprintf("[%s] %s", strings.Join(groups, ","), account)
+			}
+			notifications := config.AccountNotifications(account)
+			go func() {
+				for update := range updates {
+					for _, change := range update.Changes {
+						fmt.Println(label, formatChange(change))
+					}
+					for group, n := range notifications {
+						changes := n.Filter(update.Changes)
+						if n.WebhookURL == "" || len(changes) == 0 {
+							continue
+						}
+						if err := postGroupNotification(n.WebhookURL, group, update, changes); err != nil {
+							warn("could not notify the changes of %s to the group %s: %v", account, group, err)
+						}
+					}
+				}
+			}()
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if err := service.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			fail(err)
+		}
+	}
+}
+
+// groupNotification is posted to the webhook of a group with the changes of one of its accounts.
+type groupNotification struct {
+	Group     string            `json:"group"`
+	Account   string            `json:"account"`
+	Changes   []*scanner.Change `json:"changes"`
+	ScannedAt time.Time         `json:"scanned_at"`
+}
+
+func postGroupNotification(webhookURL, group string, update *scanner.Update, changes []*scanner.Change) error {
+	body, err := json.Marshal(groupNotification{Group: group, Account: update.Account, Changes: changes, ScannedAt: update.ScannedAt})
+	if err != nil {
+		return err
+	}
+	client := http.Client{Timeout: notificationTimeout}
+	response, err := client.Post(webhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", response.Status)
+	}
+
+	return nil
+}
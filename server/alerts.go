@@ -0,0 +1,86 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const defaultStaleAfter = time.Hour
+
+// AlertRulesOptions parameterizes the generated Prometheus alert rules.
+type AlertRulesOptions struct {
+	// Accounts limits the rules to the accounts. Rules match all accounts if it is empty.
+	Accounts []string
+	// StaleAfter is how old the last successful scan of an account may be before its data is alerted as stale.
+	StaleAfter time.Duration
+}
+
+// WriteAlertRules writes a Prometheus rules file alerting on the metrics the server exposes: failing scans,
+// exhausted rate limit, stale account data and configured accounts that were never scanned.
+func WriteAlertRules(w io.Writer, options AlertRulesOptions) error {
+	staleAfter := options.StaleAfter
+	if staleAfter <= 0 {
+		staleAfter = defaultStaleAfter
+	}
+	selector := ""
+	if len(options.Accounts) > 0 {
+		patterns := make([]string, 0, len(options.Accounts))
+		for _, account := range options.Accounts {
+			patterns = append(patterns, regexp.QuoteMeta(account))
+		}
+		selector = fmt.Sprintf(`account=~"%s"`, promQLEscape(strings.Join(patterns, "|")))
+	}
+
+	var b strings.Builder
+	b.WriteString("groups:\n- name: githubscanner\n  rules:\n")
+	writeAlertRule(&b, "GitHubScannerScanFailures", "warning",
+		fmt.Sprintf(`increase(%s{%s}[15m]) > 0`, metricScansTotal, joinSelectors(selector, `result="error"`)),
+		"Scans of {{ $labels.account }} are failing")
+	writeAlertRule(&b, "GitHubScannerRateLimited", "critical",
+		fmt.Sprintf(`increase(%s{%s}[15m]) > 0`, metricScansTotal, joinSelectors(selector, `result="rate_limited"`)),
+		"Scans of {{ $labels.account }} hit the API rate limit")
+	writeAlertRule(&b, "GitHubScannerStaleAccountData", "warning",
+		fmt.Sprintf(`time() - %s{%s} > %d`, metricLastSuccessfulScan, selector, int(staleAfter.Seconds())),
+		fmt.Sprintf("Data of {{ $labels.account }} was not refreshed for more than %s", staleAfter))
+	for _, account := range options.Accounts {
+		writeAlertRule(&b, "GitHubScannerAccountNeverScanned", "warning",
+			fmt.Sprintf(`absent(%s{account="%s"})`, metricLastSuccessfulScan, promQLEscape(account)),
+			fmt.Sprintf("Account %s has never been scanned successfully", account))
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+func writeAlertRule(b *strings.Builder, name, severity, expr, summary string) {
+	fmt.Fprintf(b, "  - alert: %s\n", name)
+	fmt.Fprintf(b, "    expr: %s\n", yamlQuote(expr))
+	fmt.Fprintf(b, "    for: 5m\n")
+	fmt.Fprintf(b, "    labels:\n      severity: %s\n", severity)
+	fmt.Fprintf(b, "    annotations:\n      summary: %s\n", yamlQuote(summary))
+}
+
+func joinSelectors(selectors ...string) string {
+	var nonEmpty []string
+	for _, selector := range selectors {
+		if selector != "" {
+			nonEmpty = append(nonEmpty, selector)
+		}
+	}
+
+	return strings.Join(nonEmpty, ",")
+}
+
+// promQLEscape escapes the value for a double quoted PromQL string.
+func promQLEscape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)
+}
+
+// yamlQuote returns the value as a single quoted YAML string.
+func yamlQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"githubscanner/scanner"
+)
+
+type approvalRequest struct {
+	Status   string `json:"status"`
+	Reviewer string `json:"reviewer"`
+}
+
+func (s *Server) handleListApprovals(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, http.StatusOK, s.Approvals.List())
+}
+
+// handleSetApproval marks the release as reviewed or approved by the reviewer.
+func (s *Server) handleSetApproval(w http.ResponseWriter, r *http.Request) {
+	var request approvalRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		s.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid approval request: %v", err))
+		return
+	}
+
+	approval := &scanner.Approval{
+		Repository: r.PathValue("owner") + "/" + r.PathValue("repo"),
+		Release:    r.PathValue("release"),
+		Status:     request.Status,
+		Reviewer:   request.Reviewer,
+		ReviewedAt: time.Now().UTC(),
+	}
+	if err := s.Approvals.Set(approval); err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.writeJSON(w, http.StatusOK, approval)
+}
+
+func (s *Server) handleDeleteApproval(w http.ResponseWriter, r *http.Request) {
+	if err := s.Approvals.Delete(r.PathValue("owner")+"/"+r.PathValue("repo"), r.PathValue("release")); err != nil {
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// filterByReview keeps only releases in the review status requested by the "review" query parameter.
+func (s *Server) filterByReview(r *http.Request, items []*scanner.ResultItem) []*scanner.ResultItem {
+	status := r.URL.Query().Get("review")
+	if status == "" {
+		return items
+	}
+
+	return s.Approvals.FilterByReviewStatus(items, status)
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"githubscanner/output"
+)
+
+// handleFeed renders the releases of the accounts of the account query parameters as an Atom feed, scanning them
+// if their cache is empty or expired. Without the parameters the feed is of all cached scans.
+func (s *Server) handleFeed(w http.ResponseWriter, r *http.Request) {
+	accounts := r.URL.Query()["account"]
+	scans := make(map[string]*cachedScan)
+	if len(accounts) == 0 {
+		s.mu.RLock()
+		for account, scan := range s.cache {
+			accounts = append(accounts, account)
+			scans[account] = scan
+		}
+		s.mu.RUnlock()
+		sort.Strings(accounts)
+	}
+
+	report := &output.Report{Title: "Releases of " + strings.Join(accounts, ", ")}
+	for _, account := range accounts {
+		scan, ok := scans[account]
+		if !ok {
+			if scan, ok = s.getCached(account); !ok {
+				var err error
+				if scan, err = s.scan(account); err != nil {
+					s.writeError(w, http.StatusBadGateway, err.Error())
+					return
+				}
+			}
+		}
+		report.Items = append(report.Items, s.filterByReview(r, scan.items)...)
+		if scan.scannedAt.After(report.GeneratedAt) {
+			report.GeneratedAt = scan.scannedAt
+		}
+	}
+	if len(accounts) == 0 {
+		report.Title, report.GeneratedAt = "Releases", time.Now()
+	}
+
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	output.WriteAtom(w, report)
+}
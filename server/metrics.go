@@ -0,0 +1,106 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"githubscanner/scanner"
+)
+
+// Names of the metrics exposed in the Prometheus text format on /metrics.
+const (
+	metricScansTotal         = "githubscanner_scans_total"
+	metricScanDuration       = "githubscanner_scan_duration_seconds"
+	metricLastSuccessfulScan = "githubscanner_last_successful_scan_timestamp_seconds"
+	scanResultSuccess        = "success"
+	scanResultError          = "error"
+	scanResultRateLimited    = "rate_limited"
+	metricsContentType       = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+type scanCounterKey struct {
+	account string
+	result  string
+}
+
+// metrics collects scan outcomes of the server per account.
+type metrics struct {
+	mu          sync.Mutex
+	scans       map[scanCounterKey]int
+	durations   map[string]time.Duration
+	lastSuccess map[string]time.Time
+}
+
+func (m *metrics) observeScan(account string, duration time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.scans == nil {
+		m.scans = make(map[scanCounterKey]int)
+		m.durations = make(map[string]time.Duration)
+		m.lastSuccess = make(map[string]time.Time)
+	}
+
+	result := scanResultSuccess
+	switch {
+	case errors.Is(err, scanner.ErrRateLimited):
+		result = scanResultRateLimited
+	case err != nil:
+		result = scanResultError
+	default:
+		m.lastSuccess[account] = time.Now()
+	}
+	m.scans[scanCounterKey{account, result}]++
+	m.durations[account] = duration
+}
+
+func (m *metrics) write(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s Scans of the account by result.\n# TYPE %s counter\n", metricScansTotal, metricScansTotal)
+	keys := make([]scanCounterKey, 0, len(m.scans))
+	for key := range m.scans {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b scanCounterKey) int {
+		if a.account != b.account {
+			return strings.Compare(a.account, b.account)
+		}
+		return strings.Compare(a.result, b.result)
+	})
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s{account=%q,result=%q} %d\n", metricScansTotal, key.account, key.result, m.scans[key])
+	}
+
+	fmt.Fprintf(w, "# HELP %s Duration of the last scan of the account.\n# TYPE %s gauge\n", metricScanDuration, metricScanDuration)
+	for _, account := range sortedAccounts(m.durations) {
+		fmt.Fprintf(w, "%s{account=%q} %g\n", metricScanDuration, account, m.durations[account].Seconds())
+	}
+
+	fmt.Fprintf(w, "# HELP %s Unix time of the last successful scan of the account.\n# TYPE %s gauge\n", metricLastSuccessfulScan, metricLastSuccessfulScan)
+	for _, account := range sortedAccounts(m.lastSuccess) {
+		fmt.Fprintf(w, "%s{account=%q} %d\n", metricLastSuccessfulScan, account, m.lastSuccess[account].Unix())
+	}
+}
+
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", metricsContentType)
+	s.metrics.write(w)
+}
+
+func sortedAccounts[V any](values map[string]V) []string {
+	accounts := make([]string, 0, len(values))
+	for account := range values {
+		accounts = append(accounts, account)
+	}
+	slices.Sort(accounts)
+
+	return accounts
+}
@@ -0,0 +1,332 @@
+package server
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"githubscanner/scanner"
+)
+
+const (
+	queueSize         = 100
+	queueWorkersCount = 4
+	// defaultMaxDeferredJobs is how many later queued jobs may start before a job, so jobs of high priority
+	// accounts can not starve the others.
+	defaultMaxDeferredJobs = 3
+	callbackTimeout        = 10 * time.Second
+	callbackAttempts       = 3
+	// finishedJobTTL is how long finished jobs are kept to be polled, at most maxFinishedJobs of them.
+	finishedJobTTL  = time.Hour
+	maxFinishedJobs = 1000
+)
+
+// callbackBackoff is the delay before the second callback attempt, it doubles with every next one.
+var callbackBackoff = time.Second
+
+const (
+	ScanStatusQueued    = "queued"
+	ScanStatusRunning   = "running"
+	ScanStatusCompleted = "completed"
+	ScanStatusFailed    = "failed"
+)
+
+type ScanRequest struct {
+	Account     string      `json:"account"`
+	Options     ScanOptions `json:"options"`
+	CallbackURL string      `json:"callback_url,omitempty"`
+}
+
+type ScanOptions struct {
+	// Refresh forces a new scan even if a cached result for the account is still fresh.
+	Refresh bool `json:"refresh"`
+}
+
+type ScanJob struct {
+	ID         string                `json:"id"`
+	Status     string                `json:"status"`
+	Request    ScanRequest           `json:"request"`
+	Items      []*scanner.ResultItem `json:"items,omitempty"`
+	Error      string                `json:"error,omitempty"`
+	CreatedAt  time.Time             `json:"created_at"`
+	FinishedAt *time.Time            `json:"finished_at,omitempty"`
+
+	// deferred is the number of jobs queued after the job that started before it.
+	deferred int
+}
+
+func (s *Server) startQueue() {
+	s.queueReady = sync.NewCond(&s.jobsMu)
+	for i := 0; i < queueWorkersCount; i++ {
+		s.workers.Add(1)
+		go s.queueWorker()
+	}
+}
+
+// SetPriority pins the account as a high priority one, or unpins it. Queued scans of high priority accounts start
+// before the other ones.
+func (s *Server) SetPriority(account string, high bool) {
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+
+	if s.priorities == nil {
+		s.priorities = make(map[string]bool)
+	}
+	if high {
+		s.priorities[account] = true
+	} else {
+		delete(s.priorities, account)
+	}
+}
+
+// Close stops the background scan queue: new scans are rejected, already queued ones are still processed and
+// Close waits until they are finished and their callbacks are sent.
+func (s *Server) Close() {
+	s.jobsMu.Lock()
+	s.closed = true
+	s.queueReady.Broadcast()
+	s.jobsMu.Unlock()
+	s.workers.Wait()
+}
+
+func (s *Server) queueWorker() {
+	defer s.workers.Done()
+	for {
+		job := s.nextJob()
+		if job == nil {
+			return
+		}
+		s.runJob(job)
+		if job.Request.CallbackURL != "" {
+			s.notify(job)
+		}
+	}
+}
+
+// nextJob waits for a queued job and takes it from the queue: jobs deferred too many times first, then jobs of
+// high priority accounts, then the rest, in the order they were queued. It returns nil once the queue is closed
+// and empty.
+func (s *Server) nextJob() *ScanJob {
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+
+	for len(s.queue) == 0 {
+		if s.closed {
+			return nil
+		}
+		s.queueReady.Wait()
+	}
+
+	maxDeferred := s.getMaxDeferredJobs()
+	rank := func(job *ScanJob) int {
+		switch {
+		case job.deferred >= maxDeferred:
+			return 0
+		case s.priorities[job.Request.Account]:
+			return 1
+		default:
+			return 2
+		}
+	}
+	next := 0
+	for i, job := range s.queue {
+		if rank(job) < rank(s.queue[next]) {
+			next = i
+		}
+	}
+	job := s.queue[next]
+	for _, queued := range s.queue[:next] {
+		queued.deferred++
+	}
+	s.queue = slices.Delete(s.queue, next, next+1)
+
+	return job
+}
+
+func (s *Server) getMaxDeferredJobs() int {
+	if s.MaxDeferredJobs <= 0 {
+		return defaultMaxDeferredJobs
+	}
+
+	return s.MaxDeferredJobs
+}
+
+func (s *Server) handleCreateScan(w http.ResponseWriter, r *http.Request) {
+	var request ScanRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		s.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid scan request: %v", err))
+		return
+	}
+	if request.Account == "" {
+		s.writeError(w, http.StatusBadRequest, "account is not specified")
+		return
+	}
+	if request.CallbackURL != "" {
+		if u, err := url.Parse(request.CallbackURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			s.writeError(w, http.StatusBadRequest, "callback url must be an absolute http(s) url")
+			return
+		}
+	}
+
+	job := &ScanJob{
+		ID:        newScanID(),
+		Status:    ScanStatusQueued,
+		Request:   request,
+		CreatedAt: time.Now(),
+	}
+	// the job is queued under the lock, so it is never added to the queue closed by Close
+	s.jobsMu.Lock()
+	if s.closed {
+		s.jobsMu.Unlock()
+		s.writeError(w, http.StatusServiceUnavailable, "server is shutting down")
+		return
+	}
+	s.evictJobs(time.Now())
+	if len(s.queue) >= queueSize {
+		s.jobsMu.Unlock()
+		s.writeError(w, http.StatusServiceUnavailable, "scan queue is full")
+		return
+	}
+	s.queue = append(s.queue, job)
+	s.jobs[job.ID] = job
+	s.queueReady.Signal()
+	s.jobsMu.Unlock()
+
+	s.writeJSON(w, http.StatusAccepted, s.getJob(job.ID))
+}
+
+// evictJobs drops the jobs finished more than finishedJobTTL ago and the oldest finished ones over
+// maxFinishedJobs, so the jobs of a long running server do not grow without bound. It is called with jobsMu locked.
+func (s *Server) evictJobs(now time.Time) {
+	var finished []*ScanJob
+	for id, job := range s.jobs {
+		if job.FinishedAt == nil {
+			continue
+		}
+		if now.Sub(*job.FinishedAt) > finishedJobTTL {
+			delete(s.jobs, id)
+			continue
+		}
+		finished = append(finished, job)
+	}
+	if len(finished) <= maxFinishedJobs {
+		return
+	}
+	slices.SortFunc(finished, func(a, b *ScanJob) int {
+		return a.FinishedAt.Compare(*b.FinishedAt)
+	})
+	for _, job := range finished[:len(finished)-maxFinishedJobs] {
+		delete(s.jobs, job.ID)
+	}
+}
+
+func (s *Server) handleGetScan(w http.ResponseWriter, r *http.Request) {
+	job := s.getJob(r.PathValue("id"))
+	if job == nil {
+		s.writeError(w, http.StatusNotFound, "scan not found")
+		return
+	}
+	s.writeJSON(w, http.StatusOK, job)
+}
+
+func (s *Server) runJob(job *ScanJob) {
+	s.updateJob(job, func(job *ScanJob) {
+		job.Status = ScanStatusRunning
+	})
+
+	var (
+		scan *cachedScan
+		ok   bool
+		err  error
+	)
+	if !job.Request.Options.Refresh {
+		scan, ok = s.getCached(job.Request.Account)
+	}
+	if !ok {
+		scan, err = s.scan(job.Request.Account)
+	}
+
+	s.updateJob(job, func(job *ScanJob) {
+		now := time.Now()
+		job.FinishedAt = &now
+		if err != nil {
+			job.Status = ScanStatusFailed
+			job.Error = err.Error()
+			return
+		}
+		job.Status = ScanStatusCompleted
+		job.Items = scan.items
+	})
+}
+
+// notify posts the finished job to its callback url. Failed attempts are logged and retried with a backoff.
+func (s *Server) notify(job *ScanJob) {
+	body, err := json.Marshal(s.getJob(job.ID))
+	if err != nil {
+		s.getLogger().Error("could not encode the scan callback", "id", job.ID, "error", err)
+		return
+	}
+	client := http.Client{Timeout: callbackTimeout}
+	backoff := callbackBackoff
+	for attempt := 1; ; attempt++ {
+		err = postCallback(&client, job.Request.CallbackURL, body)
+		if err == nil {
+			return
+		}
+		if attempt == callbackAttempts {
+			s.getLogger().Error("scan callback failed", "id", job.ID, "url", job.Request.CallbackURL, "attempts", attempt, "error", err)
+			return
+		}
+		s.getLogger().Warn("scan callback failed, retrying", "id", job.ID, "url", job.Request.CallbackURL, "attempt", attempt, "retry_in", backoff, "error", err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func postCallback(client *http.Client, callbackURL string, body []byte) error {
+	response, err := client.Post(callbackURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("callback responded with %s", response.Status)
+	}
+
+	return nil
+}
+
+func (s *Server) updateJob(job *ScanJob, update func(job *ScanJob)) {
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+
+	update(job)
+}
+
+// getJob returns a copy of the job, so it could be safely encoded while the job is still processed.
+func (s *Server) getJob(id string) *ScanJob {
+	s.jobsMu.RLock()
+	defer s.jobsMu.RUnlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil
+	}
+	jobCopy := *job
+
+	return &jobCopy
+}
+
+func newScanID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
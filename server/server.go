@@ -0,0 +1,182 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"githubscanner/scanner"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const defaultCacheTTL = 10 * time.Minute
+
+type Server struct {
+	Scanner  scanner.RepositoryScanner
+	CacheTTL time.Duration
+	// Approvals stores release reviews, a store opened without a path keeps them in memory.
+	Approvals *scanner.ApprovalStore
+	// Logger receives the failures of background scans and callbacks. Logging is disabled if it is nil.
+	Logger *slog.Logger
+	// MaxDeferredJobs is how many jobs queued later may start before a queued scan job, e.g. jobs of high
+	// priority accounts, before it starts ahead of them. It is 3 by default.
+	MaxDeferredJobs int
+
+	mu    sync.RWMutex
+	cache map[string]*cachedScan
+	group singleflight.Group
+
+	jobsMu sync.RWMutex
+	jobs   map[string]*ScanJob
+	// queue holds the jobs waiting for a worker, queueReady is signalled when a job is queued.
+	queue      []*ScanJob
+	queueReady *sync.Cond
+	// priorities are the high priority accounts, their jobs start first.
+	priorities map[string]bool
+	// closed is set by Close, jobs are not queued after it.
+	closed  bool
+	workers sync.WaitGroup
+
+	metrics metrics
+}
+
+type cachedScan struct {
+	items     []*scanner.ResultItem
+	scannedAt time.Time
+}
+
+type scanResponse struct {
+	Account   string                `json:"account"`
+	ScannedAt time.Time             `json:"scanned_at"`
+	Items     []*scanner.ResultItem `json:"items"`
+}
+
+func New(s scanner.RepositoryScanner, cacheTTL time.Duration, approvals *scanner.ApprovalStore) *Server {
+	srv := &Server{
+		Scanner:   s,
+		CacheTTL:  cacheTTL,
+		Approvals: approvals,
+		cache:     make(map[string]*cachedScan),
+		jobs:      make(map[string]*ScanJob),
+	}
+	srv.startQueue()
+
+	return srv
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /accounts/{name}/scan", s.handleScan)
+	mux.HandleFunc("GET /accounts/{name}/releases", s.handleReleases)
+	mux.HandleFunc("POST /scans", s.handleCreateScan)
+	mux.HandleFunc("GET /scans/{id}", s.handleGetScan)
+	mux.HandleFunc("GET /feed", s.handleFeed)
+	mux.HandleFunc("GET /metrics", s.handleMetrics)
+	mux.HandleFunc("GET /approvals", s.handleListApprovals)
+	mux.HandleFunc("PUT /repos/{owner}/{repo}/releases/{release}/approval", s.handleSetApproval)
+	mux.HandleFunc("DELETE /repos/{owner}/{repo}/releases/{release}/approval", s.handleDeleteApproval)
+
+	return mux
+}
+
+// handleScan always runs a fresh scan of the account. Concurrent requests for the same account share one scan.
+func (s *Server) handleScan(w http.ResponseWriter, r *http.Request) {
+	account := r.PathValue("name")
+	scan, err := s.scan(account)
+	if err != nil {
+		s.writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	s.writeScan(w, r, account, scan)
+}
+
+// handleReleases returns the cached scan of the account, scanning it only if the cache is empty or expired.
+func (s *Server) handleReleases(w http.ResponseWriter, r *http.Request) {
+	account := r.PathValue("name")
+	scan, ok := s.getCached(account)
+	if !ok {
+		var err error
+		if scan, err = s.scan(account); err != nil {
+			s.writeError(w, http.StatusBadGateway, err.Error())
+			return
+		}
+	}
+	s.writeScan(w, r, account, scan)
+}
+
+func (s *Server) scan(account string) (*cachedScan, error) {
+	result, err, _ := s.group.Do(account, func() (interface{}, error) {
+		start := time.Now()
+		items, err := s.Scanner.ScanRepositories(account)
+		s.metrics.observeScan(account, time.Since(start), err)
+		if err != nil {
+			return nil, err
+		}
+		scan := &cachedScan{
+			items:     items,
+			scannedAt: time.Now(),
+		}
+		s.mu.Lock()
+		s.cache[account] = scan
+		s.mu.Unlock()
+
+		return scan, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.(*cachedScan), nil
+}
+
+func (s *Server) getCached(account string) (*cachedScan, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	scan, ok := s.cache[account]
+	if !ok || time.Since(scan.scannedAt) > s.getCacheTTL() {
+		return nil, false
+	}
+
+	return scan, true
+}
+
+func (s *Server) getCacheTTL() time.Duration {
+	if s.CacheTTL <= 0 {
+		return defaultCacheTTL
+	}
+
+	return s.CacheTTL
+}
+
+func (s *Server) writeScan(w http.ResponseWriter, r *http.Request, account string, scan *cachedScan) {
+	s.writeJSON(w, http.StatusOK, scanResponse{
+		Account:   account,
+		ScannedAt: scan.scannedAt,
+		Items:     s.filterByReview(r, scan.items),
+	})
+}
+
+func (s *Server) writeError(w http.ResponseWriter, status int, message string) {
+	s.writeJSON(w, status, struct {
+		Message string `json:"message"`
+	}{message})
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func (s *Server) getLogger() *slog.Logger {
+	if s.Logger == nil {
+		return slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+
+	return s.Logger
+}
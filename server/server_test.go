@@ -0,0 +1,399 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"githubscanner/scanner"
+	"githubscanner/scanner/scannertest"
+)
+
+func newGitHubServer(reposRequests *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/users/test/repos" {
+			atomic.AddInt32(reposRequests, 1)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"full_name": "test/test", "name": "test"}]`))
+		}
+		if r.URL.Path == "/repos/test/test/releases" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"name": "v1.0.0"}]`))
+		}
+	}))
+}
+
+func newApprovalStore(t *testing.T) *scanner.ApprovalStore {
+	store, err := scanner.OpenApprovalStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return store
+}
+
+func TestReleasesAreCached(t *testing.T) {
+	var reposRequests int32
+	github := newGitHubServer(&reposRequests)
+	defer github.Close()
+
+	srv := New(&scanner.Scanner{BaseUrl: github.URL}, time.Minute, newApprovalStore(t))
+	api := httptest.NewServer(srv.Handler())
+	defer api.Close()
+
+	for i := 0; i < 2; i++ {
+		response, err := http.Get(api.URL + "/accounts/test/releases")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var scan scanResponse
+		err = json.NewDecoder(response.Body).Decode(&scan)
+		response.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(scan.Items) != 1 || scan.Items[0].Releases[0].Name != "v1.0.0" {
+			t.Fatalf("invalid scan response: %+v", scan)
+		}
+	}
+
+	if reposRequests != 1 {
+		t.Fatalf("invalid repositories requests count, expected 1, got %d", reposRequests)
+	}
+}
+
+func TestScanError(t *testing.T) {
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message": "forbidden"}`))
+	}))
+	defer github.Close()
+
+	srv := New(&scanner.Scanner{BaseUrl: github.URL}, time.Minute, newApprovalStore(t))
+	api := httptest.NewServer(srv.Handler())
+	defer api.Close()
+
+	response, err := http.Get(api.URL + "/accounts/test/scan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusBadGateway {
+		t.Fatalf("invalid status code, expected %d, got %d", http.StatusBadGateway, response.StatusCode)
+	}
+}
+
+func TestQueuedScanCallback(t *testing.T) {
+	var reposRequests int32
+	github := newGitHubServer(&reposRequests)
+	defer github.Close()
+
+	callbacks := make(chan *ScanJob, 1)
+	callback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var job ScanJob
+		if err := json.NewDecoder(r.Body).Decode(&job); err != nil {
+			t.Error(err)
+		}
+		callbacks <- &job
+	}))
+	defer callback.Close()
+
+	srv := New(&scanner.Scanner{BaseUrl: github.URL}, time.Minute, newApprovalStore(t))
+	defer srv.Close()
+	api := httptest.NewServer(srv.Handler())
+	defer api.Close()
+
+	body := strings.NewReader(`{"account": "test", "callback_url": "` + callback.URL + `"}`)
+	response, err := http.Post(api.URL+"/scans", "application/json", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var created ScanJob
+	err = json.NewDecoder(response.Body).Decode(&created)
+	response.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.StatusCode != http.StatusAccepted || created.ID == "" {
+		t.Fatalf("invalid scan creation response: %d %+v", response.StatusCode, created)
+	}
+
+	select {
+	case job := <-callbacks:
+		if job.ID != created.ID {
+			t.Fatalf("invalid scan id in callback, expected %s, got %s", created.ID, job.ID)
+		}
+		if job.Status != ScanStatusCompleted || len(job.Items) != 1 {
+			t.Fatalf("invalid scan in callback: %+v", job)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("callback was not called")
+	}
+}
+
+func TestQueuedScanCallbackRetry(t *testing.T) {
+	callbackBackoff = time.Millisecond
+	defer func() { callbackBackoff = time.Second }()
+	var reposRequests int32
+	github := newGitHubServer(&reposRequests)
+	defer github.Close()
+
+	var attempts atomic.Int32
+	callback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < callbackAttempts {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer callback.Close()
+
+	srv := New(&scanner.Scanner{BaseUrl: github.URL}, time.Minute, newApprovalStore(t))
+	api := httptest.NewServer(srv.Handler())
+	defer api.Close()
+
+	body := strings.NewReader(`{"account": "test", "callback_url": "` + callback.URL + `"}`)
+	response, err := http.Post(api.URL+"/scans", "application/json", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+	// Close waits for the queued scan and its callback.
+	srv.Close()
+
+	if attempts.Load() != callbackAttempts {
+		t.Fatalf("invalid callback attempts, expected %d, got %d", callbackAttempts, attempts.Load())
+	}
+	response, err = http.Post(api.URL+"/scans", "application/json", strings.NewReader(`{"account": "test"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("invalid status of a scan created after Close, expected %d, got %d", http.StatusServiceUnavailable, response.StatusCode)
+	}
+}
+
+func TestEvictJobs(t *testing.T) {
+	now := time.Now()
+	srv := &Server{jobs: make(map[string]*ScanJob)}
+	finishedAt := func(age time.Duration) *time.Time {
+		finished := now.Add(-age)
+		return &finished
+	}
+	srv.jobs["running"] = &ScanJob{ID: "running", Status: ScanStatusRunning}
+	srv.jobs["expired"] = &ScanJob{ID: "expired", Status: ScanStatusCompleted, FinishedAt: finishedAt(2 * finishedJobTTL)}
+	for i := 0; i <= maxFinishedJobs; i++ {
+		id := fmt.Sprintf("finished-%d", i)
+		srv.jobs[id] = &ScanJob{ID: id, Status: ScanStatusCompleted, FinishedAt: finishedAt(time.Duration(maxFinishedJobs-i) * time.Second)}
+	}
+	srv.evictJobs(now)
+
+	if len(srv.jobs) != maxFinishedJobs+1 {
+		t.Fatalf("invalid jobs count, expected %d, got %d", maxFinishedJobs+1, len(srv.jobs))
+	}
+	for _, id := range []string{"expired", "finished-0"} {
+		if srv.jobs[id] != nil {
+			t.Fatalf("invalid jobs, expected the job %s evicted", id)
+		}
+	}
+	if srv.jobs["running"] == nil || srv.jobs["finished-1"] == nil {
+		t.Fatalf("invalid jobs, expected the running and the recently finished jobs kept")
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	var reposRequests int32
+	github := newGitHubServer(&reposRequests)
+	defer github.Close()
+
+	srv := New(&scanner.Scanner{BaseUrl: github.URL}, time.Minute, newApprovalStore(t))
+	api := httptest.NewServer(srv.Handler())
+	defer api.Close()
+
+	for _, account := range []string{"test", "missing"} {
+		response, err := http.Get(api.URL + "/accounts/" + account + "/scan")
+		if err != nil {
+			t.Fatal(err)
+		}
+		response.Body.Close()
+	}
+
+	response, err := http.Get(api.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		`githubscanner_scans_total{account="missing",result="error"} 1`,
+		`githubscanner_scans_total{account="test",result="success"} 1`,
+		`githubscanner_last_successful_scan_timestamp_seconds{account="test"}`,
+	} {
+		if !strings.Contains(string(body), expected) {
+			t.Fatalf("metrics do not contain %q:\n%s", expected, body)
+		}
+	}
+}
+
+func TestWriteAlertRules(t *testing.T) {
+	var rules strings.Builder
+	if err := WriteAlertRules(&rules, AlertRulesOptions{Accounts: []string{"org-a", "my.org"}, StaleAfter: 2 * time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		`expr: 'increase(githubscanner_scans_total{account=~"org-a|my\\.org",result="error"}[15m]) > 0'`,
+		`expr: 'time() - githubscanner_last_successful_scan_timestamp_seconds{account=~"org-a|my\\.org"} > 7200'`,
+		`expr: 'absent(githubscanner_last_successful_scan_timestamp_seconds{account="my.org"})'`,
+		`summary: 'Scans of {{ $labels.account }} hit the API rate limit'`,
+	} {
+		if !strings.Contains(rules.String(), expected) {
+			t.Fatalf("rules do not contain %q:\n%s", expected, rules.String())
+		}
+	}
+}
+
+func TestReleaseApprovals(t *testing.T) {
+	var reposRequests int32
+	github := newGitHubServer(&reposRequests)
+	defer github.Close()
+
+	srv := New(&scanner.Scanner{BaseUrl: github.URL}, time.Minute, newApprovalStore(t))
+	api := httptest.NewServer(srv.Handler())
+	defer api.Close()
+
+	getReleases := func(review string) []*scanner.ResultItem {
+		response, err := http.Get(api.URL + "/accounts/test/releases?review=" + review)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer response.Body.Close()
+		var scan scanResponse
+		if err := json.NewDecoder(response.Body).Decode(&scan); err != nil {
+			t.Fatal(err)
+		}
+		return scan.Items
+	}
+
+	if items := getReleases(scanner.ReviewStatusApproved); len(items) != 0 {
+		t.Fatalf("invalid approved releases before approval: %v", items)
+	}
+
+	body := strings.NewReader(`{"status": "approved", "reviewer": "alice"}`)
+	request, _ := http.NewRequest(http.MethodPut, api.URL+"/repos/test/test/releases/v1.0.0/approval", body)
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("invalid approval response status, expected %d, got %d", http.StatusOK, response.StatusCode)
+	}
+
+	if items := getReleases(scanner.ReviewStatusApproved); len(items) != 1 || items[0].Releases[0].Name != "v1.0.0" {
+		t.Fatalf("invalid approved releases: %v", items)
+	}
+	if items := getReleases(scanner.ReviewStatusUnreviewed); len(items) != 0 {
+		t.Fatalf("invalid unreviewed releases: %v", items)
+	}
+	if approval := srv.Approvals.Get("test/test", "v1.0.0"); approval == nil || approval.Reviewer != "alice" {
+		t.Fatalf("invalid stored approval: %v", approval)
+	}
+}
+
+func TestScanWithFakeScanner(t *testing.T) {
+	fake := &scannertest.Fake{
+		Repositories: map[string][]*scanner.Repository{"test": {scannertest.Repository(2, "test/b"), scannertest.Repository(1, "test/a")}},
+		Releases:     map[string][]*scanner.Release{"test/a": {scannertest.Release("v1.0.0", time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC))}},
+		Errors:       map[string]error{"ScanRepositories broken": errors.New("scan failed")},
+	}
+	api := httptest.NewServer(New(fake, time.Minute, newApprovalStore(t)).Handler())
+	defer api.Close()
+
+	response, err := http.Get(api.URL + "/accounts/test/scan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var scan scanResponse
+	err = json.NewDecoder(response.Body).Decode(&scan)
+	response.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scan.Items) != 2 || scan.Items[0].Repository.FullName != "test/a" || scan.Items[0].Releases[0].TagName != "v1.0.0" {
+		t.Fatalf("invalid scan response: %+v", scan)
+	}
+
+	response, err = http.Get(api.URL + "/accounts/broken/scan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusBadGateway {
+		t.Fatalf("invalid status code, expected %d, got %d", http.StatusBadGateway, response.StatusCode)
+	}
+}
+
+func TestFeed(t *testing.T) {
+	var reposRequests int32
+	github := newGitHubServer(&reposRequests)
+	defer github.Close()
+
+	srv := New(&scanner.Scanner{BaseUrl: github.URL}, time.Minute, newApprovalStore(t))
+	api := httptest.NewServer(srv.Handler())
+	defer api.Close()
+
+	// the feed of the account scans it, the feed of all cached scans reuses the scan
+	for _, path := range []string{"/feed?account=test", "/feed"} {
+		response, err := http.Get(api.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(response.Body)
+		response.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if response.StatusCode != http.StatusOK || !strings.HasPrefix(response.Header.Get("Content-Type"), "application/atom+xml") {
+			t.Fatalf("invalid feed response of %s, expected 200 with an atom feed, got %d %s", path, response.StatusCode, response.Header.Get("Content-Type"))
+		}
+		if !strings.Contains(string(body), "<title>Releases of test</title>") {
+			t.Fatalf("invalid feed of %s:\n%s", path, body)
+		}
+	}
+	if reposRequests != 1 {
+		t.Fatalf("invalid repositories requests count, expected 1, got %d", reposRequests)
+	}
+}
+
+func TestNextJobPriority(t *testing.T) {
+	srv := &Server{MaxDeferredJobs: 2}
+	srv.queueReady = sync.NewCond(&srv.jobsMu)
+	srv.SetPriority("pinned", true)
+	for _, account := range []string{"a", "b", "pinned", "c", "pinned", "pinned", "pinned"} {
+		srv.queue = append(srv.queue, &ScanJob{ID: fmt.Sprintf("%s-%d", account, len(srv.queue)), Request: ScanRequest{Account: account}})
+	}
+	srv.closed = true
+
+	var order []string
+	for job := srv.nextJob(); job != nil; job = srv.nextJob() {
+		order = append(order, job.ID)
+	}
+	// jobs passed over by two pinned ones start ahead of the rest of them
+	expected := []string{"pinned-2", "pinned-4", "a-0", "b-1", "pinned-5", "c-3", "pinned-6"}
+	if strings.Join(order, " ") != strings.Join(expected, " ") {
+		t.Fatalf("invalid job order, expected %v, got %v", expected, order)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"githubscanner/scanner"
+	"githubscanner/scanner/otel"
+)
+
+// traceShutdownTimeout bounds the export of the spans left in the batch when the CLI exits.
+const traceShutdownTimeout = 5 * time.Second
+
+// tracingEnabled reports whether the spans are exported: if the endpoint is passed or the OTLP exporter endpoint
+// env vars are set.
+func tracingEnabled(endpoint string) bool {
+	return endpoint != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != ""
+}
+
+// newTracerProvider returns the provider exporting the spans over OTLP/HTTP to the endpoint, the one of the
+// OTEL_EXPORTER_OTLP_* env vars if it is empty. The spans left in the batch are exported when the CLI exits.
+func newTracerProvider(endpoint string) (scanner.TracerProvider, error) {
+	var options []otlptracehttp.Option
+	if endpoint != "" {
+		options = append(options, otlptracehttp.WithEndpointURL(endpoint))
+	}
+	exporter, err := otlptracehttp.New(context.Background(), options...)
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", programName),
+			attribute.String("service.version", scanner.ScannerVersion()),
+		)),
+	)
+	exitHooks = append(exitHooks, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), traceShutdownTimeout)
+		defer cancel()
+		if err := provider.Shutdown(ctx); err != nil {
+			warn("could not export the trace spans: %v", err)
+		}
+	})
+
+	return otel.NewTracerProvider(provider), nil
+}